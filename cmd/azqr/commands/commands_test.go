@@ -82,6 +82,9 @@ func TestScanCommandHasRequiredFlags(t *testing.T) {
 		{"output-name", "string"},
 		{"mask", "bool"},
 		{"filters", "string"},
+		{"checkpoint", "bool"},
+		{"resume", "string"},
+		{"progress", "bool"},
 	}
 
 	for _, rf := range requiredFlags {
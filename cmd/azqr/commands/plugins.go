@@ -4,6 +4,7 @@
 package commands
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"text/tabwriter"
@@ -15,6 +16,16 @@ import (
 func init() {
 	pluginsCmd.AddCommand(pluginsListCmd)
 	pluginsCmd.AddCommand(pluginsInfoCmd)
+	pluginsCmd.AddCommand(pluginsInstallCmd)
+	pluginsCmd.AddCommand(pluginsUpdateCmd)
+	pluginsCmd.AddCommand(pluginsRemoveCmd)
+	pluginsCmd.AddCommand(pluginsVerifyCmd)
+
+	pluginsInstallCmd.Flags().String("version", "", "Version constraint the package must satisfy (e.g. \">= 1.2.0, < 2.0.0\")")
+	pluginsInstallCmd.Flags().Bool("force", false, "Replace an installed package with the same name")
+	for _, cmd := range []*cobra.Command{pluginsInstallCmd, pluginsUpdateCmd, pluginsVerifyCmd} {
+		cmd.Flags().Bool("require-signature", false, "Reject packages without a manifest signature from a trusted key (always on for URL sources)")
+	}
 	rootCmd.AddCommand(pluginsCmd)
 }
 
@@ -44,17 +55,10 @@ var pluginsListCmd = &cobra.Command{
 		_, _ = fmt.Fprintln(w, "----\t-------\t----\t-----------")
 
 		for _, p := range pluginList {
-			pluginType := "yaml"
-			switch p.Metadata.Type {
-			case plugins.PluginTypeInternal:
-				pluginType = "internal"
-			case plugins.PluginTypeYaml:
-				pluginType = "yaml"
-			}
 			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
 				p.Metadata.Name,
 				p.Metadata.Version,
-				pluginType,
+				p.Metadata.Type,
 				p.Metadata.Description,
 			)
 		}
@@ -82,14 +86,7 @@ var pluginsInfoCmd = &cobra.Command{
 		fmt.Printf("Version: %s\n", plugin.Metadata.Version)
 		fmt.Printf("Description: %s\n", plugin.Metadata.Description)
 
-		pluginType := "yaml"
-		switch plugin.Metadata.Type {
-		case plugins.PluginTypeInternal:
-			pluginType = "internal"
-		case plugins.PluginTypeYaml:
-			pluginType = "yaml"
-		}
-		fmt.Printf("Type: %s\n", pluginType)
+		fmt.Printf("Type: %s\n", plugin.Metadata.Type)
 
 		if plugin.Metadata.Author != "" {
 			fmt.Printf("Author: %s\n", plugin.Metadata.Author)
@@ -101,6 +98,14 @@ var pluginsInfoCmd = &cobra.Command{
 			fmt.Printf("Command Path: %s\n", plugin.Metadata.CommandPath)
 		}
 
+		// Show plugin options
+		if len(plugin.Metadata.Options) > 0 {
+			fmt.Printf("\nOptions (--<name> or --stage-param plugin.%s.<name>=value):\n", plugin.Metadata.Name)
+			for _, opt := range plugin.Metadata.Options {
+				fmt.Printf("  %s (%s): %s\n", opt.Name, opt.Type, opt.Description)
+			}
+		}
+
 		// Show YAML plugin recommendations
 		if len(plugin.YamlRecommendations) > 0 {
 			fmt.Printf("\nYAML Plugin Information:\n")
@@ -117,10 +122,148 @@ var pluginsInfoCmd = &cobra.Command{
 			fmt.Printf("  Resource Types: %v\n", resourceTypes)
 		}
 
+		// Show YAML plugin diagnostic settings recommendations
+		if len(plugin.DiagnosticSettingsRecommendations) > 0 {
+			fmt.Printf("\nYAML Plugin Diagnostic Settings Recommendations:\n")
+			for _, rec := range plugin.DiagnosticSettingsRecommendations {
+				fmt.Printf("  %s (%s): %s\n", rec.RecommendationID, rec.ResourceType, rec.Recommendation)
+			}
+		}
+
+		// Show YAML plugin scanners
+		if len(plugin.YamlScanners) > 0 {
+			fmt.Printf("\nYAML Plugin Scanners:\n")
+			for _, s := range plugin.YamlScanners {
+				fmt.Printf("  %s (%s): %v\n", s.Abbreviation, s.ServiceName, s.ResourceTypes)
+			}
+		}
+
+		// Show YAML plugin table queries
+		if plugin.Metadata.Type == plugins.PluginTypeYaml && plugin.InternalScanner != nil {
+			fmt.Printf("\nYAML Plugin Tables:\n")
+			fmt.Printf("  Run with: azqr %s or azqr scan --plugin %s\n", plugin.Metadata.Name, plugin.Metadata.Name)
+		}
+
 		// Show internal plugin scanner info
-		if plugin.InternalScanner != nil {
+		if plugin.Metadata.Type == plugins.PluginTypeInternal {
 			fmt.Printf("\nInternal Plugin Information:\n")
 			fmt.Printf("  Scanner available\n")
 		}
 	},
 }
+
+// newPackageManager returns a package manager configured from the command flags
+func newPackageManager(cmd *cobra.Command) *plugins.PackageManager {
+	manager := plugins.NewPackageManager(version)
+	manager.RequireSignature, _ = cmd.Flags().GetBool("require-signature")
+	return manager
+}
+
+var pluginsInstallCmd = &cobra.Command{
+	Use:   "install <path|url>",
+	Short: "Install a plugin package",
+	Long:  "Install a plugin package from a directory, a .zip/.tar.gz archive or an https archive URL into ~/.azqr/plugins. File checksums, and the manifest signature if present, are verified before installing. Packages downloaded from a URL must be signed by a trusted key.",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		constraint, _ := cmd.Flags().GetString("version")
+		force, _ := cmd.Flags().GetBool("force")
+
+		manifest, err := newPackageManager(cmd).Install(context.Background(), args[0], constraint, force)
+		if err != nil {
+			fmt.Printf("Failed to install plugin package: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Installed %s %s\n", manifest.Name, manifest.Version)
+	},
+}
+
+var pluginsUpdateCmd = &cobra.Command{
+	Use:   "update [package-name...]",
+	Short: "Update installed plugin packages",
+	Long:  "Update installed plugin packages from the source they were installed from. Without arguments all packages are updated.",
+	Run: func(cmd *cobra.Command, args []string) {
+		manager := newPackageManager(cmd)
+		names := packageNames(manager, args)
+
+		failed := false
+		for _, name := range names {
+			manifest, updated, err := manager.Update(context.Background(), name)
+			switch {
+			case err != nil:
+				fmt.Printf("%s: update failed: %v\n", name, err)
+				failed = true
+			case updated:
+				fmt.Printf("%s: updated to %s\n", name, manifest.Version)
+			default:
+				fmt.Printf("%s: %s is up to date\n", name, manifest.Version)
+			}
+		}
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+var pluginsRemoveCmd = &cobra.Command{
+	Use:   "remove <package-name>",
+	Short: "Remove an installed plugin package",
+	Long:  "Remove an installed plugin package from ~/.azqr/plugins",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := plugins.NewPackageManager(version).Remove(args[0]); err != nil {
+			fmt.Printf("Failed to remove plugin package: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Removed %s\n", args[0])
+	},
+}
+
+var pluginsVerifyCmd = &cobra.Command{
+	Use:   "verify [package-name...]",
+	Short: "Verify installed plugin packages",
+	Long:  "Verify the file checksums and manifest signatures of installed plugin packages. Without arguments all packages are verified.",
+	Run: func(cmd *cobra.Command, args []string) {
+		manager := newPackageManager(cmd)
+		names := packageNames(manager, args)
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "NAME\tVERSION\tSIGNED\tSTATUS")
+		_, _ = fmt.Fprintln(w, "----\t-------\t------\t------")
+
+		failed := false
+		for _, name := range names {
+			result, err := manager.Verify(name)
+			if err != nil {
+				_, _ = fmt.Fprintf(w, "%s\t-\t-\t%v\n", name, err)
+				failed = true
+				continue
+			}
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%t\tok\n", name, result.Manifest.Version, result.Signed)
+		}
+		_ = w.Flush()
+
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+// packageNames returns the given package names, or every installed package when none are given
+func packageNames(manager *plugins.PackageManager, args []string) []string {
+	if len(args) > 0 {
+		return args
+	}
+	installed, err := manager.List()
+	if err != nil {
+		fmt.Printf("Failed to list plugin packages: %v\n", err)
+		os.Exit(1)
+	}
+	if len(installed) == 0 {
+		fmt.Println("No plugin packages installed")
+	}
+	names := make([]string, 0, len(installed))
+	for _, p := range installed {
+		names = append(names, p.Manifest.Name)
+	}
+	return names
+}
@@ -67,6 +67,9 @@ func Execute() {
 		log.Warn().Err(err).Msg("Failed to load some plugins")
 	}
 
+	// Add scan subcommands for scanners declared by YAML plugins
+	addScannerCommands()
+
 	// Attach plugin commands as top-level commands and set their Run functions
 	registry := plugins.GetRegistry()
 	for _, plugin := range registry.List() {
@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	"github.com/Azure/azqr/internal/renderers"
+	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
 
@@ -22,7 +23,10 @@ var rulesCmd = &cobra.Command{
 	Args:  cobra.NoArgs,
 	Run: func(cmd *cobra.Command, args []string) {
 		oj, _ := cmd.Flags().GetBool("json")
-		output := renderers.GetAllRecommendations(!oj)
+		output, err := renderers.GetAllRecommendations(!oj)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to list recommendations")
+		}
 		fmt.Println(output)
 	},
 }
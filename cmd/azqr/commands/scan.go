@@ -4,12 +4,16 @@
 package commands
 
 import (
-	"strings"
+	"os"
+	"time"
 
 	"github.com/Azure/azqr/internal/models"
 	"github.com/Azure/azqr/internal/pipeline"
+	"github.com/Azure/azqr/internal/plugins"
 	"github.com/Azure/azqr/internal/profiling"
+	"github.com/Azure/azqr/internal/progress"
 
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
@@ -18,9 +22,11 @@ func init() {
 	scanCmd.PersistentFlags().StringSliceP("management-group-id", "", []string{}, "Azure Management Group Id")
 	scanCmd.PersistentFlags().StringSliceP("subscription-id", "s", []string{}, "Azure Subscription Id")
 	scanCmd.PersistentFlags().StringSliceP("resource-group", "g", []string{}, "Azure Resource Group (Use with --subscription-id)")
-	scanCmd.PersistentFlags().StringSliceP("stages", "", []string{}, "Control scan stages. Without this flag, defaults are used (enabled: diagnostics,advisor,defender). Specify stages to enable (e.g., --stages cost,policy) or prefix with '-' to disable (e.g., --stages -diagnostics). Available: advisor,defender,defender-recommendations,arc,policy,cost,diagnostics")
+	scanCmd.PersistentFlags().StringSliceP("stages", "", []string{}, "Control scan stages. Without this flag, defaults are used (enabled: diagnostics,advisor,defender). Specify stages to enable (e.g., --stages cost,policy) or prefix with '-' to disable (e.g., --stages -diagnostics). Available: advisor,defender,defender-recommendations,arc,policy,cost,quota,diagnostics")
 	scanCmd.PersistentFlags().StringArrayP("stage-param", "", []string{}, "Stage options in the form 'stage.key=value' (repeatable)")
 	scanCmd.PersistentFlags().StringSliceP("plugin", "", []string{}, "Enable internal plugins (comma-separated or multiple flags)")
+	scanCmd.PersistentFlags().Int("plugin-concurrency", models.DefaultPluginConcurrency, "Maximum number of plugins run at the same time")
+	scanCmd.PersistentFlags().Duration("plugin-timeout", models.DefaultPluginTimeout, "Maximum time each plugin may run")
 	scanCmd.PersistentFlags().BoolP("xlsx", "", true, "Create Excel report (default) (default true)")
 	scanCmd.PersistentFlags().BoolP("json", "", false, "Create JSON report files")
 	scanCmd.PersistentFlags().BoolP("csv", "", false, "Create CSV report files")
@@ -28,6 +34,9 @@ func init() {
 	scanCmd.PersistentFlags().StringP("output-name", "o", "", "Output file name without extension")
 	scanCmd.PersistentFlags().BoolP("mask", "m", true, "Mask the subscription id in the report (default) (default true)")
 	scanCmd.PersistentFlags().StringP("filters", "e", "", "Filters file (YAML format)")
+	scanCmd.PersistentFlags().BoolP("checkpoint", "", false, "Save scan progress after each stage so a failed scan can be resumed with --resume")
+	scanCmd.PersistentFlags().StringP("resume", "", "", "Resume a failed scan from the given checkpoint file")
+	scanCmd.PersistentFlags().BoolP("progress", "", true, "Show a live progress bar when writing to a terminal (default true)")
 
 	// Conditionally add profiling flags if profiling is available and enabled via environment
 	// Build with -tags debug to enable profiling features
@@ -65,6 +74,10 @@ func scan(cmd *cobra.Command, scannerKeys []string) {
 	stdout, _ := cmd.Flags().GetBool("stdout")
 	filtersFile, _ := cmd.Flags().GetString("filters")
 	pluginNames, _ := cmd.Flags().GetStringSlice("plugin")
+	pluginConcurrency, _ := cmd.Flags().GetInt("plugin-concurrency")
+	pluginTimeout, _ := cmd.Flags().GetDuration("plugin-timeout")
+	checkpoint, _ := cmd.Flags().GetBool("checkpoint")
+	resume, _ := cmd.Flags().GetString("resume")
 
 	// Get profiling flags if available
 	var cpuProfile, memProfile, traceProfile string
@@ -75,7 +88,10 @@ func scan(cmd *cobra.Command, scannerKeys []string) {
 	}
 
 	// load filters
-	filters := models.LoadFilters(filtersFile, scannerKeys)
+	filters, err := models.ParseFilters(filtersFile, scannerKeys)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed loading filters")
+	}
 
 	// Build enabled plugins map from --plugin flag
 	enabledInternalPlugins := map[string]bool{}
@@ -85,7 +101,9 @@ func scan(cmd *cobra.Command, scannerKeys []string) {
 
 	// Initialize stage configs
 	stageConfigs := models.NewStageConfigsWithDefaults()
-	stageConfigs.ConfigureStages(stageNames)
+	if err := stageConfigs.SelectStages(stageNames); err != nil {
+		log.Fatal().Err(err).Msg("Invalid stage name")
+	}
 
 	if err := stageConfigs.ApplyStageParams(stageParams); err != nil {
 		log.Fatal().Err(err).Msg("failed applying stage parameters")
@@ -105,12 +123,16 @@ func scan(cmd *cobra.Command, scannerKeys []string) {
 		ScannerKeys:            scannerKeys,
 		Filters:                filters,
 		EnabledInternalPlugins: enabledInternalPlugins,
+		PluginConcurrency:      pluginConcurrency,
+		PluginTimeout:          pluginTimeout,
+		Checkpoint:             checkpoint,
+		ResumeFrom:             resume,
 		CPUProfile:             cpuProfile,
 		MemProfile:             memProfile,
 		TraceProfile:           traceProfile,
 	}
 
-	scanner := pipeline.Scanner{}
+	scanner := pipeline.Scanner{Observer: progressObserver(cmd)}
 	scanner.Scan(&params)
 }
 
@@ -128,6 +150,7 @@ func scanWithPlugin(cmd *cobra.Command, scannerKeys []string, pluginName string)
 	debug, _ := cmd.Flags().GetBool("debug")
 	stdout, _ := cmd.Flags().GetBool("stdout")
 	filtersFile, _ := cmd.Flags().GetString("filters")
+	pluginTimeout, _ := cmd.Flags().GetDuration("plugin-timeout")
 
 	// Get profiling flags if available
 	var cpuProfile, memProfile, traceProfile string
@@ -138,7 +161,10 @@ func scanWithPlugin(cmd *cobra.Command, scannerKeys []string, pluginName string)
 	}
 
 	// load filters
-	filters := models.LoadFilters(filtersFile, scannerKeys)
+	filters, err := models.ParseFilters(filtersFile, scannerKeys)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed loading filters")
+	}
 
 	// Enable only the specified plugin
 	enabledInternalPlugins := map[string]bool{
@@ -147,12 +173,10 @@ func scanWithPlugin(cmd *cobra.Command, scannerKeys []string, pluginName string)
 
 	stageConfigs := models.NewStageConfigs()
 
-	// Bridge plugin-specific flags into stage options so plugins can read them
-	// via params.Stages.GetStageOptions without relying on package-level globals.
-	if targetRegions, _ := cmd.Flags().GetStringSlice("target-regions"); len(targetRegions) > 0 {
-		_ = stageConfigs.SetStageOptions(models.StageNamePlugin, map[string]any{
-			"target-regions": strings.Join(targetRegions, ","),
-		})
+	// Bridge the plugin option flags into stage options so plugins can read them
+	// via params.Stages.GetPluginOptions without relying on package-level globals.
+	if err := stageConfigs.SetPluginOptions(pluginName, plugins.OptionFlagValues(cmd, pluginName)); err != nil {
+		log.Fatal().Err(err).Msg("failed applying plugin options")
 	}
 
 	params := models.ScanParams{
@@ -170,12 +194,26 @@ func scanWithPlugin(cmd *cobra.Command, scannerKeys []string, pluginName string)
 		ScannerKeys:            scannerKeys,
 		Filters:                filters,
 		EnabledInternalPlugins: enabledInternalPlugins,
+		PluginTimeout:          pluginTimeout,
 		CPUProfile:             cpuProfile,
 		MemProfile:             memProfile,
 		TraceProfile:           traceProfile,
 	}
 
-	scanner := pipeline.Scanner{}
+	scanner := pipeline.Scanner{Observer: progressObserver(cmd)}
 	// Call ScanPlugins directly for optimized plugin-only execution
 	scanner.ScanPlugins(&params)
 }
+
+// progressObserver returns a live progress bar observer when the --progress
+// flag is set and stderr is a terminal, or nil otherwise. Console logs are
+// routed through the bar so they are printed above it instead of inside it.
+func progressObserver(cmd *cobra.Command) progress.Observer {
+	show, err := cmd.Flags().GetBool("progress")
+	if err != nil || !show || !progress.IsTerminal(os.Stderr) {
+		return nil
+	}
+	bar := progress.NewBar(os.Stderr)
+	log.Logger = log.Logger.Output(zerolog.ConsoleWriter{Out: bar, TimeFormat: time.RFC3339})
+	return bar
+}
@@ -15,6 +15,18 @@ import (
 )
 
 func init() {
+	addScannerCommands()
+}
+
+// addScannerCommands creates a "scan <abbreviation>" command for each
+// registered scanner that does not have one yet. It runs at init time for the
+// built-in scanners and again after plugins have registered their scanners.
+func addScannerCommands() {
+	existing := make(map[string]bool)
+	for _, cmd := range scanCmd.Commands() {
+		existing[cmd.Name()] = true
+	}
+
 	abbreviations := make([]string, 0, len(models.ScannerList))
 	for abbr := range models.ScannerList {
 		abbreviations = append(abbreviations, abbr)
@@ -24,7 +36,7 @@ func init() {
 	// Dynamically create a command for each registered scanner
 	for _, abbr := range abbreviations {
 		scanners := models.ScannerList[abbr]
-		if len(scanners) == 0 {
+		if len(scanners) == 0 || existing[abbr] {
 			continue
 		}
 
@@ -9,6 +9,7 @@ import (
 	// Import internal plugins to register them
 	_ "github.com/Azure/azqr/internal/scanners/plugins/aigov"
 	_ "github.com/Azure/azqr/internal/scanners/plugins/carbon"
+	_ "github.com/Azure/azqr/internal/scanners/plugins/costanomaly"
 	_ "github.com/Azure/azqr/internal/scanners/plugins/region"
 	_ "github.com/Azure/azqr/internal/scanners/plugins/servicehealth"
 	_ "github.com/Azure/azqr/internal/scanners/plugins/sqleol"
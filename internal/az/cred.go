@@ -16,19 +16,19 @@ import (
 // NewAzureCredential creates a new Azure credential using DefaultAzureCredential.
 // The credential chain behavior can be customized using the AZURE_TOKEN_CREDENTIALS environment variable.
 func NewAzureCredential() azcore.TokenCredential {
-	var cred azcore.TokenCredential
-	var err error
-
-	opts := azcore.ClientOptions{Cloud: GetCloudConfiguration()}
-
-	cred, err = azidentity.NewDefaultAzureCredential(&azidentity.DefaultAzureCredentialOptions{ClientOptions: opts})
-
+	cred, err := CreateAzureCredential()
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to get Azure credentials")
 	}
 	return cred
 }
 
+// CreateAzureCredential is like NewAzureCredential but returns the error instead of exiting.
+func CreateAzureCredential() (azcore.TokenCredential, error) {
+	opts := azcore.ClientOptions{Cloud: GetCloudConfiguration()}
+	return azidentity.NewDefaultAzureCredential(&azidentity.DefaultAzureCredentialOptions{ClientOptions: opts})
+}
+
 // GetCloudConfiguration returns the appropriate Azure cloud configuration
 // based on environment variables. It supports both predefined clouds
 // (AzurePublic, AzureGovernment, AzureChina) and custom cloud configurations.
@@ -3,12 +3,14 @@
 
 // Package az provides shared Azure REST API types and helpers.
 // This file defines the ARM Locations API response types used by both the
-// zone-mapping plugin and the region-selection plugin.
+// zone-mapping plugin and the region-selection plugin, and the region name
+// helpers shared by the core scanners and the plugins.
 package az
 
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 )
 
 // LocationsResponse is the ARM JSON envelope for GET /subscriptions/{id}/locations.
@@ -24,9 +26,19 @@ type LocationInfo struct {
 	AvailabilityZoneMappings []AvailabilityZoneMapping `json:"availabilityZoneMappings"`
 }
 
-// LocationMetadata carries the region classification (Physical vs. Logical).
+// LocationMetadata carries the region classification (Physical vs. Logical),
+// its geography and its paired regions.
 type LocationMetadata struct {
-	RegionType string `json:"regionType"`
+	RegionType       string         `json:"regionType"`
+	Geography        string         `json:"geography"`
+	GeographyGroup   string         `json:"geographyGroup"`
+	PhysicalLocation string         `json:"physicalLocation"`
+	PairedRegion     []PairedRegion `json:"pairedRegion"`
+}
+
+// PairedRegion is one entry of a location's pairedRegion list.
+type PairedRegion struct {
+	Name string `json:"name"`
 }
 
 // AvailabilityZoneMapping is one logical → physical zone pair for a location.
@@ -61,3 +73,41 @@ func ZoneMappingForRegion(locations []LocationInfo, regionName string) map[strin
 	}
 	return nil
 }
+
+// NormalizeRegionName converts region display names to lowercase identifiers
+// by removing spaces and converting to lowercase. Use this everywhere a region
+// name needs to be compared or stored.
+func NormalizeRegionName(region string) string {
+	return strings.ToLower(strings.ReplaceAll(region, " ", ""))
+}
+
+// nonPhysicalRegions contains Azure meta/logical region identifiers that do not
+// correspond to a deployable physical region. Quota and cost APIs are meaningless for these.
+var nonPhysicalRegions = map[string]bool{
+	"":             true,
+	"unassigned":   true,
+	"global":       true,
+	"europe":       true,
+	"unitedstates": true,
+	"asia":         true,
+	"asiapacific":  true,
+	"australia":    true,
+	"brazil":       true,
+	"canada":       true,
+	"france":       true,
+	"germany":      true,
+	"india":        true,
+	"japan":        true,
+	"korea":        true,
+	"norway":       true,
+	"southafrica":  true,
+	"switzerland":  true,
+	"uae":          true,
+	"uk":           true,
+}
+
+// IsPhysicalRegion returns true when region is a real deployable Azure region
+// (not a meta/logical identifier like "global", "europe", etc.).
+func IsPhysicalRegion(region string) bool {
+	return !nonPhysicalRegions[NormalizeRegionName(region)]
+}
@@ -0,0 +1,22 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package graph
+
+import (
+	"embed"
+
+	"github.com/Azure/azqr/internal/models"
+)
+
+// The Arc-enabled servers catalog uses the recommendation YAML format of the
+// Graph rules, without queries: the Arc servers scanner evaluates it.
+//
+//go:embed azqr/arc-servers/*.yaml
+var arcServersFiles embed.FS
+
+// ArcServerRecommendations returns the embedded Arc-enabled servers
+// recommendations grouped by lowercase resource type.
+func ArcServerRecommendations() (map[string]map[string]models.GraphRecommendation, error) {
+	return catalogRecommendations(arcServersFiles)
+}
@@ -0,0 +1,24 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package graph
+
+import "testing"
+
+func TestArcServerRecommendations(t *testing.T) {
+	recs, err := ArcServerRecommendations()
+	if err != nil {
+		t.Fatalf("ArcServerRecommendations() error = %v", err)
+	}
+
+	machines := recs["microsoft.hybridcompute/machines"]
+	for _, id := range []string{"arc-001", "arc-002", "arc-003"} {
+		rec, ok := machines[id]
+		if !ok {
+			t.Fatalf("%s missing from the catalog", id)
+		}
+		if rec.Recommendation == "" || rec.Impact == "" || rec.Category == "" || len(rec.LearnMoreLink) == 0 || rec.Source != "AZQR" {
+			t.Errorf("%s is missing metadata: %+v", id, rec)
+		}
+	}
+}
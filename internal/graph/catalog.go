@@ -0,0 +1,49 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package graph
+
+import (
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/Azure/azqr/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// catalogRecommendations returns the recommendations of the YAML files of an
+// embedded catalog grouped by lowercase resource type.
+func catalogRecommendations(catalog fs.FS) (map[string]map[string]models.GraphRecommendation, error) {
+	result := map[string]map[string]models.GraphRecommendation{}
+	err := fs.WalkDir(catalog, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".yaml") {
+			return nil
+		}
+		content, err := fs.ReadFile(catalog, path)
+		if err != nil {
+			return err
+		}
+
+		var recommendations []models.GraphRecommendation
+		if err := yaml.Unmarshal(content, &recommendations); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		for _, rec := range recommendations {
+			t := strings.ToLower(rec.ResourceType)
+			if result[t] == nil {
+				result[t] = map[string]models.GraphRecommendation{}
+			}
+			rec.Source = "AZQR"
+			result[t][rec.RecommendationID] = rec
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,22 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package graph
+
+import (
+	"embed"
+
+	"github.com/Azure/azqr/internal/models"
+)
+
+// The diagnostic settings catalog uses the recommendation YAML format of the
+// Graph rules, without queries: the diagnostic settings scanner evaluates it.
+//
+//go:embed azqr/diagnostic-settings/*.yaml
+var diagnosticSettingsFiles embed.FS
+
+// DiagnosticSettingsRecommendations returns the embedded diagnostic settings
+// recommendations grouped by lowercase resource type.
+func DiagnosticSettingsRecommendations() (map[string]map[string]models.GraphRecommendation, error) {
+	return catalogRecommendations(diagnosticSettingsFiles)
+}
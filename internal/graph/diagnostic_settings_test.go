@@ -0,0 +1,36 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package graph
+
+import (
+	"testing"
+)
+
+func TestDiagnosticSettingsRecommendations(t *testing.T) {
+	recs, err := DiagnosticSettingsRecommendations()
+	if err != nil {
+		t.Fatalf("DiagnosticSettingsRecommendations() error = %v", err)
+	}
+
+	kv, ok := recs["microsoft.keyvault/vaults"]["kv-001"]
+	if !ok {
+		t.Fatal("kv-001 missing from the catalog")
+	}
+	if kv.ResourceType != "Microsoft.KeyVault/vaults" || kv.Category != "MonitoringAndAlerting" || kv.Source != "AZQR" {
+		t.Errorf("kv-001 = %+v", kv)
+	}
+
+	seen := map[string]bool{}
+	for resourceType, byID := range recs {
+		for id, rec := range byID {
+			if seen[id] {
+				t.Errorf("recommendation %s is declared more than once", id)
+			}
+			seen[id] = true
+			if rec.Recommendation == "" || rec.Impact == "" || rec.Category == "" || len(rec.LearnMoreLink) == 0 {
+				t.Errorf("%s (%s) is missing metadata: %+v", id, resourceType, rec)
+			}
+		}
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"sync"
 
 	"github.com/Azure/azqr/internal/models"
+	"github.com/Azure/azqr/internal/progress"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/rs/zerolog/log"
 	"gopkg.in/yaml.v3"
@@ -41,6 +42,24 @@ type (
 		filters         *models.Filters
 		subscriptions   map[string]string
 		externalQueries map[string]map[string]models.GraphRecommendation // External YAML plugin queries by resource type
+		checkpoint      RuleCheckpoint
+	}
+
+	// RuleCheckpoint persists GraphScanner progress so an interrupted scan can
+	// skip the rules that already completed when it is resumed.
+	RuleCheckpoint interface {
+		// CompletedRules returns the results of rules that completed in a previous run,
+		// keyed by RuleKey.
+		CompletedRules() map[string][]*models.GraphResult
+		// SaveRules records the results of a batch of completed rules, keyed by RuleKey.
+		SaveRules(results map[string][]*models.GraphResult) error
+	}
+
+	// ruleResult pairs a rule's key with the results produced by a worker.
+	ruleResult struct {
+		key     string
+		results []*models.GraphResult
+		err     error
 	}
 
 	ScanType string
@@ -106,6 +125,16 @@ func NewScanner(serviceScanners []models.IAzureScanner, filters *models.Filters,
 	}
 }
 
+// SetCheckpoint enables per rule batch checkpointing for Scan.
+func (a *GraphScanner) SetCheckpoint(cp RuleCheckpoint) {
+	a.checkpoint = cp
+}
+
+// RuleKey returns the key identifying a rule in a RuleCheckpoint.
+func RuleKey(rule *models.GraphRecommendation) string {
+	return strings.ToLower(rule.ResourceType) + "|" + rule.RecommendationID
+}
+
 // RegisterExternalQuery adds an external YAML plugin query to the scanner
 func (a *GraphScanner) RegisterExternalQuery(resourceType string, recommendation models.GraphRecommendation) {
 	resourceType = strings.ToLower(resourceType)
@@ -206,12 +235,33 @@ func (a *GraphScanner) ListRecommendations() (map[string]map[string]*models.Grap
 	return recommendations, rules
 }
 
-// Scan scans Azure resources using Graph queries
-func (a *GraphScanner) Scan(ctx context.Context, cred azcore.TokenCredential) []*models.GraphResult {
+// Scan scans Azure resources using Graph queries. Rules that fail are not
+// checkpointed; the first failure is returned after all rules have finished.
+func (a *GraphScanner) Scan(ctx context.Context, cred azcore.TokenCredential) ([]*models.GraphResult, error) {
 	results := []*models.GraphResult{}
 	graph := NewGraphQuery(cred)
 
-	_, rules := a.ListRecommendations()
+	_, allRules := a.ListRecommendations()
+
+	// Reuse results of rules completed by a previous (interrupted) run.
+	var completed map[string][]*models.GraphResult
+	if a.checkpoint != nil {
+		completed = a.checkpoint.CompletedRules()
+	}
+	rules := make([]*models.GraphRecommendation, 0, len(allRules))
+	for _, r := range allRules {
+		if res, ok := completed[RuleKey(r)]; ok {
+			results = append(results, res...)
+			continue
+		}
+		rules = append(rules, r)
+	}
+	if len(completed) > 0 {
+		log.Info().
+			Int("completed", len(allRules)-len(rules)).
+			Int("remaining", len(rules)).
+			Msg("Resuming Graph scan from checkpoint")
+	}
 
 	batchSize := bucketCapacity
 	batches := int(math.Ceil(float64(len(rules)) / float64(batchSize)))
@@ -220,7 +270,7 @@ func (a *GraphScanner) Scan(ctx context.Context, cred azcore.TokenCredential) []
 
 	// Buffer the jobs and results channels to the number of rules to avoid deadlocks.
 	jobs := make(chan *models.GraphRecommendation, len(rules))
-	ch := make(chan []*models.GraphResult, len(rules))
+	ch := make(chan ruleResult, len(rules))
 
 	var wg sync.WaitGroup
 
@@ -243,26 +293,60 @@ func (a *GraphScanner) Scan(ctx context.Context, cred azcore.TokenCredential) []
 			jobs <- r
 		}
 	}
-
-	// Wait for all workers to finish
 	close(jobs)
-	wg.Wait()
 
-	// Receive results from workers
+	// Receive results as workers finish, checkpointing after every batch so a
+	// crash or expired token only loses the rules that were in flight.
+	tracker := progress.NewTracker(ctx, "Graph rules", len(rules))
+	batch := make(map[string][]*models.GraphResult, batchSize)
+	var scanErr error
 	for i := 0; i < len(rules); i++ {
 		res := <-ch
-		for _, r := range res {
+		tracker.Step("")
+		if res.err != nil {
+			if scanErr == nil {
+				scanErr = res.err
+			}
+			continue
+		}
+		filtered := []*models.GraphResult{}
+		for _, r := range res.results {
 			if a.filters.Azqr.IsServiceExcluded(r.ResourceID) {
 				continue
 			}
-			results = append(results, r)
+			filtered = append(filtered, r)
+		}
+		results = append(results, filtered...)
+
+		if a.checkpoint == nil {
+			continue
+		}
+		batch[res.key] = filtered
+		if len(batch) == batchSize || i == len(rules)-1 {
+			if err := a.checkpoint.SaveRules(batch); err != nil {
+				log.Warn().Err(err).Msg("Failed to save Graph scan checkpoint")
+			}
+			batch = make(map[string][]*models.GraphResult, batchSize)
 		}
 	}
 
-	return results
+	// Flush rules left over when the last result was a failure
+	if a.checkpoint != nil && len(batch) > 0 {
+		if err := a.checkpoint.SaveRules(batch); err != nil {
+			log.Warn().Err(err).Msg("Failed to save Graph scan checkpoint")
+		}
+	}
+
+	// Wait for all workers to finish
+	wg.Wait()
+
+	if scanErr != nil {
+		return nil, fmt.Errorf("failed to scan: %w", scanErr)
+	}
+	return results, nil
 }
 
-func (a *GraphScanner) worker(ctx context.Context, graph *GraphQueryClient, subscriptions map[string]string, jobs <-chan *models.GraphRecommendation, results chan<- []*models.GraphResult, wg *sync.WaitGroup) {
+func (a *GraphScanner) worker(ctx context.Context, graph *GraphQueryClient, subscriptions map[string]string, jobs <-chan *models.GraphRecommendation, results chan<- ruleResult, wg *sync.WaitGroup) {
 	// worker processes batches of Graph recommendations from the jobs channel
 	for r := range jobs {
 		models.LogGraphRecommendationScan(r.ResourceType, r.RecommendationID)
@@ -274,13 +358,15 @@ func (a *GraphScanner) worker(ctx context.Context, graph *GraphQueryClient, subs
 					Str("recommendationId", r.RecommendationID).
 					Str("resourceType", r.ResourceType).
 					Msg("Skipping recommendation due to unsupported resource graph logical table")
-				results <- []*models.GraphResult{}
+				results <- ruleResult{key: RuleKey(r), results: []*models.GraphResult{}}
 				wg.Done()
 				continue
 			}
-			log.Fatal().Err(err).Msg("Failed to scan")
+			results <- ruleResult{key: RuleKey(r), err: err}
+			wg.Done()
+			continue
 		}
-		results <- res
+		results <- ruleResult{key: RuleKey(r), results: res}
 		wg.Done()
 	}
 }
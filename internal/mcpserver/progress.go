@@ -0,0 +1,101 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package mcpserver
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Azure/azqr/internal/progress"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/rs/zerolog/log"
+)
+
+// stageStartedProgress is added to the completed stages when a stage starts:
+// MCP progress must increase with every notification, and the previous stage
+// finished event already reported the completed stages.
+const stageStartedProgress = 0.001
+
+// progressNotifier forwards scan progress events to the MCP client as
+// notifications/progress messages tied to the request's progress token.
+type progressNotifier struct {
+	ctx   context.Context
+	srv   *server.MCPServer
+	token mcp.ProgressToken
+
+	mu         sync.Mutex
+	stageIndex int
+	stageCount int
+	stage      string
+	sent       float64
+}
+
+// newProgressObserver returns an observer that sends MCP progress notifications,
+// or nil when the client did not ask for progress (no progress token).
+func newProgressObserver(ctx context.Context, request mcp.CallToolRequest) progress.Observer {
+	if request.Params.Meta == nil || request.Params.Meta.ProgressToken == nil {
+		return nil
+	}
+	srv := server.ServerFromContext(ctx)
+	if srv == nil {
+		srv = s
+	}
+	if srv == nil {
+		return nil
+	}
+	return &progressNotifier{
+		ctx:   ctx,
+		srv:   srv,
+		token: request.Params.Meta.ProgressToken,
+	}
+}
+
+// OnEvent implements progress.Observer. Progress is reported as completed
+// stages plus the fraction of the current task, out of the number of stages.
+func (n *progressNotifier) OnEvent(e progress.Event) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	var current float64
+	var message string
+	switch e.Type {
+	case progress.EventStageStarted:
+		n.stageIndex, n.stageCount, n.stage = e.StageIndex, e.StageCount, e.Stage
+		current = float64(e.StageIndex-1) + stageStartedProgress
+		message = e.Stage
+	case progress.EventStageFinished:
+		current = float64(e.StageIndex)
+		message = fmt.Sprintf("%s completed", e.Stage)
+	case progress.EventTaskProgress:
+		if e.Total <= 0 {
+			return
+		}
+		current = float64(n.stageIndex-1) + min(float64(e.Done)/float64(e.Total), 1)
+		message = fmt.Sprintf("%s: %d/%d %s", n.stage, e.Done, e.Total, e.Task)
+		if e.ETA > 0 {
+			message += fmt.Sprintf(" (ETA %s)", e.ETA.Round(time.Second))
+		}
+	}
+
+	// Progress must increase with every notification
+	if current <= n.sent {
+		return
+	}
+	n.sent = current
+
+	params := map[string]any{
+		"progressToken": n.token,
+		"progress":      current,
+		"message":       message,
+	}
+	if n.stageCount > 0 {
+		params["total"] = float64(n.stageCount)
+	}
+	if err := n.srv.SendNotificationToClient(n.ctx, string(mcp.MethodNotificationProgress), params); err != nil {
+		log.Debug().Err(err).Msg("Failed to send MCP progress notification")
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package mcpserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azqr/internal/progress"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// testSession is an initialized client session that buffers notifications.
+type testSession struct {
+	notifications chan mcp.JSONRPCNotification
+}
+
+func (s *testSession) Initialize()       {}
+func (s *testSession) Initialized() bool { return true }
+func (s *testSession) SessionID() string { return "test" }
+func (s *testSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifications
+}
+
+func TestProgressNotifier_StageStartedAfterFinished(t *testing.T) {
+	srv := server.NewMCPServer("Test Server", "0.1.0")
+	session := &testSession{notifications: make(chan mcp.JSONRPCNotification, 10)}
+	n := &progressNotifier{
+		ctx:   srv.WithContext(context.Background(), session),
+		srv:   srv,
+		token: "token",
+	}
+
+	n.OnEvent(progress.Event{Type: progress.EventStageStarted, Stage: "Initialization", StageIndex: 1, StageCount: 2})
+	n.OnEvent(progress.Event{Type: progress.EventStageFinished, Stage: "Initialization", StageIndex: 1, StageCount: 2})
+	n.OnEvent(progress.Event{Type: progress.EventStageStarted, Stage: "Graph Scan", StageIndex: 2, StageCount: 2})
+	n.OnEvent(progress.Event{Type: progress.EventTaskProgress, Done: 0, Total: 4, Task: "rules"})
+	n.OnEvent(progress.Event{Type: progress.EventTaskProgress, Done: 2, Total: 4, Task: "rules"})
+	close(session.notifications)
+
+	want := []string{"Initialization", "Initialization completed", "Graph Scan", "Graph Scan: 2/4 rules"}
+	var got []string
+	last := -1.0
+	for notification := range session.notifications {
+		fields := notification.Params.AdditionalFields
+		current, _ := fields["progress"].(float64)
+		if current <= last {
+			t.Errorf("progress %v does not increase from %v", current, last)
+		}
+		last = current
+		if total, _ := fields["total"].(float64); total != 2 {
+			t.Errorf("total = %v, want 2", fields["total"])
+		}
+		message, _ := fields["message"].(string)
+		got = append(got, message)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("messages = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("message %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
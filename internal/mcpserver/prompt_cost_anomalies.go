@@ -0,0 +1,35 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package mcpserver
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func handleCostAnomaliesPrompt() func(context.Context, mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	return func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		prompt := `Analyze cost anomalies and trends across my Azure subscriptions.
+
+Please:
+1. Use the scan-cost-anomalies tool to retrieve cost anomaly data
+2. Analyze the results focusing on:
+   - The largest cost spikes and the resources contributing to them
+   - New costs that may come from unplanned or forgotten deployments
+   - Services and resource groups with sustained cost growth
+3. Provide actionable recommendations for:
+   - Investigating and containing unexpected spend
+   - Setting budgets and cost alerts for the affected scopes
+   - Right-sizing or removing the resources driving the growth
+`
+
+		promptMessage := mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(prompt))
+
+		return mcp.NewGetPromptResult(
+			"analyze cost anomalies",
+			[]mcp.PromptMessage{promptMessage},
+		), nil
+	}
+}
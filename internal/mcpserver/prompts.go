@@ -57,6 +57,14 @@ func RegisterPrompts(s *server.MCPServer) {
 
 	s.AddPrompt(serviceHealthPrompt, handleServiceHealthPrompt())
 
+	// Cost Anomalies Plugin Prompt
+	costAnomaliesPrompt := mcp.NewPrompt(
+		"analyze_cost_anomalies",
+		mcp.WithPromptDescription("Analyze cost spikes, new costs and sustained cost growth by service and resource group"),
+	)
+
+	s.AddPrompt(costAnomaliesPrompt, handleCostAnomaliesPrompt())
+
 	// Region Selection Plugin Prompt
 	regionSelectionPrompt := mcp.NewPrompt(
 		"analyze_region_selection",
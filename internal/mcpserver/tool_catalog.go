@@ -12,7 +12,10 @@ import (
 )
 
 func handleCatalogTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	output := renderers.GetAllRecommendations(true)
+	output, err := renderers.GetAllRecommendations(true)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 
 	jsonBytes, _ := json.MarshalIndent(output, "", "  ")
 
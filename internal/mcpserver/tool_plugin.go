@@ -24,16 +24,17 @@ func scanPluginHandler(pluginName string) func(context.Context, mcp.CallToolRequ
 			log.Fatal().Err(err).Msg("failed to get current working directory")
 		}
 
-		params := models.NewScanParamsForPlugins(args)
+		params, err := models.NewScanParamsForPlugins(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
 		params.Xlsx = true
 		params.Json = true
 		params.OutputName = fmt.Sprintf("%s/azqr_%s_results", currentDir, pluginName)
 
-		// Apply plugin stage options if provided (e.g. target-regions for region-selection)
-		if len(args.Options) > 0 {
-			if err := params.Stages.SetStageOptions(models.StageNamePlugin, args.Options); err != nil {
-				log.Fatal().Err(err).Msg("failed applying plugin stage options")
-			}
+		// Apply the options declared by the plugin (e.g. target-regions for region-selection)
+		if err := params.Stages.SetPluginOptions(pluginName, args.Options); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
 		// Enable the specific plugin for execution
@@ -41,7 +42,7 @@ func scanPluginHandler(pluginName string) func(context.Context, mcp.CallToolRequ
 			pluginName: true,
 		}
 
-		scanner := pipeline.Scanner{}
+		scanner := pipeline.Scanner{Observer: newProgressObserver(ctx, request)}
 		r := scanner.ScanPlugins(params)
 
 		fileName := params.OutputName + ".xlsx"
@@ -105,3 +106,37 @@ func scanPluginHandler(pluginName string) func(context.Context, mcp.CallToolRequ
 		return result, nil
 	}
 }
+
+// withPluginOptions adds the "options" object to a plugin tool, with one
+// property per option declared by the plugin.
+func withPluginOptions(pluginName string, opts ...mcp.PropertyOption) mcp.ToolOption {
+	props := make(map[string]any)
+	for key, spec := range models.PluginOptionSpecs(pluginName) {
+		props[key] = map[string]any{
+			"type":        jsonSchemaType(spec.Type),
+			"description": spec.Description,
+			"default":     spec.Default,
+		}
+	}
+
+	opts = append([]mcp.PropertyOption{
+		mcp.Description("Plugin options."),
+		mcp.Properties(props),
+		mcp.AdditionalProperties(false),
+	}, opts...)
+	return mcp.WithObject("options", opts...)
+}
+
+// jsonSchemaType maps an option type to its JSON schema type.
+func jsonSchemaType(optionType string) string {
+	switch optionType {
+	case "bool":
+		return "boolean"
+	case "int":
+		return "integer"
+	case "float64":
+		return "number"
+	default:
+		return "string"
+	}
+}
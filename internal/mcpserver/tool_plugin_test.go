@@ -0,0 +1,42 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package mcpserver
+
+import (
+	"testing"
+
+	"github.com/Azure/azqr/internal/models"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestWithPluginOptions(t *testing.T) {
+	err := models.RegisterPluginOptions("mcp-options", map[string]models.OptionSpec{
+		"days":   {Type: "int", Default: 7, Description: "Days"},
+		"strict": {Type: "bool"},
+	})
+	if err != nil {
+		t.Fatalf("RegisterPluginOptions() error = %v", err)
+	}
+
+	tool := mcp.NewTool("scan-mcp-options", withPluginOptions("mcp-options", mcp.Required()))
+
+	options, ok := tool.InputSchema.Properties["options"].(map[string]any)
+	if !ok {
+		t.Fatal("options property missing from the input schema")
+	}
+	props := options["properties"].(map[string]any)
+	days := props["days"].(map[string]any)
+	if days["type"] != "integer" || days["default"] != 7 || days["description"] != "Days" {
+		t.Errorf("unexpected days schema: %v", days)
+	}
+	if props["strict"].(map[string]any)["type"] != "boolean" {
+		t.Errorf("unexpected strict schema: %v", props["strict"])
+	}
+	if options["additionalProperties"] != false {
+		t.Error("unknown options should be rejected by the schema")
+	}
+	if len(tool.InputSchema.Required) != 1 || tool.InputSchema.Required[0] != "options" {
+		t.Errorf("options should be required, got %v", tool.InputSchema.Required)
+	}
+}
@@ -22,12 +22,15 @@ func scanHandler(ctx context.Context, request mcp.CallToolRequest, args models.S
 		log.Fatal().Err(err).Msg("failed to get current working directory")
 	}
 
-	params := models.NewScanParamsWithDefaults(args)
+	params, err := models.NewScanParamsWithDefaults(args)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 	params.Xlsx = true
 	params.Json = true
 	params.OutputName = currentDir + "/azqr_scan_results"
 
-	scanner := pipeline.Scanner{}
+	scanner := pipeline.Scanner{Observer: newProgressObserver(ctx, request)}
 	r := scanner.Scan(params)
 
 	fileName := params.OutputName + ".xlsx"
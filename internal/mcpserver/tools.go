@@ -4,6 +4,7 @@
 package mcpserver
 
 import (
+	"github.com/Azure/azqr/internal/plugins"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
@@ -23,6 +24,7 @@ func RegisterTools(s *server.MCPServer) {
 				- Emission units
 
 				Results are saved to Excel/JSON files and returned with resource URIs for download.`),
+			withPluginOptions("carbon-emissions"),
 		)...,
 	)
 	s.AddTool(carbonEmissionsTool, mcp.NewTypedToolHandler(scanPluginHandler("carbon-emissions")))
@@ -42,6 +44,7 @@ func RegisterTools(s *server.MCPServer) {
 
 				Useful for identifying capacity planning issues and optimizing API usage patterns.
 				Results are saved to Excel/JSON files and returned with resource URIs for download.`),
+			withPluginOptions("ai-gov"),
 		)...,
 	)
 	s.AddTool(aiGovTool, mcp.NewTypedToolHandler(scanPluginHandler("ai-gov")))
@@ -102,6 +105,25 @@ func RegisterTools(s *server.MCPServer) {
 	)
 	s.AddTool(serviceHealthTool, mcp.NewTypedToolHandler(scanPluginHandler("service-health")))
 
+	// Plugin Tools: Cost Anomalies
+	costAnomaliesTool := mcp.NewTool("scan-cost-anomalies",
+		withBasicOptions(
+			mcp.WithDescription(
+				`Detect cost anomalies and trends by service and resource group.
+
+				This tool analyzes several months of daily cost across your Azure subscriptions and provides:
+				- Period-over-period cost spikes above a configurable percentage
+				- New costs in services and resource groups without previous cost
+				- Sustained cost growth over consecutive periods
+				- Current and previous period cost, change ratio and value
+				- The resources contributing most to each increase
+
+				Results are saved to Excel/JSON files and returned with resource URIs for download.`),
+			withPluginOptions("cost-anomalies"),
+		)...,
+	)
+	s.AddTool(costAnomaliesTool, mcp.NewTypedToolHandler(scanPluginHandler("cost-anomalies")))
+
 	// Plugin Tools: Region Selection
 	regionSelectionTool := mcp.NewTool("scan-region-selection",
 		withBasicOptions(
@@ -118,7 +140,7 @@ func RegisterTools(s *server.MCPServer) {
 
 				Useful for multi-region planning, disaster recovery site selection, and workload migration.
 				Results are saved to Excel/JSON files and returned with resource URIs for download.`),
-			mcp.WithObject("options",
+			withPluginOptions("region-selection",
 				mcp.Required(),
 				mcp.Description("Plugin options. Use 'target-regions' (comma-separated string) to specify which Azure regions to analyze (e.g., {\"target-regions\": \"eastus,westeurope\"})."),
 			),
@@ -126,6 +148,22 @@ func RegisterTools(s *server.MCPServer) {
 	)
 	s.AddTool(regionSelectionTool, mcp.NewTypedToolHandler(scanPluginHandler("region-selection")))
 
+	// Plugin Tools: YAML table and external plugins discovered at startup
+	for _, plugin := range plugins.GetRegistry().List() {
+		if plugin.InternalScanner == nil || plugin.Metadata.Type == plugins.PluginTypeInternal {
+			continue
+		}
+		pluginTool := mcp.NewTool("scan-"+plugin.Metadata.Name,
+			withBasicOptions(
+				mcp.WithDescription(plugin.Metadata.Description+`
+
+				Results are saved to Excel/JSON files and returned with resource URIs for download.`),
+				withPluginOptions(plugin.Metadata.Name),
+			)...,
+		)
+		s.AddTool(pluginTool, mcp.NewTypedToolHandler(scanPluginHandler(plugin.Metadata.Name)))
+	}
+
 	scan := mcp.NewTool("scan",
 		withBasicOptions(
 			mcp.WithDescription(
@@ -152,7 +190,7 @@ func RegisterTools(s *server.MCPServer) {
 				Use get-supported-services tool to see all available service abbreviations.`),
 			mcp.WithArray("stages",
 				mcp.Items(map[string]any{"type": "string"}),
-				mcp.Description("Optional array of scan stages to execute. Available stages: diagnostics, advisor, defender (enabled by default), cost, arc, policy, defender-recommendations (disabled by default). To disable a stage, prefix it with '-' (e.g., ['-diagnostics', '-defender']). Leave empty or omit to run default stages."),
+				mcp.Description("Optional array of scan stages to execute. Available stages: diagnostics, advisor, defender (enabled by default), cost, quota, arc, policy, defender-recommendations (disabled by default). To disable a stage, prefix it with '-' (e.g., ['-diagnostics', '-defender']). Leave empty or omit to run default stages."),
 			),
 			mcp.WithArray("stageParams",
 				mcp.Items(map[string]any{"type": "string"}),
@@ -0,0 +1,149 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ColumnType is the data type of a report column. It controls how the value
+// is written by the renderers: typed cells in Excel, typed values in JSON and
+// locale-neutral values in CSV.
+type ColumnType string
+
+const (
+	// ColumnTypeText is a plain text column (the default)
+	ColumnTypeText ColumnType = ""
+	// ColumnTypeNumber is a numeric column
+	ColumnTypeNumber ColumnType = "number"
+	// ColumnTypePercent is a percentage column. Values are percentage points
+	// (e.g. "12.5" or "12.5%" for 12.5%).
+	ColumnTypePercent ColumnType = "percent"
+	// ColumnTypeCurrency is a monetary column. Unit holds the currency code.
+	ColumnTypeCurrency ColumnType = "currency"
+	// ColumnTypeDate is a date or date-time column
+	ColumnTypeDate ColumnType = "date"
+	// ColumnTypeURL is a link column
+	ColumnTypeURL ColumnType = "url"
+	// ColumnTypeEnum is a text column with a small set of distinct values
+	ColumnTypeEnum ColumnType = "enum"
+)
+
+// columnDateLayouts are the date formats accepted for date columns
+var columnDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006-01",
+}
+
+// ValidateColumnType returns an error if t is not a known column type.
+func ValidateColumnType(t ColumnType) error {
+	switch t {
+	case ColumnTypeText, ColumnTypeNumber, ColumnTypePercent, ColumnTypeCurrency,
+		ColumnTypeDate, ColumnTypeURL, ColumnTypeEnum:
+		return nil
+	}
+	return fmt.Errorf("unsupported column type %q (supported: number, percent, currency, date, url, enum)", t)
+}
+
+// ColumnFormat describes the type and unit of a report column.
+type ColumnFormat struct {
+	Name string     `json:"name"`
+	Type ColumnType `json:"type,omitempty"`
+	Unit string     `json:"unit,omitempty"`
+}
+
+// IsNumeric reports whether the column holds numeric values.
+func (c ColumnFormat) IsNumeric() bool {
+	return c.Type == ColumnTypeNumber || c.Type == ColumnTypePercent || c.Type == ColumnTypeCurrency
+}
+
+// ParseNumber parses a numeric cell value. Thousands separators, currency
+// symbols, a trailing "%" and the column unit are ignored. Percent values are
+// returned in percentage points.
+func (c ColumnFormat) ParseNumber(value string) (float64, bool) {
+	s := strings.TrimSpace(value)
+	if c.Unit != "" {
+		s = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(s, c.Unit), c.Unit))
+	}
+	s = strings.TrimSuffix(s, "%")
+	s = strings.Map(func(r rune) rune {
+		switch r {
+		case ',', ' ', '$', '€', '£', '¥':
+			return -1
+		}
+		return r
+	}, s)
+	if s == "" {
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
+
+// ParseDate parses a date cell value.
+func (c ColumnFormat) ParseDate(value string) (time.Time, bool) {
+	s := strings.TrimSpace(value)
+	if s == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range columnDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Value returns the typed value of a cell: a float64 for numeric columns, a
+// time.Time for date columns and the string itself otherwise. Values that
+// cannot be parsed (e.g. "N/A") are returned unchanged with ok set to false.
+func (c ColumnFormat) Value(value string) (v any, ok bool) {
+	switch {
+	case c.IsNumeric():
+		if n, ok := c.ParseNumber(value); ok {
+			return n, true
+		}
+	case c.Type == ColumnTypeDate:
+		if t, ok := c.ParseDate(value); ok {
+			return t, true
+		}
+	}
+	return value, false
+}
+
+// Normalize returns the locale-neutral form of a cell value: numbers without
+// thousands separators, symbols or units, and ISO 8601 dates. Values that
+// cannot be parsed are returned unchanged.
+func (c ColumnFormat) Normalize(value string) string {
+	v, ok := c.Value(value)
+	if !ok {
+		return value
+	}
+	switch t := v.(type) {
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case time.Time:
+		return FormatColumnDate(t)
+	}
+	return value
+}
+
+// FormatColumnDate formats a date as ISO 8601, omitting the time of day when
+// it is midnight UTC.
+func FormatColumnDate(t time.Time) string {
+	if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Location() == time.UTC {
+		return t.Format("2006-01-02")
+	}
+	return t.Format(time.RFC3339)
+}
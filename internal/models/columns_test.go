@@ -0,0 +1,68 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestColumnFormat_Normalize(t *testing.T) {
+	tests := []struct {
+		name  string
+		col   ColumnFormat
+		value string
+		want  string
+	}{
+		{"text unchanged", ColumnFormat{}, "1,234.50", "1,234.50"},
+		{"number", ColumnFormat{Type: ColumnTypeNumber}, "1,234.50", "1234.5"},
+		{"number with unit", ColumnFormat{Type: ColumnTypeNumber, Unit: "ms"}, "12.0 ms", "12"},
+		{"percent with sign", ColumnFormat{Type: ColumnTypePercent}, "+12.50%", "12.5"},
+		{"negative percent", ColumnFormat{Type: ColumnTypePercent}, "-3.25%", "-3.25"},
+		{"currency symbol", ColumnFormat{Type: ColumnTypeCurrency, Unit: "USD"}, "$1,000.00", "1000"},
+		{"currency code", ColumnFormat{Type: ColumnTypeCurrency, Unit: "USD"}, "99.99 USD", "99.99"},
+		{"not a number", ColumnFormat{Type: ColumnTypeNumber}, "N/A", "N/A"},
+		{"empty", ColumnFormat{Type: ColumnTypeNumber}, "", ""},
+		{"date", ColumnFormat{Type: ColumnTypeDate}, "2024-07-09T00:00:00Z", "2024-07-09"},
+		{"date with time", ColumnFormat{Type: ColumnTypeDate}, "2024-07-09 15:00", "2024-07-09T15:00:00Z"},
+		{"month", ColumnFormat{Type: ColumnTypeDate}, "2024-07", "2024-07-01"},
+		{"invalid date", ColumnFormat{Type: ColumnTypeDate}, "soon", "soon"},
+		{"enum unchanged", ColumnFormat{Type: ColumnTypeEnum}, "Expired", "Expired"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.col.Normalize(tt.value); got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestColumnFormat_Value(t *testing.T) {
+	v, ok := ColumnFormat{Type: ColumnTypeNumber}.Value("42")
+	if !ok || v != 42.0 {
+		t.Errorf("Value(42) = %v, %v", v, ok)
+	}
+
+	v, ok = ColumnFormat{Type: ColumnTypeDate}.Value("2024-07-09")
+	if !ok || !v.(time.Time).Equal(time.Date(2024, 7, 9, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Value(2024-07-09) = %v, %v", v, ok)
+	}
+
+	v, ok = ColumnFormat{Type: ColumnTypeURL}.Value("https://example.com")
+	if ok || v != "https://example.com" {
+		t.Errorf("url values are not converted, got %v, %v", v, ok)
+	}
+}
+
+func TestValidateColumnType(t *testing.T) {
+	for _, ct := range []ColumnType{ColumnTypeText, ColumnTypeNumber, ColumnTypePercent, ColumnTypeCurrency, ColumnTypeDate, ColumnTypeURL, ColumnTypeEnum} {
+		if err := ValidateColumnType(ct); err != nil {
+			t.Errorf("ValidateColumnType(%q) = %v", ct, err)
+		}
+	}
+	if err := ValidateColumnType("money"); err == nil {
+		t.Error("expected an error for an unknown column type")
+	}
+}
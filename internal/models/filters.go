@@ -160,19 +160,20 @@ func NewFilters() *Filters {
 	return filters
 }
 
-func LoadFilters(filterFile string, scannerKeys []string) *Filters {
+// ParseFilters loads the filters file (if any) and selects the scanners for the given keys.
+func ParseFilters(filterFile string, scannerKeys []string) (*Filters, error) {
 	filters := NewFilters()
 
 	if filterFile != "" {
 		cleanPath := filepath.Clean(filterFile)
 		data, err := os.ReadFile(cleanPath) //nolint:gosec // filterFile comes from CLI flag
 		if err != nil {
-			log.Fatal().Err(err).Msgf("failed reading data from file: %s", filterFile)
+			return nil, fmt.Errorf("failed reading data from file %s: %w", filterFile, err)
 		}
 
 		err = yaml.Unmarshal([]byte(data), &filters)
 		if err != nil {
-			log.Fatal().Err(err).Msgf("failed parsing yaml from file: %s", filterFile)
+			return nil, fmt.Errorf("failed parsing yaml from file %s: %w", filterFile, err)
 		}
 	}
 
@@ -185,14 +186,14 @@ func LoadFilters(filterFile string, scannerKeys []string) *Filters {
 	// Validate resource group IDs in include list
 	for _, id := range filters.Azqr.Include.ResourceGroups {
 		if err := validateResourceGroupID(id); err != nil {
-			log.Fatal().Err(err).Msgf("invalid resource group ID in include list")
+			return nil, fmt.Errorf("invalid resource group ID in include list: %w", err)
 		}
 	}
 
 	// Validate resource group IDs in exclude list
 	for _, id := range filters.Azqr.Exclude.ResourceGroups {
 		if err := validateResourceGroupID(id); err != nil {
-			log.Fatal().Err(err).Msgf("invalid resource group ID in exclude list")
+			return nil, fmt.Errorf("invalid resource group ID in exclude list: %w", err)
 		}
 	}
 
@@ -265,7 +266,7 @@ func LoadFilters(filterFile string, scannerKeys []string) *Filters {
 		}
 	}
 
-	return filters
+	return filters, nil
 }
 
 func (e *AzqrFilter) isResourceGroupExcluded(resourceGroupID string) bool {
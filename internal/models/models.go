@@ -6,6 +6,7 @@ package models
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sort"
 	"time"
 
@@ -74,7 +75,10 @@ type (
 			Name string `yaml:"name"`
 			Url  string `yaml:"url"`
 		} `yaml:"learnMoreLink,flow"`
-		Source string
+		// EstimatedSavingsPct is the share (0-100) of the monthly cost of an
+		// impacted resource saved by fixing the finding; 0 for findings without savings
+		EstimatedSavingsPct float64 `yaml:"estimatedSavingsPct,omitempty"`
+		Source              string
 	}
 
 	GraphResult struct {
@@ -99,6 +103,11 @@ type (
 		Param5              string
 		AutomationAvailable string
 		Source              string
+		// MonthlyCost is the cost of the resource in the last calendar month
+		// and EstimatedSavings the part of it saved by fixing the finding.
+		// CostSource tells where the cost comes from and is empty when unknown.
+		MonthlyCost, EstimatedSavings float64
+		CostCurrency, CostSource      string
 	}
 
 	DefenderRecommendation struct {
@@ -121,10 +130,48 @@ type (
 		SubscriptionID, SubscriptionName, Name, Tier string
 	}
 
+	// SecureScoreResult - Defender for Cloud secure score of a subscription,
+	// or of the tenant when SubscriptionID is empty
+	SecureScoreResult struct {
+		SubscriptionID, SubscriptionName string
+		CurrentScore, MaxScore           float64
+		// Percentage is the current score in percent of the max score
+		Percentage float64
+		// Weight is the weight of the subscription in the tenant score
+		Weight   int64
+		Controls []*SecureScoreControl
+	}
+
+	// SecureScoreControl - Secure score of a security control
+	SecureScoreControl struct {
+		Name, DisplayName                                            string
+		CurrentScore, MaxScore, Percentage                           float64
+		HealthyResources, UnhealthyResources, NotApplicableResources int
+	}
+
 	// CostResult - Cost result,
 	CostResult struct {
 		SubscriptionID, SubscriptionName, ServiceName, Value, Currency string
 		From, To                                                       time.Time
+		// CostType is ActualCost or AmortizedCost
+		CostType string
+		// Date is the day (YYYY-MM-DD) or month (YYYY-MM) of the cost, empty without granularity
+		Date string
+		// ResourceGroup, ResourceID, MeterCategory and TagKey/TagValue are set when grouped by them
+		ResourceGroup, ResourceID, MeterCategory, TagKey, TagValue string
+	}
+
+	// ResourceCost - Cost of a resource in the last calendar month
+	ResourceCost struct {
+		SubscriptionID, ResourceID, Currency string
+		Cost                                 float64
+	}
+
+	// QuotaResult - Quota usage near or over its limit in a subscription and region
+	QuotaResult struct {
+		SubscriptionID, SubscriptionName, Location, QuotaType, Name, LocalizedName, Status string
+		Current, Limit, Available                                                          int
+		HeadroomPct                                                                        float64
 	}
 
 	// AdvisorResult - Advisor result
@@ -137,6 +184,26 @@ type (
 		SubscriptionID, SubscriptionName, PolicyDisplayName, PolicyDescription, ComplianceState, Type, Name, ResourceGroupName, ResourceID, TimeStamp, PolicyDefinitionName, PolicyDefinitionID, PolicyAssignmentName, PolicyAssignmentID string
 	}
 
+	// AzurePolicyComplianceResult - Resource compliance of a policy assignment in
+	// a subscription, or of all its assignments when PolicyAssignmentID is empty
+	AzurePolicyComplianceResult struct {
+		SubscriptionID, SubscriptionName         string
+		PolicyAssignmentID, PolicyAssignmentName string
+		// InitiativeID and InitiativeName are empty for single policy assignments
+		InitiativeID, InitiativeName              string
+		CompliantResources, NonCompliantResources int
+	}
+
+	// AzurePolicyExemptionResult - Azure Policy exemption
+	AzurePolicyExemptionResult struct {
+		SubscriptionID, SubscriptionName, Name, DisplayName, Category, Scope string
+		PolicyAssignmentID, PolicyAssignmentName, Description                string
+		// ExpiresOn is empty for exemptions without expiry
+		ExpiresOn string
+		// Status is Active, Expiring Soon or Expired
+		Status string
+	}
+
 	// ArcSQLResult - Arc-enabled SQL Server result
 	ArcSQLResult struct {
 		SubscriptionID   string
@@ -156,6 +223,26 @@ type (
 		DefenderStatus   string
 	}
 
+	// ArcServerResult - Azure Arc-enabled server
+	ArcServerResult struct {
+		SubscriptionID, SubscriptionName, ResourceGroup, Name, ResourceID, Location string
+		// Status is Connected, Disconnected or Expired; LastStatusChange is
+		// RFC 3339 and DisconnectedDays is 0 for connected servers
+		Status, LastStatusChange string
+		DisconnectedDays         int
+		// AgentStatus is Latest, Current or Outdated compared to the newest
+		// agent version found in the scan
+		AgentVersion, AgentStatus string
+		OSType, OSName, OSVersion string
+		// OSEndOfLife is the end of support date (YYYY-MM-DD) of the operating
+		// system, empty when unknown
+		OSEndOfLife, OSStatus string
+		// Extensions holds the extension types installed on the server
+		Extensions                                          []string
+		MonitoringAgent, DefenderForEndpoint, UpdateManager bool
+		ESUEligibility, ESULicenseStatus                    string
+	}
+
 	RecommendationEngine struct{}
 
 	RecommendationImpact   string
@@ -187,6 +274,21 @@ func LogSubscriptionScan(subscriptionID string, source string) {
 		Msg("Scanning")
 }
 
+// MaskSubscriptionID returns the subscription ID with all but its last 7
+// characters masked when mask is set, or "" when it is not a subscription ID.
+func MaskSubscriptionID(subscriptionID string, mask bool) string {
+	if len(subscriptionID) < 36 {
+		return ""
+	}
+
+	if !mask {
+		return subscriptionID
+	}
+
+	// Show only last 7 chars of the subscription ID
+	return "xxxxxxxx-xxxx-xxxx-xxxx-xxxxx" + subscriptionID[29:]
+}
+
 func LogResourceTypeScan(source string) {
 	log.Info().
 		Str("for", source).
@@ -295,6 +397,16 @@ func GetResourceNameFromResourceID(resourceID string) string {
 // ScannerList is a map of service abbreviation to scanner
 var ScannerList = map[string][]IAzureScanner{}
 
+// RegisterScanners adds scanners to ScannerList under a service abbreviation.
+// It returns an error if the abbreviation is already registered.
+func RegisterScanners(key string, scanners ...IAzureScanner) error {
+	if _, exists := ScannerList[key]; exists {
+		return fmt.Errorf("scanner %s is already registered", key)
+	}
+	ScannerList[key] = scanners
+	return nil
+}
+
 // GetScanners returns a list of all scanners in ScannerList
 func GetScanners() ([]string, []IAzureScanner) {
 	var scanners []IAzureScanner
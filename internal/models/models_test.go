@@ -202,3 +202,17 @@ func TestRecommendationConstants(t *testing.T) {
 		}
 	}
 }
+
+func TestRegisterScanners(t *testing.T) {
+	t.Cleanup(func() { delete(ScannerList, "zzztest") })
+
+	if err := RegisterScanners("zzztest", NewBaseScanner("Test", "Microsoft.Test/things")); err != nil {
+		t.Fatalf("RegisterScanners() error = %v", err)
+	}
+	if got := ScannerList["zzztest"]; len(got) != 1 || got[0].ServiceName() != "Test" {
+		t.Errorf("ScannerList[zzztest] = %v", got)
+	}
+	if err := RegisterScanners("zzztest", NewBaseScanner("Other", "Microsoft.Test/other")); err == nil {
+		t.Error("RegisterScanners() expected error for a duplicate abbreviation")
+	}
+}
@@ -1,6 +1,16 @@
 package models
 
-import "github.com/rs/zerolog/log"
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultPluginConcurrency is the number of plugins run at the same time
+	DefaultPluginConcurrency = 4
+	// DefaultPluginTimeout is the time each plugin may run
+	DefaultPluginTimeout = 30 * time.Minute
+)
 
 type (
 	ScanParams struct {
@@ -18,6 +28,14 @@ type (
 		ScannerKeys            []string
 		Filters                *Filters
 		EnabledInternalPlugins map[string]bool
+		// PluginConcurrency limits how many plugins run at the same time (default DefaultPluginConcurrency)
+		PluginConcurrency int
+		// PluginTimeout is the deadline of each plugin (default DefaultPluginTimeout)
+		PluginTimeout time.Duration
+		// Checkpoint persists scan progress after each stage so it can be resumed
+		Checkpoint bool
+		// ResumeFrom is the path of a checkpoint file to resume from
+		ResumeFrom string
 		// Profiling options (only effective when built with 'debug' tag)
 		CPUProfile   string
 		MemProfile   string
@@ -41,18 +59,23 @@ type (
 	}
 )
 
-func NewScanParamsWithDefaults(args ScanArgs) *ScanParams {
+// NewScanParamsWithDefaults returns the scan parameters of a full scan. It
+// returns an error on an unknown stage or invalid stage parameter.
+func NewScanParamsWithDefaults(args ScanArgs) (*ScanParams, error) {
 	stages := NewStageConfigsWithDefaults()
-	if len(args.Stages) > 0 {
-		stages.ConfigureStages(args.Stages)
+	if err := stages.SelectStages(args.Stages); err != nil {
+		return nil, err
 	}
 
 	if err := stages.ApplyStageParams(args.StageParams); err != nil {
-		log.Fatal().Err(err).Msg("failed applying stage parameters")
+		return nil, fmt.Errorf("failed applying stage parameters: %w", err)
 	}
 
 	scannerKeys := args.Services
-	filters := LoadFilters("", scannerKeys)
+	filters, err := ParseFilters("", scannerKeys)
+	if err != nil {
+		return nil, err
+	}
 
 	mask := true
 	if args.Mask != nil {
@@ -71,12 +94,16 @@ func NewScanParamsWithDefaults(args ScanArgs) *ScanParams {
 		Debug:            false,
 		ScannerKeys:      args.Services,
 		Filters:          filters,
-	}
+	}, nil
 }
 
-func NewScanParamsForPlugins(args PluginScanArgs) *ScanParams {
+// NewScanParamsForPlugins returns the scan parameters of a plugin-only scan.
+func NewScanParamsForPlugins(args PluginScanArgs) (*ScanParams, error) {
 	stages := NewStageConfigs()
-	filters := LoadFilters("", []string{})
+	filters, err := ParseFilters("", []string{})
+	if err != nil {
+		return nil, err
+	}
 	mask := true
 	if args.Mask != nil {
 		mask = *args.Mask
@@ -94,5 +121,5 @@ func NewScanParamsForPlugins(args PluginScanArgs) *ScanParams {
 		Debug:            false,
 		ScannerKeys:      []string{},
 		Filters:          filters,
-	}
+	}, nil
 }
@@ -3,8 +3,6 @@ package models
 import (
 	"fmt"
 	"strings"
-
-	"github.com/rs/zerolog/log"
 )
 
 // Stage name constants
@@ -16,6 +14,7 @@ const (
 	StageNameArc                     = "arc"
 	StageNamePolicy                  = "policy"
 	StageNameCost                    = "cost"
+	StageNameQuota                   = "quota"
 	StageNameDiagnostics             = "diagnostics"
 	StageNamePlugin                  = "plugin"
 )
@@ -31,6 +30,7 @@ var allStages = map[string]bool{
 	StageNameArc:                     false, // Disabled by default
 	StageNamePolicy:                  false, // Disabled by default
 	StageNameCost:                    false, // Disabled by default
+	StageNameQuota:                   false, // Disabled by default
 	StageNamePlugin:                  false, // Disabled by default
 }
 
@@ -116,35 +116,42 @@ func (sc *StageConfigs) ValidateGraphStageEnabled() error {
 	return nil
 }
 
-func (sc *StageConfigs) ConfigureStages(stageNames []string) {
-	// If stages are explicitly specified, determine the model to use
-	if len(stageNames) > 0 {
-		// Parse and enable/disable each specified stage
-		for _, stageName := range stageNames {
-			// Handle comma-separated values within a single flag value
-			stages := strings.Split(stageName, ",")
-			for _, s := range stages {
-				s = strings.TrimSpace(strings.ToLower(s))
-				if s == "" {
-					continue
-				}
+// SelectStages enables or disables the given stages ("name" or "-name",
+// optionally comma-separated).
+func (sc *StageConfigs) SelectStages(stageNames []string) error {
+	for _, stageName := range stageNames {
+		// Handle comma-separated values within a single flag value
+		stages := strings.Split(stageName, ",")
+		for _, s := range stages {
+			s = strings.TrimSpace(strings.ToLower(s))
+			if s == "" {
+				continue
+			}
 
-				// Check if stage should be disabled (prefix with '-')
-				if strings.HasPrefix(s, "-") {
-					// Remove the '-' prefix and disable the stage
-					stageName := strings.TrimPrefix(s, "-")
-					if err := sc.DisableStage(stageName); err != nil {
-						log.Fatal().Err(err).Msgf("Invalid stage name: %s", stageName)
-					}
-				} else {
-					// Enable the stage
-					if err := sc.EnableStage(s); err != nil {
-						log.Fatal().Err(err).Msgf("Invalid stage name: %s", s)
-					}
+			// Check if stage should be disabled (prefix with '-')
+			if strings.HasPrefix(s, "-") {
+				// Remove the '-' prefix and disable the stage
+				if err := sc.DisableStage(strings.TrimPrefix(s, "-")); err != nil {
+					return err
+				}
+			} else {
+				// Enable the stage
+				if err := sc.EnableStage(s); err != nil {
+					return err
 				}
 			}
 		}
 	}
+	return nil
+}
+
+// DefaultStages returns every stage name and whether it is enabled by default.
+func DefaultStages() map[string]bool {
+	stages := make(map[string]bool, len(allStages))
+	for name, enabled := range allStages {
+		stages[name] = enabled
+	}
+	return stages
 }
 
 // isValidStageName checks if a stage name is valid
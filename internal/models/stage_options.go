@@ -5,8 +5,12 @@ package models
 
 import (
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
+	"sync"
+
+	"github.com/rs/zerolog/log"
 )
 
 // OptionSpec defines the schema for a stage option
@@ -18,15 +22,103 @@ type OptionSpec struct {
 
 // StageOptionRegistry defines allowed options for each stage
 var stageOptionRegistry = map[string]map[string]OptionSpec{
-	StageNamePlugin: {
-		"target-regions": {
-			Type:        "string",
-			Default:     "",
-			Description: "Comma-separated list of target regions for the scan",
-		},
+	StageNameArc: {
+		"disconnected-days":     {Type: "int", Default: 7, Description: "Days after which a disconnected Arc-enabled server is reported"},
+		"min-agent-version":     {Type: "string", Description: "Oldest supported Connected Machine agent version, older agents are reported as outdated; defaults to the oldest agent version still supported"},
+		"agent-versions-behind": {Type: "int", Default: 6, Description: "Minor versions behind the newest Connected Machine agent found from which an agent is also reported as outdated"},
+	},
+	StageNameCost: {
+		"period":         {Type: "string", Default: "last-month", Description: "Relative period: last-month, month-to-date, last-<n>-days or last-<n>-months; ignored when from and to are set"},
+		"from":           {Type: "string", Description: "Start date (YYYY-MM-DD) of a custom period, set with to"},
+		"to":             {Type: "string", Description: "End date (YYYY-MM-DD, inclusive) of a custom period, set with from"},
+		"granularity":    {Type: "string", Default: "none", Description: "Cost rows per day (daily), per month (monthly) or for the whole period (none)"},
+		"group-by":       {Type: "string", Default: "service", Description: "Comma-separated groupings (at most 2): service, resource-group, resource, meter-category or tag:<key>"},
+		"type":           {Type: "string", Default: "ActualCost", Description: "Cost type: ActualCost or AmortizedCost"},
+		"scope":          {Type: "string", Default: "subscription", Description: "Query scope: subscription (one query per subscription), billing-account, billing-profile, enrollment or management-group (one query for all subscriptions)"},
+		"scope-id":       {Type: "string", Description: "ID of the billing account, enrollment or management group, or <billing account id>/<billing profile id>"},
+		"resource-costs": {Type: "bool", Default: false, Description: "Query last month's cost per resource (one more Cost Management query per subscription) to add cost and savings estimates to the findings and inventory"},
+	},
+	StageNameDiagnostics: {
+		"approved-workspaces": {Type: "string", Description: "Comma-separated resource IDs of the Log Analytics workspaces diagnostic settings must send to"},
+	},
+	StageNamePolicy: {
+		"expiry-days":  {Type: "int", Default: 30, Description: "Days before expiry from which a policy exemption is reported as expiring soon"},
+		"top-policies": {Type: "int", Default: 20, Description: "Number of policies with the most non-compliant resources to report"},
+	},
+	StageNameQuota: {
+		"threshold": {Type: "float64", Default: 15.0, Description: "Headroom percentage below which a quota is reported as near its limit"},
 	},
 }
 
+// pluginOptionRegistry defines the options declared by each plugin. Plugin
+// options are set as plugin.<plugin-name>.<key>=value and stored in the plugin
+// stage options under "<plugin-name>.<key>".
+var (
+	pluginOptionMu       sync.RWMutex
+	pluginOptionRegistry = map[string]map[string]OptionSpec{}
+)
+
+// RegisterPluginOptions declares the options accepted by a plugin, replacing
+// any options previously declared for it. Defaults are converted to the
+// option type.
+func RegisterPluginOptions(plugin string, specs map[string]OptionSpec) error {
+	validated := make(map[string]OptionSpec, len(specs))
+	for key, spec := range specs {
+		if key == "" || strings.ContainsAny(key, ".= ") {
+			return fmt.Errorf("plugin %s: invalid option name %q", plugin, key)
+		}
+		if spec.Default == nil {
+			spec.Default = zeroValue(spec.Type)
+		}
+		def, err := convertValue(spec.Default, spec.Type)
+		if err != nil {
+			return fmt.Errorf("plugin %s: invalid default for option %s: %w", plugin, key, err)
+		}
+		spec.Default = def
+		validated[key] = spec
+	}
+
+	pluginOptionMu.Lock()
+	defer pluginOptionMu.Unlock()
+	pluginOptionRegistry[plugin] = validated
+	return nil
+}
+
+// PluginOptionSpecs returns the options declared by a plugin.
+func PluginOptionSpecs(plugin string) map[string]OptionSpec {
+	pluginOptionMu.RLock()
+	defer pluginOptionMu.RUnlock()
+
+	specs := make(map[string]OptionSpec, len(pluginOptionRegistry[plugin]))
+	for key, spec := range pluginOptionRegistry[plugin] {
+		specs[key] = spec
+	}
+	return specs
+}
+
+// pluginOptionSpec returns the spec of a plugin option.
+func pluginOptionSpec(plugin, key string) (OptionSpec, error) {
+	pluginOptionMu.RLock()
+	defer pluginOptionMu.RUnlock()
+
+	specs, exists := pluginOptionRegistry[plugin]
+	if !exists {
+		return OptionSpec{}, fmt.Errorf("unknown plugin or plugin without options: %s", plugin)
+	}
+	spec, exists := specs[key]
+	if !exists {
+		return OptionSpec{}, fmt.Errorf("unknown option %q for plugin %q", key, plugin)
+	}
+	return spec, nil
+}
+
+// deprecatedPluginParams maps the global plugin.<key> parameters that predate
+// per-plugin options to the plugin option replacing them.
+var deprecatedPluginParams = map[string]string{
+	"target-regions":      "region-selection.target-regions",
+	"cost-history-months": "region-selection.cost-history-months",
+}
+
 // ParseAndValidateStageParams parses and validates stage parameters against the registry.
 // Returns a map of stage -> options with typed values according to the schema.
 // Errors on unknown stage, unknown key for a stage, or type mismatch.
@@ -49,6 +141,31 @@ func ParseAndValidateStageParams(params []string) (map[string]map[string]any, er
 			return nil, fmt.Errorf("stage param must be in the form stage.key=value: %s", param)
 		}
 
+		// Plugin options are scoped by plugin name: plugin.<name>.<key>
+		if stage == StageNamePlugin {
+			if replacement, deprecated := deprecatedPluginParams[key]; deprecated {
+				log.Warn().Msgf("Stage parameter plugin.%s is deprecated, use plugin.%s", key, replacement)
+				key = replacement
+			}
+			plugin, pluginKey, ok := strings.Cut(key, ".")
+			if !ok || plugin == "" || pluginKey == "" {
+				return nil, fmt.Errorf("plugin param must be in the form plugin.<name>.key=value: %s", param)
+			}
+			spec, err := pluginOptionSpec(plugin, pluginKey)
+			if err != nil {
+				return nil, err
+			}
+			parsed, err := parseValue(value, spec.Type)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for %s: %w", stageKey, err)
+			}
+			if _, exists := options[stage]; !exists {
+				options[stage] = make(map[string]any)
+			}
+			options[stage][key] = parsed
+			continue
+		}
+
 		// Validate stage exists in registry
 		stageSpecs, exists := stageOptionRegistry[stage]
 		if !exists {
@@ -114,6 +231,66 @@ func (sc *StageConfigs) SetStageOptions(stageName string, stageOptions map[strin
 	return nil
 }
 
+// SetPluginOptions validates options declared by a plugin and stores them in
+// the plugin stage options. Values may be typed or strings, as they arrive
+// from CLI flags, JSON or YAML.
+func (sc *StageConfigs) SetPluginOptions(plugin string, options map[string]any) error {
+	typed := make(map[string]any, len(options))
+	for key, value := range options {
+		spec, err := pluginOptionSpec(plugin, key)
+		if err != nil {
+			return err
+		}
+		converted, err := convertValue(value, spec.Type)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s.%s: %w", plugin, key, err)
+		}
+		typed[plugin+"."+key] = converted
+	}
+	if len(typed) == 0 {
+		return nil
+	}
+	return sc.SetStageOptions(StageNamePlugin, typed)
+}
+
+// GetPluginOptions returns the options of a plugin keyed by option name, with
+// the declared defaults for options that were not set. It is safe to call on
+// a nil StageConfigs.
+func (sc *StageConfigs) GetPluginOptions(plugin string) map[string]any {
+	options := make(map[string]any)
+	for key, spec := range PluginOptionSpecs(plugin) {
+		options[key] = spec.Default
+	}
+	if sc == nil {
+		return options
+	}
+
+	prefix := plugin + "."
+	for key, value := range sc.GetStageOptions(StageNamePlugin) {
+		if name, ok := strings.CutPrefix(key, prefix); ok {
+			options[name] = value
+		}
+	}
+	return options
+}
+
+// GetStageOptionsWithDefaults returns the options of a stage with the
+// registered defaults for options that were not set. It is safe to call on a
+// nil StageConfigs.
+func (sc *StageConfigs) GetStageOptionsWithDefaults(stageName string) map[string]any {
+	options := make(map[string]any, len(stageOptionRegistry[stageName]))
+	for key, spec := range stageOptionRegistry[stageName] {
+		options[key] = spec.Default
+	}
+	if sc == nil {
+		return options
+	}
+	for key, value := range sc.GetStageOptions(stageName) {
+		options[key] = value
+	}
+	return options
+}
+
 // GetStageOptions returns the raw options for a stage.
 func (sc *StageConfigs) GetStageOptions(stageName string) map[string]any {
 	cfg, exists := sc.stages[stageName]
@@ -156,3 +333,65 @@ func parseValue(value string, typeStr string) (any, error) {
 		return nil, fmt.Errorf("unsupported type: %s", typeStr)
 	}
 }
+
+// convertValue converts a value to the option type. Strings are parsed, and
+// whole float64 values (as decoded from JSON) are accepted for int options.
+func convertValue(value any, typeStr string) (any, error) {
+	if str, ok := value.(string); ok {
+		return parseValue(str, typeStr)
+	}
+
+	switch typeStr {
+	case "bool":
+		if v, ok := value.(bool); ok {
+			return v, nil
+		}
+	case "int":
+		switch v := value.(type) {
+		case int:
+			return v, nil
+		case int64:
+			return int(v), nil
+		case float64:
+			if v == math.Trunc(v) {
+				return int(v), nil
+			}
+		}
+	case "float64":
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case int:
+			return float64(v), nil
+		case int64:
+			return float64(v), nil
+		}
+	case "string":
+		// Lists are joined the way comma-separated flags are written
+		if list, ok := value.([]any); ok {
+			items := make([]string, len(list))
+			for i, item := range list {
+				items[i] = fmt.Sprint(item)
+			}
+			return strings.Join(items, ","), nil
+		}
+		return fmt.Sprint(value), nil
+	default:
+		return nil, fmt.Errorf("unsupported type: %s", typeStr)
+	}
+	return nil, fmt.Errorf("expected %s, got %v (%T)", typeStr, value, value)
+}
+
+// zeroValue returns the zero value of an option type.
+func zeroValue(typeStr string) any {
+	switch typeStr {
+	case "bool":
+		return false
+	case "int":
+		return 0
+	case "float64":
+		return 0.0
+	default:
+		return ""
+	}
+}
@@ -29,6 +29,16 @@ func TestParseAndValidateStageParams(t *testing.T) {
 			params:  []string{"stagekey=true"},
 			wantErr: true,
 		},
+		{
+			name:   "quota threshold",
+			params: []string{"quota.threshold=25"},
+			want:   map[string]map[string]any{StageNameQuota: {"threshold": 25.0}},
+		},
+		{
+			name:    "unknown quota option",
+			params:  []string{"quota.unknown=1"},
+			wantErr: true,
+		},
 		{
 			name:   "empty params ignored",
 			params: []string{"", "  "},
@@ -99,3 +109,118 @@ func TestApplyStageParams(t *testing.T) {
 		})
 	}
 }
+
+func TestGetStageOptionsWithDefaults(t *testing.T) {
+	var nilConfigs *StageConfigs
+	if got := nilConfigs.GetStageOptionsWithDefaults(StageNameQuota)["threshold"]; got != 15.0 {
+		t.Fatalf("default threshold = %v, want 15", got)
+	}
+
+	configs := NewStageConfigs()
+	if err := configs.ApplyStageParams([]string{"quota.threshold=5"}); err != nil {
+		t.Fatalf("ApplyStageParams() error = %v", err)
+	}
+	if got := configs.GetStageOptionsWithDefaults(StageNameQuota)["threshold"]; got != 5.0 {
+		t.Fatalf("threshold = %v, want 5", got)
+	}
+
+	// Per-resource costs cost one more query per subscription, so they are opt-in.
+	if got := nilConfigs.GetStageOptionsWithDefaults(StageNameCost)["resource-costs"]; got != false {
+		t.Fatalf("default resource-costs = %v, want false", got)
+	}
+}
+
+func TestPluginOptions(t *testing.T) {
+	err := RegisterPluginOptions("test-options", map[string]OptionSpec{
+		"days":    {Type: "int", Default: 30.0, Description: "Days"},
+		"regions": {Type: "string", Description: "Regions"},
+		"strict":  {Type: "bool", Default: "true"},
+	})
+	if err != nil {
+		t.Fatalf("RegisterPluginOptions() error = %v", err)
+	}
+
+	// Defaults are converted to the option type.
+	defaults := NewStageConfigs().GetPluginOptions("test-options")
+	if defaults["days"] != 30 || defaults["regions"] != "" || defaults["strict"] != true {
+		t.Fatalf("unexpected defaults: %v", defaults)
+	}
+
+	got, err := ParseAndValidateStageParams([]string{"plugin.test-options.days=7"})
+	if err != nil {
+		t.Fatalf("ParseAndValidateStageParams() error = %v", err)
+	}
+	if got[StageNamePlugin]["test-options.days"] != 7 {
+		t.Fatalf("got %v, want test-options.days=7", got)
+	}
+
+	for _, param := range []string{
+		"plugin.test-options.days=seven",
+		"plugin.test-options.unknown=1",
+		"plugin.unknown-plugin.days=1",
+		"plugin.days=1",
+	} {
+		if _, err := ParseAndValidateStageParams([]string{param}); err == nil {
+			t.Errorf("ParseAndValidateStageParams(%q) expected error", param)
+		}
+	}
+
+	configs := NewStageConfigs()
+	if err := configs.ApplyStageParams([]string{"plugin.test-options.regions=eastus"}); err != nil {
+		t.Fatalf("ApplyStageParams() error = %v", err)
+	}
+	// JSON numbers and lists are accepted as MCP arguments.
+	if err := configs.SetPluginOptions("test-options", map[string]any{"days": 14.0, "regions": []any{"eastus", "westus"}}); err != nil {
+		t.Fatalf("SetPluginOptions() error = %v", err)
+	}
+	opts := configs.GetPluginOptions("test-options")
+	if opts["days"] != 14 || opts["regions"] != "eastus,westus" || opts["strict"] != true {
+		t.Fatalf("unexpected options: %v", opts)
+	}
+
+	if err := configs.SetPluginOptions("test-options", map[string]any{"days": 1.5}); err == nil {
+		t.Error("SetPluginOptions() expected error for fractional int")
+	}
+	if err := configs.SetPluginOptions("test-options", map[string]any{"other": 1}); err == nil {
+		t.Error("SetPluginOptions() expected error for unknown option")
+	}
+
+	var nilConfigs *StageConfigs
+	if nilConfigs.GetPluginOptions("test-options")["days"] != 30 {
+		t.Error("GetPluginOptions() on nil configs should return defaults")
+	}
+}
+
+func TestParseAndValidateStageParams_DeprecatedPluginParams(t *testing.T) {
+	err := RegisterPluginOptions("region-selection", map[string]OptionSpec{
+		"target-regions":      {Type: "string"},
+		"cost-history-months": {Type: "int", Default: 1},
+	})
+	if err != nil {
+		t.Fatalf("RegisterPluginOptions() error = %v", err)
+	}
+
+	got, err := ParseAndValidateStageParams([]string{"plugin.target-regions=eastus,westus", "plugin.cost-history-months=3"})
+	if err != nil {
+		t.Fatalf("ParseAndValidateStageParams() error = %v", err)
+	}
+	if got[StageNamePlugin]["region-selection.target-regions"] != "eastus,westus" || got[StageNamePlugin]["region-selection.cost-history-months"] != 3 {
+		t.Fatalf("got %v, want the region-selection options", got)
+	}
+
+	if _, err := ParseAndValidateStageParams([]string{"plugin.cost-history-months=three"}); err == nil {
+		t.Error("deprecated parameters are still validated")
+	}
+}
+
+func TestRegisterPluginOptions_Invalid(t *testing.T) {
+	if err := RegisterPluginOptions("bad", map[string]OptionSpec{"a.b": {Type: "string"}}); err == nil {
+		t.Error("expected error for option name with a dot")
+	}
+	if err := RegisterPluginOptions("bad", map[string]OptionSpec{"days": {Type: "duration"}}); err == nil {
+		t.Error("expected error for unsupported type")
+	}
+	if err := RegisterPluginOptions("bad", map[string]OptionSpec{"days": {Type: "int", Default: "ten"}}); err == nil {
+		t.Error("expected error for default of the wrong type")
+	}
+}
@@ -46,10 +46,15 @@ func (b *ScanPipelineBuilder) BuildDefault() *Pipeline {
 		With(NewDiagnosticsScanStage()).
 		With(NewAdvisorStage()).
 		With(NewDefenderStatusStage()).
+		With(NewDefenderSecureScoreStage()).
 		With(NewDefenderRecommendationsStage()).
 		With(NewAzurePolicyStage()).
+		With(NewAzurePolicyComplianceStage()).
+		With(NewAzurePolicyExemptionsStage()).
 		With(NewArcSQLStage()).
+		With(NewArcServersStage()).
 		With(NewCostStage()).
+		With(NewQuotaStage()).
 		With(NewPluginExecutionStage()).
 		With(NewReportRenderingStage()).
 		With(NewProfilingCleanupStage()).
@@ -70,7 +75,13 @@ func (b *ScanPipelineBuilder) BuildPluginOnly() *Pipeline {
 
 // NewScanContext creates a scan context from ScanParams.
 func NewScanContext(params *models.ScanParams) *ScanContext {
-	ctx, cancel := context.WithCancel(context.Background())
+	return NewScanContextWithParent(context.Background(), params)
+}
+
+// NewScanContextWithParent creates a scan context whose Ctx is derived from parent,
+// so cancelling parent stops the scan.
+func NewScanContextWithParent(parent context.Context, params *models.ScanParams) *ScanContext {
+	ctx, cancel := context.WithCancel(parent)
 
 	return &ScanContext{
 		Ctx:           ctx,
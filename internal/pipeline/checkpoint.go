@@ -0,0 +1,359 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package pipeline
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azqr/internal/models"
+	"github.com/Azure/azqr/internal/renderers"
+)
+
+// checkpointVersion is bumped whenever the checkpoint file layout changes
+// in a way that older files can no longer be resumed.
+const checkpointVersion = 2
+
+// CheckpointExtension is appended to the output name to build the default checkpoint path.
+const CheckpointExtension = ".checkpoint.json"
+
+// Checkpoint persists the scan state after each pipeline stage (and after each
+// Graph rule batch) so that long scans can be resumed with --resume after a
+// failure instead of starting from zero.
+type Checkpoint struct {
+	Version         int                              `json:"version"`
+	UpdatedAt       time.Time                        `json:"updatedAt"`
+	OutputName      string                           `json:"outputName"`
+	Scope           *CheckpointScope                 `json:"scope,omitempty"`
+	CompletedStages []string                         `json:"completedStages"`
+	Subscriptions   map[string]string                `json:"subscriptions"`
+	Report          *checkpointReport                `json:"report,omitempty"`
+	GraphRules      map[string][]*models.GraphResult `json:"graphRules,omitempty"`
+
+	path    string
+	resumed bool
+	mu      sync.Mutex
+}
+
+// CheckpointScope records the command line parameters that decide what a scan
+// collects. A checkpoint is only resumed by a scan with the same scope, so a
+// changed command line never mixes the results of two scans in one report.
+type CheckpointScope struct {
+	ManagementGroups []string                  `json:"managementGroups,omitempty"`
+	Subscriptions    []string                  `json:"subscriptions,omitempty"`
+	ResourceGroups   []string                  `json:"resourceGroups,omitempty"`
+	Scanners         []string                  `json:"scanners,omitempty"`
+	Stages           []string                  `json:"stages,omitempty"`
+	StageOptions     map[string]map[string]any `json:"stageOptions,omitempty"`
+	Plugins          []string                  `json:"plugins,omitempty"`
+	// Filters is the SHA-256 of the include and exclude filters
+	Filters string `json:"filters,omitempty"`
+}
+
+// NewCheckpointScope captures the scope of the scan described by params.
+func NewCheckpointScope(params *models.ScanParams) *CheckpointScope {
+	scope := &CheckpointScope{
+		ManagementGroups: sortedCopy(params.ManagementGroups),
+		Subscriptions:    sortedCopy(params.Subscriptions),
+		ResourceGroups:   sortedCopy(params.ResourceGroups),
+		Scanners:         sortedCopy(params.ScannerKeys),
+	}
+	if params.Stages != nil {
+		scope.Stages = sortedCopy(params.Stages.GetEnabledStages())
+		for _, stage := range scope.Stages {
+			if options := params.Stages.GetStageOptions(stage); len(options) > 0 {
+				if scope.StageOptions == nil {
+					scope.StageOptions = map[string]map[string]any{}
+				}
+				scope.StageOptions[stage] = options
+			}
+		}
+	}
+	for name, enabled := range params.EnabledInternalPlugins {
+		if enabled {
+			scope.Plugins = append(scope.Plugins, name)
+		}
+	}
+	slices.Sort(scope.Plugins)
+	if params.Filters != nil && params.Filters.Azqr != nil {
+		data, err := json.Marshal(struct {
+			Include *models.IncludeFilter `json:"include"`
+			Exclude *models.ExcludeFilter `json:"exclude"`
+		}{params.Filters.Azqr.Include, params.Filters.Azqr.Exclude})
+		if err == nil {
+			sum := sha256.Sum256(data)
+			scope.Filters = hex.EncodeToString(sum[:])
+		}
+	}
+	return scope
+}
+
+// CheckScope returns an error naming the parameters that differ between the
+// scan that saved the checkpoint and the scan described by params.
+func (c *Checkpoint) CheckScope(params *models.ScanParams) error {
+	current := NewCheckpointScope(params)
+	saved := c.Scope
+	if saved == nil {
+		saved = &CheckpointScope{}
+	}
+
+	fields := []struct {
+		name        string
+		saved, want any
+	}{
+		{"management groups", saved.ManagementGroups, current.ManagementGroups},
+		{"subscriptions", saved.Subscriptions, current.Subscriptions},
+		{"resource groups", saved.ResourceGroups, current.ResourceGroups},
+		{"scanners", saved.Scanners, current.Scanners},
+		{"stages", saved.Stages, current.Stages},
+		{"stage parameters", saved.StageOptions, current.StageOptions},
+		{"plugins", saved.Plugins, current.Plugins},
+		{"filters", saved.Filters, current.Filters},
+	}
+
+	var changed []string
+	for _, f := range fields {
+		// Compare the JSON forms: options loaded from a checkpoint hold
+		// float64 numbers where the command line holds ints.
+		a, _ := json.Marshal(f.saved)
+		b, _ := json.Marshal(f.want)
+		if string(a) != string(b) {
+			changed = append(changed, f.name)
+		}
+	}
+	if len(changed) > 0 {
+		return fmt.Errorf("checkpoint %s was saved by a scan with different %s, resume it with the same command line", c.path, strings.Join(changed, ", "))
+	}
+	return nil
+}
+
+func sortedCopy(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	return sorted
+}
+
+// checkpointReport holds the ReportData fields accumulated by the stages.
+// ReportData hides some of them from JSON output, so they are copied here.
+type checkpointReport struct {
+	Graph                   []*models.GraphResult                             `json:"graph,omitempty"`
+	Defender                []*models.DefenderResult                          `json:"defender,omitempty"`
+	DefenderRecommendations []*models.DefenderRecommendation                  `json:"defenderRecommendations,omitempty"`
+	SecureScores            []*models.SecureScoreResult                       `json:"secureScores,omitempty"`
+	AzurePolicyCompliance   []*models.AzurePolicyComplianceResult             `json:"azurePolicyCompliance,omitempty"`
+	AzurePolicyExemptions   []*models.AzurePolicyExemptionResult              `json:"azurePolicyExemptions,omitempty"`
+	Advisor                 []*models.AdvisorResult                           `json:"advisor,omitempty"`
+	AzurePolicy             []*models.AzurePolicyResult                       `json:"azurePolicy,omitempty"`
+	ArcSQL                  []*models.ArcSQLResult                            `json:"arcSQL,omitempty"`
+	ArcServers              []*models.ArcServerResult                         `json:"arcServers,omitempty"`
+	Cost                    []*models.CostResult                              `json:"cost,omitempty"`
+	Quota                   []*models.QuotaResult                             `json:"quota,omitempty"`
+	ResourceCosts           []*models.ResourceCost                            `json:"resourceCosts,omitempty"`
+	Recommendations         map[string]map[string]*models.GraphRecommendation `json:"recommendations,omitempty"`
+	Resources               []*models.Resource                                `json:"resources,omitempty"`
+	ExcludedResources       []*models.Resource                                `json:"excludedResources,omitempty"`
+	ResourceTypeCount       []*models.ResourceTypeCount                       `json:"resourceTypeCount,omitempty"`
+	PluginResults           []*renderers.PluginResult                         `json:"pluginResults,omitempty"`
+	PluginRuns              []*renderers.PluginRun                            `json:"pluginRuns,omitempty"`
+}
+
+// checkpointExempt is implemented by stages whose effects (credentials,
+// profilers, rendered files) are not captured in a checkpoint. They always
+// run, even when resuming, and are never recorded as completed.
+type checkpointExempt interface {
+	CheckpointExempt() bool
+}
+
+// NewCheckpoint creates an empty checkpoint that will be written to path.
+func NewCheckpoint(path, outputName string) *Checkpoint {
+	return &Checkpoint{
+		Version:    checkpointVersion,
+		OutputName: outputName,
+		path:       path,
+	}
+}
+
+// LoadCheckpoint reads a checkpoint previously written by Save.
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	cp := &Checkpoint{}
+	if err := json.Unmarshal(data, cp); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint %s: %w", path, err)
+	}
+	if cp.Version != checkpointVersion {
+		return nil, fmt.Errorf("unsupported checkpoint version %d (expected %d)", cp.Version, checkpointVersion)
+	}
+
+	cp.path = path
+	cp.resumed = true
+	return cp, nil
+}
+
+// Path returns the file the checkpoint is written to.
+func (c *Checkpoint) Path() string {
+	return c.path
+}
+
+// Resumed reports whether the checkpoint was loaded from a previous run.
+func (c *Checkpoint) Resumed() bool {
+	return c.resumed
+}
+
+// IsStageCompleted reports whether a stage completed in a previous run.
+func (c *Checkpoint) IsStageCompleted(stageName string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Contains(c.CompletedStages, stageName)
+}
+
+// CompleteStage records the stage as completed, captures the scan context and saves the checkpoint.
+func (c *Checkpoint) CompleteStage(stageName string, ctx *ScanContext) error {
+	c.mu.Lock()
+	if !slices.Contains(c.CompletedStages, stageName) {
+		c.CompletedStages = append(c.CompletedStages, stageName)
+	}
+	c.Subscriptions = ctx.Subscriptions
+	if ctx.ReportData != nil {
+		c.Report = newCheckpointReport(ctx.ReportData)
+	}
+	c.mu.Unlock()
+
+	return c.Save()
+}
+
+// Restore copies the checkpointed state into the scan context.
+func (c *Checkpoint) Restore(ctx *ScanContext) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.Subscriptions != nil {
+		ctx.Subscriptions = c.Subscriptions
+	}
+	if c.Report == nil || ctx.ReportData == nil {
+		return
+	}
+
+	rd := ctx.ReportData
+	rd.Graph = c.Report.Graph
+	rd.Defender = c.Report.Defender
+	rd.DefenderRecommendations = c.Report.DefenderRecommendations
+	rd.SecureScores = c.Report.SecureScores
+	rd.AzurePolicyCompliance = c.Report.AzurePolicyCompliance
+	rd.AzurePolicyExemptions = c.Report.AzurePolicyExemptions
+	rd.Advisor = c.Report.Advisor
+	rd.AzurePolicy = c.Report.AzurePolicy
+	rd.ArcSQL = c.Report.ArcSQL
+	rd.ArcServers = c.Report.ArcServers
+	rd.Cost = c.Report.Cost
+	rd.Quota = c.Report.Quota
+	rd.ResourceCosts = c.Report.ResourceCosts
+	if c.Report.Recommendations != nil {
+		rd.Recommendations = c.Report.Recommendations
+	}
+	rd.Resources = c.Report.Resources
+	rd.ExludedResources = c.Report.ExcludedResources
+	rd.ResourceTypeCount = c.Report.ResourceTypeCount
+	rd.PluginResults = c.Report.PluginResults
+	rd.PluginRuns = c.Report.PluginRuns
+	rd.ClearTableCache()
+}
+
+// CompletedRules implements graph.RuleCheckpoint.
+func (c *Checkpoint) CompletedRules() map[string][]*models.GraphResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.GraphRules
+}
+
+// SaveRules implements graph.RuleCheckpoint.
+func (c *Checkpoint) SaveRules(results map[string][]*models.GraphResult) error {
+	c.mu.Lock()
+	if c.GraphRules == nil {
+		c.GraphRules = make(map[string][]*models.GraphResult, len(results))
+	}
+	for key, res := range results {
+		c.GraphRules[key] = res
+	}
+	c.mu.Unlock()
+
+	return c.Save()
+}
+
+// ClearRules drops the per-rule Graph progress once the Graph stage results
+// are captured at stage level.
+func (c *Checkpoint) ClearRules() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.GraphRules = nil
+}
+
+// Save writes the checkpoint atomically to its path.
+func (c *Checkpoint) Save() error {
+	c.mu.Lock()
+	c.UpdatedAt = time.Now()
+	data, err := json.Marshal(c)
+	c.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint: %w", err)
+	}
+
+	// Write to a temporary file first so a crash mid-write never corrupts
+	// the last good checkpoint.
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	return nil
+}
+
+// Remove deletes the checkpoint file once the scan has completed successfully.
+func (c *Checkpoint) Remove() error {
+	if err := os.Remove(c.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func newCheckpointReport(rd *renderers.ReportData) *checkpointReport {
+	return &checkpointReport{
+		Graph:                   rd.Graph,
+		Defender:                rd.Defender,
+		DefenderRecommendations: rd.DefenderRecommendations,
+		SecureScores:            rd.SecureScores,
+		AzurePolicyCompliance:   rd.AzurePolicyCompliance,
+		AzurePolicyExemptions:   rd.AzurePolicyExemptions,
+		Advisor:                 rd.Advisor,
+		AzurePolicy:             rd.AzurePolicy,
+		ArcSQL:                  rd.ArcSQL,
+		ArcServers:              rd.ArcServers,
+		Cost:                    rd.Cost,
+		Quota:                   rd.Quota,
+		ResourceCosts:           rd.ResourceCosts,
+		Recommendations:         rd.Recommendations,
+		Resources:               rd.Resources,
+		ExcludedResources:       rd.ExludedResources,
+		ResourceTypeCount:       rd.ResourceTypeCount,
+		PluginResults:           rd.PluginResults,
+		PluginRuns:              rd.PluginRuns,
+	}
+}
@@ -0,0 +1,203 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package pipeline
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azqr/internal/models"
+	"github.com/Azure/azqr/internal/renderers"
+)
+
+// exemptMockStage is a MockStage that is never recorded in checkpoints.
+type exemptMockStage struct {
+	*MockStage
+}
+
+func (s *exemptMockStage) CheckpointExempt() bool { return true }
+
+func newCheckpointScanContext(cp *Checkpoint) *ScanContext {
+	return &ScanContext{
+		Ctx:           context.Background(),
+		Params:        &models.ScanParams{},
+		ReportData:    &renderers.ReportData{},
+		Subscriptions: map[string]string{"sub1": "Subscription 1"},
+		Checkpoint:    cp,
+	}
+}
+
+func TestCheckpoint_SaveAndLoadRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scan"+CheckpointExtension)
+	cp := NewCheckpoint(path, "scan")
+
+	ctx := newCheckpointScanContext(cp)
+	ctx.ReportData.Resources = []*models.Resource{{ID: "/subscriptions/sub1/resourceGroups/rg/providers/a/b/c"}}
+	ctx.ReportData.Advisor = []*models.AdvisorResult{{RecommendationID: "adv1"}}
+
+	if err := cp.CompleteStage("Resource Discovery", ctx); err != nil {
+		t.Fatalf("CompleteStage: %v", err)
+	}
+
+	loaded, err := LoadCheckpoint(path)
+	if err != nil {
+		t.Fatalf("LoadCheckpoint: %v", err)
+	}
+	if !loaded.Resumed() {
+		t.Error("loaded checkpoint should report Resumed")
+	}
+	if loaded.OutputName != "scan" {
+		t.Errorf("OutputName = %q, want scan", loaded.OutputName)
+	}
+	if !loaded.IsStageCompleted("Resource Discovery") {
+		t.Error("expected Resource Discovery to be completed")
+	}
+
+	restored := &ScanContext{ReportData: &renderers.ReportData{}}
+	loaded.Restore(restored)
+	if len(restored.ReportData.Resources) != 1 || len(restored.ReportData.Advisor) != 1 {
+		t.Errorf("restored report data mismatch: %d resources, %d advisor",
+			len(restored.ReportData.Resources), len(restored.ReportData.Advisor))
+	}
+	if restored.Subscriptions["sub1"] != "Subscription 1" {
+		t.Errorf("restored subscriptions mismatch: %v", restored.Subscriptions)
+	}
+}
+
+func TestCheckpoint_LoadRejectsUnknownVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad"+CheckpointExtension)
+	if err := os.WriteFile(path, []byte(`{"version": 99}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadCheckpoint(path); err == nil {
+		t.Error("expected error for unsupported checkpoint version")
+	}
+}
+
+func TestCheckpoint_SaveRulesAccumulates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules"+CheckpointExtension)
+	cp := NewCheckpoint(path, "rules")
+
+	if err := cp.SaveRules(map[string][]*models.GraphResult{"a|1": {{RecommendationID: "1"}}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cp.SaveRules(map[string][]*models.GraphResult{"b|2": {}}); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadCheckpoint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules := loaded.CompletedRules()
+	if len(rules) != 2 {
+		t.Fatalf("expected 2 completed rules, got %d", len(rules))
+	}
+	if len(rules["a|1"]) != 1 {
+		t.Errorf("expected 1 result for a|1, got %d", len(rules["a|1"]))
+	}
+
+	loaded.ClearRules()
+	if loaded.CompletedRules() != nil {
+		t.Error("ClearRules should drop per-rule progress")
+	}
+}
+
+func TestPipeline_Execute_RecordsCompletedStages(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "p"+CheckpointExtension)
+	cp := NewCheckpoint(path, "p")
+
+	exempt := &exemptMockStage{NewMockStage("exempt", true, false)}
+	stage1 := NewMockStage("stage1", true, false)
+	stage2 := NewMockStage("stage2", true, true)
+
+	err := NewPipeline(exempt, stage1, stage2).Execute(newCheckpointScanContext(cp))
+	if err == nil {
+		t.Fatal("expected stage2 failure")
+	}
+
+	loaded, err := LoadCheckpoint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.IsStageCompleted("exempt") {
+		t.Error("exempt stage should not be recorded")
+	}
+	if !loaded.IsStageCompleted("stage1") {
+		t.Error("stage1 should be recorded as completed")
+	}
+	if loaded.IsStageCompleted("stage2") {
+		t.Error("failed stage2 should not be recorded")
+	}
+}
+
+func TestPipeline_Execute_ResumeSkipsCompletedStages(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "r"+CheckpointExtension)
+	cp := NewCheckpoint(path, "r")
+	cp.CompletedStages = []string{"stage1"}
+
+	stage1 := NewMockStage("stage1", true, false)
+	stage2 := NewMockStage("stage2", true, false)
+
+	pipe := NewPipeline(stage1, stage2)
+	if err := pipe.Execute(newCheckpointScanContext(cp)); err != nil {
+		t.Fatal(err)
+	}
+
+	if stage1.executed {
+		t.Error("stage1 completed in checkpoint and should be skipped")
+	}
+	if !stage2.executed {
+		t.Error("stage2 should be executed")
+	}
+	if pipe.metrics.StagesSkipped != 1 {
+		t.Errorf("expected 1 skipped stage, got %d", pipe.metrics.StagesSkipped)
+	}
+}
+
+func TestCheckpoint_CheckScope(t *testing.T) {
+	newParams := func(subscriptions []string, stageParams ...string) *models.ScanParams {
+		stages := models.NewStageConfigsWithDefaults()
+		_ = stages.EnableStage(models.StageNameArc)
+		if err := stages.ApplyStageParams(stageParams); err != nil {
+			t.Fatal(err)
+		}
+		return &models.ScanParams{
+			Subscriptions: subscriptions,
+			ScannerKeys:   []string{"vm", "aks"},
+			Stages:        stages,
+			Filters:       models.NewFilters(),
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "scope"+CheckpointExtension)
+	cp := NewCheckpoint(path, "scope")
+	cp.Scope = NewCheckpointScope(newParams([]string{"sub1", "sub2"}, "arc.disconnected-days=3"))
+	if err := cp.Save(); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadCheckpoint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Order does not matter, and options survive the JSON round trip
+	if err := loaded.CheckScope(newParams([]string{"sub2", "sub1"}, "arc.disconnected-days=3")); err != nil {
+		t.Errorf("CheckScope() with the same scope error = %v", err)
+	}
+
+	err = loaded.CheckScope(newParams([]string{"sub1"}, "arc.disconnected-days=5"))
+	if err == nil || !strings.Contains(err.Error(), "subscriptions, stage parameters") {
+		t.Errorf("CheckScope() with a different scope error = %v", err)
+	}
+
+	filtered := newParams([]string{"sub1", "sub2"}, "arc.disconnected-days=3")
+	filtered.Filters.Azqr.Exclude.Subscriptions = []string{"sub3"}
+	if err := loaded.CheckScope(filtered); err == nil || !strings.Contains(err.Error(), "filters") {
+		t.Errorf("CheckScope() with different filters error = %v", err)
+	}
+}
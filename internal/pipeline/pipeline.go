@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/Azure/azqr/internal/models"
+	"github.com/Azure/azqr/internal/progress"
 	"github.com/Azure/azqr/internal/renderers"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
@@ -26,6 +27,8 @@ type ScanContext struct {
 	Params        *models.ScanParams
 	// Accumulated data through pipeline stages
 	ReportData *renderers.ReportData
+	// Checkpoint persists progress between stages (nil when disabled)
+	Checkpoint *Checkpoint
 	// Profiler instance (if profiling is enabled)
 	Profiler interface {
 		Cleanup()
@@ -55,8 +58,9 @@ type Stage interface {
 
 // Pipeline orchestrates the execution of multiple stages in sequence.
 type Pipeline struct {
-	stages  []Stage
-	metrics *PipelineMetrics
+	stages    []Stage
+	metrics   *PipelineMetrics
+	observers progress.Observers
 }
 
 // PipelineMetrics tracks performance of each pipeline stage.
@@ -79,6 +83,12 @@ func NewPipeline(stages ...Stage) *Pipeline {
 	}
 }
 
+// AddObserver registers an observer for stage events. The observer is also
+// attached to the scan context so long-running loops can report task progress.
+func (p *Pipeline) AddObserver(o progress.Observer) {
+	p.observers = append(p.observers, o)
+}
+
 // Execute runs all pipeline stages in sequence.
 func (p *Pipeline) Execute(ctx *ScanContext) error {
 	startTime := time.Now()
@@ -86,6 +96,10 @@ func (p *Pipeline) Execute(ctx *ScanContext) error {
 		Int("stages", len(p.stages)).
 		Msg("Scan started")
 
+	if len(p.observers) > 0 && ctx.Ctx != nil {
+		ctx.Ctx = progress.WithObserver(ctx.Ctx, p.observers)
+	}
+
 	for i, stage := range p.stages {
 		stageName := stage.Name()
 
@@ -99,6 +113,15 @@ func (p *Pipeline) Execute(ctx *ScanContext) error {
 			continue
 		}
 
+		// Skip stages already completed by the run being resumed
+		if ctx.Checkpoint != nil && ctx.Checkpoint.IsStageCompleted(stageName) {
+			log.Info().
+				Str("stage", stageName).
+				Msg("Skipping stage completed in checkpoint")
+			p.metrics.StagesSkipped++
+			continue
+		}
+
 		// Execute stage
 		log.Debug().
 			Str("stage", stageName).
@@ -106,10 +129,26 @@ func (p *Pipeline) Execute(ctx *ScanContext) error {
 			Int("total", len(p.stages)).
 			Msg("Executing stage")
 
+		p.observers.OnEvent(progress.Event{
+			Type:       progress.EventStageStarted,
+			Stage:      stageName,
+			StageIndex: i + 1,
+			StageCount: len(p.stages),
+		})
+
 		stageStart := time.Now()
 		err := stage.Execute(ctx)
 		stageDuration := time.Since(stageStart)
 
+		p.observers.OnEvent(progress.Event{
+			Type:       progress.EventStageFinished,
+			Stage:      stageName,
+			StageIndex: i + 1,
+			StageCount: len(p.stages),
+			Elapsed:    stageDuration,
+			Err:        err,
+		})
+
 		p.metrics.StageDurations[stageName] = stageDuration
 		p.metrics.StagesExecuted++
 
@@ -127,6 +166,15 @@ func (p *Pipeline) Execute(ctx *ScanContext) error {
 			Str("stage", stageName).
 			Dur("duration", stageDuration).
 			Msg("Stage completed")
+
+		if ctx.Checkpoint != nil && !isCheckpointExempt(stage) {
+			if err := ctx.Checkpoint.CompleteStage(stageName, ctx); err != nil {
+				log.Warn().
+					Err(err).
+					Str("stage", stageName).
+					Msg("Failed to save checkpoint")
+			}
+		}
 	}
 
 	p.metrics.TotalDuration = time.Since(startTime)
@@ -162,6 +210,12 @@ func (p *Pipeline) LogMetrics() {
 		Msg("=== End Scan Metrics ===")
 }
 
+// isCheckpointExempt reports whether a stage must not be recorded in a checkpoint.
+func isCheckpointExempt(stage Stage) bool {
+	exempt, ok := stage.(checkpointExempt)
+	return ok && exempt.CheckpointExempt()
+}
+
 // BaseStage provides default implementations for Stage interface.
 // Stages can embed this to inherit default behavior.
 type BaseStage struct {
@@ -6,9 +6,11 @@ package pipeline
 import (
 	"context"
 	"errors"
+	"sync"
 	"testing"
 
 	"github.com/Azure/azqr/internal/models"
+	"github.com/Azure/azqr/internal/progress"
 	"github.com/Azure/azqr/internal/renderers"
 )
 
@@ -224,3 +226,39 @@ func TestPipeline_Integration(t *testing.T) {
 
 	t.Logf("Pipeline created with %d stages", len(pipeline.stages))
 }
+
+func TestPipeline_Execute_EmitsStageEvents(t *testing.T) {
+	var mu sync.Mutex
+	var events []progress.Event
+	observer := progress.ObserverFunc(func(e progress.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, e)
+	})
+
+	pipeline := NewPipeline(NewMockStage("stage1", true, false), NewMockStage("stage2", false, false))
+	pipeline.AddObserver(observer)
+
+	ctx := &ScanContext{
+		Ctx:        context.Background(),
+		Params:     &models.ScanParams{},
+		ReportData: &renderers.ReportData{},
+	}
+	if err := pipeline.Execute(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// stage2 is skipped, so only stage1 emits events
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(events))
+	}
+	if events[0].Type != progress.EventStageStarted || events[0].Stage != "stage1" || events[0].StageCount != 2 {
+		t.Errorf("Unexpected start event: %+v", events[0])
+	}
+	if events[1].Type != progress.EventStageFinished || events[1].StageIndex != 1 {
+		t.Errorf("Unexpected finish event: %+v", events[1])
+	}
+	if progress.FromContext(ctx.Ctx) == nil {
+		t.Error("Expected observer to be attached to the scan context")
+	}
+}
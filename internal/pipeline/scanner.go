@@ -4,47 +4,92 @@
 package pipeline
 
 import (
+	"context"
+	"fmt"
 	"time"
 
 	"github.com/Azure/azqr/internal/models"
+	"github.com/Azure/azqr/internal/progress"
 	"github.com/Azure/azqr/internal/renderers"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/rs/zerolog/log"
 )
 
-type Scanner struct{}
+type Scanner struct {
+	// Observer receives progress events during the scan (optional)
+	Observer progress.Observer
+	// Credential is used instead of DefaultAzureCredential when set (optional)
+	Credential azcore.TokenCredential
+}
 
 // Scan performs a full scan using the default pipeline
 func (sc *Scanner) Scan(params *models.ScanParams) *renderers.ReportData {
-	return sc.scan(params, true)
+	return sc.mustRun(params, true)
 }
 
 // ScanPlugins performs a scan using only the plugin execution stage
 func (sc *Scanner) ScanPlugins(params *models.ScanParams) *renderers.ReportData {
-	return sc.scan(params, false)
+	return sc.mustRun(params, false)
+}
+
+// Run performs a full scan using the default pipeline and returns the first error
+// instead of exiting. Cancelling ctx stops the scan.
+func (sc *Scanner) Run(ctx context.Context, params *models.ScanParams) (*renderers.ReportData, error) {
+	data, _, err := sc.run(ctx, params, true)
+	return data, err
+}
+
+// RunPlugins performs a plugin-only scan and returns the first error instead of exiting.
+func (sc *Scanner) RunPlugins(ctx context.Context, params *models.ScanParams) (*renderers.ReportData, error) {
+	data, _, err := sc.run(ctx, params, false)
+	return data, err
+}
+
+// mustRun executes the scan and exits the process on failure.
+func (sc *Scanner) mustRun(params *models.ScanParams, defaultPipeline bool) *renderers.ReportData {
+	data, checkpoint, err := sc.run(context.Background(), params, defaultPipeline)
+	if err != nil {
+		if checkpoint != nil {
+			log.Fatal().Err(err).Msgf("Scan failed. Resume with --resume %s", checkpoint.Path())
+		}
+		log.Fatal().Err(err).Msg("Scan failed")
+	}
+	return data
 }
 
-// scan executes the scan using the composable pipeline pattern
-func (sc *Scanner) scan(params *models.ScanParams, defaultPipeline bool) *renderers.ReportData {
-	// Import pipeline package
+// run executes the scan using the composable pipeline pattern
+func (sc *Scanner) run(parent context.Context, params *models.ScanParams, defaultPipeline bool) (*renderers.ReportData, *Checkpoint, error) {
 	builder := NewScanPipelineBuilder()
 
 	// Create scan context
-	scanCtx := NewScanContext(params)
+	scanCtx := NewScanContextWithParent(parent, params)
+	defer scanCtx.Cancel()
+	scanCtx.Cred = sc.Credential
 
 	var pipe *Pipeline
 	if defaultPipeline {
 		// Ensure graph stage is enabled for regular scans
 		if err := params.Stages.ValidateGraphStageEnabled(); err != nil {
-			log.Fatal().Err(err).Msg("Configuration error")
+			return nil, nil, fmt.Errorf("configuration error: %w", err)
 		}
 		pipe = builder.BuildDefault()
 	} else {
 		pipe = builder.BuildPluginOnly()
 	}
 
-	err := pipe.Execute(scanCtx)
-	if err != nil {
-		log.Fatal().Err(err).Msg("Scan failed")
+	if sc.Observer != nil {
+		pipe.AddObserver(sc.Observer)
+	}
+
+	if err := pipe.Execute(scanCtx); err != nil {
+		return nil, scanCtx.Checkpoint, err
+	}
+
+	// The scan completed, so the checkpoint is no longer needed
+	if scanCtx.Checkpoint != nil {
+		if err := scanCtx.Checkpoint.Remove(); err != nil {
+			log.Warn().Err(err).Msg("Failed to remove checkpoint")
+		}
 	}
 
 	// Log metrics in debug mode
@@ -59,5 +104,5 @@ func (sc *Scanner) scan(params *models.ScanParams, defaultPipeline bool) *render
 	seconds := int(elapsedTime.Seconds()) % 60
 	log.Info().Msgf("Scan completed in %02d:%02d:%02d", hours, minutes, seconds)
 
-	return scanCtx.ReportData
+	return scanCtx.ReportData, nil, nil
 }
@@ -152,6 +152,46 @@ func TestNewArcSQLStage(t *testing.T) {
 	})
 }
 
+func TestNewArcServersStage(t *testing.T) {
+	s := NewArcServersStage()
+
+	t.Run("name", func(t *testing.T) {
+		if got := s.Name(); got != "Arc-enabled Servers Scan" {
+			t.Errorf("Name() = %q, want %q", got, "Arc-enabled Servers Scan")
+		}
+	})
+	t.Run("skip_when_disabled", func(t *testing.T) {
+		if !s.Skip(stageDisabledCtx()) {
+			t.Error("Skip should return true when arc stage is disabled")
+		}
+	})
+	t.Run("run_when_enabled", func(t *testing.T) {
+		if s.Skip(stageEnabledCtx(models.StageNameArc)) {
+			t.Error("Skip should return false when arc stage is enabled")
+		}
+	})
+}
+
+func TestNewQuotaStage(t *testing.T) {
+	s := NewQuotaStage()
+
+	t.Run("name", func(t *testing.T) {
+		if got := s.Name(); got != "Quota Scan" {
+			t.Errorf("Name() = %q, want %q", got, "Quota Scan")
+		}
+	})
+	t.Run("skip_when_disabled", func(t *testing.T) {
+		if !s.Skip(stageDisabledCtx()) {
+			t.Error("Skip should return true when quota stage is disabled")
+		}
+	})
+	t.Run("run_when_enabled", func(t *testing.T) {
+		if s.Skip(stageEnabledCtx(models.StageNameQuota)) {
+			t.Error("Skip should return false when quota stage is enabled")
+		}
+	})
+}
+
 func TestNewAzurePolicyStage(t *testing.T) {
 	s := NewAzurePolicyStage()
 
@@ -202,6 +242,26 @@ func TestNewDefenderStatusStage(t *testing.T) {
 	})
 }
 
+func TestNewDefenderSecureScoreStage(t *testing.T) {
+	s := NewDefenderSecureScoreStage()
+
+	t.Run("name", func(t *testing.T) {
+		if got := s.Name(); got != "Defender Secure Score Scan" {
+			t.Errorf("Name() = %q, want %q", got, "Defender Secure Score Scan")
+		}
+	})
+	t.Run("skip_when_disabled", func(t *testing.T) {
+		if !s.Skip(stageDisabledCtx()) {
+			t.Error("Skip should return true when defender stage is disabled")
+		}
+	})
+	t.Run("run_when_enabled", func(t *testing.T) {
+		if s.Skip(stageEnabledCtx(models.StageNameDefender)) {
+			t.Error("Skip should return false when defender stage is enabled")
+		}
+	})
+}
+
 func TestNewDefenderRecommendationsStage(t *testing.T) {
 	s := NewDefenderRecommendationsStage()
 
@@ -265,10 +325,15 @@ func TestBuildDefault_StageCountAndOrder(t *testing.T) {
 		"Diagnostics Settings Scan",
 		"Advisor Scan",
 		"Defender Status Scan",
+		"Defender Secure Score Scan",
 		"Defender Recommendations Scan",
 		"Azure Policy Scan",
+		"Azure Policy Compliance Scan",
+		"Azure Policy Exemptions Scan",
 		"Arc-enabled SQL Server Scan",
+		"Arc-enabled Servers Scan",
 		"Cost Analysis Scan",
+		"Quota Scan",
 		"Plugin Execution",
 		"Report Rendering",
 		"Profiling Cleanup",
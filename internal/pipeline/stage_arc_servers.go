@@ -0,0 +1,59 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package pipeline
+
+import (
+	"github.com/Azure/azqr/internal/models"
+	"github.com/Azure/azqr/internal/scanners"
+	"github.com/rs/zerolog/log"
+)
+
+// ArcServersStage scans the Arc-enabled servers and adds their findings to the
+// recommendations. It runs with the Arc-enabled SQL Server stage.
+type ArcServersStage struct {
+	*BaseStage
+}
+
+func NewArcServersStage() *ArcServersStage {
+	return &ArcServersStage{
+		BaseStage: NewBaseStage("Arc-enabled Servers Scan", false),
+	}
+}
+
+func (s *ArcServersStage) Skip(ctx *ScanContext) bool {
+	return !ctx.Params.Stages.IsStageEnabled(models.StageNameArc)
+}
+
+func (s *ArcServersStage) Execute(ctx *ScanContext) error {
+	options := ctx.Params.Stages.GetStageOptionsWithDefaults(models.StageNameArc)
+	disconnectedDays, _ := options["disconnected-days"].(int)
+	minAgentVersion, _ := options["min-agent-version"].(string)
+	if minAgentVersion == "" {
+		minAgentVersion = scanners.ArcMinSupportedAgentVersion
+	}
+	agentVersionsBehind, _ := options["agent-versions-behind"].(int)
+
+	scanner := scanners.ArcServerScanner{}
+	ctx.ReportData.ArcServers = scanner.Scan(ctx.Ctx, ctx.Cred, ctx.Subscriptions, ctx.Params.Filters, minAgentVersion, agentVersionsBehind)
+
+	recommendations := scanner.Recommendations()
+	for resourceType, recs := range recommendations {
+		for _, rec := range recs {
+			if ctx.ReportData.Recommendations[resourceType] == nil {
+				ctx.ReportData.Recommendations[resourceType] = make(map[string]*models.GraphRecommendation)
+			}
+			ctx.ReportData.Recommendations[resourceType][rec.RecommendationID] = &rec
+		}
+	}
+
+	results := scanner.Evaluate(ctx.ReportData.ArcServers, recommendations, ctx.Params.Filters, disconnectedDays, minAgentVersion)
+	ctx.ReportData.Graph = append(ctx.ReportData.Graph, results...)
+
+	log.Debug().
+		Int("arc_servers", len(ctx.ReportData.ArcServers)).
+		Int("arc_server_findings", len(results)).
+		Msg("Arc-enabled servers scan completed")
+
+	return nil
+}
@@ -4,10 +4,17 @@
 package pipeline
 
 import (
+	"context"
 	"sync"
+	"time"
 
+	"github.com/Azure/azqr/internal/az"
 	"github.com/Azure/azqr/internal/models"
+	"github.com/Azure/azqr/internal/progress"
+	"github.com/Azure/azqr/internal/savings"
 	"github.com/Azure/azqr/internal/scanners"
+	"github.com/Azure/azqr/internal/scanners/plugins/region/cost"
+	"github.com/Azure/azqr/internal/scanners/plugins/region/types"
 )
 
 // CostStage executes Cost analysis scan.
@@ -32,6 +39,51 @@ func (s *CostStage) Execute(ctx *ScanContext) error {
 		return nil
 	}
 
+	options := ctx.Params.Stages.GetStageOptionsWithDefaults(models.StageNameCost)
+	query, err := scanners.NewCostQuery(options, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	withResourceCosts, _ := options["resource-costs"].(bool)
+
+	var allCosts []*models.CostResult
+	var allResourceCosts []*models.ResourceCost
+	if query.Scope != "" {
+		// One query for all subscriptions of the billing or management group scope
+		scanner := scanners.CostScanner{Query: query, ResourceCosts: withResourceCosts}
+		allCosts, allResourceCosts, err = scanner.ScanScope(&models.ScannerConfig{
+			Ctx:           ctx.Ctx,
+			Cred:          ctx.Cred,
+			ClientOptions: ctx.ClientOptions,
+		}, ctx.Subscriptions)
+	} else {
+		allCosts, allResourceCosts, err = s.scanSubscriptions(ctx, query, withResourceCosts)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Aggregate all cost items into report data
+	ctx.ReportData.Cost = allCosts
+	ctx.ReportData.ResourceCosts = allResourceCosts
+
+	if withResourceCosts {
+		// Attribute cost to the findings of the graph scan and estimate savings,
+		// pricing resources without cost data from retail prices
+		httpClient := az.NewHttpClient(ctx.Cred, nil)
+		savings.Estimate(ctx.Ctx, ctx.ReportData.Graph, ctx.ReportData.Recommendations, ctx.ReportData.Resources, allResourceCosts,
+			func(c context.Context, filters map[string]string) ([]types.RetailPriceItem, error) {
+				return cost.FetchRetailPrices(c, httpClient, filters)
+			})
+	}
+
+	return nil
+}
+
+// scanSubscriptions queries the costs of each subscription.
+func (s *CostStage) scanSubscriptions(ctx *ScanContext, query *scanners.CostQuery, withResourceCosts bool) ([]*models.CostResult, []*models.ResourceCost, error) {
+	subCount := len(ctx.Subscriptions)
+
 	// Worker count is capped at the costLimiter burst (2) so no worker ever
 	// waits for a token that another worker already holds. More workers than
 	// burst capacity just adds goroutine overhead without increasing throughput.
@@ -40,6 +92,10 @@ func (s *CostStage) Execute(ctx *ScanContext) error {
 
 	jobs := make(chan string, subCount)
 	results := make(chan []*models.CostResult, subCount)
+	resourceResults := make(chan []*models.ResourceCost, subCount)
+	errs := make(chan error, subCount)
+
+	tracker := progress.NewTracker(ctx.Ctx, "subscriptions", subCount)
 
 	// Start worker pool
 	var workerWg sync.WaitGroup
@@ -49,18 +105,27 @@ func (s *CostStage) Execute(ctx *ScanContext) error {
 			defer workerWg.Done()
 			// Create a new CostScanner per worker to avoid race conditions
 			// since CostScanner stores state in struct fields during Scan()
-			workerScanner := scanners.CostScanner{}
+			workerScanner := scanners.CostScanner{Query: query, ResourceCosts: withResourceCosts}
 			for subID := range jobs {
 				scannerConfig := &models.ScannerConfig{
-					Ctx:            ctx.Ctx,
-					Cred:           ctx.Cred,
-					ClientOptions:  ctx.ClientOptions,
-					SubscriptionID: subID,
+					Ctx:              ctx.Ctx,
+					Cred:             ctx.Cred,
+					ClientOptions:    ctx.ClientOptions,
+					SubscriptionID:   subID,
+					SubscriptionName: ctx.Subscriptions[subID],
+				}
+				result, resourceCosts, err := workerScanner.Scan(scannerConfig)
+				tracker.Step(ctx.Subscriptions[subID])
+				if err != nil {
+					errs <- err
+					continue
 				}
-				result := workerScanner.Scan(scannerConfig)
 				if len(result) > 0 {
 					results <- result
 				}
+				if len(resourceCosts) > 0 {
+					resourceResults <- resourceCosts
+				}
 			}
 		}()
 	}
@@ -75,6 +140,7 @@ func (s *CostStage) Execute(ctx *ScanContext) error {
 	go func() {
 		workerWg.Wait()
 		close(results)
+		close(resourceResults)
 	}()
 
 	// Collect results from all workers
@@ -82,9 +148,13 @@ func (s *CostStage) Execute(ctx *ScanContext) error {
 	for result := range results {
 		allCosts = append(allCosts, result...)
 	}
-
-	// Aggregate all cost items into report data
-	ctx.ReportData.Cost = allCosts
-
-	return nil
+	var allResourceCosts []*models.ResourceCost
+	for result := range resourceResults {
+		allResourceCosts = append(allResourceCosts, result...)
+	}
+	close(errs)
+	if err := <-errs; err != nil {
+		return nil, nil, err
+	}
+	return allCosts, allResourceCosts, nil
 }
@@ -5,6 +5,7 @@ package pipeline
 
 import (
 	"github.com/Azure/azqr/internal/models"
+	"github.com/Azure/azqr/internal/plugins"
 	"github.com/Azure/azqr/internal/scanners"
 	"github.com/rs/zerolog/log"
 )
@@ -36,9 +37,10 @@ func (s *DiagnosticsScanStage) Execute(ctx *ScanContext) error {
 		log.Error().Err(err).Msg("Failed to initialize diagnostic settings scanner")
 		return err
 	}
+	s.registerYamlPlugins(&diagnosticsScanner)
 
 	// Get diagnostic settings recommendations and add to report
-	recommendations := scanners.GetRecommendations()
+	recommendations := diagnosticsScanner.Recommendations()
 	recommendationCount := 0
 	for resourceType, recs := range recommendations {
 		for _, rec := range recs {
@@ -57,7 +59,10 @@ func (s *DiagnosticsScanStage) Execute(ctx *ScanContext) error {
 		Msg("Diagnostics recommendations collected")
 
 	// Execute diagnostic settings scan to find resources without diagnostic settings
-	diagResults := diagnosticsScanner.Scan(ctx.ReportData.Resources)
+	diagResults, err := diagnosticsScanner.Scan(ctx.ReportData.Resources)
+	if err != nil {
+		return err
+	}
 	ctx.ReportData.Graph = append(ctx.ReportData.Graph, diagResults...)
 
 	log.Debug().
@@ -67,3 +72,17 @@ func (s *DiagnosticsScanStage) Execute(ctx *ScanContext) error {
 
 	return nil
 }
+
+func (s *DiagnosticsScanStage) registerYamlPlugins(diagnosticsScanner *scanners.DiagnosticSettingsScanner) {
+	for _, plugin := range plugins.GetRegistry().List() {
+		if len(plugin.DiagnosticSettingsRecommendations) > 0 {
+			log.Info().
+				Str("plugin", plugin.Metadata.Name).
+				Int("recommendations", len(plugin.DiagnosticSettingsRecommendations)).
+				Msg("Registering YAML plugin recommendations with diagnostic settings scanner")
+			for _, rec := range plugin.DiagnosticSettingsRecommendations {
+				diagnosticsScanner.RegisterRecommendation(rec)
+			}
+		}
+	}
+}
@@ -84,10 +84,21 @@ func (s *GraphScanStage) Execute(ctx *ScanContext) error {
 	log.Debug().Msg("Graph Phase 2: Creating scanner with filtered scanners")
 	scanner = graph.NewScanner(filteredScanners, ctx.Params.Filters, ctx.Subscriptions)
 	s.registerYamlPlugins(&scanner)
+	if ctx.Checkpoint != nil {
+		scanner.SetCheckpoint(ctx.Checkpoint)
+	}
 
 	// Execute ARG scan
 	log.Debug().Msg("Graph Phase 2: Executing scan")
-	ctx.ReportData.Graph = scanner.Scan(ctx.Ctx, ctx.Cred)
+	graphResults, err := scanner.Scan(ctx.Ctx, ctx.Cred)
+	if err != nil {
+		return err
+	}
+	ctx.ReportData.Graph = graphResults
+	if ctx.Checkpoint != nil {
+		// Results are now checkpointed at stage level.
+		ctx.Checkpoint.ClearRules()
+	}
 
 	log.Debug().
 		Int("graph_results", len(ctx.ReportData.Graph)).
@@ -25,24 +25,65 @@ func NewInitializationStage() *InitializationStage {
 	}
 }
 
+// CheckpointExempt implements checkpointExempt: credentials and filters are
+// rebuilt on every run, and this stage restores the checkpointed state.
+func (s *InitializationStage) CheckpointExempt() bool {
+	return true
+}
+
 func (s *InitializationStage) Execute(ctx *ScanContext) error {
 	// Step 1: Check scanner registry
 	s.logScannerRegistryInfo()
 
-	// Step 2: Generate output file name
+	// Step 2: Load the checkpoint being resumed, reusing its output name
+	if ctx.Params.ResumeFrom != "" {
+		cp, err := LoadCheckpoint(ctx.Params.ResumeFrom)
+		if err != nil {
+			return err
+		}
+		if err := cp.CheckScope(ctx.Params); err != nil {
+			return err
+		}
+		if ctx.Params.OutputName == "" {
+			ctx.Params.OutputName = cp.OutputName
+		}
+		ctx.Checkpoint = cp
+		log.Info().
+			Str("checkpoint", cp.Path()).
+			Strs("completed_stages", cp.CompletedStages).
+			Msg("Resuming scan from checkpoint")
+	}
+
+	// Step 3: Generate output file name
 	outputFile := s.generateOutputFileName(ctx.Params.OutputName)
 	ctx.Params.OutputName = outputFile
 
-	// Step 3: Validate and prepare filters
-	s.validateAndPrepareFilters(ctx.Params)
+	if ctx.Checkpoint == nil && ctx.Params.Checkpoint {
+		ctx.Checkpoint = NewCheckpoint(outputFile+CheckpointExtension, outputFile)
+		ctx.Checkpoint.Scope = NewCheckpointScope(ctx.Params)
+		log.Info().
+			Str("checkpoint", ctx.Checkpoint.Path()).
+			Msg("Saving scan progress to checkpoint")
+	}
 
-	// Step 4: Create Azure credentials
-	ctx.Cred = az.NewAzureCredential()
+	// Step 4: Validate and prepare filters
+	if err := s.validateAndPrepareFilters(ctx.Params); err != nil {
+		return err
+	}
+
+	// Step 5: Create Azure credentials, unless the caller supplied them
+	if ctx.Cred == nil {
+		cred, err := az.CreateAzureCredential()
+		if err != nil {
+			return fmt.Errorf("failed to get Azure credentials: %w", err)
+		}
+		ctx.Cred = cred
+	}
 
-	// Step 5: Create client options
+	// Step 6: Create client options
 	ctx.ClientOptions = az.NewDefaultClientOptions()
 
-	// Step 6: Initialize report data
+	// Step 7: Initialize report data
 	reportData := renderers.NewReportData(
 		outputFile,
 		ctx.Params.Mask,
@@ -50,6 +91,11 @@ func (s *InitializationStage) Execute(ctx *ScanContext) error {
 	)
 	ctx.ReportData = &reportData
 
+	// Step 8: Restore the state accumulated by completed stages
+	if ctx.Checkpoint != nil && ctx.Checkpoint.Resumed() {
+		ctx.Checkpoint.Restore(ctx)
+	}
+
 	log.Debug().Msg("Initialization stage completed")
 	return nil
 }
@@ -96,7 +142,7 @@ func (s *InitializationStage) generateOutputFileName(outputName string) string {
 }
 
 // validateAndPrepareFilters validates input parameters and prepares filters
-func (s *InitializationStage) validateAndPrepareFilters(params *models.ScanParams) {
+func (s *InitializationStage) validateAndPrepareFilters(params *models.ScanParams) error {
 	filters := params.Filters
 
 	log.Debug().
@@ -105,15 +151,15 @@ func (s *InitializationStage) validateAndPrepareFilters(params *models.ScanParam
 
 	// validate input
 	if len(params.ManagementGroups) > 0 && (len(params.Subscriptions) > 0 || len(params.ResourceGroups) > 0) {
-		log.Fatal().Msg("Management Group name cannot be used with a Subscription Id or Resource Group name")
+		return fmt.Errorf("management group name cannot be used with a subscription id or resource group name")
 	}
 
 	if len(params.Subscriptions) < 1 && len(params.ResourceGroups) > 0 {
-		log.Fatal().Msg("Resource Group name can only be used with a Subscription Id")
+		return fmt.Errorf("resource group name can only be used with a subscription id")
 	}
 
 	if len(params.Subscriptions) > 1 && len(params.ResourceGroups) > 0 {
-		log.Fatal().Msg("Resource Group name can only be used with 1 Subscription Id")
+		return fmt.Errorf("resource group name can only be used with 1 subscription id")
 	}
 
 	if len(params.Subscriptions) > 0 {
@@ -131,4 +177,5 @@ func (s *InitializationStage) validateAndPrepareFilters(params *models.ScanParam
 	log.Debug().
 		Int("scanners_after", len(filters.Azqr.Scanners)).
 		Msg("Filters validation completed")
+	return nil
 }
@@ -4,12 +4,23 @@
 package pipeline
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/Azure/azqr/internal/models"
 	"github.com/Azure/azqr/internal/plugins"
+	"github.com/Azure/azqr/internal/progress"
 	"github.com/Azure/azqr/internal/renderers"
 	"github.com/rs/zerolog/log"
 )
 
-// PluginExecutionStage executes internal plugin scanners.
+// PluginExecutionStage executes internal and external plugin scanners.
+// Plugins run concurrently, each with its own deadline, and a failing or
+// panicking plugin does not affect the others.
 type PluginExecutionStage struct {
 	*BaseStage
 }
@@ -28,43 +39,132 @@ func (s *PluginExecutionStage) Execute(ctx *ScanContext) error {
 	pluginRegistry := plugins.GetRegistry()
 	registeredPlugins := pluginRegistry.List()
 
-	var internalPluginScanners []plugins.InternalPluginScanner
+	var enabledPlugins []*plugins.Plugin
 	for _, plugin := range registeredPlugins {
 		if plugin.InternalScanner != nil {
 			if enabled, ok := ctx.Params.EnabledInternalPlugins[plugin.Metadata.Name]; ok && enabled {
-				log.Info().
-					Str("plugin", plugin.Metadata.Name).
-					Str("version", plugin.Metadata.Version).
-					Msg("Executing internal plugin")
-				internalPluginScanners = append(internalPluginScanners, plugin.InternalScanner)
+				enabledPlugins = append(enabledPlugins, plugin)
 			}
 		}
 	}
 
-	// Execute plugins and collect results
+	concurrency := ctx.Params.PluginConcurrency
+	if concurrency < 1 {
+		concurrency = models.DefaultPluginConcurrency
+	}
+	timeout := ctx.Params.PluginTimeout
+	if timeout <= 0 {
+		timeout = models.DefaultPluginTimeout
+	}
+
+	// Execute plugins concurrently, keeping results in registry order
+	sheets := make([][]plugins.ExternalPluginOutput, len(enabledPlugins))
+	runs := make([]*renderers.PluginRun, len(enabledPlugins))
+	tracker := progress.NewTracker(ctx.Ctx, "plugins", len(enabledPlugins))
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, plugin := range enabledPlugins {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer tracker.Step("")
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			sheets[i], runs[i] = runPlugin(ctx, plugin, timeout)
+		}()
+	}
+	wg.Wait()
+
 	results := []*renderers.PluginResult{}
-	for _, pluginScanner := range internalPluginScanners {
-		pluginName := pluginScanner.GetMetadata().Name
-		sheets, err := pluginScanner.Scan(ctx.Ctx, ctx.Cred, ctx.Subscriptions, ctx.Params)
-		if err != nil {
-			log.Error().Err(err).Str("plugin", pluginName).Msg("Plugin scan failed")
-			continue
-		}
-		for _, sheet := range sheets {
+	for i, run := range runs {
+		for _, sheet := range sheets[i] {
 			results = append(results, &renderers.PluginResult{
-				PluginName:  pluginName,
+				PluginName:  run.PluginName,
 				SheetName:   sheet.SheetName,
 				Description: sheet.Description,
 				Table:       sheet.Table,
+				Columns:     sheet.ColumnFormats(),
 			})
 		}
 	}
 
 	ctx.ReportData.PluginResults = results
+	ctx.ReportData.PluginRuns = runs
 
 	log.Info().
-		Int("plugins", len(internalPluginScanners)).
+		Int("plugins", len(enabledPlugins)).
 		Msg("Plugin execution completed")
 
 	return nil
 }
+
+// pluginOutcome is the result of a plugin scan.
+type pluginOutcome struct {
+	sheets []plugins.ExternalPluginOutput
+	err    error
+}
+
+// runPlugin runs a single plugin with a deadline, recovering panics. A plugin
+// that does not return once its deadline expires is abandoned.
+func runPlugin(ctx *ScanContext, plugin *plugins.Plugin, timeout time.Duration) ([]plugins.ExternalPluginOutput, *renderers.PluginRun) {
+	name := plugin.Metadata.Name
+	log.Info().
+		Str("plugin", name).
+		Str("version", plugin.Metadata.Version).
+		Stringer("type", plugin.Metadata.Type).
+		Msg("Executing plugin")
+
+	pluginCtx, cancel := context.WithTimeout(ctx.Ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan pluginOutcome, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Debug().Str("plugin", name).Msgf("Plugin panic stack:\n%s", debug.Stack())
+				done <- pluginOutcome{err: fmt.Errorf("panic: %v", r)}
+			}
+		}()
+		sheets, err := plugin.InternalScanner.Scan(pluginCtx, ctx.Cred, ctx.Subscriptions, ctx.Params)
+		done <- pluginOutcome{sheets: sheets, err: err}
+	}()
+
+	var outcome pluginOutcome
+	select {
+	case outcome = <-done:
+	case <-pluginCtx.Done():
+		outcome.err = pluginCtx.Err()
+	}
+
+	run := &renderers.PluginRun{
+		PluginName: name,
+		Version:    plugin.Metadata.Version,
+		Duration:   time.Since(start),
+	}
+	switch {
+	case outcome.err == nil:
+		run.Status = renderers.PluginRunSucceeded
+		run.Sheets = len(outcome.sheets)
+	case errors.Is(pluginCtx.Err(), context.DeadlineExceeded):
+		run.Status = renderers.PluginRunTimedOut
+		run.Error = fmt.Sprintf("plugin did not finish within %s", timeout)
+		log.Error().Str("plugin", name).Dur("timeout", timeout).Msg("Plugin timed out")
+		return nil, run
+	default:
+		run.Status = renderers.PluginRunFailed
+		run.Error = outcome.err.Error()
+		log.Error().Err(outcome.err).Str("plugin", name).Msg("Plugin scan failed")
+		return nil, run
+	}
+
+	log.Info().
+		Str("plugin", name).
+		Dur("duration", run.Duration).
+		Int("sheets", run.Sheets).
+		Msg("Plugin completed")
+	return outcome.sheets, run
+}
@@ -0,0 +1,131 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Azure/azqr/internal/models"
+	"github.com/Azure/azqr/internal/plugins"
+	"github.com/Azure/azqr/internal/renderers"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePluginScanner is an InternalPluginScanner whose behavior is set by scan.
+type fakePluginScanner struct {
+	name string
+	scan func(ctx context.Context) ([]plugins.ExternalPluginOutput, error)
+}
+
+func (s *fakePluginScanner) GetMetadata() plugins.PluginMetadata {
+	return plugins.PluginMetadata{Name: s.name, Version: "1.0.0", Type: plugins.PluginTypeInternal}
+}
+
+func (s *fakePluginScanner) Scan(ctx context.Context, _ azcore.TokenCredential, _ map[string]string, _ *models.ScanParams) ([]plugins.ExternalPluginOutput, error) {
+	return s.scan(ctx)
+}
+
+// registerFakePlugin registers a fake plugin in the global registry.
+func registerFakePlugin(t *testing.T, name string, scan func(ctx context.Context) ([]plugins.ExternalPluginOutput, error)) {
+	t.Helper()
+	scanner := &fakePluginScanner{name: name, scan: scan}
+	require.NoError(t, plugins.GetRegistry().Register(&plugins.Plugin{Metadata: scanner.GetMetadata(), InternalScanner: scanner}))
+}
+
+func newPluginScanContext(params *models.ScanParams) *ScanContext {
+	return &ScanContext{
+		Ctx:        context.Background(),
+		Params:     params,
+		ReportData: &renderers.ReportData{},
+	}
+}
+
+func TestPluginExecutionStage_Isolation(t *testing.T) {
+	registerFakePlugin(t, "test-exec-ok", func(context.Context) ([]plugins.ExternalPluginOutput, error) {
+		return []plugins.ExternalPluginOutput{{SheetName: "OK", Table: [][]string{{"A"}, {"1"}}}}, nil
+	})
+	registerFakePlugin(t, "test-exec-error", func(context.Context) ([]plugins.ExternalPluginOutput, error) {
+		return nil, errors.New("no access")
+	})
+	registerFakePlugin(t, "test-exec-panic", func(context.Context) ([]plugins.ExternalPluginOutput, error) {
+		panic("boom")
+	})
+	registerFakePlugin(t, "test-exec-slow", func(ctx context.Context) ([]plugins.ExternalPluginOutput, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	registerFakePlugin(t, "test-exec-stuck", func(context.Context) ([]plugins.ExternalPluginOutput, error) {
+		time.Sleep(5 * time.Second) // ignores its context
+		return nil, nil
+	})
+
+	ctx := newPluginScanContext(&models.ScanParams{
+		EnabledInternalPlugins: map[string]bool{
+			"test-exec-ok": true, "test-exec-error": true, "test-exec-panic": true,
+			"test-exec-slow": true, "test-exec-stuck": true,
+		},
+		PluginTimeout: 200 * time.Millisecond,
+	})
+
+	start := time.Now()
+	require.NoError(t, NewPluginExecutionStage().Execute(ctx))
+	assert.Less(t, time.Since(start), 3*time.Second, "stuck plugins are abandoned at their deadline")
+
+	require.Len(t, ctx.ReportData.PluginResults, 1)
+	assert.Equal(t, "test-exec-ok", ctx.ReportData.PluginResults[0].PluginName)
+	assert.Equal(t, "OK", ctx.ReportData.PluginResults[0].SheetName)
+
+	runs := map[string]*renderers.PluginRun{}
+	for _, run := range ctx.ReportData.PluginRuns {
+		runs[run.PluginName] = run
+	}
+	require.Len(t, runs, 5)
+	assert.Equal(t, renderers.PluginRunSucceeded, runs["test-exec-ok"].Status)
+	assert.Equal(t, 1, runs["test-exec-ok"].Sheets)
+	assert.Equal(t, renderers.PluginRunFailed, runs["test-exec-error"].Status)
+	assert.Equal(t, "no access", runs["test-exec-error"].Error)
+	assert.Equal(t, renderers.PluginRunFailed, runs["test-exec-panic"].Status)
+	assert.Equal(t, "panic: boom", runs["test-exec-panic"].Error)
+	assert.Equal(t, renderers.PluginRunTimedOut, runs["test-exec-slow"].Status)
+	assert.Equal(t, renderers.PluginRunTimedOut, runs["test-exec-stuck"].Status)
+
+	table := ctx.ReportData.PluginRunsTable()
+	assert.Equal(t, []string{"Plugin", "Version", "Status", "Duration (s)", "Sheets", "Error"}, table[0])
+	assert.Len(t, table, 6)
+}
+
+func TestPluginExecutionStage_Concurrency(t *testing.T) {
+	var running, peak atomic.Int32
+	scan := func(context.Context) ([]plugins.ExternalPluginOutput, error) {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+		running.Add(-1)
+		return nil, nil
+	}
+
+	enabled := map[string]bool{}
+	for _, name := range []string{"test-conc-a", "test-conc-b", "test-conc-c", "test-conc-d"} {
+		registerFakePlugin(t, name, scan)
+		enabled[name] = true
+	}
+
+	ctx := newPluginScanContext(&models.ScanParams{EnabledInternalPlugins: enabled, PluginConcurrency: 2})
+	require.NoError(t, NewPluginExecutionStage().Execute(ctx))
+
+	assert.Equal(t, int32(2), peak.Load())
+	require.Len(t, ctx.ReportData.PluginRuns, 4)
+	assert.Equal(t, "test-conc-a", ctx.ReportData.PluginRuns[0].PluginName, "runs are reported in registry order")
+}
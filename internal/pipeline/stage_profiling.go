@@ -22,6 +22,11 @@ func NewProfilingStage() *ProfilingStage {
 	}
 }
 
+// CheckpointExempt implements checkpointExempt: profilers are per process.
+func (s *ProfilingStage) CheckpointExempt() bool {
+	return true
+}
+
 func (s *ProfilingStage) Execute(ctx *ScanContext) error {
 	// Check if profiling parameters are set
 	if ctx.Params.CPUProfile != "" || ctx.Params.MemProfile != "" || ctx.Params.TraceProfile != "" {
@@ -64,6 +69,11 @@ func NewProfilingCleanupStage() *ProfilingCleanupStage {
 	}
 }
 
+// CheckpointExempt implements checkpointExempt: profilers are per process.
+func (s *ProfilingCleanupStage) CheckpointExempt() bool {
+	return true
+}
+
 func (s *ProfilingCleanupStage) Execute(ctx *ScanContext) error {
 	if ctx.Profiler != nil {
 		log.Debug().Msg("Cleaning up profiling resources")
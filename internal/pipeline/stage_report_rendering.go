@@ -23,6 +23,11 @@ func NewReportRenderingStage() *ReportRenderingStage {
 	}
 }
 
+// CheckpointExempt implements checkpointExempt: reports are always re-rendered.
+func (s *ReportRenderingStage) CheckpointExempt() bool {
+	return true
+}
+
 func (s *ReportRenderingStage) Execute(ctx *ScanContext) error {
 	log.Info().Msg("Starting report rendering")
 
@@ -38,24 +43,33 @@ func (s *ReportRenderingStage) Execute(ctx *ScanContext) error {
 	// Generate Excel report
 	if ctx.Params.Xlsx {
 		log.Info().Msg("Generating Excel report")
-		excel.CreateExcelReport(ctx.ReportData)
+		if err := excel.CreateExcelReport(ctx.ReportData); err != nil {
+			return err
+		}
 	}
 
 	// Generate JSON report
 	if ctx.Params.Json {
 		log.Info().Msg("Generating JSON report")
-		json.CreateJsonReport(ctx.ReportData)
+		if err := json.CreateJsonReport(ctx.ReportData); err != nil {
+			return err
+		}
 	}
 
 	// Generate CSV report
 	if ctx.Params.Csv {
 		log.Info().Msg("Generating CSV report")
-		csv.CreateCsvReport(ctx.ReportData)
+		if err := csv.CreateCsvReport(ctx.ReportData); err != nil {
+			return err
+		}
 	}
 
 	// Generate JSON output for stdout
 	if ctx.Params.Stdout {
-		outputJson := json.CreateJsonOutput(ctx.ReportData)
+		outputJson, err := json.CreateJsonOutput(ctx.ReportData)
+		if err != nil {
+			return err
+		}
 		fmt.Println(outputJson)
 	}
 
@@ -58,6 +58,20 @@ func NewArcSQLStage() Stage {
 	}
 }
 
+// NewQuotaStage creates the quota headroom scan stage. It runs for every
+// subscription and region holding discovered resources.
+func NewQuotaStage() Stage {
+	return &simpleStage[[]*models.QuotaResult]{
+		BaseStage: NewBaseStage("Quota Scan", false),
+		stageName: models.StageNameQuota,
+		run: func(ctx *ScanContext) []*models.QuotaResult {
+			threshold, _ := ctx.Params.Stages.GetStageOptionsWithDefaults(models.StageNameQuota)["threshold"].(float64)
+			return (&scanners.QuotaScanner{}).Scan(ctx.Ctx, ctx.Cred, ctx.ClientOptions, ctx.Subscriptions, ctx.ReportData.Resources, threshold)
+		},
+		assign: func(rd *renderers.ReportData, r []*models.QuotaResult) { rd.Quota = r },
+	}
+}
+
 // NewAzurePolicyStage creates the Azure Policy scan stage.
 func NewAzurePolicyStage() Stage {
 	return &simpleStage[[]*models.AzurePolicyResult]{
@@ -70,6 +84,33 @@ func NewAzurePolicyStage() Stage {
 	}
 }
 
+// NewAzurePolicyComplianceStage creates the Azure Policy compliance roll-up
+// scan stage. It runs with the Azure Policy stage.
+func NewAzurePolicyComplianceStage() Stage {
+	return &simpleStage[[]*models.AzurePolicyComplianceResult]{
+		BaseStage: NewBaseStage("Azure Policy Compliance Scan", false),
+		stageName: models.StageNamePolicy,
+		run: func(ctx *ScanContext) []*models.AzurePolicyComplianceResult {
+			return (&scanners.AzurePolicyScanner{}).ScanCompliance(ctx.Ctx, ctx.Cred, ctx.Subscriptions, ctx.Params.Filters)
+		},
+		assign: func(rd *renderers.ReportData, r []*models.AzurePolicyComplianceResult) { rd.AzurePolicyCompliance = r },
+	}
+}
+
+// NewAzurePolicyExemptionsStage creates the Azure Policy exemptions scan stage.
+// It runs with the Azure Policy stage.
+func NewAzurePolicyExemptionsStage() Stage {
+	return &simpleStage[[]*models.AzurePolicyExemptionResult]{
+		BaseStage: NewBaseStage("Azure Policy Exemptions Scan", false),
+		stageName: models.StageNamePolicy,
+		run: func(ctx *ScanContext) []*models.AzurePolicyExemptionResult {
+			expiryDays, _ := ctx.Params.Stages.GetStageOptionsWithDefaults(models.StageNamePolicy)["expiry-days"].(int)
+			return (&scanners.AzurePolicyScanner{}).ScanExemptions(ctx.Ctx, ctx.Cred, ctx.Subscriptions, ctx.Params.Filters, expiryDays)
+		},
+		assign: func(rd *renderers.ReportData, r []*models.AzurePolicyExemptionResult) { rd.AzurePolicyExemptions = r },
+	}
+}
+
 // NewDefenderStatusStage creates the Defender status scan stage.
 func NewDefenderStatusStage() Stage {
 	return &simpleStage[[]*models.DefenderResult]{
@@ -82,6 +123,19 @@ func NewDefenderStatusStage() Stage {
 	}
 }
 
+// NewDefenderSecureScoreStage creates the Defender secure score scan stage. It
+// runs with the Defender status stage.
+func NewDefenderSecureScoreStage() Stage {
+	return &simpleStage[[]*models.SecureScoreResult]{
+		BaseStage: NewBaseStage("Defender Secure Score Scan", false),
+		stageName: models.StageNameDefender,
+		run: func(ctx *ScanContext) []*models.SecureScoreResult {
+			return (&scanners.DefenderScanner{}).GetSecureScores(ctx.Ctx, ctx.Cred, ctx.Subscriptions, ctx.Params.Filters)
+		},
+		assign: func(rd *renderers.ReportData, r []*models.SecureScoreResult) { rd.SecureScores = r },
+	}
+}
+
 // NewDefenderRecommendationsStage creates the Defender recommendations scan stage.
 func NewDefenderRecommendationsStage() Stage {
 	return &simpleStage[[]*models.DefenderRecommendation]{
@@ -22,9 +22,10 @@ func NewSubscriptionDiscoveryStage() *SubscriptionDiscoveryStage {
 func (s *SubscriptionDiscoveryStage) Execute(ctx *ScanContext) error {
 	params := ctx.Params
 
+	var err error
 	if len(params.ManagementGroups) > 0 {
 		scanner := scanners.ManagementGroupDiscovery{}
-		ctx.Subscriptions = scanner.ListSubscriptions(
+		ctx.Subscriptions, err = scanner.ListSubscriptions(
 			ctx.Ctx,
 			ctx.Cred,
 			params.ManagementGroups,
@@ -33,7 +34,7 @@ func (s *SubscriptionDiscoveryStage) Execute(ctx *ScanContext) error {
 		)
 	} else {
 		scanner := scanners.SubcriptionDiscovery{}
-		ctx.Subscriptions = scanner.ListSubscriptions(
+		ctx.Subscriptions, err = scanner.ListSubscriptions(
 			ctx.Ctx,
 			ctx.Cred,
 			params.Subscriptions,
@@ -41,6 +42,9 @@ func (s *SubscriptionDiscoveryStage) Execute(ctx *ScanContext) error {
 			ctx.ClientOptions,
 		)
 	}
+	if err != nil {
+		return err
+	}
 
 	log.Info().
 		Int("subscriptions", len(ctx.Subscriptions)).
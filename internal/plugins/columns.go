@@ -0,0 +1,51 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package plugins
+
+import (
+	"fmt"
+
+	"github.com/Azure/azqr/internal/models"
+)
+
+// validateColumns checks that every column declares a supported type.
+func validateColumns(columns []ColumnMetadata) error {
+	for _, col := range columns {
+		if err := models.ValidateColumnType(col.Type); err != nil {
+			return fmt.Errorf("column %q: %w", col.Name, err)
+		}
+	}
+	return nil
+}
+
+// ColumnFormats returns the format of each column of the output table, matched
+// by header name against the sheet's Columns and then the plugin's
+// ColumnMetadata. It returns nil when no column declares a type.
+func (o ExternalPluginOutput) ColumnFormats() []models.ColumnFormat {
+	if len(o.Table) == 0 {
+		return nil
+	}
+
+	declared := make(map[string]ColumnMetadata, len(o.Columns)+len(o.Metadata.ColumnMetadata))
+	for _, col := range o.Metadata.ColumnMetadata {
+		declared[col.Name] = col
+	}
+	for _, col := range o.Columns {
+		declared[col.Name] = col
+	}
+
+	formats := make([]models.ColumnFormat, len(o.Table[0]))
+	typed := false
+	for i, header := range o.Table[0] {
+		col := declared[header]
+		formats[i] = models.ColumnFormat{Name: header, Type: col.Type, Unit: col.Unit}
+		if col.Type != models.ColumnTypeText {
+			typed = true
+		}
+	}
+	if !typed {
+		return nil
+	}
+	return formats
+}
@@ -0,0 +1,46 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package plugins
+
+import (
+	"testing"
+
+	"github.com/Azure/azqr/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExternalPluginOutput_ColumnFormats(t *testing.T) {
+	output := ExternalPluginOutput{
+		Metadata: PluginMetadata{
+			ColumnMetadata: []ColumnMetadata{
+				{Name: "Name"},
+				{Name: "Cost", Type: models.ColumnTypeCurrency, Unit: "USD"},
+				{Name: "Date", Type: models.ColumnTypeDate},
+			},
+		},
+		Columns: []ColumnMetadata{
+			{Name: "Cost", Type: models.ColumnTypeCurrency, Unit: "EUR"},
+		},
+		Table: [][]string{{"Date", "Name", "Cost", "Extra"}},
+	}
+
+	assert.Equal(t, []models.ColumnFormat{
+		{Name: "Date", Type: models.ColumnTypeDate},
+		{Name: "Name"},
+		{Name: "Cost", Type: models.ColumnTypeCurrency, Unit: "EUR"},
+		{Name: "Extra"},
+	}, output.ColumnFormats(), "columns are matched by header, sheet columns win")
+
+	untyped := ExternalPluginOutput{
+		Metadata: PluginMetadata{ColumnMetadata: []ColumnMetadata{{Name: "Name"}}},
+		Table:    [][]string{{"Name"}},
+	}
+	assert.Nil(t, untyped.ColumnFormats())
+	assert.Nil(t, ExternalPluginOutput{}.ColumnFormats())
+}
+
+func TestValidateColumns(t *testing.T) {
+	assert.NoError(t, validateColumns([]ColumnMetadata{{Name: "A"}, {Name: "B", Type: models.ColumnTypePercent}}))
+	assert.Error(t, validateColumns([]ColumnMetadata{{Name: "A", Type: "money"}}))
+}
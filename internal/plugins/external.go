@@ -0,0 +1,440 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package plugins
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/Azure/azqr/internal/az"
+	"github.com/Azure/azqr/internal/models"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/rs/zerolog/log"
+)
+
+// External plugins are executables shipped in installed plugin packages and
+// declared in the executables section of the package manifest. They are
+// registered from the manifest at startup and only run when the plugin is
+// invoked, after checking the executable against its manifest checksum.
+// azqr talks to them with a single JSON request on stdin and a single JSON
+// response on stdout:
+//
+//	{"protocolVersion":1,"action":"metadata"}
+//	  -> {"protocolVersion":1,"metadata":{"name":"...","version":"...","columns":[{"name":"...","type":"number","unit":"ms"}]}}
+//
+//	{"protocolVersion":1,"action":"scan","subscriptions":{"<id>":"<name>"},"options":{...},
+//	 "accessToken":"...","resourceManagerEndpoint":"https://management.azure.com"}
+//	  -> {"protocolVersion":1,"outputs":[{"sheet_name":"...","description":"...","table":[["h1"],["v1"]]}]}
+//
+// Teams developing a plugin can opt in to running azqr-plugin-* executables
+// outside packages by listing their directories in AZQR_TRUSTED_PLUGIN_DIRS.
+// These are checksummed when discovered and, like packaged executables, only
+// run when the plugin is invoked.
+//
+// Column types (number, percent, currency, date, url, enum) control report
+// formatting; an output may set "columns" to override them for its sheet.
+// A response may set "error" to fail the whole request. Anything written to
+// stderr is logged at debug level.
+const (
+	// ExternalProtocolVersion is the protocol version spoken by azqr
+	ExternalProtocolVersion = 1
+
+	externalActionMetadata = "metadata"
+	externalActionScan     = "scan"
+)
+
+// ExternalPluginPrefix is the file name prefix of the external plugin
+// executables discovered in trusted plugin directories.
+const ExternalPluginPrefix = "azqr-plugin-"
+
+// TrustedPluginDirsEnv names the environment variable listing, separated like
+// PATH, the trusted directories searched for azqr-plugin-* executables.
+const TrustedPluginDirsEnv = "AZQR_TRUSTED_PLUGIN_DIRS"
+
+// ExternalMetadataTimeout bounds the metadata handshake run before a scan.
+// Scans are bounded by the plugin timeout of the calling context.
+var ExternalMetadataTimeout = 10 * time.Second
+
+// externalRequest is the JSON document written to an external plugin's stdin.
+type externalRequest struct {
+	ProtocolVersion         int               `json:"protocolVersion"`
+	Action                  string            `json:"action"`
+	Subscriptions           map[string]string `json:"subscriptions,omitempty"`
+	Options                 map[string]any    `json:"options,omitempty"`
+	AccessToken             string            `json:"accessToken,omitempty"`
+	ResourceManagerEndpoint string            `json:"resourceManagerEndpoint,omitempty"`
+}
+
+// externalResponse is the JSON document an external plugin writes to stdout.
+type externalResponse struct {
+	ProtocolVersion int                    `json:"protocolVersion"`
+	Metadata        *externalMetadata      `json:"metadata,omitempty"`
+	Outputs         []ExternalPluginOutput `json:"outputs,omitempty"`
+	Error           string                 `json:"error,omitempty"`
+}
+
+// externalMetadata is the wire form of PluginMetadata.
+type externalMetadata struct {
+	Name        string           `json:"name"`
+	Version     string           `json:"version"`
+	Description string           `json:"description"`
+	Author      string           `json:"author,omitempty"`
+	License     string           `json:"license,omitempty"`
+	Columns     []ColumnMetadata `json:"columns,omitempty"`
+	Options     []PluginOption   `json:"options,omitempty"`
+}
+
+// externalScanner runs an external plugin executable. It implements
+// InternalPluginScanner so the plugin execution stage treats it like a
+// compiled-in plugin.
+type externalScanner struct {
+	path     string
+	sha256   string
+	metadata PluginMetadata
+}
+
+// GetMetadata implements InternalPluginScanner.
+func (s *externalScanner) GetMetadata() PluginMetadata {
+	return s.metadata
+}
+
+// Scan implements InternalPluginScanner by sending a scan request to the executable.
+func (s *externalScanner) Scan(ctx context.Context, cred azcore.TokenCredential, subscriptions map[string]string, params *models.ScanParams) ([]ExternalPluginOutput, error) {
+	if err := s.verify(); err != nil {
+		return nil, err
+	}
+	metadata, err := s.handshake(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := az.GetResourceManagerEndpoint()
+	token, err := cred.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{endpoint + "/.default"}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token for plugin %s: %w", metadata.Name, err)
+	}
+
+	var stages *models.StageConfigs
+	if params != nil {
+		stages = params.Stages
+	}
+	options := stages.GetPluginOptions(metadata.Name)
+
+	resp, err := runExternalPlugin(ctx, s.path, externalRequest{
+		ProtocolVersion:         ExternalProtocolVersion,
+		Action:                  externalActionScan,
+		Subscriptions:           subscriptions,
+		Options:                 options,
+		AccessToken:             token.Token,
+		ResourceManagerEndpoint: endpoint,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	outputs := make([]ExternalPluginOutput, 0, len(resp.Outputs))
+	for _, out := range resp.Outputs {
+		if out.Error != "" {
+			log.Warn().Str("plugin", metadata.Name).Str("sheet", out.SheetName).Msgf("Plugin reported an error: %s", out.Error)
+			continue
+		}
+		if out.SheetName == "" {
+			out.SheetName = metadata.Name
+		}
+		if err := validateColumns(out.Columns); err != nil {
+			log.Warn().Str("plugin", metadata.Name).Str("sheet", out.SheetName).Err(err).Msg("Ignoring invalid column types")
+			out.Columns = nil
+		}
+		out.Metadata = metadata
+		outputs = append(outputs, out)
+	}
+	return outputs, nil
+}
+
+// verify checks the executable against the checksum listed in its package
+// manifest, or taken when it was discovered in a trusted directory, so a file
+// replaced after installation or discovery is never run.
+func (s *externalScanner) verify() error {
+	sum, err := fileSHA256(s.path)
+	if err != nil {
+		return fmt.Errorf("plugin %s: %w", s.metadata.Name, err)
+	}
+	if !strings.EqualFold(sum, s.sha256) {
+		return fmt.Errorf("plugin %s: executable does not match its package checksum", s.metadata.Name)
+	}
+	return nil
+}
+
+// handshake asks the executable for its metadata and returns the plugin
+// metadata completed with the reported version and columns.
+func (s *externalScanner) handshake(ctx context.Context) (PluginMetadata, error) {
+	metadata := s.metadata
+	ctx, cancel := context.WithTimeout(ctx, ExternalMetadataTimeout)
+	defer cancel()
+
+	resp, err := runExternalPlugin(ctx, s.path, externalRequest{
+		ProtocolVersion: ExternalProtocolVersion,
+		Action:          externalActionMetadata,
+	})
+	if err != nil {
+		return metadata, err
+	}
+	if resp.Metadata == nil {
+		return metadata, fmt.Errorf("plugin %s returned no metadata", s.metadata.Name)
+	}
+
+	md := resp.Metadata
+	if err := validateColumns(md.Columns); err != nil {
+		return metadata, fmt.Errorf("plugin %s: %w", s.metadata.Name, err)
+	}
+	if md.Version != "" {
+		metadata.Version = md.Version
+	}
+	if md.Author != "" {
+		metadata.Author = md.Author
+	}
+	if md.License != "" {
+		metadata.License = md.License
+	}
+	metadata.ColumnMetadata = md.Columns
+	return metadata, nil
+}
+
+// newExternalPlugin returns the plugin for an executable declared in a
+// verified package, with its command, ready to be registered. The
+// executable is not run.
+func newExternalPlugin(dir string, manifest *PackageManifest, exe PackageExecutable) (*Plugin, error) {
+	path, err := packageFilePath(dir, exe.Path)
+	if err != nil {
+		return nil, fmt.Errorf("package %s: %w", manifest.Name, err)
+	}
+
+	description := exe.Description
+	if description == "" {
+		description = manifest.Description
+	}
+	return newExternalScannerPlugin(path, manifest.fileChecksum(exe.Path), PluginMetadata{
+		Name:        exe.Name,
+		Version:     manifest.Version,
+		Description: description,
+		Options:     exe.Options,
+	}), nil
+}
+
+// newExternalScannerPlugin returns the plugin running the executable at path,
+// which must match the sha256 checksum.
+func newExternalScannerPlugin(path, sha256 string, metadata PluginMetadata) *Plugin {
+	metadata.Type = PluginTypeExternal
+	metadata.CommandPath = path
+	scanner := &externalScanner{
+		path:     path,
+		sha256:   sha256,
+		metadata: metadata,
+	}
+	return &Plugin{
+		Metadata:        scanner.metadata,
+		InternalScanner: scanner,
+		Command:         createPluginCommand(metadata.Name, metadata.Description),
+	}
+}
+
+// runExternalPlugin executes the plugin with the request on stdin and decodes
+// its response. The plugin is killed when ctx is done.
+func runExternalPlugin(ctx context.Context, path string, req externalRequest) (*externalResponse, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode plugin request: %w", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, path) //nolint:gosec // path is a checksum verified executable of an installed package
+	cmd.Stdin = bytes.NewReader(body)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = time.Second
+
+	runErr := cmd.Run()
+	if stderr.Len() > 0 {
+		log.Debug().Str("plugin", path).Msg(strings.TrimSpace(stderr.String()))
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("plugin %s timed out", path)
+	}
+	if runErr != nil {
+		return nil, fmt.Errorf("plugin %s failed: %w: %s", path, runErr, lastLine(stderr.String()))
+	}
+
+	var resp externalResponse
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		return nil, fmt.Errorf("plugin %s returned invalid JSON: %w", path, err)
+	}
+	if resp.ProtocolVersion != ExternalProtocolVersion {
+		return nil, fmt.Errorf("plugin %s speaks protocol version %d, expected %d", path, resp.ProtocolVersion, ExternalProtocolVersion)
+	}
+	if resp.Error != "" {
+		return nil, fmt.Errorf("plugin %s: %s", path, resp.Error)
+	}
+	return &resp, nil
+}
+
+// discoverExternalPlugins returns the executables declared by the packages
+// installed in dir. Packages failing verification are skipped. No
+// executable is run.
+func discoverExternalPlugins(dir string) []*Plugin {
+	plugins := make([]*Plugin, 0)
+	seen := make(map[string]bool)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return plugins
+	}
+	trustedKeys, err := LoadTrustedKeys(getTrustedKeysDir())
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to load trusted plugin keys")
+	}
+
+	for _, entry := range entries {
+		pkgDir := filepath.Join(dir, entry.Name())
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || !IsPackageDir(pkgDir) {
+			continue
+		}
+		if manifest, _, err := ReadPackageManifest(pkgDir); err != nil || len(manifest.Executables) == 0 {
+			continue
+		}
+		result, err := VerifyPackage(pkgDir, trustedKeys, false)
+		if err != nil {
+			log.Warn().Err(err).Str("path", pkgDir).Msg("Skipping external plugins of a package that failed verification")
+			continue
+		}
+
+		for _, exe := range result.Manifest.Executables {
+			// Skip duplicates (first found wins)
+			if seen[exe.Name] {
+				log.Debug().
+					Str("plugin", exe.Name).
+					Str("package", result.Manifest.Name).
+					Msg("Skipping duplicate external plugin")
+				continue
+			}
+			plugin, err := newExternalPlugin(pkgDir, result.Manifest, exe)
+			if err != nil {
+				log.Warn().Err(err).Str("plugin", exe.Name).Msg("Skipping external plugin")
+				continue
+			}
+			seen[exe.Name] = true
+
+			plugins = append(plugins, plugin)
+			log.Debug().
+				Str("plugin", exe.Name).
+				Str("path", plugin.Metadata.CommandPath).
+				Msg("Discovered external plugin")
+		}
+	}
+	return plugins
+}
+
+// getTrustedPluginDirs returns the directories listed in AZQR_TRUSTED_PLUGIN_DIRS.
+func getTrustedPluginDirs() []string {
+	dirs := make([]string, 0)
+	for _, dir := range filepath.SplitList(os.Getenv(TrustedPluginDirsEnv)) {
+		if dir = strings.TrimSpace(dir); dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// discoverTrustedExternalPlugins returns the azqr-plugin-* executables of the
+// trusted directories (not searched recursively). The plugin name is the file
+// name without prefix and extension. Executables writable by other users are
+// skipped. No executable is run.
+func discoverTrustedExternalPlugins(dirs []string) []*Plugin {
+	plugins := make([]*Plugin, 0)
+	seen := make(map[string]bool)
+
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			log.Warn().Err(err).Str("path", dir).Msg("Failed to read trusted plugin directory")
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasPrefix(entry.Name(), ExternalPluginPrefix) {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			if !isExecutable(path, entry) {
+				continue
+			}
+			if info, err := entry.Info(); err == nil && runtime.GOOS != "windows" && info.Mode().Perm()&0022 != 0 {
+				log.Warn().Str("path", path).Msg("Skipping external plugin writable by other users")
+				continue
+			}
+
+			name := externalPluginName(path)
+			if !packageNamePattern.MatchString(name) {
+				log.Warn().Str("path", path).Msgf("Skipping external plugin with invalid name %q", name)
+				continue
+			}
+			// Skip duplicates (first found wins)
+			if seen[name] {
+				log.Debug().
+					Str("plugin", name).
+					Str("path", path).
+					Msg("Skipping duplicate external plugin")
+				continue
+			}
+			sum, err := fileSHA256(path)
+			if err != nil {
+				log.Warn().Err(err).Str("path", path).Msg("Skipping external plugin")
+				continue
+			}
+			seen[name] = true
+
+			plugins = append(plugins, newExternalScannerPlugin(path, sum, PluginMetadata{
+				Name:        name,
+				Description: fmt.Sprintf("External plugin %s", entry.Name()),
+			}))
+			log.Debug().
+				Str("plugin", name).
+				Str("path", path).
+				Msg("Discovered external plugin in trusted directory")
+		}
+	}
+	return plugins
+}
+
+// externalPluginName derives a plugin name from its executable file name.
+func externalPluginName(path string) string {
+	name := strings.TrimPrefix(filepath.Base(path), ExternalPluginPrefix)
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+// isExecutable reports whether the directory entry is an executable file.
+func isExecutable(path string, entry os.DirEntry) bool {
+	if runtime.GOOS == "windows" {
+		return strings.EqualFold(filepath.Ext(path), ".exe")
+	}
+	info, err := entry.Info()
+	if err != nil {
+		return false
+	}
+	return info.Mode().IsRegular() && info.Mode().Perm()&0111 != 0
+}
+
+// lastLine returns the last non-empty line of s, for concise error messages.
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
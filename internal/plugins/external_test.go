@@ -0,0 +1,257 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package plugins
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/Azure/azqr/internal/models"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type externalTestCredential struct{}
+
+func (externalTestCredential) GetToken(context.Context, policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: "test-token", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+// externalPluginScript returns a shell script plugin answering both protocol actions.
+func externalPluginScript(t *testing.T, scanResponse string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script plugins are not supported on Windows")
+	}
+	return `#!/bin/sh
+req=$(cat)
+case "$req" in
+  *'"action":"metadata"'*)
+    echo '{"protocolVersion":1,"metadata":{"name":"ignored","version":"0.2.0","columns":[{"name":"Subscription"},{"name":"Token"}]}}'
+    ;;
+  *)
+` + scanResponse + `
+    ;;
+esac
+`
+}
+
+// writeExternalPackage writes a package declaring a script plugin named name
+// to dir and returns the registered-ready plugin.
+func writeExternalPackage(t *testing.T, dir, name, scanResponse string) *Plugin {
+	t.Helper()
+	writeExternalPackageFiles(t, dir, name, scanResponse)
+	result, err := VerifyPackage(dir, nil, false)
+	require.NoError(t, err)
+	plugin, err := newExternalPlugin(dir, result.Manifest, result.Manifest.Executables[0])
+	require.NoError(t, err)
+	return plugin
+}
+
+// writeExternalPackageFiles writes the files and manifest of a package declaring a script plugin.
+func writeExternalPackageFiles(t *testing.T, dir, name, scanResponse string) {
+	t.Helper()
+	script := externalPluginScript(t, scanResponse)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "bin"), 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bin", name), []byte(script), 0700)) //nolint:gosec // test plugin must be executable
+	sum := sha256.Sum256([]byte(script))
+	manifest := PackageManifest{
+		Name:        name,
+		Version:     "1.0.0",
+		Description: "Test external plugin",
+		Files:       []PackageFile{{Path: "bin/" + name, SHA256: hex.EncodeToString(sum[:])}},
+		Executables: []PackageExecutable{{
+			Name:    name,
+			Path:    "bin/" + name,
+			Options: []PluginOption{{Name: "days", Type: "int", Default: float64(30)}},
+		}},
+	}
+	data, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, PackageManifestFile), data, 0600))
+}
+
+func TestNewExternalPlugin_DoesNotRunExecutable(t *testing.T) {
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "ran")
+	plugin := writeExternalPackage(t, dir, "ext-lazy", `touch `+marker+`; echo '{}'`)
+
+	assert.Equal(t, "ext-lazy", plugin.Metadata.Name)
+	assert.Equal(t, "1.0.0", plugin.Metadata.Version)
+	assert.Equal(t, "Test external plugin", plugin.Metadata.Description)
+	assert.Equal(t, PluginTypeExternal, plugin.Metadata.Type)
+	assert.Equal(t, filepath.Join(dir, "bin", "ext-lazy"), plugin.Metadata.CommandPath)
+	assert.Equal(t, []PluginOption{{Name: "days", Type: "int", Default: float64(30)}}, plugin.Metadata.Options)
+	require.NotNil(t, plugin.Command)
+	assert.Equal(t, "ext-lazy", plugin.Command.Name())
+	assert.NotNil(t, plugin.Command.Flags().Lookup("plugin-timeout"))
+	assert.Nil(t, plugin.Command.Flags().Lookup("timeout"))
+	assert.NoFileExists(t, marker)
+}
+
+func TestExternalScanner_Scan(t *testing.T) {
+	// The scan echoes the subscription and token it received.
+	plugin := writeExternalPackage(t, t.TempDir(), "ext-scan", `
+    sub=$(echo "$req" | sed 's/.*"subscriptions":{"\([^"]*\)".*/\1/')
+    tok=$(echo "$req" | sed 's/.*"accessToken":"\([^"]*\)".*/\1/')
+    echo "{\"protocolVersion\":1,\"outputs\":[{\"sheet_name\":\"Ext\",\"description\":\"d\",\"table\":[[\"Subscription\",\"Token\"],[\"$sub\",\"$tok\"]]},{\"sheet_name\":\"Bad\",\"error\":\"boom\"}]}"`)
+
+	params := &models.ScanParams{Stages: models.NewStageConfigs()}
+	outputs, err := plugin.InternalScanner.Scan(context.Background(), externalTestCredential{}, map[string]string{"sub-1": "Sub One"}, params)
+	require.NoError(t, err)
+
+	require.Len(t, outputs, 1, "sheets reporting an error are dropped")
+	assert.Equal(t, "Ext", outputs[0].SheetName)
+	assert.Equal(t, [][]string{{"Subscription", "Token"}, {"sub-1", "test-token"}}, outputs[0].Table)
+	assert.Equal(t, "ext-scan", outputs[0].Metadata.Name, "the manifest name wins over the reported one")
+	assert.Equal(t, "0.2.0", outputs[0].Metadata.Version)
+	assert.Equal(t, []string{"Subscription", "Token"}, outputs[0].Metadata.HeaderRow())
+}
+
+func TestExternalScanner_RejectsModifiedExecutable(t *testing.T) {
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "ran")
+	plugin := writeExternalPackage(t, dir, "ext-tampered", `echo '{}'`)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bin", "ext-tampered"), []byte("#!/bin/sh\ntouch "+marker+"\n"), 0700)) //nolint:gosec // test plugin must be executable
+
+	_, err := plugin.InternalScanner.Scan(context.Background(), externalTestCredential{}, nil, nil)
+	assert.ErrorContains(t, err, "does not match its package checksum")
+	assert.NoFileExists(t, marker)
+}
+
+func TestExternalScanner_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		wantErr  string
+	}{
+		{name: "error response", response: `echo '{"protocolVersion":1,"error":"no access"}'`, wantErr: "no access"},
+		{name: "invalid json", response: `echo 'not json'`, wantErr: "invalid JSON"},
+		{name: "protocol mismatch", response: `echo '{"protocolVersion":2}'`, wantErr: "protocol version 2"},
+		{name: "non-zero exit", response: `echo 'bad credentials' >&2; exit 3`, wantErr: "bad credentials"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := writeExternalPackage(t, t.TempDir(), "ext-errors", tt.response)
+
+			_, err := plugin.InternalScanner.Scan(context.Background(), externalTestCredential{}, nil, nil)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestExternalScanner_Timeout(t *testing.T) {
+	plugin := writeExternalPackage(t, t.TempDir(), "ext-slow", `exec sleep 5`)
+	// The plugin is bounded by the deadline of the plugin execution context.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := plugin.InternalScanner.Scan(ctx, externalTestCredential{}, nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timed out")
+	assert.Less(t, time.Since(start), 3*time.Second)
+}
+
+func TestDiscoverExternalPlugins(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "ran")
+	writeExternalPackageFiles(t, filepath.Join(dir, "ext-found"), "ext-found", `echo '{}'`)
+
+	// A package failing verification and a loose executable are skipped.
+	writeExternalPackageFiles(t, filepath.Join(dir, "ext-bad"), "ext-bad", `echo '{}'`)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ext-bad", "bin", "ext-bad"), []byte("#!/bin/sh\n"), 0700))           //nolint:gosec // test file
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "azqr-plugin-loose"), []byte("#!/bin/sh\ntouch "+marker+"\n"), 0700)) //nolint:gosec // test file
+
+	found := discoverExternalPlugins(dir)
+	require.Len(t, found, 1)
+	assert.Equal(t, "ext-found", found[0].Metadata.Name)
+	assert.NoFileExists(t, marker, "discovery does not run executables")
+
+	assert.Empty(t, discoverExternalPlugins(filepath.Join(dir, "missing")))
+}
+
+func TestDiscoverTrustedExternalPlugins(t *testing.T) {
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "ran")
+	script := externalPluginScript(t, `echo '{"protocolVersion":1,"outputs":[{"table":[["Subscription"],["sub1"]]}]}'`)
+	trusted := filepath.Join(dir, "azqr-plugin-trusted.sh")
+	require.NoError(t, os.WriteFile(trusted, []byte(script+"touch "+marker+"\n"), 0700)) //nolint:gosec // test plugin must be executable
+	require.NoError(t, os.Chmod(trusted, 0700))
+
+	// Not executable, not prefixed and writable by other users: skipped.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "azqr-plugin-data"), []byte("data"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "other-tool"), []byte(script), 0700)) //nolint:gosec // test file
+	shared := filepath.Join(dir, "azqr-plugin-shared")
+	require.NoError(t, os.WriteFile(shared, []byte(script), 0700)) //nolint:gosec // test file
+	require.NoError(t, os.Chmod(shared, 0777))                     //nolint:gosec // test file writable by others
+
+	t.Setenv(TrustedPluginDirsEnv, filepath.Join(dir, "missing")+string(os.PathListSeparator)+dir)
+	found := discoverTrustedExternalPlugins(getTrustedPluginDirs())
+	require.Len(t, found, 1)
+	assert.Equal(t, "trusted", found[0].Metadata.Name)
+	assert.Equal(t, PluginTypeExternal, found[0].Metadata.Type)
+	assert.NoFileExists(t, marker, "discovery does not run executables")
+
+	outputs, err := found[0].InternalScanner.Scan(context.Background(), externalTestCredential{}, map[string]string{"sub1": "Sub 1"}, nil)
+	require.NoError(t, err)
+	require.Len(t, outputs, 1)
+	assert.Equal(t, "0.2.0", outputs[0].Metadata.Version)
+
+	// An executable changed after discovery is not run.
+	require.NoError(t, os.WriteFile(trusted, []byte(script+"# changed\n"), 0700)) //nolint:gosec // test file
+	_, err = found[0].InternalScanner.Scan(context.Background(), externalTestCredential{}, nil, nil)
+	assert.ErrorContains(t, err, "checksum")
+
+	t.Setenv(TrustedPluginDirsEnv, "")
+	assert.Empty(t, discoverTrustedExternalPlugins(getTrustedPluginDirs()))
+}
+
+func TestReadPackageManifest_Executables(t *testing.T) {
+	dir := t.TempDir()
+	writePackage(t, dir, "rules", "1.0.0", testPackageFiles("rules"), nil)
+	manifestPath := filepath.Join(dir, PackageManifestFile)
+	data, err := os.ReadFile(manifestPath) //nolint:gosec // test file
+	require.NoError(t, err)
+	var manifest PackageManifest
+	require.NoError(t, json.Unmarshal(data, &manifest))
+
+	manifest.Executables = []PackageExecutable{{Name: "rules-exe", Path: "bin/unlisted"}}
+	data, err = json.Marshal(manifest)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(manifestPath, data, 0600))
+
+	_, _, err = ReadPackageManifest(dir)
+	assert.ErrorContains(t, err, "not listed in files")
+}
+
+func TestPackageManager_InstallExecutable(t *testing.T) {
+	manager := newTestManager(t)
+	source := t.TempDir()
+	writeExternalPackageFiles(t, source, "ext-install", `echo '{}'`)
+	require.NoError(t, os.Chmod(filepath.Join(source, "bin", "ext-install"), 0600))
+
+	_, err := manager.Install(context.Background(), source, "", false)
+	require.NoError(t, err)
+
+	info, err := os.Stat(filepath.Join(manager.Dir, "ext-install", "bin", "ext-install"))
+	require.NoError(t, err)
+	assert.NotZero(t, info.Mode().Perm()&0100, "declared executables are installed executable")
+
+	found := discoverExternalPlugins(manager.Dir)
+	require.Len(t, found, 1)
+	assert.Equal(t, "ext-install", found[0].Metadata.Name)
+}
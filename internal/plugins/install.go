@@ -0,0 +1,552 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package plugins
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// maxPackageSize bounds the size of a downloaded or extracted plugin package
+const maxPackageSize = 100 << 20
+
+// PackageManager installs, updates, removes and verifies plugin packages.
+type PackageManager struct {
+	// Dir is the directory packages are installed in (~/.azqr/plugins)
+	Dir string
+	// TrustedKeysDir holds the ed25519 public keys packages may be signed with (~/.azqr/trusted-keys)
+	TrustedKeysDir string
+	// AzqrVersion is checked against the azqrVersion constraint of package manifests
+	AzqrVersion string
+	// RequireSignature rejects packages without a manifest signature. Packages
+	// downloaded from a URL always require one.
+	RequireSignature bool
+	// HTTPClient downloads packages from URLs
+	HTTPClient *http.Client
+}
+
+// InstalledPackage is a package found in the install directory
+type InstalledPackage struct {
+	Manifest *PackageManifest
+	Install  PackageInstall
+	Dir      string
+}
+
+// NewPackageManager returns a package manager for the user's plugin directory.
+func NewPackageManager(azqrVersion string) *PackageManager {
+	return &PackageManager{
+		Dir:            getPackageDir(),
+		TrustedKeysDir: getTrustedKeysDir(),
+		AzqrVersion:    azqrVersion,
+		HTTPClient:     &http.Client{Timeout: 5 * time.Minute},
+	}
+}
+
+// getPackageDir returns the directory plugin packages are installed in
+func getPackageDir() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".azqr", "plugins")
+}
+
+// getTrustedKeysDir returns the directory of trusted package signing keys
+func getTrustedKeysDir() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".azqr", "trusted-keys")
+}
+
+// Install installs a package from a directory, a .zip/.tar.gz archive or an
+// https archive URL. Packages downloaded from a URL must be signed by a
+// trusted key. The package version must satisfy versionConstraint (if set).
+// An installed package with the same name is only replaced when force is set.
+func (m *PackageManager) Install(ctx context.Context, source, versionConstraint string, force bool) (*PackageManifest, error) {
+	return m.install(ctx, source, func(manifest *PackageManifest, installed *InstalledPackage) error {
+		if ok, err := versionSatisfies(manifest.Version, versionConstraint); err != nil {
+			return err
+		} else if !ok {
+			return fmt.Errorf("package %s version %s does not satisfy %q", manifest.Name, manifest.Version, versionConstraint)
+		}
+		if installed != nil && !force {
+			return fmt.Errorf("package %s %s is already installed, use update or --force", manifest.Name, installed.Manifest.Version)
+		}
+		return nil
+	})
+}
+
+// Update reinstalls a package from the source it was installed from when a
+// newer version is available. It returns the installed manifest and whether
+// the package changed.
+func (m *PackageManager) Update(ctx context.Context, name string) (*PackageManifest, bool, error) {
+	installed, err := m.Get(name)
+	if err != nil {
+		return nil, false, err
+	}
+	if installed.Install.Source == "" {
+		return nil, false, fmt.Errorf("package %s has no recorded source, reinstall it", name)
+	}
+
+	updated := false
+	manifest, err := m.install(ctx, installed.Install.Source, func(manifest *PackageManifest, _ *InstalledPackage) error {
+		if manifest.Name != name {
+			return fmt.Errorf("source %s now provides package %s, not %s", installed.Install.Source, manifest.Name, name)
+		}
+		cmp, err := compareVersions(manifest.Version, installed.Manifest.Version)
+		if err != nil {
+			return err
+		}
+		if cmp <= 0 {
+			return errUpToDate
+		}
+		updated = true
+		return nil
+	})
+	if errors.Is(err, errUpToDate) {
+		return installed.Manifest, false, nil
+	}
+	return manifest, updated, err
+}
+
+// errUpToDate stops an update when the source has no newer version
+var errUpToDate = errors.New("package is up to date")
+
+// Remove deletes an installed package.
+func (m *PackageManager) Remove(name string) error {
+	installed, err := m.Get(name)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(installed.Dir)
+}
+
+// Verify checks the checksums and signature of an installed package.
+func (m *PackageManager) Verify(name string) (*VerifyResult, error) {
+	installed, err := m.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := LoadTrustedKeys(m.TrustedKeysDir)
+	if err != nil {
+		return nil, err
+	}
+	return VerifyPackage(installed.Dir, keys, m.RequireSignature)
+}
+
+// Get returns an installed package by name.
+func (m *PackageManager) Get(name string) (*InstalledPackage, error) {
+	if !packageNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid package name %q", name)
+	}
+	dir := filepath.Join(m.Dir, name)
+	if !IsPackageDir(dir) {
+		return nil, fmt.Errorf("package %s is not installed", name)
+	}
+	return readInstalledPackage(dir)
+}
+
+// List returns the installed packages sorted by name.
+func (m *PackageManager) List() ([]*InstalledPackage, error) {
+	entries, err := os.ReadDir(m.Dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	packages := make([]*InstalledPackage, 0, len(entries))
+	for _, entry := range entries {
+		dir := filepath.Join(m.Dir, entry.Name())
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || !IsPackageDir(dir) {
+			continue
+		}
+		pkg, err := readInstalledPackage(dir)
+		if err != nil {
+			log.Warn().Err(err).Str("dir", dir).Msg("Skipping invalid plugin package")
+			continue
+		}
+		packages = append(packages, pkg)
+	}
+	sort.Slice(packages, func(i, j int) bool { return packages[i].Manifest.Name < packages[j].Manifest.Name })
+	return packages, nil
+}
+
+// readInstalledPackage reads the manifest and install record of a package directory.
+func readInstalledPackage(dir string) (*InstalledPackage, error) {
+	manifest, _, err := ReadPackageManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	pkg := &InstalledPackage{Manifest: manifest, Dir: dir}
+	if data, err := os.ReadFile(filepath.Join(dir, packageInstallFile)); err == nil { //nolint:gosec // package directory
+		_ = json.Unmarshal(data, &pkg.Install)
+	}
+	return pkg, nil
+}
+
+// install fetches and verifies the package at source, lets check accept or
+// reject it, and then replaces the installed copy.
+func (m *PackageManager) install(ctx context.Context, source string, check func(*PackageManifest, *InstalledPackage) error) (*PackageManifest, error) {
+	// Downloads are only trusted over TLS and with a trusted signature
+	if strings.HasPrefix(strings.ToLower(source), "http://") {
+		return nil, fmt.Errorf("refusing to download package over insecure http: %s, use https", source)
+	}
+	requireSignature := m.RequireSignature || isURL(source)
+
+	// Record local sources by absolute path so update works from any directory
+	if !isURL(source) {
+		if abs, err := filepath.Abs(source); err == nil {
+			source = abs
+		}
+	}
+
+	root, cleanup, err := m.fetch(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	keys, err := LoadTrustedKeys(m.TrustedKeysDir)
+	if err != nil {
+		return nil, err
+	}
+	result, err := VerifyPackage(root, keys, requireSignature)
+	if err != nil {
+		return nil, err
+	}
+	manifest := result.Manifest
+
+	if m.AzqrVersion != "" && manifest.AzqrVersion != "" {
+		ok, err := versionSatisfies(m.AzqrVersion, manifest.AzqrVersion)
+		switch {
+		case err != nil:
+			log.Debug().Err(err).Str("package", manifest.Name).Msg("Skipping azqr version check")
+		case !ok:
+			return nil, fmt.Errorf("package %s requires azqr %s, running %s", manifest.Name, manifest.AzqrVersion, m.AzqrVersion)
+		}
+	}
+
+	var installed *InstalledPackage
+	if existing, err := m.Get(manifest.Name); err == nil {
+		installed = existing
+	}
+	if err := check(manifest, installed); err != nil {
+		return nil, err
+	}
+
+	if err := m.copyPackage(root, manifest, source, keys, requireSignature); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// copyPackage copies the manifest, signature and listed files of a verified
+// package into the install directory, replacing any installed copy. The
+// copy is verified again, since a directory source may change after it was
+// verified.
+func (m *PackageManager) copyPackage(root string, manifest *PackageManifest, source string, keys []ed25519.PublicKey, requireSignature bool) error {
+	if err := os.MkdirAll(m.Dir, 0750); err != nil {
+		return fmt.Errorf("failed to create plugin directory: %w", err)
+	}
+	staging, err := os.MkdirTemp(m.Dir, "."+manifest.Name+"-")
+	if err != nil {
+		return fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(staging) }()
+
+	files := []string{PackageManifestFile, PackageSignatureFile}
+	for _, f := range manifest.Files {
+		files = append(files, filepath.FromSlash(f.Path))
+	}
+	for _, rel := range files {
+		if err := copyFile(filepath.Join(root, rel), filepath.Join(staging, rel)); err != nil {
+			if rel == PackageSignatureFile && errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return fmt.Errorf("failed to copy %s: %w", rel, err)
+		}
+	}
+
+	staged, err := VerifyPackage(staging, keys, requireSignature)
+	if err != nil {
+		return fmt.Errorf("package changed while installing: %w", err)
+	}
+	if !reflect.DeepEqual(staged.Manifest, manifest) {
+		return fmt.Errorf("package %s changed while installing", manifest.Name)
+	}
+
+	record, err := json.MarshalIndent(PackageInstall{
+		Source:      source,
+		InstalledAt: time.Now().UTC().Format(time.RFC3339),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(staging, packageInstallFile), record, 0600); err != nil {
+		return fmt.Errorf("failed to write install record: %w", err)
+	}
+
+	for _, e := range manifest.Executables {
+		path, _ := packageFilePath(staging, e.Path)
+		if err := os.Chmod(path, 0700); err != nil { //nolint:gosec // plugin executables must be executable
+			return fmt.Errorf("failed to make %s executable: %w", e.Path, err)
+		}
+	}
+
+	// Refuse packages whose plugins would not load once installed
+	for _, f := range manifest.Files {
+		if ext := filepath.Ext(f.Path); ext == ".yaml" || ext == ".yml" {
+			path, _ := packageFilePath(staging, f.Path)
+			if _, _, err := LoadYamlPlugin(path); err != nil {
+				return fmt.Errorf("package %s: %s: %w", manifest.Name, f.Path, err)
+			}
+		}
+	}
+
+	target := filepath.Join(m.Dir, manifest.Name)
+	if err := os.RemoveAll(target); err != nil {
+		return fmt.Errorf("failed to remove installed package: %w", err)
+	}
+	return os.Rename(staging, target)
+}
+
+// fetch returns the package root directory for a source path or URL and a
+// function removing any temporary files.
+func (m *PackageManager) fetch(ctx context.Context, source string) (string, func(), error) {
+	noop := func() {}
+	archive := source
+
+	if isURL(source) {
+		path, err := m.download(ctx, source)
+		if err != nil {
+			return "", noop, err
+		}
+		archive = path
+		defer func() { _ = os.Remove(path) }()
+	} else if info, err := os.Stat(source); err != nil {
+		return "", noop, fmt.Errorf("failed to read package source: %w", err)
+	} else if info.IsDir() {
+		return source, noop, nil
+	}
+
+	dir, err := os.MkdirTemp("", "azqr-plugin-")
+	if err != nil {
+		return "", noop, err
+	}
+	cleanup := func() { _ = os.RemoveAll(dir) }
+	if err := extractArchive(archive, source, dir); err != nil {
+		cleanup()
+		return "", noop, err
+	}
+
+	// Archives may wrap the package in a single top-level directory
+	root := dir
+	if !IsPackageDir(root) {
+		entries, _ := os.ReadDir(dir)
+		if len(entries) == 1 && entries[0].IsDir() {
+			root = filepath.Join(dir, entries[0].Name())
+		}
+	}
+	if !IsPackageDir(root) {
+		cleanup()
+		return "", noop, fmt.Errorf("%s does not contain a %s", source, PackageManifestFile)
+	}
+	return root, cleanup, nil
+}
+
+// download saves the archive at url to a temporary file.
+func (m *PackageManager) download(ctx context.Context, url string) (string, error) {
+	client := m.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to download package: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download package: %s", resp.Status)
+	}
+
+	f, err := os.CreateTemp("", "azqr-plugin-*"+archiveExt(url))
+	if err != nil {
+		return "", err
+	}
+	n, err := io.Copy(f, io.LimitReader(resp.Body, maxPackageSize+1))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && n > maxPackageSize {
+		err = fmt.Errorf("package exceeds %d bytes", maxPackageSize)
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return "", fmt.Errorf("failed to download package: %w", err)
+	}
+	return f.Name(), nil
+}
+
+// isURL reports whether a package source is an HTTPS URL.
+func isURL(source string) bool {
+	return strings.HasPrefix(strings.ToLower(source), "https://")
+}
+
+// archiveExt returns the archive extension of a path or URL.
+func archiveExt(name string) string {
+	name = strings.ToLower(name)
+	if i := strings.IndexAny(name, "?#"); i >= 0 {
+		name = name[:i]
+	}
+	for _, ext := range []string{".tar.gz", ".tgz", ".zip"} {
+		if strings.HasSuffix(name, ext) {
+			return ext
+		}
+	}
+	return ""
+}
+
+// extractArchive extracts a .zip, .tar.gz or .tgz archive into dir. The
+// archive format is taken from the source name.
+func extractArchive(path, source, dir string) error {
+	switch archiveExt(source) {
+	case ".zip":
+		return extractZip(path, dir)
+	case ".tar.gz", ".tgz":
+		return extractTarGz(path, dir)
+	default:
+		return fmt.Errorf("unsupported package archive %s (use a directory, .zip, .tar.gz or .tgz)", source)
+	}
+}
+
+// extractZip extracts the regular files of a zip archive into dir.
+func extractZip(path, dir string) error {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return fmt.Errorf("failed to open package archive: %w", err)
+	}
+	defer func() { _ = r.Close() }()
+
+	var total int64
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() || !f.Mode().IsRegular() {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", f.Name, err)
+		}
+		n, err := extractFile(dir, f.Name, rc, maxPackageSize-total)
+		_ = rc.Close()
+		if err != nil {
+			return err
+		}
+		total += n
+	}
+	return nil
+}
+
+// extractTarGz extracts the regular files of a gzipped tar archive into dir.
+func extractTarGz(path, dir string) error {
+	f, err := os.Open(path) //nolint:gosec // package archive chosen by the user
+	if err != nil {
+		return fmt.Errorf("failed to open package archive: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("failed to open package archive: %w", err)
+	}
+	defer func() { _ = gz.Close() }()
+
+	var total int64
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read package archive: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		n, err := extractFile(dir, hdr.Name, tr, maxPackageSize-total)
+		if err != nil {
+			return err
+		}
+		total += n
+	}
+}
+
+// extractFile writes an archive entry below dir, rejecting entries that
+// escape dir or exceed the remaining size budget.
+func extractFile(dir, name string, r io.Reader, limit int64) (int64, error) {
+	clean := filepath.Clean(filepath.FromSlash(name))
+	if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return 0, fmt.Errorf("archive entry %q escapes the package", name)
+	}
+	target := filepath.Join(dir, clean)
+	if err := os.MkdirAll(filepath.Dir(target), 0750); err != nil {
+		return 0, err
+	}
+
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600) //nolint:gosec // target is inside dir
+	if err != nil {
+		return 0, fmt.Errorf("failed to extract %s: %w", name, err)
+	}
+	n, err := io.Copy(out, io.LimitReader(r, limit+1))
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return n, fmt.Errorf("failed to extract %s: %w", name, err)
+	}
+	if n > limit {
+		return n, fmt.Errorf("package exceeds %d bytes", maxPackageSize)
+	}
+	return n, nil
+}
+
+// copyFile copies src to dst, creating parent directories.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src) //nolint:gosec // src is a verified package file
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0750); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600) //nolint:gosec // dst is inside the staging directory
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
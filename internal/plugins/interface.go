@@ -16,8 +16,22 @@ const (
 	PluginTypeYaml PluginType = iota
 	// PluginTypeInternal represents an internal Go-based plugin
 	PluginTypeInternal
+	// PluginTypeExternal represents an azqr-plugin-* executable speaking the JSON protocol
+	PluginTypeExternal
 )
 
+// String returns the plugin type name shown by "azqr plugins".
+func (t PluginType) String() string {
+	switch t {
+	case PluginTypeInternal:
+		return "internal"
+	case PluginTypeExternal:
+		return "external"
+	default:
+		return "yaml"
+	}
+}
+
 // PluginMetadata contains information about a plugin
 type PluginMetadata struct {
 	// Name is the unique identifier for the plugin (e.g., "myservice")
@@ -36,16 +50,37 @@ type PluginMetadata struct {
 	CommandPath string
 	// ColumnMetadata defines the columns and their filter types for the viewer
 	ColumnMetadata []ColumnMetadata
+	// Options declares the typed options accepted by the plugin
+	Options []PluginOption
+}
+
+// PluginOption declares a plugin option. Options are exposed as flags on the
+// plugin command, as plugin.<plugin-name>.<name>=value stage params and as
+// properties of the MCP tool "options" object.
+type PluginOption struct {
+	// Name of the option (e.g., "target-regions")
+	Name string `yaml:"name" json:"name"`
+	// Type of the option value: "bool", "int", "float64" or "string"
+	Type string `yaml:"type" json:"type"`
+	// Default value used when the option is not set
+	Default any `yaml:"default,omitempty" json:"default,omitempty"`
+	// Description shown in the flag help and the MCP tool schema
+	Description string `yaml:"description" json:"description"`
 }
 
 // Plugin represents a loaded plugin with its scanner and command
 type Plugin struct {
 	// Metadata contains information about the plugin
 	Metadata PluginMetadata
-	// InternalScanner is the internal plugin scanner (for internal plugins)
+	// InternalScanner is the plugin scanner (for internal and external plugins)
 	InternalScanner InternalPluginScanner
 	// YamlRecommendations holds APRL recommendations for YAML plugins
 	YamlRecommendations []models.GraphRecommendation
+	// DiagnosticSettingsRecommendations holds the diagnostic settings
+	// recommendations of YAML plugins, overriding the built-in catalog
+	DiagnosticSettingsRecommendations []models.GraphRecommendation
+	// YamlScanners holds the service scanners declared by YAML plugins
+	YamlScanners []YamlPluginScanner
 	// Command is the Cobra command for this plugin (optional)
 	Command *cobra.Command
 }
@@ -81,6 +116,9 @@ type YamlPluginQuery struct {
 		Name string `yaml:"name"`
 		Url  string `yaml:"url"`
 	} `yaml:"learnMoreLink"`
+	// EstimatedSavingsPct is the share (0-100) of the monthly cost of an
+	// impacted resource saved by fixing the finding (optional)
+	EstimatedSavingsPct float64 `yaml:"estimatedSavingsPct,omitempty"`
 	// Query is the inline KQL query (optional if using external .kql file)
 	Query string `yaml:"query,omitempty"`
 	// QueryFile is the path to external .kql file (optional if using inline query)
@@ -101,6 +139,53 @@ type YamlPluginConfig struct {
 	License string `yaml:"license,omitempty"`
 	// Queries list of graph queries to execute
 	Queries []YamlPluginQuery `yaml:"queries,omitempty"`
+	// Tables list of graph queries rendered as their own report sheets
+	Tables []YamlPluginTable `yaml:"tables,omitempty"`
+	// DiagnosticSettings list of diagnostic settings recommendations (no query):
+	// they replace the built-in recommendations of their resource type
+	DiagnosticSettings []YamlPluginQuery `yaml:"diagnosticSettings,omitempty"`
+	// Scanners list of service scanners for resource types azqr does not cover
+	Scanners []YamlPluginScanner `yaml:"scanners,omitempty"`
+	// Options declares options usable as {{name}} placeholders in table queries
+	Options []PluginOption `yaml:"options,omitempty"`
+}
+
+// YamlPluginScanner declares a service scanner, registered under its
+// abbreviation like the built-in scanners
+type YamlPluginScanner struct {
+	// Abbreviation is the service key used by "azqr scan <abbreviation>" (e.g., hdw)
+	Abbreviation string `yaml:"abbreviation"`
+	// ServiceName is the display name of the service (defaults to Abbreviation)
+	ServiceName string `yaml:"serviceName"`
+	// ResourceTypes are the Azure resource types scanned by this scanner
+	ResourceTypes []string `yaml:"resourceTypes"`
+}
+
+// YamlPluginTable represents a graph query whose rows are rendered as a report
+// sheet instead of being evaluated as a recommendation
+type YamlPluginTable struct {
+	// SheetName is the name of the Excel sheet (and CSV/JSON section)
+	SheetName string `yaml:"sheetName"`
+	// Description of the data returned by the query
+	Description string `yaml:"description"`
+	// Query is the inline KQL query (optional if using external .kql file)
+	Query string `yaml:"query,omitempty"`
+	// QueryFile is the path to external .kql file (optional if using inline query)
+	QueryFile string `yaml:"queryFile,omitempty"`
+	// Columns defines the sheet columns, in order
+	Columns []YamlPluginColumn `yaml:"columns"`
+}
+
+// YamlPluginColumn maps a query result column to a sheet column
+type YamlPluginColumn struct {
+	// Name is the column header shown in the report
+	Name string `yaml:"name"`
+	// Field is the query result column (defaults to Name)
+	Field string `yaml:"field,omitempty"`
+	// Type is the column data type (number, percent, currency, date, url, enum)
+	Type models.ColumnType `yaml:"type,omitempty"`
+	// Unit is the unit or currency code of the column values
+	Unit string `yaml:"unit,omitempty"`
 }
 
 // FilterType represents the type of filter for a column
@@ -133,16 +218,19 @@ func (m PluginMetadata) HeaderRow() []string {
 	return headers
 }
 
-// ColumnMetadata defines filtering behavior for a column in the viewer
+// ColumnMetadata describes a report column: its display name, data type and unit
 type ColumnMetadata struct {
-	Name       string     `json:"name"`        // Display name (e.g., "Latest Month Emissions")
+	Name string            `json:"name"`           // Display name (e.g., "Latest Month Emissions")
+	Type models.ColumnType `json:"type,omitempty"` // Data type used to format the column in reports
+	Unit string            `json:"unit,omitempty"` // Unit or currency code (e.g., "kgCO2e", "USD")
 }
 
 // ExternalPluginOutput represents the output from a plugin execution
 type ExternalPluginOutput struct {
-	Metadata    PluginMetadata `json:"metadata"`
-	SheetName   string         `json:"sheet_name"`      // Name for Excel sheet
-	Description string         `json:"description"`     // Description of data
-	Table       [][]string     `json:"table"`           // Headers + data rows
-	Error       string         `json:"error,omitempty"` // Error if failed
+	Metadata    PluginMetadata   `json:"metadata"`
+	SheetName   string           `json:"sheet_name"`        // Name for Excel sheet
+	Description string           `json:"description"`       // Description of data
+	Table       [][]string       `json:"table"`             // Headers + data rows
+	Columns     []ColumnMetadata `json:"columns,omitempty"` // Column types for this sheet (defaults to Metadata.ColumnMetadata)
+	Error       string           `json:"error,omitempty"`   // Error if failed
 }
@@ -77,6 +77,8 @@ func createPluginCommand(name, description string) *cobra.Command {
 	cmd.Flags().StringP("output-name", "o", "", "Output file name without extension")
 	cmd.Flags().BoolP("mask", "m", true, "Mask the subscription id in the report (default) (default true)")
 	cmd.Flags().StringP("filters", "e", "", "Filters file (YAML format)")
+	cmd.Flags().BoolP("progress", "", true, "Show a live progress bar when writing to a terminal (default true)")
+	cmd.Flags().Duration("plugin-timeout", models.DefaultPluginTimeout, "Maximum time the plugin may run")
 
 	return cmd
 }
@@ -4,22 +4,19 @@
 package plugins
 
 import (
-	"os"
-	"path/filepath"
-
 	"github.com/rs/zerolog/log"
 )
 
 // getPluginDirs returns the directories to search for YAML plugins
 func getPluginDirs() []string {
-	home, _ := os.UserHomeDir()
 	return []string{
-		filepath.Join(home, ".azqr", "plugins"),
+		getPackageDir(),
 		"./plugins",
 	}
 }
 
-// LoadAll discovers and loads all available plugins (internal and YAML)
+// LoadAll discovers and loads all available plugins (YAML and external executables).
+// Internal plugins register themselves at init time.
 func LoadAll() error {
 	registry := GetRegistry()
 
@@ -34,7 +31,32 @@ func LoadAll() error {
 					Err(err).
 					Str("plugin", plugin.Metadata.Name).
 					Msg("Failed to register YAML plugin")
+				continue
 			}
+			registerYamlScanners(plugin)
+		}
+		for _, plugin := range yamlPlugins {
+			warnUncoveredRecommendations(plugin)
+		}
+	}
+
+	// Register the external plugin executables of installed packages, then
+	// those of the trusted directories. They cannot replace built-in plugins.
+	externalPlugins := discoverExternalPlugins(getPackageDir())
+	externalPlugins = append(externalPlugins, discoverTrustedExternalPlugins(getTrustedPluginDirs())...)
+	for _, plugin := range externalPlugins {
+		if existing, exists := registry.Get(plugin.Metadata.Name); exists && existing.Metadata.Type == PluginTypeInternal {
+			log.Warn().
+				Str("plugin", plugin.Metadata.Name).
+				Str("path", plugin.Metadata.CommandPath).
+				Msg("External plugin name conflicts with a built-in plugin, skipping")
+			continue
+		}
+		if err := registry.Register(plugin); err != nil {
+			log.Warn().
+				Err(err).
+				Str("plugin", plugin.Metadata.Name).
+				Msg("Failed to register external plugin")
 		}
 	}
 
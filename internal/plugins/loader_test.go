@@ -34,7 +34,7 @@ func TestLoadAll_RegistersDiscoveredPlugin(t *testing.T) {
 	// Isolate home so ~/.azqr/plugins cannot interfere.
 	t.Setenv("HOME", t.TempDir())
 
-	// Create a ./plugins directory with a valid YAML plugin relative to the cwd.
+	// Create a ./plugins directory with a package holding a valid YAML plugin relative to the cwd.
 	workDir := t.TempDir()
 	pluginDir := filepath.Join(workDir, "plugins")
 	if err := os.MkdirAll(pluginDir, 0750); err != nil {
@@ -53,9 +53,7 @@ queries:
       resources
       | project id, name
 `
-	if err := os.WriteFile(filepath.Join(pluginDir, "plugin.yaml"), []byte(yamlContent), 0600); err != nil {
-		t.Fatalf("failed to write plugin file: %v", err)
-	}
+	writePackage(t, filepath.Join(pluginDir, pluginName), pluginName, "2.1.0", map[string]string{"plugin.yaml": yamlContent}, nil)
 
 	t.Chdir(workDir)
 
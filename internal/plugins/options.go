@@ -0,0 +1,110 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package plugins
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Azure/azqr/internal/models"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// registerPluginOptions validates the options declared by a plugin and
+// registers their schema so stage params, flags and MCP arguments can be
+// checked against it.
+func registerPluginOptions(name string, options []PluginOption) error {
+	specs := make(map[string]models.OptionSpec, len(options))
+	for _, opt := range options {
+		if err := validatePluginOption(opt); err != nil {
+			return fmt.Errorf("plugin %s: %w", name, err)
+		}
+		if _, exists := specs[opt.Name]; exists {
+			return fmt.Errorf("plugin %s declares option %q more than once", name, opt.Name)
+		}
+		specs[opt.Name] = models.OptionSpec{
+			Type:        opt.Type,
+			Default:     opt.Default,
+			Description: opt.Description,
+		}
+	}
+	return models.RegisterPluginOptions(name, specs)
+}
+
+// validatePluginOption checks the name and type of an option declaration.
+func validatePluginOption(opt PluginOption) error {
+	if opt.Name == "" {
+		return fmt.Errorf("option missing required field 'name'")
+	}
+	switch opt.Type {
+	case "bool", "int", "float64", "string":
+		return nil
+	default:
+		return fmt.Errorf("option %s has unsupported type %q", opt.Name, opt.Type)
+	}
+}
+
+// addOptionFlags adds a typed flag for each registered option of the plugin.
+// Options whose name collides with an existing flag are not exposed as flags.
+func addOptionFlags(cmd *cobra.Command, name string) {
+	specs := models.PluginOptionSpecs(name)
+	for _, key := range sortedOptionNames(specs) {
+		spec := specs[key]
+		if cmd.Flags().Lookup(key) != nil {
+			log.Warn().
+				Str("plugin", name).
+				Str("option", key).
+				Msg("Plugin option conflicts with an existing flag, use --stage-param instead")
+			continue
+		}
+		switch spec.Type {
+		case "bool":
+			cmd.Flags().Bool(key, spec.Default.(bool), spec.Description)
+		case "int":
+			cmd.Flags().Int(key, spec.Default.(int), spec.Description)
+		case "float64":
+			cmd.Flags().Float64(key, spec.Default.(float64), spec.Description)
+		default:
+			cmd.Flags().String(key, spec.Default.(string), spec.Description)
+		}
+	}
+}
+
+// OptionFlagValues returns the plugin options explicitly set as flags on the
+// plugin command.
+func OptionFlagValues(cmd *cobra.Command, name string) map[string]any {
+	values := make(map[string]any)
+	for key, spec := range models.PluginOptionSpecs(name) {
+		if !cmd.Flags().Changed(key) {
+			continue
+		}
+		var value any
+		var err error
+		switch spec.Type {
+		case "bool":
+			value, err = cmd.Flags().GetBool(key)
+		case "int":
+			value, err = cmd.Flags().GetInt(key)
+		case "float64":
+			value, err = cmd.Flags().GetFloat64(key)
+		default:
+			value, err = cmd.Flags().GetString(key)
+		}
+		if err == nil {
+			values[key] = value
+		}
+	}
+	return values
+}
+
+// sortedOptionNames returns the option names in a stable order.
+func sortedOptionNames(specs map[string]models.OptionSpec) []string {
+	names := make([]string, 0, len(specs))
+	for key := range specs {
+		names = append(names, key)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,104 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package plugins
+
+import (
+	"testing"
+
+	"github.com/Azure/azqr/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegister_PluginOptions(t *testing.T) {
+	registry := &Registry{plugins: make(map[string]*Plugin)}
+	cmd := createPluginCommand("opts-plugin", "Plugin with options")
+
+	err := registry.Register(&Plugin{
+		Metadata: PluginMetadata{
+			Name: "opts-plugin",
+			Options: []PluginOption{
+				{Name: "days", Type: "int", Default: 30, Description: "Days of history"},
+				{Name: "ratio", Type: "float64", Default: 0.5},
+				{Name: "strict", Type: "bool"},
+				{Name: "regions", Type: "string"},
+				{Name: "subscription-id", Type: "string"},
+			},
+		},
+		Command: cmd,
+	})
+	require.NoError(t, err)
+
+	days := cmd.Flags().Lookup("days")
+	require.NotNil(t, days)
+	assert.Equal(t, "30", days.DefValue)
+	assert.Equal(t, "Days of history", days.Usage)
+	assert.Equal(t, "int", days.Value.Type())
+	assert.Equal(t, "float64", cmd.Flags().Lookup("ratio").Value.Type())
+	assert.Equal(t, "bool", cmd.Flags().Lookup("strict").Value.Type())
+	assert.Equal(t, "stringSlice", cmd.Flags().Lookup("subscription-id").Value.Type(), "standard flags are not replaced")
+
+	require.NoError(t, cmd.Flags().Set("days", "7"))
+	require.NoError(t, cmd.Flags().Set("strict", "true"))
+	assert.Equal(t, map[string]any{"days": 7, "strict": true}, OptionFlagValues(cmd, "opts-plugin"), "only changed flags are returned")
+
+	stages := models.NewStageConfigs()
+	require.NoError(t, stages.SetPluginOptions("opts-plugin", OptionFlagValues(cmd, "opts-plugin")))
+	opts := stages.GetPluginOptions("opts-plugin")
+	assert.Equal(t, 7, opts["days"])
+	assert.Equal(t, 0.5, opts["ratio"])
+	assert.Equal(t, "", opts["regions"])
+}
+
+func TestRegister_InvalidPluginOptions(t *testing.T) {
+	registry := &Registry{plugins: make(map[string]*Plugin)}
+
+	for _, options := range [][]PluginOption{
+		{{Name: "", Type: "string"}},
+		{{Name: "days", Type: "duration"}},
+		{{Name: "days", Type: "int"}, {Name: "days", Type: "int"}},
+		{{Name: "days", Type: "int", Default: "thirty"}},
+	} {
+		err := registry.Register(&Plugin{Metadata: PluginMetadata{Name: "bad-options", Options: options}})
+		assert.Error(t, err, "options %v", options)
+	}
+	_, exists := registry.Get("bad-options")
+	assert.False(t, exists)
+}
+
+func TestLoadYamlPlugin_Options(t *testing.T) {
+	path := writeYamlPlugin(t, `---
+name: stale-disks
+options:
+  - name: days
+    type: int
+    default: 30
+    description: Minimum age in days
+tables:
+  - sheetName: Stale Disks
+    query: resources | where todatetime(properties.timeCreated) < ago({{days}}d)
+    columns:
+      - name: id
+`)
+	plugin, _, err := LoadYamlPlugin(path)
+	require.NoError(t, err)
+	require.Len(t, plugin.Metadata.Options, 1)
+	assert.Equal(t, "days", plugin.Metadata.Options[0].Name)
+
+	replacer := yamlQueryReplacer(map[string]any{"days": 90})
+	assert.Equal(t, "resources | where todatetime(properties.timeCreated) < ago(90d)", replacer.Replace(plugin.InternalScanner.(*yamlTableScanner).tables[0].Query))
+
+	_, _, err = LoadYamlPlugin(writeYamlPlugin(t, `---
+name: bad-option
+options:
+  - name: days
+    type: duration
+tables:
+  - sheetName: Sheet
+    query: resources
+    columns:
+      - name: id
+`))
+	assert.ErrorContains(t, err, "unsupported type")
+}
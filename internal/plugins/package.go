@@ -0,0 +1,292 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package plugins
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// A plugin package is a directory (or an archive of one) holding a manifest,
+// an optional detached signature of the manifest, and the files listed in the
+// manifest. Installed packages live in ~/.azqr/plugins/<name>.
+const (
+	// PackageManifestFile is the name of the package manifest
+	PackageManifestFile = "manifest.json"
+	// PackageSignatureFile is the name of the detached ed25519 manifest signature
+	PackageSignatureFile = "manifest.json.sig"
+	// packageInstallFile records where an installed package came from
+	packageInstallFile = "install.json"
+)
+
+// packageNamePattern matches valid package names (also used as directory names)
+var packageNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// PackageManifest describes a plugin package
+type PackageManifest struct {
+	// Name of the package, used as its install directory
+	Name string `json:"name"`
+	// Version is the semantic version of the package
+	Version string `json:"version"`
+	// Description of the package
+	Description string `json:"description,omitempty"`
+	// AzqrVersion is a version constraint on azqr (e.g., ">= 2.0.0, < 3.0.0")
+	AzqrVersion string `json:"azqrVersion,omitempty"`
+	// Files lists every file of the package with its SHA-256 checksum
+	Files []PackageFile `json:"files"`
+	// Executables lists the external plugin executables shipped in the package
+	Executables []PackageExecutable `json:"executables,omitempty"`
+}
+
+// PackageExecutable declares an external plugin executable of a package. Its
+// options are declared here so the plugin command can be built without
+// running the executable.
+type PackageExecutable struct {
+	// Name of the plugin and of its command
+	Name string `json:"name"`
+	// Path of the executable, which must also be listed in Files
+	Path string `json:"path"`
+	// Description of the plugin
+	Description string `json:"description,omitempty"`
+	// Options accepted by the plugin
+	Options []PluginOption `json:"options,omitempty"`
+}
+
+// PackageFile is a file of a plugin package
+type PackageFile struct {
+	// Path relative to the package root, using forward slashes
+	Path string `json:"path"`
+	// SHA256 is the hex encoded SHA-256 checksum of the file
+	SHA256 string `json:"sha256"`
+}
+
+// PackageInstall records how an installed package was obtained
+type PackageInstall struct {
+	// Source is the path or URL the package was installed from
+	Source string `json:"source"`
+	// InstalledAt is the RFC 3339 install time
+	InstalledAt string `json:"installedAt"`
+}
+
+// VerifyResult is the outcome of verifying a package directory
+type VerifyResult struct {
+	// Manifest is the package manifest
+	Manifest *PackageManifest
+	// Signed reports whether the manifest signature was verified against a trusted key
+	Signed bool
+}
+
+// IsPackageDir reports whether dir contains a package manifest.
+func IsPackageDir(dir string) bool {
+	info, err := os.Stat(filepath.Join(dir, PackageManifestFile))
+	return err == nil && !info.IsDir()
+}
+
+// ReadPackageManifest reads and validates the manifest of a package directory.
+func ReadPackageManifest(dir string) (*PackageManifest, []byte, error) {
+	data, err := os.ReadFile(filepath.Join(dir, PackageManifestFile)) //nolint:gosec // dir is a plugin package directory
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read package manifest: %w", err)
+	}
+
+	var manifest PackageManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse package manifest: %w", err)
+	}
+	if !packageNamePattern.MatchString(manifest.Name) {
+		return nil, nil, fmt.Errorf("invalid package name %q", manifest.Name)
+	}
+	if _, err := parseVersion(manifest.Version); err != nil {
+		return nil, nil, fmt.Errorf("package %s: %w", manifest.Name, err)
+	}
+	if _, err := versionSatisfies("0.0.0", manifest.AzqrVersion); err != nil {
+		return nil, nil, fmt.Errorf("package %s: %w", manifest.Name, err)
+	}
+	if len(manifest.Files) == 0 {
+		return nil, nil, fmt.Errorf("package %s lists no files", manifest.Name)
+	}
+	for _, f := range manifest.Files {
+		if _, err := packageFilePath(dir, f.Path); err != nil {
+			return nil, nil, fmt.Errorf("package %s: %w", manifest.Name, err)
+		}
+		if len(f.SHA256) != sha256.Size*2 {
+			return nil, nil, fmt.Errorf("package %s: file %s has an invalid sha256 checksum", manifest.Name, f.Path)
+		}
+	}
+	names := make(map[string]bool, len(manifest.Executables))
+	for _, e := range manifest.Executables {
+		if !packageNamePattern.MatchString(e.Name) || names[e.Name] {
+			return nil, nil, fmt.Errorf("package %s: invalid or duplicate executable name %q", manifest.Name, e.Name)
+		}
+		names[e.Name] = true
+		if manifest.fileChecksum(e.Path) == "" {
+			return nil, nil, fmt.Errorf("package %s: executable %s is not listed in files", manifest.Name, e.Path)
+		}
+	}
+	return &manifest, data, nil
+}
+
+// fileChecksum returns the SHA-256 checksum the manifest lists for path, or
+// an empty string when path is not part of the package.
+func (m *PackageManifest) fileChecksum(path string) string {
+	for _, f := range m.Files {
+		if f.Path == path {
+			return f.SHA256
+		}
+	}
+	return ""
+}
+
+// VerifyPackage checks the checksum of every file listed in the manifest of a
+// package directory. When the package has a signature it must verify against
+// one of the trusted keys; when requireSignature is set, unsigned packages
+// are rejected.
+func VerifyPackage(dir string, trustedKeys []ed25519.PublicKey, requireSignature bool) (*VerifyResult, error) {
+	manifest, data, err := ReadPackageManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &VerifyResult{Manifest: manifest}
+
+	sig, err := os.ReadFile(filepath.Join(dir, PackageSignatureFile)) //nolint:gosec // dir is a plugin package directory
+	switch {
+	case err == nil:
+		if err := verifySignature(data, sig, trustedKeys); err != nil {
+			return nil, fmt.Errorf("package %s: %w", manifest.Name, err)
+		}
+		result.Signed = true
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, fmt.Errorf("failed to read package signature: %w", err)
+	case requireSignature:
+		return nil, fmt.Errorf("package %s is not signed", manifest.Name)
+	}
+
+	for _, f := range manifest.Files {
+		path, _ := packageFilePath(dir, f.Path)
+		sum, err := fileSHA256(path)
+		if err != nil {
+			return nil, fmt.Errorf("package %s: %w", manifest.Name, err)
+		}
+		if !strings.EqualFold(sum, f.SHA256) {
+			return nil, fmt.Errorf("package %s: checksum mismatch for %s", manifest.Name, f.Path)
+		}
+	}
+	return result, nil
+}
+
+// verifySignature checks a raw or base64 encoded ed25519 signature of data.
+func verifySignature(data, sig []byte, trustedKeys []ed25519.PublicKey) error {
+	if len(trustedKeys) == 0 {
+		return fmt.Errorf("package is signed but no trusted keys are configured")
+	}
+	if len(sig) != ed25519.SignatureSize {
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+		if err != nil || len(decoded) != ed25519.SignatureSize {
+			return fmt.Errorf("invalid manifest signature")
+		}
+		sig = decoded
+	}
+	for _, key := range trustedKeys {
+		if ed25519.Verify(key, data, sig) {
+			return nil
+		}
+	}
+	return fmt.Errorf("manifest signature does not match any trusted key")
+}
+
+// LoadTrustedKeys reads the ed25519 public keys in dir. Each *.pub file holds
+// a PEM encoded PKIX public key or a base64 encoded raw key. A missing
+// directory yields no keys.
+func LoadTrustedKeys(dir string) ([]ed25519.PublicKey, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read trusted keys: %w", err)
+	}
+
+	keys := make([]ed25519.PublicKey, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".pub" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name())) //nolint:gosec // trusted keys directory
+		if err != nil {
+			return nil, fmt.Errorf("failed to read trusted key %s: %w", entry.Name(), err)
+		}
+		key, err := parsePublicKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("trusted key %s: %w", entry.Name(), err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// parsePublicKey parses a PEM or base64 encoded ed25519 public key.
+func parsePublicKey(data []byte) (ed25519.PublicKey, error) {
+	if block, _ := pem.Decode(data); block != nil {
+		pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		key, ok := pub.(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("not an ed25519 public key")
+		}
+		return key, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(data)))
+	if err != nil || len(raw) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("not an ed25519 public key")
+	}
+	return ed25519.PublicKey(raw), nil
+}
+
+// packageFilePath resolves a manifest file path inside the package directory,
+// rejecting absolute paths and paths escaping the package.
+func packageFilePath(dir, rel string) (string, error) {
+	if rel == "" || filepath.IsAbs(rel) || strings.HasPrefix(rel, "/") {
+		return "", fmt.Errorf("invalid file path %q", rel)
+	}
+	clean := filepath.Clean(filepath.FromSlash(rel))
+	if clean == "." || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("file path %q escapes the package", rel)
+	}
+	if clean == PackageManifestFile || clean == PackageSignatureFile || clean == packageInstallFile {
+		return "", fmt.Errorf("file path %q is reserved", rel)
+	}
+	return filepath.Join(dir, clean), nil
+}
+
+// fileSHA256 returns the hex encoded SHA-256 checksum of a file.
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path) //nolint:gosec // path is validated by packageFilePath
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,423 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package plugins
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPackageYaml returns a valid YAML plugin named name.
+func testPackageYaml(name string) string {
+	return `name: ` + name + `
+queries:
+  - aprlGuid: ` + name + `-001
+    description: Test recommendation
+    queryFile: queries/check.kql
+`
+}
+
+// writePackage writes a plugin package with the given files to dir and
+// returns its manifest bytes. The manifest is signed when key is not nil.
+func writePackage(t *testing.T, dir, name, version string, files map[string]string, key ed25519.PrivateKey) []byte {
+	t.Helper()
+	manifest := PackageManifest{Name: name, Version: version}
+	for path, content := range files {
+		full := filepath.Join(dir, filepath.FromSlash(path))
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0750))
+		require.NoError(t, os.WriteFile(full, []byte(content), 0600))
+		sum := sha256.Sum256([]byte(content))
+		manifest.Files = append(manifest.Files, PackageFile{Path: path, SHA256: hex.EncodeToString(sum[:])})
+	}
+	data, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, PackageManifestFile), data, 0600))
+	if key != nil {
+		sig := base64.StdEncoding.EncodeToString(ed25519.Sign(key, data))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, PackageSignatureFile), []byte(sig), 0600))
+	}
+	return data
+}
+
+// testPackageFiles returns the files of a valid package named name.
+func testPackageFiles(name string) map[string]string {
+	return map[string]string{
+		name + ".yaml":      testPackageYaml(name),
+		"queries/check.kql": "resources | project id",
+	}
+}
+
+func newTestManager(t *testing.T) *PackageManager {
+	t.Helper()
+	root := t.TempDir()
+	return &PackageManager{
+		Dir:            filepath.Join(root, "plugins"),
+		TrustedKeysDir: filepath.Join(root, "trusted-keys"),
+		AzqrVersion:    "2.5.0",
+	}
+}
+
+func TestVerifyPackage(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	otherPub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	t.Run("unsigned", func(t *testing.T) {
+		dir := t.TempDir()
+		writePackage(t, dir, "rules", "1.0.0", testPackageFiles("rules"), nil)
+
+		result, err := VerifyPackage(dir, nil, false)
+		require.NoError(t, err)
+		assert.Equal(t, "rules", result.Manifest.Name)
+		assert.False(t, result.Signed)
+
+		_, err = VerifyPackage(dir, nil, true)
+		assert.ErrorContains(t, err, "not signed")
+	})
+
+	t.Run("tampered file", func(t *testing.T) {
+		dir := t.TempDir()
+		writePackage(t, dir, "rules", "1.0.0", testPackageFiles("rules"), nil)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "queries", "check.kql"), []byte("resources"), 0600))
+
+		_, err := VerifyPackage(dir, nil, false)
+		assert.ErrorContains(t, err, "checksum mismatch for queries/check.kql")
+	})
+
+	t.Run("signed", func(t *testing.T) {
+		dir := t.TempDir()
+		writePackage(t, dir, "rules", "1.0.0", testPackageFiles("rules"), priv)
+
+		result, err := VerifyPackage(dir, []ed25519.PublicKey{otherPub, pub}, true)
+		require.NoError(t, err)
+		assert.True(t, result.Signed)
+
+		_, err = VerifyPackage(dir, []ed25519.PublicKey{otherPub}, false)
+		assert.ErrorContains(t, err, "does not match any trusted key")
+
+		_, err = VerifyPackage(dir, nil, false)
+		assert.ErrorContains(t, err, "no trusted keys")
+	})
+
+	t.Run("path escapes package", func(t *testing.T) {
+		dir := t.TempDir()
+		manifest := `{"name":"rules","version":"1.0.0","files":[{"path":"../evil.yaml","sha256":"` + hex.EncodeToString(make([]byte, 32)) + `"}]}`
+		require.NoError(t, os.WriteFile(filepath.Join(dir, PackageManifestFile), []byte(manifest), 0600))
+
+		_, err := VerifyPackage(dir, nil, false)
+		assert.ErrorContains(t, err, "escapes the package")
+	})
+}
+
+func TestLoadTrustedKeys(t *testing.T) {
+	dir := t.TempDir()
+	pub1, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	pub2, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKIXPublicKey(pub1)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.pub"), pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.pub"), []byte(base64.StdEncoding.EncodeToString(pub2)+"\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("ignored"), 0600))
+
+	keys, err := LoadTrustedKeys(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []ed25519.PublicKey{pub1, pub2}, keys)
+
+	keys, err = LoadTrustedKeys(filepath.Join(dir, "missing"))
+	assert.NoError(t, err)
+	assert.Empty(t, keys)
+}
+
+func TestPackageManager_InstallUpdateRemove(t *testing.T) {
+	manager := newTestManager(t)
+	source := t.TempDir()
+	writePackage(t, source, "rules", "1.0.0", testPackageFiles("rules"), nil)
+	ctx := context.Background()
+
+	manifest, err := manager.Install(ctx, source, ">= 1.0.0", false)
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", manifest.Version)
+	assert.FileExists(t, filepath.Join(manager.Dir, "rules", "queries", "check.kql"))
+
+	_, err = manager.Install(ctx, source, "", false)
+	assert.ErrorContains(t, err, "already installed")
+
+	_, err = manager.Install(ctx, source, ">= 2.0.0", true)
+	assert.ErrorContains(t, err, "does not satisfy")
+
+	_, updated, err := manager.Update(ctx, "rules")
+	require.NoError(t, err)
+	assert.False(t, updated, "same version is up to date")
+
+	writePackage(t, source, "rules", "1.1.0", testPackageFiles("rules"), nil)
+	manifest, updated, err = manager.Update(ctx, "rules")
+	require.NoError(t, err)
+	assert.True(t, updated)
+	assert.Equal(t, "1.1.0", manifest.Version)
+
+	installed, err := manager.List()
+	require.NoError(t, err)
+	require.Len(t, installed, 1)
+	assert.Equal(t, "1.1.0", installed[0].Manifest.Version)
+	assert.Equal(t, source, installed[0].Install.Source)
+
+	result, err := manager.Verify("rules")
+	require.NoError(t, err)
+	assert.False(t, result.Signed)
+
+	require.NoError(t, manager.Remove("rules"))
+	assert.NoDirExists(t, filepath.Join(manager.Dir, "rules"))
+	assert.Error(t, manager.Remove("rules"))
+}
+
+func TestPackageManager_InstallRejects(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("incompatible azqr", func(t *testing.T) {
+		manager := newTestManager(t)
+		source := t.TempDir()
+		writePackage(t, source, "rules", "1.0.0", testPackageFiles("rules"), nil)
+		manifestPath := filepath.Join(source, PackageManifestFile)
+		var manifest PackageManifest
+		data, _ := os.ReadFile(manifestPath)
+		require.NoError(t, json.Unmarshal(data, &manifest))
+		manifest.AzqrVersion = ">= 3.0.0"
+		data, _ = json.Marshal(manifest)
+		require.NoError(t, os.WriteFile(manifestPath, data, 0600))
+
+		_, err := manager.Install(ctx, source, "", false)
+		assert.ErrorContains(t, err, "requires azqr >= 3.0.0")
+	})
+
+	t.Run("invalid plugin", func(t *testing.T) {
+		manager := newTestManager(t)
+		source := t.TempDir()
+		writePackage(t, source, "rules", "1.0.0", map[string]string{"rules.yaml": "name: rules\n"}, nil)
+
+		_, err := manager.Install(ctx, source, "", false)
+		assert.ErrorContains(t, err, "rules.yaml")
+		assert.NoDirExists(t, filepath.Join(manager.Dir, "rules"))
+	})
+
+	t.Run("unlisted query file", func(t *testing.T) {
+		manager := newTestManager(t)
+		source := t.TempDir()
+		writePackage(t, source, "rules", "1.0.0", map[string]string{"rules.yaml": testPackageYaml("rules")}, nil)
+		require.NoError(t, os.MkdirAll(filepath.Join(source, "queries"), 0750))
+		require.NoError(t, os.WriteFile(filepath.Join(source, "queries", "check.kql"), []byte("resources"), 0600))
+
+		_, err := manager.Install(ctx, source, "", false)
+		assert.ErrorContains(t, err, "check.kql", "files not in the manifest are not installed")
+	})
+
+	t.Run("signature required", func(t *testing.T) {
+		manager := newTestManager(t)
+		manager.RequireSignature = true
+		source := t.TempDir()
+		writePackage(t, source, "rules", "1.0.0", testPackageFiles("rules"), nil)
+
+		_, err := manager.Install(ctx, source, "", false)
+		assert.ErrorContains(t, err, "not signed")
+	})
+
+	t.Run("changed after verification", func(t *testing.T) {
+		manager := newTestManager(t)
+		source := t.TempDir()
+		writePackage(t, source, "rules", "1.0.0", testPackageFiles("rules"), nil)
+		result, err := VerifyPackage(source, nil, false)
+		require.NoError(t, err)
+
+		require.NoError(t, os.WriteFile(filepath.Join(source, "queries", "check.kql"), []byte("resources | take 1"), 0600))
+		err = manager.copyPackage(source, result.Manifest, source, nil, false)
+		assert.ErrorContains(t, err, "changed while installing")
+		assert.NoDirExists(t, filepath.Join(manager.Dir, "rules"))
+
+		// A manifest swapped for another valid one is rejected too
+		writePackage(t, source, "rules", "1.0.1", testPackageFiles("rules"), nil)
+		err = manager.copyPackage(source, result.Manifest, source, nil, false)
+		assert.ErrorContains(t, err, "rules changed while installing")
+		assert.NoDirExists(t, filepath.Join(manager.Dir, "rules"))
+	})
+}
+
+func TestPackageManager_InstallArchives(t *testing.T) {
+	ctx := context.Background()
+	source := t.TempDir()
+	writePackage(t, source, "rules", "1.0.0", testPackageFiles("rules"), nil)
+	entries := map[string]string{}
+	for _, rel := range []string{PackageManifestFile, "rules.yaml", "queries/check.kql"} {
+		data, err := os.ReadFile(filepath.Join(source, filepath.FromSlash(rel)))
+		require.NoError(t, err)
+		entries["rules-1.0.0/"+rel] = string(data)
+	}
+
+	t.Run("tar.gz", func(t *testing.T) {
+		manager := newTestManager(t)
+		archive := filepath.Join(t.TempDir(), "rules.tar.gz")
+		writeTarGz(t, archive, entries)
+
+		manifest, err := manager.Install(ctx, archive, "", false)
+		require.NoError(t, err)
+		assert.Equal(t, "rules", manifest.Name)
+		assert.FileExists(t, filepath.Join(manager.Dir, "rules", "rules.yaml"))
+	})
+
+	t.Run("zip", func(t *testing.T) {
+		manager := newTestManager(t)
+		archive := filepath.Join(t.TempDir(), "rules.zip")
+		writeZip(t, archive, entries)
+
+		manifest, err := manager.Install(ctx, archive, "", false)
+		require.NoError(t, err)
+		assert.Equal(t, "rules", manifest.Name)
+	})
+
+	t.Run("entry escaping the package", func(t *testing.T) {
+		manager := newTestManager(t)
+		archive := filepath.Join(t.TempDir(), "evil.zip")
+		writeZip(t, archive, map[string]string{"../evil.yaml": "name: evil"})
+
+		_, err := manager.Install(ctx, archive, "", false)
+		assert.ErrorContains(t, err, "escapes the package")
+	})
+
+	t.Run("unsupported archive", func(t *testing.T) {
+		manager := newTestManager(t)
+		archive := filepath.Join(t.TempDir(), "rules.rar")
+		require.NoError(t, os.WriteFile(archive, []byte("x"), 0600))
+
+		_, err := manager.Install(ctx, archive, "", false)
+		assert.ErrorContains(t, err, "unsupported package archive")
+	})
+}
+
+func TestPackageManager_InstallFromURL(t *testing.T) {
+	ctx := context.Background()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	archives := map[string]string{}
+	for name, key := range map[string]ed25519.PrivateKey{"signed": priv, "unsigned": nil} {
+		source := t.TempDir()
+		writePackage(t, source, "rules", "1.0.0", testPackageFiles("rules"), key)
+		entries := map[string]string{}
+		for _, rel := range []string{PackageManifestFile, PackageSignatureFile, "rules.yaml", "queries/check.kql"} {
+			data, err := os.ReadFile(filepath.Join(source, filepath.FromSlash(rel))) //nolint:gosec // test file
+			if err == nil {
+				entries[rel] = string(data)
+			}
+		}
+		archive := filepath.Join(t.TempDir(), name+".tar.gz")
+		writeTarGz(t, archive, entries)
+		archives["/"+name+".tar.gz"] = archive
+	}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, archives[r.URL.Path])
+	}))
+	defer server.Close()
+
+	newManager := func(t *testing.T) *PackageManager {
+		manager := newTestManager(t)
+		manager.HTTPClient = server.Client()
+		require.NoError(t, os.MkdirAll(manager.TrustedKeysDir, 0750))
+		require.NoError(t, os.WriteFile(filepath.Join(manager.TrustedKeysDir, "test.pub"), []byte(base64.StdEncoding.EncodeToString(pub)), 0600))
+		return manager
+	}
+
+	t.Run("signed", func(t *testing.T) {
+		manager := newManager(t)
+		manifest, err := manager.Install(ctx, server.URL+"/signed.tar.gz", "", false)
+		require.NoError(t, err)
+		assert.Equal(t, "rules", manifest.Name)
+	})
+
+	t.Run("unsigned", func(t *testing.T) {
+		manager := newManager(t)
+		_, err := manager.Install(ctx, server.URL+"/unsigned.tar.gz", "", false)
+		assert.ErrorContains(t, err, "not signed")
+		assert.NoDirExists(t, filepath.Join(manager.Dir, "rules"))
+	})
+
+	t.Run("plain http", func(t *testing.T) {
+		manager := newManager(t)
+		_, err := manager.Install(ctx, "http://example.com/signed.tar.gz", "", false)
+		assert.ErrorContains(t, err, "insecure http")
+	})
+}
+
+func TestDiscoverYamlPlugins_VerifiesPackages(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+
+	good := filepath.Join(dir, "good")
+	writePackage(t, good, "good", "1.0.0", testPackageFiles("good"), nil)
+	// A YAML file not listed in the manifest is not loaded
+	require.NoError(t, os.WriteFile(filepath.Join(good, "extra.yaml"), []byte(testPackageYaml("extra")), 0600))
+
+	bad := filepath.Join(dir, "bad")
+	writePackage(t, bad, "bad", "1.0.0", testPackageFiles("bad"), nil)
+	require.NoError(t, os.WriteFile(filepath.Join(bad, "queries", "check.kql"), []byte("tampered"), 0600))
+
+	plugins, err := discoverYamlPlugins([]string{dir})
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(plugins))
+	for _, p := range plugins {
+		names = append(names, p.Metadata.Name)
+	}
+	assert.Equal(t, []string{"good"}, names)
+}
+
+func writeTarGz(t *testing.T, path string, entries map[string]string) {
+	t.Helper()
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for name, content := range entries {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	require.NoError(t, f.Close())
+}
+
+func writeZip(t *testing.T, path string, entries map[string]string) {
+	t.Helper()
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	zw := zip.NewWriter(f)
+	for name, content := range entries {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	require.NoError(t, f.Close())
+}
@@ -35,6 +35,12 @@ func (r *Registry) Register(plugin *Plugin) error {
 	if plugin.Metadata.Name == "" {
 		return fmt.Errorf("plugin name cannot be empty")
 	}
+	if err := registerPluginOptions(plugin.Metadata.Name, plugin.Metadata.Options); err != nil {
+		return err
+	}
+	if plugin.Command != nil {
+		addOptionFlags(plugin.Command, plugin.Metadata.Name)
+	}
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
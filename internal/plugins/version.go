@@ -0,0 +1,129 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package plugins
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// semVersion is a parsed major.minor.patch version with an optional
+// pre-release suffix (e.g., "1.2.0-beta.1").
+type semVersion struct {
+	parts      [3]int
+	prerelease string
+}
+
+// parseVersion parses versions such as "1", "1.2", "v1.2.3" and "1.2.3-rc.1".
+// Missing minor and patch numbers default to zero; build metadata is ignored.
+func parseVersion(s string) (semVersion, error) {
+	var v semVersion
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	s, _, _ = strings.Cut(s, "+")
+	s, v.prerelease, _ = strings.Cut(s, "-")
+
+	fields := strings.Split(s, ".")
+	if s == "" || len(fields) > 3 {
+		return v, fmt.Errorf("invalid version %q", s)
+	}
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 {
+			return v, fmt.Errorf("invalid version %q", s)
+		}
+		v.parts[i] = n
+	}
+	return v, nil
+}
+
+// compare returns -1, 0 or 1. A pre-release sorts before its release.
+func (v semVersion) compare(o semVersion) int {
+	for i := range v.parts {
+		if v.parts[i] != o.parts[i] {
+			if v.parts[i] < o.parts[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case v.prerelease == o.prerelease:
+		return 0
+	case v.prerelease == "":
+		return 1
+	case o.prerelease == "":
+		return -1
+	case v.prerelease < o.prerelease:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// compareVersions compares two version strings, returning -1, 0 or 1.
+func compareVersions(a, b string) (int, error) {
+	va, err := parseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := parseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+	return va.compare(vb), nil
+}
+
+// versionSatisfies reports whether version meets every comma-separated clause
+// of the constraint, e.g. ">= 1.2.0, < 2.0.0". Supported operators are
+// =, !=, >, >=, < and <=; a bare version means =. An empty constraint
+// matches every version.
+func versionSatisfies(version, constraint string) (bool, error) {
+	v, err := parseVersion(version)
+	if err != nil {
+		return false, err
+	}
+
+	for _, clause := range strings.Split(constraint, ",") {
+		clause = strings.TrimSpace(clause)
+		if clause == "" {
+			continue
+		}
+
+		op := "="
+		for _, candidate := range []string{">=", "<=", "!=", "==", ">", "<", "="} {
+			if strings.HasPrefix(clause, candidate) {
+				op = candidate
+				clause = strings.TrimSpace(strings.TrimPrefix(clause, candidate))
+				break
+			}
+		}
+
+		want, err := parseVersion(clause)
+		if err != nil {
+			return false, fmt.Errorf("invalid version constraint %q: %w", constraint, err)
+		}
+
+		cmp := v.compare(want)
+		ok := false
+		switch op {
+		case "=", "==":
+			ok = cmp == 0
+		case "!=":
+			ok = cmp != 0
+		case ">":
+			ok = cmp > 0
+		case ">=":
+			ok = cmp >= 0
+		case "<":
+			ok = cmp < 0
+		case "<=":
+			ok = cmp <= 0
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
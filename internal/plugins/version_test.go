@@ -0,0 +1,61 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package plugins
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0.0", "1.0.0", 0},
+		{"1.2", "1.2.0", 0},
+		{"v1.2.3", "1.2.3", 0},
+		{"1.10.0", "1.9.0", 1},
+		{"1.0.0", "2.0.0", -1},
+		{"1.0.0-beta", "1.0.0", -1},
+		{"1.0.0-beta", "1.0.0-alpha", 1},
+		{"1.0.0+build.1", "1.0.0", 0},
+	}
+	for _, tt := range tests {
+		got, err := compareVersions(tt.a, tt.b)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, got, "compareVersions(%q, %q)", tt.a, tt.b)
+	}
+
+	_, err := compareVersions("1.x", "1.0")
+	assert.Error(t, err)
+}
+
+func TestVersionSatisfies(t *testing.T) {
+	tests := []struct {
+		version    string
+		constraint string
+		want       bool
+	}{
+		{"1.2.0", "", true},
+		{"1.2.0", "1.2.0", true},
+		{"1.2.0", "= 1.2", true},
+		{"1.2.0", "!= 1.2.0", false},
+		{"1.2.0", ">= 1.0.0, < 2.0.0", true},
+		{"2.0.0", ">= 1.0.0, < 2.0.0", false},
+		{"0.9.0", ">1.0", false},
+		{"1.0.0", "<=1.0.0", true},
+	}
+	for _, tt := range tests {
+		got, err := versionSatisfies(tt.version, tt.constraint)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, got, "versionSatisfies(%q, %q)", tt.version, tt.constraint)
+	}
+
+	_, err := versionSatisfies("1.0.0", ">= one")
+	assert.Error(t, err)
+	_, err = versionSatisfies("dev", ">= 1.0.0")
+	assert.Error(t, err)
+}
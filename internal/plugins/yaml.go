@@ -4,16 +4,23 @@
 package plugins
 
 import (
+	"crypto/ed25519"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 
 	"github.com/Azure/azqr/internal/models"
 	"github.com/rs/zerolog/log"
 	"gopkg.in/yaml.v3"
 )
 
-// LoadYamlPlugin loads a YAML plugin from a file and converts queries to AprlRecommendation format
+// scannerAbbreviationPattern matches valid service abbreviations (e.g., "st", "vnet")
+var scannerAbbreviationPattern = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+
+// LoadYamlPlugin loads a YAML plugin from a file and converts queries to AprlRecommendation format.
+// Plugins that declare tables also get a scanner and command, like internal plugins.
 func LoadYamlPlugin(filePath string) (*Plugin, []models.GraphRecommendation, error) {
 	cleanPath := filepath.Clean(filePath)
 	data, err := os.ReadFile(cleanPath) //nolint:gosec // filePath comes from plugin discovery in trusted directories
@@ -33,8 +40,8 @@ func LoadYamlPlugin(filePath string) (*Plugin, []models.GraphRecommendation, err
 	if config.Version == "" {
 		config.Version = "1.0.0"
 	}
-	if len(config.Queries) == 0 {
-		return nil, nil, fmt.Errorf("plugin must have at least one query")
+	if len(config.Queries) == 0 && len(config.Tables) == 0 && len(config.Scanners) == 0 && len(config.DiagnosticSettings) == 0 {
+		return nil, nil, fmt.Errorf("plugin must have at least one query, table, scanner or diagnostic settings recommendation")
 	}
 
 	baseDir := filepath.Dir(cleanPath)
@@ -60,33 +67,88 @@ func LoadYamlPlugin(filePath string) (*Plugin, []models.GraphRecommendation, err
 		if config.Queries[i].Description == "" {
 			return nil, nil, fmt.Errorf("query %s missing required field 'description'", config.Queries[i].AprlGuid)
 		}
+		if pct := config.Queries[i].EstimatedSavingsPct; pct < 0 || pct > 100 {
+			return nil, nil, fmt.Errorf("query %s has estimatedSavingsPct %g outside 0-100", config.Queries[i].AprlGuid, pct)
+		}
+	}
+
+	for i := range config.Tables {
+		table := &config.Tables[i]
+		if table.QueryFile != "" {
+			queryPath := filepath.Clean(filepath.Join(baseDir, table.QueryFile))
+			queryData, err := os.ReadFile(queryPath) //nolint:gosec // queryFile is relative to plugin directory
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to read query file %s: %w", table.QueryFile, err)
+			}
+			table.Query = string(queryData)
+		}
+
+		if table.SheetName == "" {
+			return nil, nil, fmt.Errorf("table missing required field 'sheetName'")
+		}
+		if table.Query == "" {
+			return nil, nil, fmt.Errorf("table %s must have either 'query' or 'queryFile' specified", table.SheetName)
+		}
+		if len(table.Columns) == 0 {
+			return nil, nil, fmt.Errorf("table %s must have at least one column", table.SheetName)
+		}
+		for j := range table.Columns {
+			if table.Columns[j].Name == "" {
+				return nil, nil, fmt.Errorf("table %s has a column without a name", table.SheetName)
+			}
+			if table.Columns[j].Field == "" {
+				table.Columns[j].Field = table.Columns[j].Name
+			}
+			if err := models.ValidateColumnType(table.Columns[j].Type); err != nil {
+				return nil, nil, fmt.Errorf("table %s column %s: %w", table.SheetName, table.Columns[j].Name, err)
+			}
+		}
+	}
+
+	for _, rec := range config.DiagnosticSettings {
+		if rec.AprlGuid == "" {
+			return nil, nil, fmt.Errorf("diagnostic settings recommendation missing required field 'aprlGuid'")
+		}
+		if rec.Description == "" {
+			return nil, nil, fmt.Errorf("diagnostic settings recommendation %s missing required field 'description'", rec.AprlGuid)
+		}
+		if rec.RecommendationResourceType == "" {
+			return nil, nil, fmt.Errorf("diagnostic settings recommendation %s missing required field 'recommendationResourceType'", rec.AprlGuid)
+		}
+	}
+
+	for i := range config.Scanners {
+		scanner := &config.Scanners[i]
+		if !scannerAbbreviationPattern.MatchString(scanner.Abbreviation) {
+			return nil, nil, fmt.Errorf("scanner abbreviation %q must be lowercase letters and digits", scanner.Abbreviation)
+		}
+		if len(scanner.ResourceTypes) == 0 {
+			return nil, nil, fmt.Errorf("scanner %s must have at least one resource type", scanner.Abbreviation)
+		}
+		for _, resourceType := range scanner.ResourceTypes {
+			if !strings.Contains(resourceType, "/") {
+				return nil, nil, fmt.Errorf("scanner %s has invalid resource type %q", scanner.Abbreviation, resourceType)
+			}
+		}
+		if scanner.ServiceName == "" {
+			scanner.ServiceName = scanner.Abbreviation
+		}
+	}
+
+	for _, opt := range config.Options {
+		if err := validatePluginOption(opt); err != nil {
+			return nil, nil, err
+		}
 	}
 
 	// Convert YamlPluginQuery to AprlRecommendation format
 	recommendations := make([]models.GraphRecommendation, 0, len(config.Queries))
 	for _, query := range config.Queries {
-		automationAvailable := "false"
-		if query.AutomationAvailable {
-			automationAvailable = "true"
-		}
-
-		recommendation := models.GraphRecommendation{
-			RecommendationID:    query.AprlGuid,
-			ResourceType:        query.RecommendationResourceType,
-			Recommendation:      query.Description,
-			Category:            query.RecommendationControl,
-			Impact:              query.RecommendationImpact,
-			LearnMoreLink:       query.LearnMoreLink,
-			GraphQuery:          query.Query,
-			Source:              config.Name,
-			MetadataState:       query.RecommendationMetadataState,
-			LongDescription:     query.LongDescription,
-			PotentialBenefits:   query.PotentialBenefits,
-			PgVerified:          query.PgVerified,
-			AutomationAvailable: automationAvailable,
-			Tags:                query.Tags,
-		}
-		recommendations = append(recommendations, recommendation)
+		recommendations = append(recommendations, query.toGraphRecommendation(config.Name))
+	}
+	diagnosticSettings := make([]models.GraphRecommendation, 0, len(config.DiagnosticSettings))
+	for _, rec := range config.DiagnosticSettings {
+		diagnosticSettings = append(diagnosticSettings, rec.toGraphRecommendation(config.Name))
 	}
 
 	plugin := &Plugin{
@@ -98,18 +160,149 @@ func LoadYamlPlugin(filePath string) (*Plugin, []models.GraphRecommendation, err
 			License:     config.License,
 			Type:        PluginTypeYaml,
 			CommandPath: filePath,
+			Options:     config.Options,
 		},
-		YamlRecommendations: recommendations,
+		YamlRecommendations:               recommendations,
+		DiagnosticSettingsRecommendations: diagnosticSettings,
+		YamlScanners:                      config.Scanners,
+	}
+
+	if len(config.Tables) > 0 {
+		scanner := &yamlTableScanner{metadata: plugin.Metadata, tables: config.Tables}
+		plugin.InternalScanner = scanner
+		plugin.Command = createPluginCommand(config.Name, config.Description)
 	}
 
 	return plugin, recommendations, nil
 }
 
-// discoverYamlPlugins searches for YAML plugins in configured directories
+// toGraphRecommendation converts a YAML plugin query to the recommendation format.
+func (query YamlPluginQuery) toGraphRecommendation(source string) models.GraphRecommendation {
+	automationAvailable := "false"
+	if query.AutomationAvailable {
+		automationAvailable = "true"
+	}
+
+	return models.GraphRecommendation{
+		RecommendationID:    query.AprlGuid,
+		ResourceType:        query.RecommendationResourceType,
+		Recommendation:      query.Description,
+		Category:            query.RecommendationControl,
+		Impact:              query.RecommendationImpact,
+		LearnMoreLink:       query.LearnMoreLink,
+		GraphQuery:          query.Query,
+		Source:              source,
+		MetadataState:       query.RecommendationMetadataState,
+		LongDescription:     query.LongDescription,
+		PotentialBenefits:   query.PotentialBenefits,
+		PgVerified:          query.PgVerified,
+		AutomationAvailable: automationAvailable,
+		Tags:                query.Tags,
+		EstimatedSavingsPct: query.EstimatedSavingsPct,
+	}
+}
+
+// registerYamlScanners adds the scanners declared by a YAML plugin to
+// models.ScannerList so they are filtered, scanned and listed like built-in
+// scanners. Scanners whose abbreviation or resource types are already
+// registered are skipped.
+func registerYamlScanners(plugin *Plugin) {
+	for _, s := range plugin.YamlScanners {
+		if owner := scannerForResourceTypes(s.ResourceTypes); owner != "" {
+			log.Warn().
+				Str("plugin", plugin.Metadata.Name).
+				Str("scanner", s.Abbreviation).
+				Str("existing", owner).
+				Msg("Resource type is already scanned by another scanner, skipping YAML scanner")
+			continue
+		}
+		if err := models.RegisterScanners(s.Abbreviation, models.NewBaseScanner(s.ServiceName, s.ResourceTypes...)); err != nil {
+			log.Warn().
+				Err(err).
+				Str("plugin", plugin.Metadata.Name).
+				Msg("Failed to register YAML scanner")
+			continue
+		}
+		log.Debug().
+			Str("plugin", plugin.Metadata.Name).
+			Str("scanner", s.Abbreviation).
+			Strs("resource_types", s.ResourceTypes).
+			Msg("Registered YAML scanner")
+	}
+}
+
+// warnUncoveredRecommendations logs the YAML plugin recommendations whose
+// resource type no registered scanner covers, since they are never executed.
+func warnUncoveredRecommendations(plugin *Plugin) {
+	for _, rec := range plugin.YamlRecommendations {
+		if rec.ResourceType != "" && scannerForResourceTypes([]string{rec.ResourceType}) == "" {
+			log.Warn().
+				Str("plugin", plugin.Metadata.Name).
+				Str("recommendation", rec.RecommendationID).
+				Str("resource_type", rec.ResourceType).
+				Msg("No scanner covers this resource type; declare it under 'scanners' to run the recommendation")
+		}
+	}
+}
+
+// scannerForResourceTypes returns the abbreviation of a registered scanner
+// covering any of the resource types, or an empty string.
+func scannerForResourceTypes(resourceTypes []string) string {
+	for key, scanners := range models.ScannerList {
+		for _, scanner := range scanners {
+			for _, t := range scanner.ResourceTypes() {
+				for _, resourceType := range resourceTypes {
+					if strings.EqualFold(t, resourceType) {
+						return key
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// discoverYamlPlugins searches for plugin packages in configured directories.
+// Packages are verified first and only the plugins listed in the manifest
+// are loaded; packages failing verification and YAML files outside packages
+// are skipped. Invalid plugins are reported as warnings.
 func discoverYamlPlugins(dirs []string) ([]*Plugin, error) {
 	plugins := make([]*Plugin, 0, 16)
 	seen := make(map[string]bool)
 
+	trustedKeys, err := LoadTrustedKeys(getTrustedKeysDir())
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to load trusted plugin keys")
+	}
+
+	load := func(path string) {
+		plugin, recommendations, err := LoadYamlPlugin(path)
+		if err != nil {
+			log.Warn().
+				Err(err).
+				Str("path", path).
+				Msg("Skipping invalid YAML plugin")
+			return
+		}
+
+		// Skip duplicates (first found wins)
+		if seen[plugin.Metadata.Name] {
+			log.Debug().
+				Str("plugin", plugin.Metadata.Name).
+				Str("path", path).
+				Msg("Skipping duplicate YAML plugin")
+			return
+		}
+		seen[plugin.Metadata.Name] = true
+
+		plugins = append(plugins, plugin)
+		log.Debug().
+			Str("plugin", plugin.Metadata.Name).
+			Str("path", path).
+			Int("queries", len(recommendations)).
+			Msg("Discovered YAML plugin")
+	}
+
 	for _, dir := range dirs {
 		// Check if directory exists
 		info, err := os.Stat(dir)
@@ -124,49 +317,30 @@ func discoverYamlPlugins(dirs []string) ([]*Plugin, error) {
 			continue
 		}
 
-		// Walk directory looking for .yaml or .yml files
+		// Walk directory looking for plugin packages
 		err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
 			if err != nil {
 				return nil // Skip files we can't access
 			}
 
 			if info.IsDir() {
+				// Skip staging directories of in-progress installs
+				if path != dir && strings.HasPrefix(info.Name(), ".") {
+					return filepath.SkipDir
+				}
+				if IsPackageDir(path) {
+					loadYamlPackage(path, trustedKeys, load)
+					return filepath.SkipDir
+				}
 				return nil
 			}
 
-			// Only process .yaml and .yml files
-			ext := filepath.Ext(path)
-			if ext != ".yaml" && ext != ".yml" {
-				return nil
-			}
-
-			// Try to load as plugin
-			plugin, recommendations, err := LoadYamlPlugin(path)
-			if err != nil {
-				log.Debug().
-					Err(err).
-					Str("path", path).
-					Msg("Skipping file - not a valid YAML plugin")
-				return nil
-			}
-
-			// Skip duplicates (first found wins)
-			if seen[plugin.Metadata.Name] {
-				log.Debug().
-					Str("plugin", plugin.Metadata.Name).
+			// YAML files outside packages are not verified and are not loaded
+			if ext := filepath.Ext(path); ext == ".yaml" || ext == ".yml" {
+				log.Warn().
 					Str("path", path).
-					Msg("Skipping duplicate YAML plugin")
-				return nil
+					Msg("Skipping YAML plugin outside a plugin package, install it with 'azqr plugins install'")
 			}
-			seen[plugin.Metadata.Name] = true
-
-			plugins = append(plugins, plugin)
-			log.Debug().
-				Str("plugin", plugin.Metadata.Name).
-				Str("path", path).
-				Int("queries", len(recommendations)).
-				Msg("Discovered YAML plugin")
-
 			return nil
 		})
 
@@ -180,3 +354,21 @@ func discoverYamlPlugins(dirs []string) ([]*Plugin, error) {
 
 	return plugins, nil
 }
+
+// loadYamlPackage verifies a plugin package and loads the YAML plugins it lists.
+func loadYamlPackage(dir string, trustedKeys []ed25519.PublicKey, load func(path string)) {
+	result, err := VerifyPackage(dir, trustedKeys, false)
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Str("path", dir).
+			Msg("Skipping plugin package that failed verification")
+		return
+	}
+	for _, f := range result.Manifest.Files {
+		if ext := filepath.Ext(f.Path); ext == ".yaml" || ext == ".yml" {
+			path, _ := packageFilePath(dir, f.Path)
+			load(path)
+		}
+	}
+}
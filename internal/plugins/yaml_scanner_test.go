@@ -0,0 +1,98 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package plugins
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Azure/azqr/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadYamlPlugin_Scanners(t *testing.T) {
+	path := writeYamlPlugin(t, `---
+name: healthcare
+scanners:
+  - abbreviation: hdw
+    serviceName: Health Data Services Workspace
+    resourceTypes:
+      - Microsoft.HealthcareApis/workspaces
+  - abbreviation: fhir
+    resourceTypes:
+      - Microsoft.HealthcareApis/workspaces/fhirservices
+queries:
+  - aprlGuid: hdw-001
+    description: Workspace should use private endpoints
+    recommendationResourceType: Microsoft.HealthcareApis/workspaces
+    query: resources
+`)
+	plugin, _, err := LoadYamlPlugin(path)
+	require.NoError(t, err)
+	require.Len(t, plugin.YamlScanners, 2)
+	assert.Equal(t, "Health Data Services Workspace", plugin.YamlScanners[0].ServiceName)
+	assert.Equal(t, "fhir", plugin.YamlScanners[1].ServiceName, "service name defaults to the abbreviation")
+}
+
+func TestLoadYamlPlugin_ScannerValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		scanner string
+		wantErr string
+	}{
+		{
+			name:    "missing abbreviation",
+			scanner: "  - resourceTypes: [Microsoft.Test/things]\n",
+			wantErr: "must be lowercase letters and digits",
+		},
+		{
+			name:    "invalid abbreviation",
+			scanner: "  - abbreviation: My-Scanner\n    resourceTypes: [Microsoft.Test/things]\n",
+			wantErr: "must be lowercase letters and digits",
+		},
+		{
+			name:    "missing resource types",
+			scanner: "  - abbreviation: test\n",
+			wantErr: "at least one resource type",
+		},
+		{
+			name:    "invalid resource type",
+			scanner: "  - abbreviation: test\n    resourceTypes: [things]\n",
+			wantErr: "invalid resource type",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeYamlPlugin(t, "name: test-plugin\nscanners:\n"+tt.scanner)
+			_, _, err := LoadYamlPlugin(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestRegisterYamlScanners(t *testing.T) {
+	t.Cleanup(func() {
+		delete(models.ScannerList, "yamlnew")
+		delete(models.ScannerList, "yamlold")
+		delete(models.ScannerList, "yamldup")
+	})
+	require.NoError(t, models.RegisterScanners("yamlold", models.NewBaseScanner("Old", "Microsoft.Old/things")))
+
+	registerYamlScanners(&Plugin{
+		Metadata: PluginMetadata{Name: "test-plugin"},
+		YamlScanners: []YamlPluginScanner{
+			{Abbreviation: "yamlnew", ServiceName: "New", ResourceTypes: []string{"Microsoft.New/things"}},
+			{Abbreviation: "yamldup", ServiceName: "Duplicate type", ResourceTypes: []string{"microsoft.old/things"}},
+			{Abbreviation: "yamlold", ServiceName: "Duplicate key", ResourceTypes: []string{"Microsoft.Other/things"}},
+		},
+	})
+
+	require.Len(t, models.ScannerList["yamlnew"], 1)
+	assert.Equal(t, []string{"Microsoft.New/things"}, models.ScannerList["yamlnew"][0].ResourceTypes())
+	assert.NotContains(t, models.ScannerList, "yamldup", "resource types already scanned are skipped")
+	assert.Equal(t, "Old", models.ScannerList["yamlold"][0].ServiceName(), "existing abbreviations are kept")
+}
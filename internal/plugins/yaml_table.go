@@ -0,0 +1,118 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package plugins
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azqr/internal/graph"
+	"github.com/Azure/azqr/internal/models"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/rs/zerolog/log"
+)
+
+// yamlTableScanner runs the table queries of a YAML plugin. It implements
+// InternalPluginScanner so each table is rendered as a plugin sheet.
+type yamlTableScanner struct {
+	metadata PluginMetadata
+	tables   []YamlPluginTable
+}
+
+// GetMetadata implements InternalPluginScanner.
+func (s *yamlTableScanner) GetMetadata() PluginMetadata {
+	return s.metadata
+}
+
+// Scan implements InternalPluginScanner by running each table query against Resource Graph.
+func (s *yamlTableScanner) Scan(ctx context.Context, cred azcore.TokenCredential, subscriptions map[string]string, params *models.ScanParams) ([]ExternalPluginOutput, error) {
+	graphClient := graph.NewGraphQuery(cred)
+
+	var stages *models.StageConfigs
+	if params != nil {
+		stages = params.Stages
+	}
+	replacer := yamlQueryReplacer(stages.GetPluginOptions(s.metadata.Name))
+
+	outputs := make([]ExternalPluginOutput, 0, len(s.tables))
+	for _, table := range s.tables {
+		log.Info().
+			Str("plugin", s.metadata.Name).
+			Str("sheet", table.SheetName).
+			Msg("Running YAML plugin table query")
+
+		result, err := graphClient.Query(ctx, replacer.Replace(table.Query), subscriptions)
+		if err != nil {
+			return nil, fmt.Errorf("table %s: %w", table.SheetName, err)
+		}
+
+		var rows []json.RawMessage
+		if result != nil {
+			rows = result.Data
+		}
+		outputs = append(outputs, ExternalPluginOutput{
+			Metadata:    s.metadata,
+			SheetName:   table.SheetName,
+			Description: table.Description,
+			Table:       yamlTableRows(table.Columns, rows),
+			Columns:     yamlTableColumns(table.Columns),
+		})
+	}
+	return outputs, nil
+}
+
+// yamlQueryReplacer replaces the {{name}} placeholders of plugin options in
+// table queries with the option values.
+func yamlQueryReplacer(options map[string]any) *strings.Replacer {
+	pairs := make([]string, 0, len(options)*2)
+	for key, value := range options {
+		pairs = append(pairs, "{{"+key+"}}", fmt.Sprint(value))
+	}
+	return strings.NewReplacer(pairs...)
+}
+
+// yamlTableColumns returns the column metadata of a table.
+func yamlTableColumns(columns []YamlPluginColumn) []ColumnMetadata {
+	metadata := make([]ColumnMetadata, len(columns))
+	for i, col := range columns {
+		metadata[i] = ColumnMetadata{Name: col.Name, Type: col.Type, Unit: col.Unit}
+	}
+	return metadata
+}
+
+// yamlTableRows converts Resource Graph rows into a table with a header row
+// followed by one row per result, in column order.
+func yamlTableRows(columns []YamlPluginColumn, data []json.RawMessage) [][]string {
+	header := make([]string, len(columns))
+	for i, col := range columns {
+		header[i] = col.Name
+	}
+
+	table := make([][]string, 0, len(data)+1)
+	table = append(table, header)
+	for _, row := range graph.UnmarshalRows[map[string]json.RawMessage](data, "YAML plugin table") {
+		values := make([]string, len(columns))
+		for i, col := range columns {
+			values[i] = yamlTableValue(row[col.Field])
+		}
+		table = append(table, values)
+	}
+	return table
+}
+
+// yamlTableValue formats a JSON value for a table cell. Strings are unquoted,
+// null and missing values are empty and objects or arrays are kept as JSON.
+func yamlTableValue(raw json.RawMessage) string {
+	value := strings.TrimSpace(string(raw))
+	if value == "" || value == "null" {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	return value
+}
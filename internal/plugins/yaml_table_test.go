@@ -0,0 +1,122 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package plugins
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadYamlPlugin_Tables(t *testing.T) {
+	path := writeYamlPlugin(t, `---
+name: inventory
+description: Inventory reports
+tables:
+  - sheetName: VMs Without Backup
+    description: Virtual machines without a backup item
+    query: |
+      resources
+      | where type =~ 'microsoft.compute/virtualmachines'
+      | project id, name, owner = tostring(tags.owner)
+    columns:
+      - name: Resource ID
+        field: id
+      - name: name
+      - name: Owner
+        field: owner
+`)
+	plugin, recommendations, err := LoadYamlPlugin(path)
+	require.NoError(t, err)
+	assert.Empty(t, recommendations)
+	require.NotNil(t, plugin.InternalScanner, "table plugins need a scanner")
+	require.NotNil(t, plugin.Command, "table plugins need a command")
+	assert.Equal(t, "inventory", plugin.Command.Use)
+	assert.Equal(t, "inventory", plugin.InternalScanner.GetMetadata().Name)
+
+	scanner := plugin.InternalScanner.(*yamlTableScanner)
+	require.Len(t, scanner.tables, 1)
+	assert.Equal(t, "name", scanner.tables[0].Columns[1].Field, "field defaults to the column name")
+}
+
+func TestLoadYamlPlugin_QueriesOnlyHasNoScanner(t *testing.T) {
+	path := writeYamlPlugin(t, `---
+name: checks
+queries:
+  - aprlGuid: guid-001
+    description: A recommendation
+    query: resources
+`)
+	plugin, _, err := LoadYamlPlugin(path)
+	require.NoError(t, err)
+	assert.Nil(t, plugin.InternalScanner)
+	assert.Nil(t, plugin.Command)
+}
+
+func TestLoadYamlPlugin_TableValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		table   string
+		wantErr string
+	}{
+		{
+			name:    "missing sheet name",
+			table:   "  - query: resources\n    columns: [{name: id}]\n",
+			wantErr: "sheetName",
+		},
+		{
+			name:    "missing query",
+			table:   "  - sheetName: Inventory\n    columns: [{name: id}]\n",
+			wantErr: "must have either 'query' or 'queryFile'",
+		},
+		{
+			name:    "missing columns",
+			table:   "  - sheetName: Inventory\n    query: resources\n",
+			wantErr: "at least one column",
+		},
+		{
+			name:    "unnamed column",
+			table:   "  - sheetName: Inventory\n    query: resources\n    columns: [{field: id}]\n",
+			wantErr: "column without a name",
+		},
+		{
+			name:    "unknown column type",
+			table:   "  - sheetName: Inventory\n    query: resources\n    columns: [{name: id, type: money}]\n",
+			wantErr: "unsupported column type",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeYamlPlugin(t, "name: inventory\ntables:\n"+tt.table)
+			_, _, err := LoadYamlPlugin(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestYamlTableRows(t *testing.T) {
+	columns := []YamlPluginColumn{
+		{Name: "Name", Field: "name"},
+		{Name: "Count", Field: "count"},
+		{Name: "Tags", Field: "tags"},
+		{Name: "Owner", Field: "owner"},
+	}
+	data := []json.RawMessage{
+		json.RawMessage(`{"name":"vm1","count":3,"tags":{"env":"prod"},"owner":null}`),
+		json.RawMessage(`{"name":"vm2"}`),
+	}
+
+	table := yamlTableRows(columns, data)
+
+	assert.Equal(t, [][]string{
+		{"Name", "Count", "Tags", "Owner"},
+		{"vm1", "3", `{"env":"prod"}`, ""},
+		{"vm2", "", "", ""},
+	}, table)
+}
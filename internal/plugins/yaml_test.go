@@ -271,9 +271,9 @@ func TestDiscoverYamlPluginsMultiple(t *testing.T) {
 		{"plugin-three", "custom-checks.yaml"},
 	}
 
+	files := make(map[string]string, len(plugins))
 	for _, p := range plugins {
-		yamlPath := filepath.Join(tmpDir, p.filename)
-		yamlContent := `name: ` + p.name + `
+		files[p.filename] = `name: ` + p.name + `
 version: 1.0.0
 description: Test plugin ` + p.name + `
 queries:
@@ -284,10 +284,8 @@ queries:
     recommendationResourceType: Microsoft.Test/resources
     query: resources
 `
-		if err := os.WriteFile(yamlPath, []byte(yamlContent), 0600); err != nil {
-			t.Fatalf("Failed to create YAML file: %v", err)
-		}
 	}
+	writePackage(t, filepath.Join(tmpDir, "multiple"), "multiple", "1.0.0", files, nil)
 
 	// Discover plugins
 	discovered, err := discoverYamlPlugins([]string{tmpDir})
@@ -325,12 +323,7 @@ func TestDiscoverYamlPluginsIgnoresNonYaml(t *testing.T) {
 		"data.txt":    "some text data",
 	}
 
-	for filename, content := range files {
-		path := filepath.Join(tmpDir, filename)
-		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
-			t.Fatalf("Failed to create file %s: %v", filename, err)
-		}
-	}
+	writePackage(t, filepath.Join(tmpDir, "mixed"), "mixed", "1.0.0", files, nil)
 
 	discovered, err := discoverYamlPlugins([]string{tmpDir})
 	if err != nil {
@@ -346,22 +339,11 @@ func TestDiscoverYamlPluginsIgnoresNonYaml(t *testing.T) {
 // TestDiscoverYamlPluginsDuplicates tests that duplicate plugins are handled correctly
 func TestDiscoverYamlPluginsDuplicates(t *testing.T) {
 	tmpDir := t.TempDir()
-	subDir := filepath.Join(tmpDir, "subdir")
-	if err := os.MkdirAll(subDir, 0750); err != nil {
-		t.Fatalf("Failed to create subdirectory: %v", err)
-	}
-
-	// Create same plugin in two locations
-	yamlContent := validPluginYaml()
-	path1 := filepath.Join(tmpDir, "plugin.yaml")
-	path2 := filepath.Join(subDir, "plugin.yaml")
 
-	if err := os.WriteFile(path1, []byte(yamlContent), 0600); err != nil {
-		t.Fatalf("Failed to create first YAML file: %v", err)
-	}
-	if err := os.WriteFile(path2, []byte(yamlContent), 0600); err != nil {
-		t.Fatalf("Failed to create second YAML file: %v", err)
-	}
+	// Create the same plugin in two packages
+	files := map[string]string{"plugin.yaml": validPluginYaml()}
+	writePackage(t, filepath.Join(tmpDir, "first"), "first", "1.0.0", files, nil)
+	writePackage(t, filepath.Join(tmpDir, "second"), "second", "1.0.0", files, nil)
 
 	discovered, err := discoverYamlPlugins([]string{tmpDir})
 	if err != nil {
@@ -374,6 +356,23 @@ func TestDiscoverYamlPluginsDuplicates(t *testing.T) {
 	}
 }
 
+// TestDiscoverYamlPluginsSkipsLooseFiles tests that YAML files outside packages are not loaded
+func TestDiscoverYamlPluginsSkipsLooseFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "plugin.yaml"), []byte(validPluginYaml()), 0600); err != nil {
+		t.Fatalf("Failed to create YAML file: %v", err)
+	}
+
+	discovered, err := discoverYamlPlugins([]string{tmpDir})
+	if err != nil {
+		t.Fatalf("discoverYamlPlugins failed: %v", err)
+	}
+
+	if len(discovered) != 0 {
+		t.Errorf("Expected loose YAML files to be skipped, got %d plugins", len(discovered))
+	}
+}
+
 // Helper functions
 
 func validPluginYaml() string {
@@ -63,6 +63,22 @@ queries:
 	}
 }
 
+func TestLoadYamlPlugin_EstimatedSavingsPctOutOfRange(t *testing.T) {
+	path := writeYamlPlugin(t, `---
+name: test-plugin
+queries:
+  - aprlGuid: guid-001
+    description: A recommendation
+    estimatedSavingsPct: 150
+    query: |
+      resources | project id
+`)
+	_, _, err := LoadYamlPlugin(path)
+	if err == nil || !strings.Contains(err.Error(), "estimatedSavingsPct") {
+		t.Fatalf("expected estimatedSavingsPct range error, got %v", err)
+	}
+}
+
 func TestLoadYamlPlugin_MissingQueryFile(t *testing.T) {
 	// queryFile references a file that does not exist.
 	dir := t.TempDir()
@@ -131,3 +147,42 @@ queries:
 		t.Errorf("expected default version 1.0.0, got %q", plugin.Metadata.Version)
 	}
 }
+
+func TestLoadYamlPlugin_DiagnosticSettings(t *testing.T) {
+	path := writeYamlPlugin(t, `---
+name: test-plugin
+diagnosticSettings:
+  - aprlGuid: custom-kv-001
+    description: Send Key Vault logs to the SOC workspace
+    recommendationControl: Security
+    recommendationImpact: High
+    recommendationResourceType: Microsoft.KeyVault/vaults
+`)
+	plugin, recs, err := LoadYamlPlugin(path)
+	if err != nil {
+		t.Fatalf("LoadYamlPlugin failed: %v", err)
+	}
+	if len(recs) != 0 {
+		t.Errorf("expected no graph recommendations, got %d", len(recs))
+	}
+	if len(plugin.DiagnosticSettingsRecommendations) != 1 {
+		t.Fatalf("expected 1 diagnostic settings recommendation, got %d", len(plugin.DiagnosticSettingsRecommendations))
+	}
+	rec := plugin.DiagnosticSettingsRecommendations[0]
+	if rec.RecommendationID != "custom-kv-001" || rec.ResourceType != "Microsoft.KeyVault/vaults" || rec.Source != "test-plugin" {
+		t.Errorf("unexpected recommendation: %+v", rec)
+	}
+}
+
+func TestLoadYamlPlugin_DiagnosticSettingsMissingResourceType(t *testing.T) {
+	path := writeYamlPlugin(t, `---
+name: test-plugin
+diagnosticSettings:
+  - aprlGuid: custom-kv-001
+    description: Send Key Vault logs to the SOC workspace
+`)
+	_, _, err := LoadYamlPlugin(path)
+	if err == nil || !strings.Contains(err.Error(), "recommendationResourceType") {
+		t.Fatalf("expected missing resource type error, got %v", err)
+	}
+}
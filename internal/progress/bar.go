@@ -0,0 +1,121 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	barWidth        = 30
+	redrawInterval  = 100 * time.Millisecond
+	clearLineEscape = "\r\033[K"
+)
+
+// Bar renders a single-line live progress bar, typically to stderr. Bar is
+// also an io.Writer: log output written through it is printed above the bar,
+// so that logs and the bar can share the stream.
+type Bar struct {
+	w io.Writer
+
+	mu         sync.Mutex
+	visible    bool
+	stage      string
+	stageIndex int
+	stageCount int
+	last       Event
+	lastDraw   time.Time
+}
+
+// NewBar creates a progress bar that writes to w.
+func NewBar(w io.Writer) *Bar {
+	return &Bar{w: w}
+}
+
+// IsTerminal reports whether f is attached to a character device (a terminal),
+// where a live progress bar can be redrawn in place.
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// OnEvent implements Observer.
+func (b *Bar) OnEvent(e Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch e.Type {
+	case EventStageStarted:
+		b.stage = e.Stage
+		b.stageIndex = e.StageIndex
+		b.stageCount = e.StageCount
+		b.last = Event{}
+		b.draw(true)
+	case EventStageFinished:
+		_, _ = fmt.Fprint(b.w, clearLineEscape)
+		b.visible = false
+		b.last = Event{}
+	case EventTaskProgress:
+		b.last = e
+		b.draw(e.Done >= e.Total)
+	}
+}
+
+// draw redraws the bar, throttled to redrawInterval unless force is set.
+func (b *Bar) draw(force bool) {
+	if !force && time.Since(b.lastDraw) < redrawInterval {
+		return
+	}
+	b.lastDraw = time.Now()
+	b.visible = true
+	_, _ = fmt.Fprint(b.w, clearLineEscape+b.render())
+}
+
+// Write clears the bar, writes p, which should end with a newline, and
+// redraws the bar below it.
+func (b *Bar) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.visible {
+		return b.w.Write(p)
+	}
+	_, _ = fmt.Fprint(b.w, clearLineEscape)
+	n, err := b.w.Write(p)
+	_, _ = fmt.Fprint(b.w, b.render())
+	return n, err
+}
+
+// render formats the current state as a single line.
+func (b *Bar) render() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "[%d/%d] %s", b.stageIndex, b.stageCount, b.stage)
+
+	e := b.last
+	if e.Total <= 0 {
+		return sb.String()
+	}
+
+	ratio := float64(e.Done) / float64(e.Total)
+	ratio = min(ratio, 1)
+	filled := int(ratio * barWidth)
+	fmt.Fprintf(&sb, " [%s%s] %d/%d %s",
+		strings.Repeat("#", filled), strings.Repeat(".", barWidth-filled),
+		e.Done, e.Total, e.Task)
+	if e.Subscription != "" {
+		fmt.Fprintf(&sb, " (%s)", e.Subscription)
+	}
+	if e.ETA > 0 {
+		fmt.Fprintf(&sb, " ETA %s", e.ETA.Round(time.Second))
+	}
+	return sb.String()
+}
@@ -0,0 +1,156 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+// Package progress provides typed progress events for long-running scans.
+// The pipeline emits stage events, and the long-running loops (Graph rule
+// workers, diagnostics batches, plugin subscriptions, region comparisons)
+// emit task events through the Observer attached to their context.
+package progress
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// EventType identifies the kind of progress event.
+type EventType string
+
+const (
+	// EventStageStarted is emitted when a pipeline stage starts executing.
+	EventStageStarted EventType = "stage_started"
+	// EventStageFinished is emitted when a pipeline stage completes or fails.
+	EventStageFinished EventType = "stage_finished"
+	// EventTaskProgress is emitted when a unit of work inside a stage completes.
+	EventTaskProgress EventType = "task_progress"
+)
+
+// Event is a single progress update.
+type Event struct {
+	Type EventType
+	// Stage is the pipeline stage name (stage events only)
+	Stage string
+	// StageIndex is the 1-based position of the stage in the pipeline
+	StageIndex int
+	// StageCount is the number of stages in the pipeline
+	StageCount int
+	// Task describes the unit being counted (e.g. "Graph rules")
+	Task string
+	// Done and Total count the completed and total units of the task
+	Done, Total int
+	// Subscription is the subscription currently being processed, when known
+	Subscription string
+	// Elapsed is the time since the stage or task started
+	Elapsed time.Duration
+	// ETA is the estimated remaining time for the task (zero when unknown)
+	ETA time.Duration
+	// Err is set on EventStageFinished when the stage failed
+	Err error
+}
+
+// Observer receives progress events. Implementations must be safe for
+// concurrent use because loops emit events from worker goroutines.
+type Observer interface {
+	OnEvent(Event)
+}
+
+// ObserverFunc adapts a function to the Observer interface.
+type ObserverFunc func(Event)
+
+// OnEvent implements Observer.
+func (f ObserverFunc) OnEvent(e Event) {
+	f(e)
+}
+
+// Observers fans events out to several observers.
+type Observers []Observer
+
+// OnEvent implements Observer.
+func (o Observers) OnEvent(e Event) {
+	for _, obs := range o {
+		obs.OnEvent(e)
+	}
+}
+
+type observerKey struct{}
+
+// WithObserver returns a context that carries the observer.
+func WithObserver(ctx context.Context, o Observer) context.Context {
+	return context.WithValue(ctx, observerKey{}, o)
+}
+
+// FromContext returns the observer carried by ctx, or nil.
+func FromContext(ctx context.Context) Observer {
+	if ctx == nil {
+		return nil
+	}
+	o, _ := ctx.Value(observerKey{}).(Observer)
+	return o
+}
+
+// Emit sends the event to the observer carried by ctx, if any.
+func Emit(ctx context.Context, e Event) {
+	if o := FromContext(ctx); o != nil {
+		o.OnEvent(e)
+	}
+}
+
+// Tracker counts completed units of a task and emits EventTaskProgress
+// events with an ETA extrapolated from the average time per unit.
+type Tracker struct {
+	observer Observer
+	task     string
+	total    int
+	start    time.Time
+
+	mu   sync.Mutex
+	done int
+}
+
+// NewTracker creates a tracker for a task of total units. It is a no-op
+// when ctx carries no observer.
+func NewTracker(ctx context.Context, task string, total int) *Tracker {
+	return &Tracker{
+		observer: FromContext(ctx),
+		task:     task,
+		total:    total,
+		start:    time.Now(),
+	}
+}
+
+// Step records one completed unit, optionally naming the subscription it belonged to.
+func (t *Tracker) Step(subscription string) {
+	t.Add(1, subscription)
+}
+
+// Add records n completed units.
+func (t *Tracker) Add(n int, subscription string) {
+	if t == nil || t.observer == nil {
+		return
+	}
+
+	t.mu.Lock()
+	t.done += n
+	done := t.done
+	t.mu.Unlock()
+
+	elapsed := time.Since(t.start)
+	t.observer.OnEvent(Event{
+		Type:         EventTaskProgress,
+		Task:         t.task,
+		Done:         done,
+		Total:        t.total,
+		Subscription: subscription,
+		Elapsed:      elapsed,
+		ETA:          EstimateETA(elapsed, done, t.total),
+	})
+}
+
+// EstimateETA extrapolates the remaining time from the elapsed time per completed unit.
+func EstimateETA(elapsed time.Duration, done, total int) time.Duration {
+	if done <= 0 || total <= done {
+		return 0
+	}
+	perUnit := elapsed / time.Duration(done)
+	return perUnit * time.Duration(total-done)
+}
@@ -0,0 +1,127 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package progress
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder collects events for assertions.
+type recorder struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (r *recorder) OnEvent(e Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, e)
+}
+
+func TestEmit_NoObserverIsNoop(t *testing.T) {
+	Emit(context.Background(), Event{Type: EventTaskProgress})
+	if FromContext(context.Background()) != nil {
+		t.Error("expected nil observer for plain context")
+	}
+}
+
+func TestTracker_EmitsTaskProgress(t *testing.T) {
+	rec := &recorder{}
+	ctx := WithObserver(context.Background(), rec)
+
+	tracker := NewTracker(ctx, "rules", 3)
+	tracker.Step("sub-a")
+	tracker.Add(2, "")
+
+	if len(rec.events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(rec.events))
+	}
+	first := rec.events[0]
+	if first.Type != EventTaskProgress || first.Task != "rules" || first.Done != 1 || first.Total != 3 {
+		t.Errorf("unexpected first event: %+v", first)
+	}
+	if first.Subscription != "sub-a" {
+		t.Errorf("Subscription = %q, want sub-a", first.Subscription)
+	}
+	if rec.events[1].Done != 3 {
+		t.Errorf("Done = %d, want 3", rec.events[1].Done)
+	}
+}
+
+func TestTracker_NilSafe(t *testing.T) {
+	var tracker *Tracker
+	tracker.Step("")
+	NewTracker(context.Background(), "x", 1).Step("")
+}
+
+func TestEstimateETA(t *testing.T) {
+	tests := []struct {
+		elapsed     time.Duration
+		done, total int
+		want        time.Duration
+	}{
+		{10 * time.Second, 1, 4, 30 * time.Second},
+		{10 * time.Second, 0, 4, 0},
+		{10 * time.Second, 4, 4, 0},
+	}
+	for _, tt := range tests {
+		if got := EstimateETA(tt.elapsed, tt.done, tt.total); got != tt.want {
+			t.Errorf("EstimateETA(%v, %d, %d) = %v, want %v", tt.elapsed, tt.done, tt.total, got, tt.want)
+		}
+	}
+}
+
+func TestObservers_FanOut(t *testing.T) {
+	a, b := &recorder{}, &recorder{}
+	Observers{a, b}.OnEvent(Event{Type: EventStageStarted})
+	if len(a.events) != 1 || len(b.events) != 1 {
+		t.Error("expected both observers to receive the event")
+	}
+}
+
+func TestBar_RendersStageAndTask(t *testing.T) {
+	var buf bytes.Buffer
+	bar := NewBar(&buf)
+
+	bar.OnEvent(Event{Type: EventStageStarted, Stage: "Graph Scan", StageIndex: 5, StageCount: 15})
+	bar.OnEvent(Event{Type: EventTaskProgress, Task: "Graph rules", Done: 10, Total: 10})
+
+	out := buf.String()
+	if !strings.Contains(out, "[5/15] Graph Scan") {
+		t.Errorf("missing stage in output: %q", out)
+	}
+	if !strings.Contains(out, "10/10 Graph rules") {
+		t.Errorf("missing task progress in output: %q", out)
+	}
+}
+
+func TestBar_WritePrintsAboveBar(t *testing.T) {
+	var buf bytes.Buffer
+	bar := NewBar(&buf)
+
+	// Without a bar on screen, writes pass through unchanged
+	_, _ = bar.Write([]byte("before\n"))
+	if got := buf.String(); got != "before\n" {
+		t.Errorf("output = %q, want %q", got, "before\n")
+	}
+
+	bar.OnEvent(Event{Type: EventStageStarted, Stage: "Graph Scan", StageIndex: 5, StageCount: 15})
+	buf.Reset()
+	_, _ = bar.Write([]byte("log line\n"))
+	if want := clearLineEscape + "log line\n[5/15] Graph Scan"; buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+
+	bar.OnEvent(Event{Type: EventStageFinished})
+	buf.Reset()
+	_, _ = bar.Write([]byte("after\n"))
+	if got := buf.String(); got != "after\n" {
+		t.Errorf("output = %q, want %q", got, "after\n")
+	}
+}
@@ -23,6 +23,9 @@ const (
 	nearLimitThreshold = 0.15 // resources with < 15% headroom are flagged at-risk
 )
 
+// DefaultNearLimitPct is the default headroom percentage below which an entry is near its limit.
+const DefaultNearLimitPct = nearLimitThreshold * 100
+
 // UsageEntry holds quota data for one resource type in one subscription+region.
 // It is returned by both FetchVMQuota and FetchNetworkQuota.
 type UsageEntry struct {
@@ -142,14 +145,36 @@ func fetchUsages(ctx context.Context, httpClient *az.HttpClient, startURL string
 	return entries, nil
 }
 
+// RegionalVCPUs is the API identifier of the Total Regional vCPUs quota
+const RegionalVCPUs = "cores"
+
 // FetchVMQuota queries Microsoft.Compute/locations/{region}/usages for VM family quotas.
 // It returns only VM family-level counters (names containing "Family") so that the
 // aggregate "cores" (Total Regional vCPUs) entry is excluded — that counter reflects ALL
 // workloads in the region (including unrelated ones and stopped-but-not-deallocated VMs),
-// making it noisy and misleading for migration planning. Family-level entries are
+// making it noisy and misleading for headroom reporting. Family-level entries are
 // actionable: they tell you whether the specific VM families you need have available quota.
 // The caller should treat a nil return as "no data available".
 func FetchVMQuota(ctx context.Context, cred azcore.TokenCredential, clientOpts *arm.ClientOptions, subscriptionID, region string) ([]UsageEntry, error) {
+	return fetchVMQuota(ctx, cred, clientOpts, subscriptionID, region, false)
+}
+
+// FetchVMQuotaWithRegional is FetchVMQuota plus the Total Regional vCPUs
+// ("cores") counter. Deployments must fit both the family and the regional
+// limit, so checks of a planned deployment (such as the migration quota
+// pre-flight) need it.
+func FetchVMQuotaWithRegional(ctx context.Context, cred azcore.TokenCredential, clientOpts *arm.ClientOptions, subscriptionID, region string) ([]UsageEntry, error) {
+	return fetchVMQuota(ctx, cred, clientOpts, subscriptionID, region, true)
+}
+
+// isVMQuotaCounter reports whether a compute usage counter is returned by the VM quota fetch.
+func isVMQuotaCounter(name string, includeRegional bool) bool {
+	return strings.Contains(name, "Family") || (includeRegional && name == RegionalVCPUs)
+}
+
+// fetchVMQuota lists the VM family counters and, when includeRegional is set,
+// the Total Regional vCPUs counter.
+func fetchVMQuota(ctx context.Context, cred azcore.TokenCredential, clientOpts *arm.ClientOptions, subscriptionID, region string, includeRegional bool) ([]UsageEntry, error) {
 	client, err := armcompute.NewUsageClient(subscriptionID, cred, clientOpts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create compute usage client for %s: %w", subscriptionID, err)
@@ -165,7 +190,7 @@ func FetchVMQuota(ctx context.Context, cred azcore.TokenCredential, clientOpts *
 			return nil, fmt.Errorf("VM quota API error for %s in %s: %w", subscriptionID, region, err)
 		}
 		for _, item := range page.Value {
-			if item.Name == nil || item.Name.Value == nil || !strings.Contains(*item.Name.Value, "Family") {
+			if item.Name == nil || item.Name.Value == nil || !isVMQuotaCounter(*item.Name.Value, includeRegional) {
 				continue
 			}
 			if item.Limit == nil {
@@ -203,6 +228,14 @@ func FetchVMQuota(ctx context.Context, cred azcore.TokenCredential, clientOpts *
 	return entries, nil
 }
 
+// ApplyThreshold recomputes IsNearLimit for a headroom threshold in percent
+// (e.g. 25 flags entries with less than 25% headroom).
+func ApplyThreshold(entries []UsageEntry, thresholdPct float64) {
+	for i := range entries {
+		entries[i].IsNearLimit = entries[i].HeadroomPct < thresholdPct
+	}
+}
+
 // AtRiskSummaries returns a human-readable list of at-risk entries from a UsageEntry slice.
 // The format is "<LocalizedName> (<currentValue>/<limit>, <pctUsed>% used)" for each entry
 // with < 15% headroom.
@@ -4,7 +4,15 @@
 package quota
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
 	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 )
 
 func TestAtRiskSummaries(t *testing.T) {
@@ -97,3 +105,61 @@ func TestUsageEntry_HeadroomCalculation(t *testing.T) {
 		t.Errorf("expected IsAtOrOverLimit=false when available=%d", available)
 	}
 }
+
+func TestApplyThreshold(t *testing.T) {
+	entries := []UsageEntry{
+		{ResourceName: "a", HeadroomPct: 20, IsNearLimit: false},
+		{ResourceName: "b", HeadroomPct: 10, IsNearLimit: true},
+		{ResourceName: "c", HeadroomPct: 40, IsNearLimit: false},
+	}
+
+	ApplyThreshold(entries, 25)
+	if !entries[0].IsNearLimit || !entries[1].IsNearLimit || entries[2].IsNearLimit {
+		t.Errorf("threshold 25: got %+v", entries)
+	}
+
+	ApplyThreshold(entries, 5)
+	for _, e := range entries {
+		if e.IsNearLimit {
+			t.Errorf("threshold 5: %s flagged near limit", e.ResourceName)
+		}
+	}
+}
+
+func TestFetchVMQuota_RegionalVCPUs(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"value":[
+			{"name":{"value":"cores","localizedValue":"Total Regional vCPUs"},"currentValue":12,"limit":10,"unit":"Count"},
+			{"name":{"value":"standardDSv3Family","localizedValue":"Standard DSv3 Family vCPUs"},"currentValue":8,"limit":10,"unit":"Count"},
+			{"name":{"value":"availabilitySets","localizedValue":"Availability Sets"},"currentValue":1,"limit":2500,"unit":"Count"}
+		]}`))
+	}))
+	t.Cleanup(server.Close)
+	target, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("url.Parse(server.URL) error = %v", err)
+	}
+	clientOpts := &arm.ClientOptions{ClientOptions: policy.ClientOptions{
+		Transport: &rewriteTransport{client: server.Client(), target: target},
+	}}
+
+	families, err := FetchVMQuota(context.Background(), testCredential{}, clientOpts, "sub1", "swedencentral")
+	if err != nil {
+		t.Fatalf("FetchVMQuota() error = %v", err)
+	}
+	if got := usageNames(families); !reflect.DeepEqual(got, []string{"standardDSv3Family"}) {
+		t.Errorf("FetchVMQuota() = %v, want only the VM family counter", got)
+	}
+
+	withRegional, err := FetchVMQuotaWithRegional(context.Background(), testCredential{}, clientOpts, "sub1", "swedencentral")
+	if err != nil {
+		t.Fatalf("FetchVMQuotaWithRegional() error = %v", err)
+	}
+	if got := usageNames(withRegional); !reflect.DeepEqual(got, []string{RegionalVCPUs, "standardDSv3Family"}) {
+		t.Errorf("FetchVMQuotaWithRegional() = %v, want the regional and VM family counters", got)
+	}
+	if !withRegional[0].IsAtOrOverLimit || withRegional[0].Available != -2 {
+		t.Errorf("regional vCPUs = %+v, want 2 over the limit", withRegional[0])
+	}
+}
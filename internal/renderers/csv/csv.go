@@ -14,23 +14,34 @@ import (
 	"github.com/Azure/azqr/internal/renderers"
 )
 
-func CreateCsvReport(data *renderers.ReportData) {
+// CreateCsvReport writes one CSV file per enabled table to <OutputFileName>.<table>.csv.
+func CreateCsvReport(data *renderers.ReportData) error {
 	// Only create AZQR-related CSV files if the feature is enabled
 	if data.Stages.IsStageEnabled(models.StageNameGraph) {
 		records := data.RecommendationsTable()
-		writeData(records, data.OutputFileName, "recommendations")
+		if err := writeData(records, data.OutputFileName, "recommendations"); err != nil {
+			return err
+		}
 
 		records = data.ImpactedTable()
-		writeData(records, data.OutputFileName, "impacted")
+		if err := writeData(records, data.OutputFileName, "impacted"); err != nil {
+			return err
+		}
 
 		records = data.ResourceTypesTable()
-		writeData(records, data.OutputFileName, "resourceType")
+		if err := writeData(records, data.OutputFileName, "resourceType"); err != nil {
+			return err
+		}
 
 		records = data.ResourcesTable()
-		writeData(records, data.OutputFileName, "inventory")
+		if err := writeData(records, data.OutputFileName, "inventory"); err != nil {
+			return err
+		}
 
 		records = data.ExcludedResourcesTable()
-		writeData(records, data.OutputFileName, "outofscope")
+		if err := writeData(records, data.OutputFileName, "outofscope"); err != nil {
+			return err
+		}
 	} else {
 		log.Debug().Msg("Skipping AZQR CSV files. Feature is disabled")
 	}
@@ -38,7 +49,17 @@ func CreateCsvReport(data *renderers.ReportData) {
 	// Only create Defender CSV files if the feature is enabled
 	if data.Stages.IsStageEnabled(models.StageNameDefender) {
 		records := data.DefenderTable()
-		writeData(records, data.OutputFileName, "defender")
+		if err := writeData(records, data.OutputFileName, "defender"); err != nil {
+			return err
+		}
+		records = data.SecureScoreTable()
+		if err := writeData(records, data.OutputFileName, "secureScore"); err != nil {
+			return err
+		}
+		records = data.SecureScoreControlsTable()
+		if err := writeData(records, data.OutputFileName, "secureScoreControls"); err != nil {
+			return err
+		}
 	} else {
 		log.Debug().Msg("Skipping Defender CSV files. Feature is disabled")
 	}
@@ -46,7 +67,9 @@ func CreateCsvReport(data *renderers.ReportData) {
 	// Only create Defender Recommendations CSV files if the feature is enabled
 	if data.Stages.IsStageEnabled(models.StageNameDefenderRecommendations) {
 		records := data.DefenderRecommendationsTable()
-		writeData(records, data.OutputFileName, "defenderRecommendations")
+		if err := writeData(records, data.OutputFileName, "defenderRecommendations"); err != nil {
+			return err
+		}
 	} else {
 		log.Debug().Msg("Skipping Defender Recommendations CSV files. Feature is disabled")
 	}
@@ -54,23 +77,54 @@ func CreateCsvReport(data *renderers.ReportData) {
 	// Only create Azure Policy CSV files if the feature is enabled
 	if data.Stages.IsStageEnabled(models.StageNamePolicy) {
 		records := data.AzurePolicyTable()
-		writeData(records, data.OutputFileName, "azurePolicy")
+		if err := writeData(records, data.OutputFileName, "azurePolicy"); err != nil {
+			return err
+		}
+		records = data.AzurePolicySubscriptionsTable()
+		if err := writeData(records, data.OutputFileName, "policySubscriptions"); err != nil {
+			return err
+		}
+		records = data.AzurePolicyInitiativesTable()
+		if err := writeData(records, data.OutputFileName, "policyInitiatives"); err != nil {
+			return err
+		}
+		records = data.AzurePolicyAssignmentsTable()
+		if err := writeData(records, data.OutputFileName, "policyAssignments"); err != nil {
+			return err
+		}
+		records = data.AzurePolicyTopTable()
+		if err := writeData(records, data.OutputFileName, "policyTopNonCompliant"); err != nil {
+			return err
+		}
+		records = data.AzurePolicyExemptionsTable()
+		if err := writeData(records, data.OutputFileName, "policyExemptions"); err != nil {
+			return err
+		}
 	} else {
 		log.Debug().Msg("Skipping Azure Policy CSV file. Feature is disabled")
 	}
 
-	// Only create Arc SQL CSV files if the feature is enabled
+	// Only create Arc CSV files if the feature is enabled
 	if data.Stages.IsStageEnabled(models.StageNameArc) {
 		records := data.ArcSQLTable()
-		writeData(records, data.OutputFileName, "arcSQL")
+		if err := writeData(records, data.OutputFileName, "arcSQL"); err != nil {
+			return err
+		}
+
+		records = data.ArcServersTable()
+		if err := writeData(records, data.OutputFileName, "arcServers"); err != nil {
+			return err
+		}
 	} else {
-		log.Debug().Msg("Skipping Arc SQL CSV file. Feature is disabled")
+		log.Debug().Msg("Skipping Arc CSV files. Feature is disabled")
 	}
 
 	// Only create Advisor CSV files if the feature is enabled
 	if data.Stages.IsStageEnabled(models.StageNameAdvisor) {
 		records := data.AdvisorTable()
-		writeData(records, data.OutputFileName, "advisor")
+		if err := writeData(records, data.OutputFileName, "advisor"); err != nil {
+			return err
+		}
 	} else {
 		log.Debug().Msg("Skipping Advisor CSV file. Feature is disabled")
 	}
@@ -78,34 +132,58 @@ func CreateCsvReport(data *renderers.ReportData) {
 	// Only create Cost CSV files if the feature is enabled
 	if data.Stages.IsStageEnabled(models.StageNameCost) {
 		records := data.CostTable()
-		writeData(records, data.OutputFileName, "costs")
+		if err := writeData(records, data.OutputFileName, "costs"); err != nil {
+			return err
+		}
 	} else {
 		log.Debug().Msg("Skipping Cost CSV file. Feature is disabled")
 	}
 
+	// Only create Quota CSV files if the feature is enabled
+	if data.Stages.IsStageEnabled(models.StageNameQuota) {
+		records := data.QuotaTable()
+		if err := writeData(records, data.OutputFileName, "quota"); err != nil {
+			return err
+		}
+	} else {
+		log.Debug().Msg("Skipping Quota CSV file. Feature is disabled")
+	}
+
+	if len(data.PluginRuns) > 0 {
+		if err := writeData(data.PluginRunsTable(), data.OutputFileName, "plugin_runs"); err != nil {
+			return err
+		}
+	}
+
 	// Render external plugin results
 	// Use SheetName (not PluginName) so that plugins returning multiple sheets
 	// (e.g. region-selection) each get their own CSV file instead of overwriting.
+	// Typed columns are written as locale-neutral values.
 	for _, result := range data.PluginResults {
 		if len(result.Table) > 0 {
-			writeData(result.Table, data.OutputFileName, fmt.Sprintf("plugin_%s", result.SheetName))
+			if err := writeData(result.NormalizedTable(), data.OutputFileName, fmt.Sprintf("plugin_%s", result.SheetName)); err != nil {
+				return err
+			}
 		}
 	}
+	return nil
 }
 
-func writeData(data [][]string, fileName, extension string) {
+func writeData(data [][]string, fileName, extension string) error {
 	filename := fmt.Sprintf("%s.%s.csv", fileName, extension)
 	log.Info().Msgf("Generating Report: %s", filename)
 
 	f, err := os.Create(filename) //nolint:gosec // filename is generated from user's output-name flag
 	if err != nil {
-		log.Fatal().Err(err).Msg("error creating csv:")
+		return fmt.Errorf("error creating csv: %w", err)
 	}
+	defer func() {
+		_ = f.Close()
+	}()
 
 	w := csv.NewWriter(f)
-	err = w.WriteAll(data) // calls Flush internally
-
-	if err != nil {
-		log.Fatal().Err(w.Error()).Msg("error writing csv:")
+	if err := w.WriteAll(data); err != nil { // calls Flush internally
+		return fmt.Errorf("error writing csv %s: %w", filename, err)
 	}
+	return nil
 }
@@ -44,7 +44,9 @@ func TestCreateCsvReport(t *testing.T) {
 	}
 
 	// Create the report
-	CreateCsvReport(data)
+	if err := CreateCsvReport(data); err != nil {
+		t.Fatalf("CreateCsvReport() error = %v", err)
+	}
 
 	// Expected CSV files
 	expectedFiles := []string{
@@ -120,7 +122,9 @@ func TestCreateCsvReportWithPlugins(t *testing.T) {
 	}
 
 	// Create the report
-	CreateCsvReport(data)
+	if err := CreateCsvReport(data); err != nil {
+		t.Fatalf("CreateCsvReport() error = %v", err)
+	}
 
 	// Verify plugin CSV files were created
 	expectedPluginFiles := []string{
@@ -150,7 +154,9 @@ func TestWriteData(t *testing.T) {
 	extension := "data"
 
 	// Write the data
-	writeData(testData, fileName, extension)
+	if err := writeData(testData, fileName, extension); err != nil {
+		t.Fatalf("writeData() error = %v", err)
+	}
 
 	// Verify file was created
 	expectedFile := filepath.Join(tmpDir, "test.data.csv")
@@ -190,7 +196,9 @@ func TestWriteDataEmptyTable(t *testing.T) {
 	extension := "empty"
 
 	// Write the data
-	writeData(testData, fileName, extension)
+	if err := writeData(testData, fileName, extension); err != nil {
+		t.Fatalf("writeData() error = %v", err)
+	}
 
 	// Verify file was created
 	expectedFile := filepath.Join(tmpDir, "test_empty.empty.csv")
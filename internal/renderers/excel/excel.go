@@ -6,9 +6,13 @@ package excel
 import (
 	"fmt"
 	_ "image/png"
+	"math"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/Azure/azqr/internal/embeded"
+	"github.com/Azure/azqr/internal/models"
 	"github.com/Azure/azqr/internal/renderers"
 	"github.com/rs/zerolog/log"
 	"github.com/xuri/excelize/v2"
@@ -22,6 +26,11 @@ type StyleCache struct {
 	Header int
 	Blue   int
 	White  int
+
+	// file and typed hold the number format styles of typed plugin columns,
+	// created on first use
+	file  *excelize.File
+	typed map[typedStyleKey]int
 }
 
 // Hyperlink column positions (1-based) for sheets that embed a URL column.
@@ -31,8 +40,6 @@ const (
 	hyperlinkColRecommendations = 11
 	// hyperlinkColImpacted is col 18 — "Learn" in ImpactedTable
 	hyperlinkColImpacted = 18
-	// hyperlinkColResources is col 12 — beyond the 10-column ResourcesTable (no-op, preserved for parity)
-	hyperlinkColResources = 12
 	// hyperlinkColDefenderRecommendations is col 11 — "AzPortal Link" in DefenderRecommendationsTable
 	hyperlinkColDefenderRecommendations = 11
 )
@@ -49,19 +56,19 @@ type sheetConfig struct {
 // renderSheet renders a data sheet using the provided configuration.
 // It handles stage gating, sheet creation, and delegates all row writing
 // and formatting to streamSheet.
-func renderSheet(f *excelize.File, data *renderers.ReportData, cfg sheetConfig, styles *StyleCache) {
+func renderSheet(f *excelize.File, data *renderers.ReportData, cfg sheetConfig, styles *StyleCache) error {
 	if !data.Stages.IsStageEnabled(cfg.stageName) {
 		log.Debug().Msgf("Skipping %s. Feature is disabled", cfg.sheetName)
-		return
+		return nil
 	}
 
 	if cfg.isFirstSheet {
 		if err := f.SetSheetName("Sheet1", cfg.sheetName); err != nil {
-			log.Fatal().Err(err).Msgf("Failed to create %s sheet", cfg.sheetName)
+			return fmt.Errorf("failed to create %s sheet: %w", cfg.sheetName, err)
 		}
 	} else {
 		if _, err := f.NewSheet(cfg.sheetName); err != nil {
-			log.Fatal().Err(err).Msgf("Failed to create %s sheet", cfg.sheetName)
+			return fmt.Errorf("failed to create %s sheet: %w", cfg.sheetName, err)
 		}
 	}
 
@@ -70,16 +77,23 @@ func renderSheet(f *excelize.File, data *renderers.ReportData, cfg sheetConfig,
 		log.Info().Msgf("Skipping %s. No data to render", cfg.sheetName)
 	}
 
-	streamSheet(f, cfg.sheetName, records, cfg.hyperlinkCol, styles)
+	return streamSheet(f, cfg.sheetName, records, cfg.hyperlinkCol, styles)
 }
 
 // streamSheet writes all rows for a sheet using excelize StreamWriter, which streams
 // directly to the zip buffer instead of keeping every cell in an in-memory map.
 // Column widths, alternating row styles, HYPERLINK formulas, AutoFilter, and the
 // logo are all applied before Flush so they are serialised into the worksheet XML.
-func streamSheet(f *excelize.File, sheetName string, records [][]string, hyperlinkCol int, styles *StyleCache) {
+func streamSheet(f *excelize.File, sheetName string, records [][]string, hyperlinkCol int, styles *StyleCache) error {
+	return streamTypedSheet(f, sheetName, records, hyperlinkCol, nil, styles)
+}
+
+// streamTypedSheet is streamSheet for tables with typed columns: numeric and
+// date values are written as typed cells with a number format and url
+// columns as hyperlinks.
+func streamTypedSheet(f *excelize.File, sheetName string, records [][]string, hyperlinkCol int, columns []models.ColumnFormat, styles *StyleCache) error {
 	if len(records) == 0 {
-		return
+		return nil
 	}
 	headers := records[0]
 	hasData := len(records) > 1
@@ -88,8 +102,7 @@ func streamSheet(f *excelize.File, sheetName string, records [][]string, hyperli
 
 	sw, err := f.NewStreamWriter(sheetName)
 	if err != nil {
-		log.Fatal().Err(err).Msgf("Failed to create stream writer for %s", sheetName)
-		return
+		return fmt.Errorf("failed to create stream writer for %s: %w", sheetName, err)
 	}
 
 	// Column widths must be set before any SetRow calls.
@@ -108,7 +121,7 @@ func streamSheet(f *excelize.File, sheetName string, records [][]string, hyperli
 		headerCells[i] = excelize.Cell{Value: h, StyleID: styles.Header}
 	}
 	if err := sw.SetRow("A4", headerCells, excelize.RowOpts{StyleID: styles.Header}); err != nil {
-		log.Fatal().Err(err).Msg("Failed to write header row")
+		return fmt.Errorf("failed to write header row for %s: %w", sheetName, err)
 	}
 
 	lastRow := 4
@@ -117,12 +130,15 @@ func streamSheet(f *excelize.File, sheetName string, records [][]string, hyperli
 		for i, row := range records[1:] {
 			lastRow = i + 5
 			styleID := styles.White
-			if lastRow%2 == 0 {
+			blue := lastRow%2 == 0
+			if blue {
 				styleID = styles.Blue
 			}
 
 			for j, val := range row {
-				if hyperlinkCol > 0 && j == hyperlinkCol-1 && val != "" {
+				if j < len(columns) && columns[j].Type != models.ColumnTypeText {
+					cells[j] = styles.typedCell(columns[j], val, styleID, blue)
+				} else if hyperlinkCol > 0 && j == hyperlinkCol-1 && val != "" {
 					cells[j] = excelize.Cell{
 						Formula: `HYPERLINK("` + val + `","` + val + `")`,
 						StyleID: styleID,
@@ -133,7 +149,7 @@ func streamSheet(f *excelize.File, sheetName string, records [][]string, hyperli
 			}
 			cellName := "A" + strconv.Itoa(lastRow)
 			if err := sw.SetRow(cellName, cells, excelize.RowOpts{StyleID: styleID}); err != nil {
-				log.Fatal().Err(err).Msg("Failed to write data row")
+				return fmt.Errorf("failed to write data row for %s: %w", sheetName, err)
 			}
 		}
 
@@ -155,13 +171,14 @@ func streamSheet(f *excelize.File, sheetName string, records [][]string, hyperli
 				AltText:     "azqr logo",
 			},
 		}); err != nil {
-			log.Fatal().Err(err).Msg("Failed to add logo")
+			return fmt.Errorf("failed to add logo to %s: %w", sheetName, err)
 		}
 	}
 
 	if err := sw.Flush(); err != nil {
-		log.Fatal().Err(err).Msgf("Failed to flush stream writer for %s", sheetName)
+		return fmt.Errorf("failed to flush stream writer for %s: %w", sheetName, err)
 	}
+	return nil
 }
 
 // createSharedStyles creates all shared styles once and caches their IDs
@@ -209,10 +226,105 @@ func createSharedStyles(f *excelize.File) (*StyleCache, error) {
 		Header: header,
 		Blue:   blue,
 		White:  white,
+		file:   f,
+		typed:  map[typedStyleKey]int{},
 	}, nil
 }
 
-func CreateExcelReport(data *renderers.ReportData) {
+// typedStyleKey identifies the style of a typed cell
+type typedStyleKey struct {
+	numFmt string
+	blue   bool
+}
+
+// typedStyle returns the row style with the given number format, creating it
+// on first use. It falls back to the plain row style if it cannot be created.
+func (s *StyleCache) typedStyle(numFmt string, blue bool, rowStyle int) int {
+	key := typedStyleKey{numFmt: numFmt, blue: blue}
+	if id, ok := s.typed[key]; ok {
+		return id
+	}
+	if s.file == nil {
+		return rowStyle
+	}
+
+	style := &excelize.Style{
+		Alignment: &excelize.Alignment{
+			Vertical: "top",
+			WrapText: true,
+		},
+		CustomNumFmt: &numFmt,
+	}
+	if blue {
+		style.Fill = excelize.Fill{
+			Type:    "pattern",
+			Color:   []string{"#CAEDFB"},
+			Pattern: 1,
+		}
+	}
+	id, err := s.file.NewStyle(style)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Failed to create style for number format %s", numFmt)
+		id = rowStyle
+	}
+	if s.typed == nil {
+		s.typed = map[typedStyleKey]int{}
+	}
+	s.typed[key] = id
+	return id
+}
+
+// typedCell returns the cell for a value of a typed column. Values that cannot
+// be parsed (e.g. "N/A") are written as text.
+func (s *StyleCache) typedCell(col models.ColumnFormat, val string, rowStyle int, blue bool) excelize.Cell {
+	if col.Type == models.ColumnTypeURL {
+		if val == "" {
+			return excelize.Cell{Value: val, StyleID: rowStyle}
+		}
+		return excelize.Cell{
+			Formula: `HYPERLINK("` + val + `","` + val + `")`,
+			StyleID: rowStyle,
+		}
+	}
+
+	v, ok := col.Value(val)
+	if !ok {
+		return excelize.Cell{Value: val, StyleID: rowStyle}
+	}
+	switch t := v.(type) {
+	case float64:
+		if col.Type == models.ColumnTypePercent {
+			t /= 100
+		}
+		return excelize.Cell{Value: t, StyleID: s.typedStyle(numberFormat(col, t), blue, rowStyle)}
+	case time.Time:
+		numFmt := "yyyy-mm-dd"
+		if t.Hour() != 0 || t.Minute() != 0 {
+			numFmt = "yyyy-mm-dd hh:mm"
+		}
+		return excelize.Cell{Value: t, StyleID: s.typedStyle(numFmt, blue, rowStyle)}
+	}
+	return excelize.Cell{Value: val, StyleID: rowStyle}
+}
+
+// numberFormat returns the Excel number format of a numeric value. Whole
+// numbers have no decimals and the column unit is shown after the value.
+func numberFormat(col models.ColumnFormat, v float64) string {
+	if col.Type == models.ColumnTypePercent {
+		return "0.00%"
+	}
+	numFmt := "#,##0.00"
+	if col.Type == models.ColumnTypeNumber && v == math.Trunc(v) {
+		numFmt = "#,##0"
+	}
+	if col.Unit != "" {
+		numFmt += ` "` + strings.ReplaceAll(col.Unit, `"`, "") + `"`
+	}
+	return numFmt
+}
+
+// CreateExcelReport renders the report data to <OutputFileName>.xlsx.
+func CreateExcelReport(data *renderers.ReportData) error {
 	filename := fmt.Sprintf("%s.xlsx", data.OutputFileName)
 	log.Info().Msgf("Generating Report: %s", filename)
 	f := excelize.NewFile()
@@ -225,14 +337,17 @@ func CreateExcelReport(data *renderers.ReportData) {
 	// Create shared styles once for all sheets
 	styles, err := createSharedStyles(f)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to create shared styles")
-		return
+		return fmt.Errorf("failed to create shared styles: %w", err)
 	}
 
 	for _, cfg := range builtinSheets(data) {
-		renderSheet(f, data, cfg, styles)
+		if err := renderSheet(f, data, cfg, styles); err != nil {
+			return err
+		}
+	}
+	if err := renderExternalPlugins(f, data, styles); err != nil {
+		return err
 	}
-	renderExternalPlugins(f, data, styles)
 
 	// Delete the default "Sheet1" if other sheets were created
 	sheets := f.GetSheetList()
@@ -243,9 +358,9 @@ func CreateExcelReport(data *renderers.ReportData) {
 	}
 
 	if err := f.SaveAs(filename); err != nil {
-		_ = f.Close()                                         // Close the file before exiting to ensure cleanup
-		log.Fatal().Err(err).Msg("Failed to save Excel file") //nolint:gocritic // File is explicitly closed above
+		return fmt.Errorf("failed to save Excel file %s: %w", filename, err)
 	}
+	return nil
 }
 
 // computeWidthsFromRecords calculates per-column max widths by scanning the already-in-memory
@@ -283,12 +398,23 @@ func computeWidthsFromRecords(records [][]string, maxSampleRows int) []int {
 	return widths
 }
 
+// pluginRunsSheet lists the status and duration of each plugin
+const pluginRunsSheet = "Plugin Runs"
+
 // renderExternalPlugins creates Excel sheets for external plugin results.
 // If a sheet with the same name already exists in the workbook (e.g. "Inventory"
 // written by the main scan), that plugin sheet is silently skipped.
-func renderExternalPlugins(f *excelize.File, data *renderers.ReportData, styles *StyleCache) {
+func renderExternalPlugins(f *excelize.File, data *renderers.ReportData, styles *StyleCache) error {
+	if len(data.PluginRuns) > 0 {
+		if _, err := f.NewSheet(pluginRunsSheet); err != nil {
+			log.Error().Err(err).Msg("Failed to create plugin runs sheet")
+		} else if err := streamSheet(f, pluginRunsSheet, data.PluginRunsTable(), 0, styles); err != nil {
+			return err
+		}
+	}
+
 	if len(data.PluginResults) == 0 {
-		return
+		return nil
 	}
 
 	for _, result := range data.PluginResults {
@@ -306,6 +432,9 @@ func renderExternalPlugins(f *excelize.File, data *renderers.ReportData, styles
 			continue
 		}
 
-		streamSheet(f, result.SheetName, result.Table, 0, styles)
+		if err := streamTypedSheet(f, result.SheetName, result.Table, 0, result.Columns, styles); err != nil {
+			return err
+		}
 	}
+	return nil
 }
@@ -55,7 +55,9 @@ func TestCreateExcelReport(t *testing.T) {
 			}()
 
 			// Create report - should not panic
-			CreateExcelReport(tt.data)
+			if err := CreateExcelReport(tt.data); err != nil {
+				t.Fatalf("CreateExcelReport() error = %v", err)
+			}
 
 			// Verify file was created
 			if _, err := os.Stat(filename); os.IsNotExist(err) {
@@ -245,3 +247,89 @@ func BenchmarkCoordinateConversion(b *testing.B) {
 		}
 	})
 }
+
+func TestRenderExternalPlugins_TypedColumns(t *testing.T) {
+	f := excelize.NewFile()
+	defer func() { _ = f.Close() }()
+
+	styles, err := createSharedStyles(f)
+	if err != nil {
+		t.Fatalf("createSharedStyles() error = %v", err)
+	}
+
+	data := &renderers.ReportData{
+		PluginResults: []*renderers.PluginResult{
+			{
+				PluginName: "typed",
+				SheetName:  "Typed",
+				Table: [][]string{
+					{"Name", "Count", "Share", "Cost", "Date", "Link"},
+					{"a", "1,200", "12.5%", "99.90", "2024-07-09", "https://example.com"},
+					{"b", "N/A", "", "1", "2024-07-09 15:00", ""},
+				},
+				Columns: []models.ColumnFormat{
+					{Name: "Name"},
+					{Name: "Count", Type: models.ColumnTypeNumber},
+					{Name: "Share", Type: models.ColumnTypePercent},
+					{Name: "Cost", Type: models.ColumnTypeCurrency, Unit: "USD"},
+					{Name: "Date", Type: models.ColumnTypeDate},
+					{Name: "Link", Type: models.ColumnTypeURL},
+				},
+			},
+		},
+	}
+	if err := renderExternalPlugins(f, data, styles); err != nil {
+		t.Fatalf("renderExternalPlugins() error = %v", err)
+	}
+
+	raw := func(cell string) string {
+		t.Helper()
+		v, err := f.GetCellValue("Typed", cell, excelize.Options{RawCellValue: true})
+		if err != nil {
+			t.Fatalf("GetCellValue(%s): %v", cell, err)
+		}
+		return v
+	}
+	numFmt := func(cell string) string {
+		t.Helper()
+		id, err := f.GetCellStyle("Typed", cell)
+		if err != nil {
+			t.Fatalf("GetCellStyle(%s): %v", cell, err)
+		}
+		style, err := f.GetStyle(id)
+		if err != nil {
+			t.Fatalf("GetStyle(%d): %v", id, err)
+		}
+		if style.CustomNumFmt == nil {
+			return ""
+		}
+		return *style.CustomNumFmt
+	}
+
+	tests := []struct {
+		cell   string
+		value  string
+		numFmt string
+	}{
+		{"A5", "a", ""},
+		{"B5", "1200", "#,##0"},
+		{"C5", "0.125", "0.00%"},
+		{"D5", "99.9", `#,##0.00 "USD"`},
+		{"E5", "45482", "yyyy-mm-dd"},
+		{"E6", "45482.625", "yyyy-mm-dd hh:mm"},
+		{"B6", "N/A", ""},
+		{"C6", "", ""},
+	}
+	for _, tt := range tests {
+		if got := raw(tt.cell); got != tt.value {
+			t.Errorf("%s = %q, want %q", tt.cell, got, tt.value)
+		}
+		if got := numFmt(tt.cell); got != tt.numFmt {
+			t.Errorf("%s number format = %q, want %q", tt.cell, got, tt.numFmt)
+		}
+	}
+
+	if formula, _ := f.GetCellFormula("Typed", "F5"); formula == "" {
+		t.Error("url column should be written as a HYPERLINK formula")
+	}
+}
@@ -69,22 +69,13 @@ func TestHyperlinkColDefenderRecommendations_PointsAtAzPortalLink(t *testing.T)
 	}
 }
 
-// TestHyperlinkColResources_DocumentedNoOp verifies that hyperlinkColResources
-// (col 12) is intentionally beyond the 10-column ResourcesTable. If someone
-// ever extends ResourcesTable to 12+ columns and adds a URL column at col 12,
-// this test will remind them to update the constant and add a real hyperlink.
-func TestHyperlinkColResources_BeyondTableWidth(t *testing.T) {
-	rd := emptyReportData()
-	table := rd.ResourcesTable()
-	if len(table) == 0 {
-		t.Fatal("ResourcesTable returned empty slice")
-	}
-	tableWidth := len(table[0])
-	if hyperlinkColResources <= tableWidth {
-		// The table has grown to include a URL column — update the constant and
-		// the comment in excel.go to point at the correct column name.
-		t.Errorf("hyperlinkColResources=%d is now within the %d-column ResourcesTable: "+
-			"update the constant to point at the URL column",
-			hyperlinkColResources, tableWidth)
+// TestBuiltinSheets_InventoryHasNoHyperlink verifies that the resource sheets,
+// which have no URL column, do not turn a column into hyperlinks, including the
+// cost columns appended when the cost stage is enabled.
+func TestBuiltinSheets_InventoryHasNoHyperlink(t *testing.T) {
+	for _, cfg := range builtinSheets(emptyReportData()) {
+		if (cfg.sheetName == "Inventory" || cfg.sheetName == "OutOfScope") && cfg.hyperlinkCol != 0 {
+			t.Errorf("sheet %s has hyperlinkCol=%d, want none", cfg.sheetName, cfg.hyperlinkCol)
+		}
 	}
 }
@@ -404,7 +404,7 @@ func TestRenderCosts(t *testing.T) {
 			t.Fatalf("sheet %q not created", sheet)
 		}
 		f = saveAndReopen(t, f)
-		// CostTable header order: From, To, Subscription Id, Subscription Name, Service Name, Value, Currency
+		// CostTable header order: From, To, Subscription Id, Subscription Name, Date, Cost Type, Service Name, ...
 		if got := cellAt(t, f, sheet, 1, 4); got != "From" {
 			t.Errorf("A4 = %q, want %q", got, "From")
 		}
@@ -741,6 +741,7 @@ func TestFullReport_AllSheetsPresent(t *testing.T) {
 	_ = stages.EnableStage(models.StageNameArc)
 	_ = stages.EnableStage(models.StageNamePolicy)
 	_ = stages.EnableStage(models.StageNameCost)
+	_ = stages.EnableStage(models.StageNameQuota)
 
 	data := &renderers.ReportData{
 		OutputFileName:  outputName,
@@ -756,6 +757,9 @@ func TestFullReport_AllSheetsPresent(t *testing.T) {
 		ArcSQL: []*models.ArcSQLResult{
 			{SubscriptionID: testSubID, SubscriptionName: "Sub One", Edition: "Enterprise"},
 		},
+		ArcServers: []*models.ArcServerResult{
+			{SubscriptionID: testSubID, SubscriptionName: "Sub One", Name: "web01", Status: "Connected"},
+		},
 		AzurePolicy: []*models.AzurePolicyResult{
 			{SubscriptionID: testSubID, PolicyDisplayName: "Policy A"},
 		},
@@ -763,12 +767,20 @@ func TestFullReport_AllSheetsPresent(t *testing.T) {
 			{SubscriptionID: testSubID, ServiceName: "Compute", Value: "10.00", Currency: "USD",
 				From: time.Now(), To: time.Now()},
 		},
+		Quota: []*models.QuotaResult{
+			{SubscriptionID: testSubID, Location: "westeurope", QuotaType: "VM", Name: "standardDSv3Family",
+				Current: 95, Limit: 100, Available: 5, HeadroomPct: 5, Status: "Near Limit"},
+		},
 		Defender: []*models.DefenderResult{
 			{SubscriptionID: testSubID, SubscriptionName: "Sub One", Name: "VMs", Tier: "Standard"},
 		},
 		DefenderRecommendations: []*models.DefenderRecommendation{
 			{SubscriptionId: testSubID, AzPortalLink: "https://portal.azure.com/rec"},
 		},
+		SecureScores: []*models.SecureScoreResult{
+			{SubscriptionID: testSubID, SubscriptionName: "Sub One", CurrentScore: 30, MaxScore: 60, Percentage: 50, Weight: 60,
+				Controls: []*models.SecureScoreControl{{Name: "mfa", DisplayName: "Enable MFA", CurrentScore: 5, MaxScore: 10, Percentage: 50}}},
+		},
 		ResourceTypeCount: []*models.ResourceTypeCount{
 			{Subscription: "Sub One", ResourceType: "Microsoft.Compute/virtualMachines", Count: 1},
 		},
@@ -782,7 +794,9 @@ func TestFullReport_AllSheetsPresent(t *testing.T) {
 		},
 	}
 
-	CreateExcelReport(data)
+	if err := CreateExcelReport(data); err != nil {
+		t.Fatalf("CreateExcelReport() error = %v", err)
+	}
 
 	f, err := excelize.OpenFile(filename)
 	if err != nil {
@@ -793,8 +807,9 @@ func TestFullReport_AllSheetsPresent(t *testing.T) {
 	// Every expected sheet must be present
 	expectedSheets := []string{
 		"Recommendations", "ImpactedResources", "ResourceTypes",
-		"Inventory", "Advisor", "Azure Policy", "Arc SQL",
-		"DefenderRecommendations", "Defender", "OutOfScope", "Costs",
+		"Inventory", "Advisor", "Azure Policy", "Policy Subscriptions", "Policy Initiatives",
+		"Policy Assignments", "Policy Top Non-Compliant", "Policy Exemptions", "Arc SQL", "Arc Servers",
+		"DefenderRecommendations", "Defender", "SecureScore", "SecureScoreControls", "OutOfScope", "Costs", "Quota",
 	}
 	for _, sheet := range expectedSheets {
 		if !hasSheet(f, sheet) {
@@ -809,17 +824,25 @@ func TestFullReport_AllSheetsPresent(t *testing.T) {
 
 	// First header cell (A4) must match the known first column of each table
 	wantHeaderA4 := map[string]string{
-		"Recommendations":         "Implemented",
-		"ImpactedResources":       "Validated Using",
-		"ResourceTypes":           "Subscription Name",
-		"Inventory":               "Subscription Id",
-		"Advisor":                 "Subscription Id",
-		"Azure Policy":            "Subscription Id",
-		"Arc SQL":                 "Subscription Id",
-		"DefenderRecommendations": "Subscription Id",
-		"Defender":                "Subscription Id",
-		"OutOfScope":              "Subscription Id",
-		"Costs":                   "From", // CostTable: From, To, Subscription Id, ...
+		"Recommendations":          "Implemented",
+		"ImpactedResources":        "Validated Using",
+		"ResourceTypes":            "Subscription Name",
+		"Inventory":                "Subscription Id",
+		"Advisor":                  "Subscription Id",
+		"Azure Policy":             "Subscription Id",
+		"Policy Subscriptions":     "Subscription Id",
+		"Policy Initiatives":       "Initiative",
+		"Policy Top Non-Compliant": "Policy Display Name",
+		"Policy Exemptions":        "Subscription Id",
+		"Arc SQL":                  "Subscription Id",
+		"Arc Servers":              "Subscription Id",
+		"DefenderRecommendations":  "Subscription Id",
+		"Defender":                 "Subscription Id",
+		"SecureScore":              "Subscription Id",
+		"SecureScoreControls":      "Subscription Id",
+		"OutOfScope":               "Subscription Id",
+		"Costs":                    "From", // CostTable: From, To, Subscription Id, ...
+		"Quota":                    "Subscription Id",
 	}
 	for sheet, want := range wantHeaderA4 {
 		if got := cellAt(t, f, sheet, 1, 4); got != want {
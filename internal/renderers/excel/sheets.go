@@ -32,10 +32,9 @@ func builtinSheets(data *renderers.ReportData) []sheetConfig {
 			tableFunc: data.ResourceTypesTable,
 		},
 		{
-			stageName:    models.StageNameGraph,
-			sheetName:    "Inventory",
-			tableFunc:    data.ResourcesTable,
-			hyperlinkCol: hyperlinkColResources,
+			stageName: models.StageNameGraph,
+			sheetName: "Inventory",
+			tableFunc: data.ResourcesTable,
 		},
 		{
 			stageName: models.StageNameAdvisor,
@@ -47,11 +46,41 @@ func builtinSheets(data *renderers.ReportData) []sheetConfig {
 			sheetName: "Azure Policy",
 			tableFunc: data.AzurePolicyTable,
 		},
+		{
+			stageName: models.StageNamePolicy,
+			sheetName: "Policy Subscriptions",
+			tableFunc: data.AzurePolicySubscriptionsTable,
+		},
+		{
+			stageName: models.StageNamePolicy,
+			sheetName: "Policy Initiatives",
+			tableFunc: data.AzurePolicyInitiativesTable,
+		},
+		{
+			stageName: models.StageNamePolicy,
+			sheetName: "Policy Assignments",
+			tableFunc: data.AzurePolicyAssignmentsTable,
+		},
+		{
+			stageName: models.StageNamePolicy,
+			sheetName: "Policy Top Non-Compliant",
+			tableFunc: data.AzurePolicyTopTable,
+		},
+		{
+			stageName: models.StageNamePolicy,
+			sheetName: "Policy Exemptions",
+			tableFunc: data.AzurePolicyExemptionsTable,
+		},
 		{
 			stageName: models.StageNameArc,
 			sheetName: "Arc SQL",
 			tableFunc: data.ArcSQLTable,
 		},
+		{
+			stageName: models.StageNameArc,
+			sheetName: "Arc Servers",
+			tableFunc: data.ArcServersTable,
+		},
 		{
 			stageName:    models.StageNameDefenderRecommendations,
 			sheetName:    "DefenderRecommendations",
@@ -64,15 +93,29 @@ func builtinSheets(data *renderers.ReportData) []sheetConfig {
 			tableFunc: data.DefenderTable,
 		},
 		{
-			stageName:    models.StageNameGraph,
-			sheetName:    "OutOfScope",
-			tableFunc:    data.ExcludedResourcesTable,
-			hyperlinkCol: hyperlinkColResources,
+			stageName: models.StageNameDefender,
+			sheetName: "SecureScore",
+			tableFunc: data.SecureScoreTable,
+		},
+		{
+			stageName: models.StageNameDefender,
+			sheetName: "SecureScoreControls",
+			tableFunc: data.SecureScoreControlsTable,
+		},
+		{
+			stageName: models.StageNameGraph,
+			sheetName: "OutOfScope",
+			tableFunc: data.ExcludedResourcesTable,
 		},
 		{
 			stageName: models.StageNameCost,
 			sheetName: "Costs",
 			tableFunc: data.CostTable,
 		},
+		{
+			stageName: models.StageNameQuota,
+			sheetName: "Quota",
+			tableFunc: data.QuotaTable,
+		},
 	}
 }
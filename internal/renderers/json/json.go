@@ -8,6 +8,8 @@ import (
 	"fmt"
 
 	"os"
+	"strings"
+	"time"
 
 	"github.com/Azure/azqr/internal/models"
 
@@ -42,20 +44,28 @@ func buildConsolidatedReport(data *renderers.ReportData) map[string]interface{}
 	// Only include Azure Policy data if the feature is enabled
 	if data.Stages.IsStageEnabled(models.StageNamePolicy) {
 		consolidatedReport["azurePolicy"] = convertToJSON(data.AzurePolicyTable())
+		consolidatedReport["policySubscriptions"] = convertToJSON(data.AzurePolicySubscriptionsTable())
+		consolidatedReport["policyInitiatives"] = convertToJSON(data.AzurePolicyInitiativesTable())
+		consolidatedReport["policyAssignments"] = convertToJSON(data.AzurePolicyAssignmentsTable())
+		consolidatedReport["policyTopNonCompliant"] = convertToJSON(data.AzurePolicyTopTable())
+		consolidatedReport["policyExemptions"] = convertToJSON(data.AzurePolicyExemptionsTable())
 	} else {
 		log.Debug().Msg("Skipping Azure Policy data in JSON. Feature is disabled")
 	}
 
-	// Only include Arc SQL data if the feature is enabled
+	// Only include Arc data if the feature is enabled
 	if data.Stages.IsStageEnabled(models.StageNameArc) {
 		consolidatedReport["arcSQL"] = convertToJSON(data.ArcSQLTable())
+		consolidatedReport["arcServers"] = convertToJSON(data.ArcServersTable())
 	} else {
-		log.Debug().Msg("Skipping Arc SQL data in JSON. Feature is disabled")
+		log.Debug().Msg("Skipping Arc data in JSON. Feature is disabled")
 	}
 
 	// Only include Defender data if the feature is enabled
 	if data.Stages.IsStageEnabled(models.StageNameDefender) {
 		consolidatedReport["defender"] = convertToJSON(data.DefenderTable())
+		consolidatedReport["secureScore"] = convertToJSON(data.SecureScoreTable())
+		consolidatedReport["secureScoreControls"] = convertToJSON(data.SecureScoreControlsTable())
 	} else {
 		log.Debug().Msg("Skipping Defender data in JSON. Feature is disabled")
 	}
@@ -74,6 +84,13 @@ func buildConsolidatedReport(data *renderers.ReportData) map[string]interface{}
 		log.Debug().Msg("Skipping Cost data in JSON. Feature is disabled")
 	}
 
+	// Only include Quota data if the feature is enabled
+	if data.Stages.IsStageEnabled(models.StageNameQuota) {
+		consolidatedReport["quota"] = convertToJSON(data.QuotaTable())
+	} else {
+		log.Debug().Msg("Skipping Quota data in JSON. Feature is disabled")
+	}
+
 	// Add external plugin results
 	if len(data.PluginResults) > 0 {
 		// Use a slice so that plugins returning multiple sheets (e.g. region-selection)
@@ -86,44 +103,51 @@ func buildConsolidatedReport(data *renderers.ReportData) map[string]interface{}
 				"pluginName":  result.PluginName,
 				"sheetName":   result.SheetName,
 				"description": result.Description,
-				dataKey:       convertToJSON(result.Table),
+			}
+			if len(result.Columns) > 0 {
+				pluginData["columns"] = result.Columns
+				pluginData[dataKey] = convertTypedToJSON(result)
+			} else {
+				pluginData[dataKey] = convertToJSON(result.Table)
 			}
 			pluginsList = append(pluginsList, pluginData)
 		}
 		consolidatedReport["externalPlugins"] = pluginsList
 	}
+	if len(data.PluginRuns) > 0 {
+		consolidatedReport["pluginRuns"] = convertToJSON(data.PluginRunsTable())
+	}
 
 	return consolidatedReport
 }
 
 // CreateJsonReport generates a single consolidated JSON report file
-func CreateJsonReport(data *renderers.ReportData) {
+func CreateJsonReport(data *renderers.ReportData) error {
 	filename := fmt.Sprintf("%s.json", data.OutputFileName)
 	log.Info().Msgf("Generating Report: %s", filename)
 
 	consolidatedReport := buildConsolidatedReport(data)
-	writeData(consolidatedReport, filename)
+	return writeData(consolidatedReport, filename)
 }
 
 // CreateJsonOutput generates the same consolidated JSON structure as CreateJsonReport
 // but returns it as a string for console output instead of writing to a file
-func CreateJsonOutput(data *renderers.ReportData) string {
+func CreateJsonOutput(data *renderers.ReportData) (string, error) {
 	consolidatedReport := buildConsolidatedReport(data)
 
 	js, err := json.MarshalIndent(consolidatedReport, "", "\t")
 	if err != nil {
-		log.Fatal().Err(err).Msg("error marshaling data:")
+		return "", fmt.Errorf("error marshaling data: %w", err)
 	}
 
-	return string(js)
+	return string(js), nil
 }
 
 // writeData writes the consolidated JSON data to a single file
-func writeData(data map[string]interface{}, filename string) {
+func writeData(data map[string]interface{}, filename string) error {
 	f, err := os.Create(filename) //nolint:gosec // filename is generated from user's output-name flag
 	if err != nil {
-		log.Fatal().Err(err).Msg("error creating json:")
-		return
+		return fmt.Errorf("error creating json: %w", err)
 	}
 
 	defer func() {
@@ -135,15 +159,13 @@ func writeData(data map[string]interface{}, filename string) {
 
 	js, err := json.MarshalIndent(data, "", "\t")
 	if err != nil {
-		log.Error().Err(err).Msg("error marshaling data:")
-		return
+		return fmt.Errorf("error marshaling data: %w", err)
 	}
 
-	_, err = f.Write(js)
-	if err != nil {
-		_ = f.Close()                                   // Close the file before exiting to ensure cleanup
-		log.Fatal().Err(err).Msg("error writing json:") //nolint:gocritic // File is explicitly closed above
+	if _, err := f.Write(js); err != nil {
+		return fmt.Errorf("error writing json: %w", err)
 	}
+	return nil
 }
 
 func convertToJSON(data [][]string) []map[string]string {
@@ -160,3 +182,36 @@ func convertToJSON(data [][]string) []map[string]string {
 
 	return result
 }
+
+// convertTypedToJSON converts a plugin table using its column types: numeric
+// columns become numbers, dates ISO 8601 strings and empty typed cells null.
+// Values that cannot be parsed (e.g. "N/A") are kept as strings.
+func convertTypedToJSON(result *renderers.PluginResult) []map[string]interface{} {
+	var items []map[string]interface{}
+	headers := result.Table[0]
+
+	for _, row := range result.Table[1:] {
+		item := make(map[string]interface{}, len(row))
+		for i, value := range row {
+			item[strcase.ToLowerCamel(headers[i])] = typedValue(result.Column(i), value)
+		}
+		items = append(items, item)
+	}
+
+	return items
+}
+
+// typedValue returns the JSON value of a cell in a typed column
+func typedValue(col models.ColumnFormat, value string) interface{} {
+	if col.Type != models.ColumnTypeText && strings.TrimSpace(value) == "" {
+		return nil
+	}
+	v, ok := col.Value(value)
+	if !ok {
+		return value
+	}
+	if t, isDate := v.(time.Time); isDate {
+		return models.FormatColumnDate(t)
+	}
+	return v
+}
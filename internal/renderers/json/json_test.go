@@ -88,7 +88,10 @@ func TestCreateJsonOutput(t *testing.T) {
 	}
 
 	// Test that it returns valid JSON
-	output := CreateJsonOutput(data)
+	output, err := CreateJsonOutput(data)
+	if err != nil {
+		t.Fatalf("CreateJsonOutput() error = %v", err)
+	}
 
 	// Verify it's valid JSON
 	var result map[string]interface{}
@@ -127,7 +130,9 @@ func TestCreateJsonReport(t *testing.T) {
 	}
 
 	// Create the report
-	CreateJsonReport(data)
+	if err := CreateJsonReport(data); err != nil {
+		t.Fatalf("CreateJsonReport() error = %v", err)
+	}
 
 	// Verify file was created
 	filename := filepath.Join(tmpDir, "test_report.json")
@@ -220,7 +225,9 @@ func TestCreateJsonReportWithPlugins(t *testing.T) {
 	}
 
 	// Create the report
-	CreateJsonReport(data)
+	if err := CreateJsonReport(data); err != nil {
+		t.Fatalf("CreateJsonReport() error = %v", err)
+	}
 
 	// Verify file was created
 	filename := filepath.Join(tmpDir, "test_report_plugins.json")
@@ -296,3 +303,27 @@ func mapKeys(m map[string]interface{}) []string {
 	}
 	return keys
 }
+
+func TestConvertTypedToJSON(t *testing.T) {
+	result := &renderers.PluginResult{
+		Table: [][]string{
+			{"Name", "Request Count", "Share", "Hour"},
+			{"a", "1,200", "12.5%", "2024-07-09 15:00"},
+			{"b", "N/A", "", "2024-07-09"},
+		},
+		Columns: []models.ColumnFormat{
+			{Name: "Name"},
+			{Name: "Request Count", Type: models.ColumnTypeNumber},
+			{Name: "Share", Type: models.ColumnTypePercent},
+			{Name: "Hour", Type: models.ColumnTypeDate},
+		},
+	}
+
+	want := []map[string]interface{}{
+		{"name": "a", "requestCount": 1200.0, "share": 12.5, "hour": "2024-07-09T15:00:00Z"},
+		{"name": "b", "requestCount": "N/A", "share": nil, "hour": "2024-07-09"},
+	}
+	if got := convertTypedToJSON(result); !reflect.DeepEqual(got, want) {
+		t.Errorf("convertTypedToJSON() = %v, want %v", got, want)
+	}
+}
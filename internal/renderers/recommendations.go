@@ -12,18 +12,18 @@ import (
 	"github.com/Azure/azqr/internal/graph"
 	"github.com/Azure/azqr/internal/models"
 	"github.com/Azure/azqr/internal/scanners"
-
-	"github.com/rs/zerolog/log"
 )
 
-func GetAllRecommendations(md bool) string {
+// SupportedRecommendations returns every recommendation azqr can evaluate with
+// the registered scanners, sorted by recommendation ID, along with the number
+// of supported resource types. Rules that cannot be validated with Azure
+// Resource Graph or are disabled are excluded.
+func SupportedRecommendations() ([]models.GraphRecommendation, int) {
 	_, serviceScanners := models.GetScanners()
 	graphScanner := graph.NewScanner(serviceScanners, nil, nil)
 	graphRec := graphScanner.GetRecommendations()
 	diagSettingsRec := scanners.GetRecommendations()
 
-	var output string
-
 	graphRecommendations := map[string]models.GraphRecommendation{}
 	for _, scanner := range serviceScanners {
 		for _, t := range scanner.ResourceTypes() {
@@ -48,24 +48,36 @@ func GetAllRecommendations(md bool) string {
 	}
 	sort.Strings(graphKeys)
 
+	result := make([]models.GraphRecommendation, 0, len(graphKeys))
+	for _, k := range graphKeys {
+		result = append(result, graphRecommendations[k])
+	}
+	return result, len(graphRec)
+}
+
+// GetAllRecommendations returns the supported recommendations as a markdown
+// table when md is set, or as JSON otherwise.
+func GetAllRecommendations(md bool) (string, error) {
+	recommendations, resourceTypeCount := SupportedRecommendations()
+
+	var output string
+
 	if md {
 		output += "## Recommendations List\n\n"
-		output += fmt.Sprintf("Total Supported Azure Resource Types: %d\n\n", len(graphRec))
+		output += fmt.Sprintf("Total Supported Azure Resource Types: %d\n\n", resourceTypeCount)
 		output += "|  | Id | Resource Type | Category | Impact | Recommendation | Learn\n"
 		output += "---|---|---|---|---|---|---\n"
 
 		i := 0
 
-		for _, k := range graphKeys {
-			r := graphRecommendations[k]
+		for _, r := range recommendations {
 			i++
 			output += fmt.Sprintf("%s | %s | %s | %s | %s | %s | [Learn](%s)\n", fmt.Sprint(i), r.RecommendationID, r.ResourceType, r.Category, r.Impact, r.Recommendation, r.LearnMoreLink[0].Url)
 		}
 	} else {
 		j := []map[string]string{}
 
-		for _, k := range graphKeys {
-			r := graphRecommendations[k]
+		for _, r := range recommendations {
 			j = append(j, map[string]string{
 				"recommendationId": r.RecommendationID,
 				"resourceType":     r.ResourceType,
@@ -79,10 +91,10 @@ func GetAllRecommendations(md bool) string {
 		// print j as json to stdout
 		js, err := json.MarshalIndent(j, "", "\t")
 		if err != nil {
-			log.Fatal().Err(err).Msg("error marshaling data:")
+			return "", fmt.Errorf("error marshaling data: %w", err)
 		}
 		output = string(js)
 	}
 
-	return output
+	return output, nil
 }
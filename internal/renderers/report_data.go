@@ -5,9 +5,13 @@ package renderers
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Azure/azqr/internal/models"
+	"github.com/Azure/azqr/internal/scanners"
 	"github.com/Azure/azqr/internal/skus"
 )
 
@@ -18,24 +22,40 @@ type (
 		Graph                   []*models.GraphResult                             `json:"graph,omitempty"`
 		Defender                []*models.DefenderResult                          `json:"defender,omitempty"`
 		DefenderRecommendations []*models.DefenderRecommendation                  `json:"defenderRecommendations,omitempty"`
+		SecureScores            []*models.SecureScoreResult                       `json:"secureScores,omitempty"`
 		Advisor                 []*models.AdvisorResult                           `json:"advisor,omitempty"`
 		AzurePolicy             []*models.AzurePolicyResult                       `json:"azurePolicy,omitempty"`
+		AzurePolicyCompliance   []*models.AzurePolicyComplianceResult             `json:"azurePolicyCompliance,omitempty"`
+		AzurePolicyExemptions   []*models.AzurePolicyExemptionResult              `json:"azurePolicyExemptions,omitempty"`
 		ArcSQL                  []*models.ArcSQLResult                            `json:"arcSQL,omitempty"`
+		ArcServers              []*models.ArcServerResult                         `json:"arcServers,omitempty"`
 		Cost                    []*models.CostResult                              `json:"cost,omitempty"`
+		Quota                   []*models.QuotaResult                             `json:"quota,omitempty"`
+		ResourceCosts           []*models.ResourceCost                            `json:"resourceCosts,omitempty"`
 		Recommendations         map[string]map[string]*models.GraphRecommendation `json:"-"`
 		Resources               []*models.Resource                                `json:"resources,omitempty"`
 		ExludedResources        []*models.Resource                                `json:"-"`
 		ResourceTypeCount       []*models.ResourceTypeCount                       `json:"resourceTypeCount,omitempty"`
 		PluginResults           []*PluginResult                                   `json:"pluginResults,omitempty"`
+		PluginRuns              []*PluginRun                                      `json:"pluginRuns,omitempty"`
 		Stages                  *models.StageConfigs                              `json:"-"`
 
 		// Table caches - populated on first call, reused thereafter
 		cachedImpactedTable                [][]string `json:"-"`
 		cachedCostTable                    [][]string `json:"-"`
+		cachedQuotaTable                   [][]string `json:"-"`
 		cachedDefenderTable                [][]string `json:"-"`
+		cachedSecureScoreTable             [][]string `json:"-"`
+		cachedSecureScoreControlsTable     [][]string `json:"-"`
 		cachedAdvisorTable                 [][]string `json:"-"`
 		cachedAzurePolicyTable             [][]string `json:"-"`
+		cachedPolicySubscriptionsTable     [][]string `json:"-"`
+		cachedPolicyInitiativesTable       [][]string `json:"-"`
+		cachedPolicyAssignmentsTable       [][]string `json:"-"`
+		cachedPolicyTopTable               [][]string `json:"-"`
+		cachedPolicyExemptionsTable        [][]string `json:"-"`
 		cachedArcSQLTable                  [][]string `json:"-"`
+		cachedArcServersTable              [][]string `json:"-"`
 		cachedRecommendationsTable         [][]string `json:"-"`
 		cachedResourceTypesTable           [][]string `json:"-"`
 		cachedDefenderRecommendationsTable [][]string `json:"-"`
@@ -49,6 +69,18 @@ type (
 		SheetName   string     // Name for Excel sheet
 		Description string     // Description of the data
 		Table       [][]string // Table data (first row is headers)
+		// Columns holds the format of each table column; nil when untyped
+		Columns []models.ColumnFormat `json:",omitempty"`
+	}
+
+	// PluginRun records the outcome of a plugin execution
+	PluginRun struct {
+		PluginName string        // Name of the plugin
+		Version    string        // Version of the plugin
+		Status     string        // PluginRunSucceeded, PluginRunFailed or PluginRunTimedOut
+		Duration   time.Duration // Time the plugin ran
+		Sheets     int           // Number of sheets returned
+		Error      string        // Error, if the plugin did not succeed
 	}
 
 	ResourceTypeCountResults struct {
@@ -56,6 +88,62 @@ type (
 	}
 )
 
+// Plugin run statuses
+const (
+	PluginRunSucceeded = "Succeeded"
+	PluginRunFailed    = "Failed"
+	PluginRunTimedOut  = "Timed Out"
+)
+
+// PluginRunsTable returns the status and duration of each plugin execution.
+func (rd *ReportData) PluginRunsTable() [][]string {
+	headers := []string{"Plugin", "Version", "Status", "Duration (s)", "Sheets", "Error"}
+
+	rows := make([][]string, 1, len(rd.PluginRuns)+1)
+	rows[0] = headers
+
+	for _, r := range rd.PluginRuns {
+		rows = append(rows, []string{
+			r.PluginName,
+			r.Version,
+			r.Status,
+			fmt.Sprintf("%.1f", r.Duration.Seconds()),
+			fmt.Sprintf("%d", r.Sheets),
+			r.Error,
+		})
+	}
+	return rows
+}
+
+// Column returns the format of the i-th table column (text when untyped).
+func (r *PluginResult) Column(i int) models.ColumnFormat {
+	if i < len(r.Columns) {
+		return r.Columns[i]
+	}
+	if len(r.Table) > 0 && i < len(r.Table[0]) {
+		return models.ColumnFormat{Name: r.Table[0][i]}
+	}
+	return models.ColumnFormat{}
+}
+
+// NormalizedTable returns the table with locale-neutral values in typed
+// columns: plain numbers and ISO 8601 dates.
+func (r *PluginResult) NormalizedTable() [][]string {
+	if len(r.Columns) == 0 || len(r.Table) == 0 {
+		return r.Table
+	}
+	table := make([][]string, len(r.Table))
+	table[0] = r.Table[0]
+	for i, row := range r.Table[1:] {
+		normalized := make([]string, len(row))
+		for j, value := range row {
+			normalized[j] = r.Column(j).Normalize(value)
+		}
+		table[i+1] = normalized
+	}
+	return table
+}
+
 func (rd *ReportData) ResourcesTable() [][]string {
 	if rd.cachedResourcesTable != nil {
 		return rd.cachedResourcesTable
@@ -78,6 +166,10 @@ func (rd *ReportData) ImpactedTable() [][]string {
 	}
 
 	headers := []string{"Validated Using", "Source", "Category", "Impact", "Resource Type", "Recommendation", "Recommendation Id", "Subscription Id", "Subscription Name", "Resource Group", "Resource Name", "Resource Id", "Param1", "Param2", "Param3", "Param4", "Param5", "Learn"}
+	withCost := rd.costEnabled()
+	if withCost {
+		headers = append(headers, "Monthly Cost", "Estimated Monthly Savings", "Currency", "Cost Source")
+	}
 
 	// Composite key type for deduplication - avoids string concatenation allocations
 	type impactedKey struct {
@@ -132,6 +224,14 @@ func (rd *ReportData) ImpactedTable() [][]string {
 			r.Param5,
 			r.Learn,
 		}
+		if withCost {
+			var monthlyCost, savings string
+			if r.CostSource != "" {
+				monthlyCost = formatAmount(r.MonthlyCost)
+				savings = formatAmount(r.EstimatedSavings)
+			}
+			row = append(row, monthlyCost, savings, r.CostCurrency, r.CostSource)
+		}
 		rows = append(rows, row)
 	}
 
@@ -144,7 +244,7 @@ func (rd *ReportData) CostTable() [][]string {
 		return rd.cachedCostTable
 	}
 
-	headers := []string{"From", "To", "Subscription Id", "Subscription Name", "Service Name", "Value", "Currency"}
+	headers := []string{"From", "To", "Subscription Id", "Subscription Name", "Date", "Cost Type", "Service Name", "Resource Group", "Resource Id", "Meter Category", "Tag Key", "Tag Value", "Value", "Currency"}
 
 	// Pre-allocate with capacity to avoid reallocations
 	rows := make([][]string, 1, len(rd.Cost)+1)
@@ -156,7 +256,14 @@ func (rd *ReportData) CostTable() [][]string {
 			r.To.Format("2006-01-02"),
 			MaskSubscriptionID(r.SubscriptionID, rd.Mask),
 			r.SubscriptionName,
+			r.Date,
+			r.CostType,
 			r.ServiceName,
+			r.ResourceGroup,
+			MaskSubscriptionIDInResourceID(r.ResourceID, rd.Mask),
+			r.MeterCategory,
+			r.TagKey,
+			r.TagValue,
 			r.Value,
 			r.Currency,
 		}
@@ -167,6 +274,39 @@ func (rd *ReportData) CostTable() [][]string {
 	return rows
 }
 
+// QuotaTable returns the quotas near or over their limit formatted as a table with headers and rows for reporting.
+func (rd *ReportData) QuotaTable() [][]string {
+	if rd.cachedQuotaTable != nil {
+		return rd.cachedQuotaTable
+	}
+
+	headers := []string{"Subscription Id", "Subscription Name", "Location", "Quota Type", "Quota", "Quota Name", "Current", "Limit", "Available", "Headroom %", "Status"}
+
+	// Pre-allocate with capacity to avoid reallocations
+	rows := make([][]string, 1, len(rd.Quota)+1)
+	rows[0] = headers
+
+	for _, q := range rd.Quota {
+		row := []string{
+			MaskSubscriptionID(q.SubscriptionID, rd.Mask),
+			q.SubscriptionName,
+			q.Location,
+			q.QuotaType,
+			q.Name,
+			q.LocalizedName,
+			fmt.Sprintf("%d", q.Current),
+			fmt.Sprintf("%d", q.Limit),
+			fmt.Sprintf("%d", q.Available),
+			fmt.Sprintf("%.1f", q.HeadroomPct),
+			q.Status,
+		}
+		rows = append(rows, row)
+	}
+
+	rd.cachedQuotaTable = rows
+	return rows
+}
+
 func (rd *ReportData) DefenderTable() [][]string {
 	if rd.cachedDefenderTable != nil {
 		return rd.cachedDefenderTable
@@ -192,6 +332,74 @@ func (rd *ReportData) DefenderTable() [][]string {
 	return rows
 }
 
+// secureScores returns the tenant secure score followed by the secure score of
+// each subscription.
+func (rd *ReportData) secureScores() []*models.SecureScoreResult {
+	tenant := scanners.TenantSecureScore(rd.SecureScores)
+	if tenant == nil {
+		return nil
+	}
+	return append([]*models.SecureScoreResult{tenant}, rd.SecureScores...)
+}
+
+// SecureScoreTable returns the Defender for Cloud secure score of the tenant
+// and of each subscription.
+func (rd *ReportData) SecureScoreTable() [][]string {
+	if rd.cachedSecureScoreTable != nil {
+		return rd.cachedSecureScoreTable
+	}
+
+	headers := []string{"Subscription Id", "Subscription Name", "Current Score", "Max Score", "Percentage", "Weight"}
+
+	scores := rd.secureScores()
+	rows := make([][]string, 1, len(scores)+1)
+	rows[0] = headers
+
+	for _, s := range scores {
+		rows = append(rows, []string{
+			MaskSubscriptionID(s.SubscriptionID, rd.Mask),
+			s.SubscriptionName,
+			formatAmount(s.CurrentScore),
+			formatAmount(s.MaxScore),
+			fmt.Sprintf("%.1f", s.Percentage),
+			fmt.Sprintf("%d", s.Weight),
+		})
+	}
+
+	rd.cachedSecureScoreTable = rows
+	return rows
+}
+
+// SecureScoreControlsTable returns the secure score of each security control
+// in the tenant and in each subscription.
+func (rd *ReportData) SecureScoreControlsTable() [][]string {
+	if rd.cachedSecureScoreControlsTable != nil {
+		return rd.cachedSecureScoreControlsTable
+	}
+
+	headers := []string{"Subscription Id", "Subscription Name", "Control", "Current Score", "Max Score", "Percentage", "Healthy Resources", "Unhealthy Resources", "Not Applicable Resources"}
+
+	rows := [][]string{headers}
+	for _, s := range rd.secureScores() {
+		for _, c := range s.Controls {
+			rows = append(rows, []string{
+				MaskSubscriptionID(s.SubscriptionID, rd.Mask),
+				s.SubscriptionName,
+				c.DisplayName,
+				formatAmount(c.CurrentScore),
+				formatAmount(c.MaxScore),
+				fmt.Sprintf("%.1f", c.Percentage),
+				fmt.Sprintf("%d", c.HealthyResources),
+				fmt.Sprintf("%d", c.UnhealthyResources),
+				fmt.Sprintf("%d", c.NotApplicableResources),
+			})
+		}
+	}
+
+	rd.cachedSecureScoreControlsTable = rows
+	return rows
+}
+
 func (rd *ReportData) AdvisorTable() [][]string {
 	if rd.cachedAdvisorTable != nil {
 		return rd.cachedAdvisorTable
@@ -258,6 +466,221 @@ func (rd *ReportData) AzurePolicyTable() [][]string {
 	return rows
 }
 
+// compliancePct formats the percentage of compliant resources, empty without
+// evaluated resources.
+func compliancePct(compliant, nonCompliant int) string {
+	if compliant+nonCompliant == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.1f", float64(compliant)/float64(compliant+nonCompliant)*100)
+}
+
+// AzurePolicySubscriptionsTable returns the resource compliance of each
+// subscription across all its policy assignments.
+func (rd *ReportData) AzurePolicySubscriptionsTable() [][]string {
+	if rd.cachedPolicySubscriptionsTable != nil {
+		return rd.cachedPolicySubscriptionsTable
+	}
+
+	headers := []string{"Subscription Id", "Subscription Name", "Compliant Resources", "Non-Compliant Resources", "Compliance (%)"}
+
+	rows := [][]string{headers}
+	for _, c := range rd.AzurePolicyCompliance {
+		if c.PolicyAssignmentID != "" {
+			continue
+		}
+		rows = append(rows, []string{
+			MaskSubscriptionID(c.SubscriptionID, rd.Mask),
+			c.SubscriptionName,
+			fmt.Sprintf("%d", c.CompliantResources),
+			fmt.Sprintf("%d", c.NonCompliantResources),
+			compliancePct(c.CompliantResources, c.NonCompliantResources),
+		})
+	}
+
+	rd.cachedPolicySubscriptionsTable = rows
+	return rows
+}
+
+// AzurePolicyAssignmentsTable returns the resource compliance of each policy
+// assignment in each subscription.
+func (rd *ReportData) AzurePolicyAssignmentsTable() [][]string {
+	if rd.cachedPolicyAssignmentsTable != nil {
+		return rd.cachedPolicyAssignmentsTable
+	}
+
+	headers := []string{"Subscription Id", "Subscription Name", "Policy Assignment Name", "Initiative", "Compliant Resources", "Non-Compliant Resources", "Compliance (%)", "Policy Assignment Id"}
+
+	rows := [][]string{headers}
+	for _, c := range rd.AzurePolicyCompliance {
+		if c.PolicyAssignmentID == "" {
+			continue
+		}
+		rows = append(rows, []string{
+			MaskSubscriptionID(c.SubscriptionID, rd.Mask),
+			c.SubscriptionName,
+			c.PolicyAssignmentName,
+			c.InitiativeName,
+			fmt.Sprintf("%d", c.CompliantResources),
+			fmt.Sprintf("%d", c.NonCompliantResources),
+			compliancePct(c.CompliantResources, c.NonCompliantResources),
+			c.PolicyAssignmentID,
+		})
+	}
+
+	rd.cachedPolicyAssignmentsTable = rows
+	return rows
+}
+
+// AzurePolicyInitiativesTable returns the resource compliance of each
+// initiative across its assignments in all subscriptions, least compliant
+// first.
+func (rd *ReportData) AzurePolicyInitiativesTable() [][]string {
+	if rd.cachedPolicyInitiativesTable != nil {
+		return rd.cachedPolicyInitiativesTable
+	}
+
+	headers := []string{"Initiative", "Assignments", "Subscriptions", "Compliant Resources", "Non-Compliant Resources", "Compliance (%)", "Initiative Id"}
+
+	type initiative struct {
+		id, name                string
+		assignments, subs       map[string]struct{}
+		compliant, nonCompliant int
+	}
+	var initiatives []*initiative
+	byID := map[string]*initiative{}
+	for _, c := range rd.AzurePolicyCompliance {
+		if c.PolicyAssignmentID == "" || c.InitiativeID == "" {
+			continue
+		}
+		i, ok := byID[c.InitiativeID]
+		if !ok {
+			i = &initiative{id: c.InitiativeID, name: c.InitiativeName, assignments: map[string]struct{}{}, subs: map[string]struct{}{}}
+			byID[c.InitiativeID] = i
+			initiatives = append(initiatives, i)
+		}
+		i.assignments[c.PolicyAssignmentID] = struct{}{}
+		i.subs[c.SubscriptionID] = struct{}{}
+		i.compliant += c.CompliantResources
+		i.nonCompliant += c.NonCompliantResources
+	}
+	sort.SliceStable(initiatives, func(a, b int) bool {
+		return initiatives[a].nonCompliant > initiatives[b].nonCompliant
+	})
+
+	rows := make([][]string, 1, len(initiatives)+1)
+	rows[0] = headers
+	for _, i := range initiatives {
+		rows = append(rows, []string{
+			i.name,
+			fmt.Sprintf("%d", len(i.assignments)),
+			fmt.Sprintf("%d", len(i.subs)),
+			fmt.Sprintf("%d", i.compliant),
+			fmt.Sprintf("%d", i.nonCompliant),
+			compliancePct(i.compliant, i.nonCompliant),
+			i.id,
+		})
+	}
+
+	rd.cachedPolicyInitiativesTable = rows
+	return rows
+}
+
+// AzurePolicyTopTable returns the policies with the most non-compliant
+// resources, up to the policy stage top-policies option.
+func (rd *ReportData) AzurePolicyTopTable() [][]string {
+	if rd.cachedPolicyTopTable != nil {
+		return rd.cachedPolicyTopTable
+	}
+
+	headers := []string{"Policy Display Name", "Non-Compliant Resources", "Subscriptions", "Policy Definition Id"}
+
+	type policy struct {
+		id, name  string
+		resources int
+		subs      map[string]struct{}
+	}
+	var policies []*policy
+	byID := map[string]*policy{}
+	for _, d := range rd.AzurePolicy {
+		id := strings.ToLower(d.PolicyDefinitionID)
+		p, ok := byID[id]
+		if !ok {
+			p = &policy{id: d.PolicyDefinitionID, name: d.PolicyDisplayName, subs: map[string]struct{}{}}
+			byID[id] = p
+			policies = append(policies, p)
+		}
+		p.resources++
+		p.subs[d.SubscriptionID] = struct{}{}
+	}
+	sort.SliceStable(policies, func(a, b int) bool {
+		return policies[a].resources > policies[b].resources
+	})
+
+	top := 20
+	if rd.Stages != nil {
+		if n, ok := rd.Stages.GetStageOptionsWithDefaults(models.StageNamePolicy)["top-policies"].(int); ok {
+			top = n
+		}
+	}
+	if top >= 0 && len(policies) > top {
+		policies = policies[:top]
+	}
+
+	rows := make([][]string, 1, len(policies)+1)
+	rows[0] = headers
+	for _, p := range policies {
+		rows = append(rows, []string{
+			p.name,
+			fmt.Sprintf("%d", p.resources),
+			fmt.Sprintf("%d", len(p.subs)),
+			p.id,
+		})
+	}
+
+	rd.cachedPolicyTopTable = rows
+	return rows
+}
+
+// maskScope masks the subscription ID of a subscription, resource group or
+// resource scope; management group scopes are returned as is.
+func maskScope(scope string, mask bool) string {
+	if !strings.HasPrefix(strings.ToLower(scope), "/subscriptions/") {
+		return scope
+	}
+	return MaskSubscriptionIDInResourceID(scope, mask)
+}
+
+// AzurePolicyExemptionsTable returns the policy exemptions with their status.
+func (rd *ReportData) AzurePolicyExemptionsTable() [][]string {
+	if rd.cachedPolicyExemptionsTable != nil {
+		return rd.cachedPolicyExemptionsTable
+	}
+
+	headers := []string{"Subscription Id", "Subscription Name", "Exemption Name", "Display Name", "Category", "Status", "Expires On", "Scope", "Policy Assignment Name", "Description", "Policy Assignment Id"}
+
+	rows := make([][]string, 1, len(rd.AzurePolicyExemptions)+1)
+	rows[0] = headers
+	for _, e := range rd.AzurePolicyExemptions {
+		rows = append(rows, []string{
+			MaskSubscriptionID(e.SubscriptionID, rd.Mask),
+			e.SubscriptionName,
+			e.Name,
+			e.DisplayName,
+			e.Category,
+			e.Status,
+			e.ExpiresOn,
+			maskScope(e.Scope, rd.Mask),
+			e.PolicyAssignmentName,
+			e.Description,
+			e.PolicyAssignmentID,
+		})
+	}
+
+	rd.cachedPolicyExemptionsTable = rows
+	return rows
+}
+
 // ArcSQLTable returns Arc-enabled SQL Server data formatted as a table with headers and rows for reporting.
 func (rd *ReportData) ArcSQLTable() [][]string {
 	if rd.cachedArcSQLTable != nil {
@@ -294,6 +717,48 @@ func (rd *ReportData) ArcSQLTable() [][]string {
 	return rows
 }
 
+// ArcServersTable returns the Arc-enabled servers with their connection, agent,
+// operating system, extensions and ESU license status.
+func (rd *ReportData) ArcServersTable() [][]string {
+	if rd.cachedArcServersTable != nil {
+		return rd.cachedArcServersTable
+	}
+
+	headers := []string{"Subscription Id", "Subscription Name", "Resource Group", "Name", "Location", "Status", "Last Status Change", "Disconnected Days", "Agent Version", "Agent Status", "OS Type", "OS Name", "OS Version", "OS End of Life", "OS Status", "Azure Monitor Agent", "Defender for Endpoint", "Update Manager", "ESU Eligibility", "ESU License Status", "Extensions"}
+
+	rows := make([][]string, 1, len(rd.ArcServers)+1)
+	rows[0] = headers
+
+	for _, d := range rd.ArcServers {
+		rows = append(rows, []string{
+			MaskSubscriptionID(d.SubscriptionID, rd.Mask),
+			d.SubscriptionName,
+			d.ResourceGroup,
+			d.Name,
+			d.Location,
+			d.Status,
+			d.LastStatusChange,
+			strconv.Itoa(d.DisconnectedDays),
+			d.AgentVersion,
+			d.AgentStatus,
+			d.OSType,
+			d.OSName,
+			d.OSVersion,
+			d.OSEndOfLife,
+			d.OSStatus,
+			strconv.FormatBool(d.MonitoringAgent),
+			strconv.FormatBool(d.DefenderForEndpoint),
+			strconv.FormatBool(d.UpdateManager),
+			d.ESUEligibility,
+			d.ESULicenseStatus,
+			strings.Join(d.Extensions, ", "),
+		})
+	}
+
+	rd.cachedArcServersTable = rows
+	return rows
+}
+
 func (rd *ReportData) RecommendationsTable() [][]string {
 	if rd.cachedRecommendationsTable != nil {
 		return rd.cachedRecommendationsTable
@@ -442,10 +907,19 @@ func (rd *ReportData) DefenderRecommendationsTable() [][]string {
 func (rd *ReportData) ClearTableCache() {
 	rd.cachedImpactedTable = nil
 	rd.cachedCostTable = nil
+	rd.cachedQuotaTable = nil
 	rd.cachedDefenderTable = nil
+	rd.cachedSecureScoreTable = nil
+	rd.cachedSecureScoreControlsTable = nil
 	rd.cachedAdvisorTable = nil
 	rd.cachedAzurePolicyTable = nil
+	rd.cachedPolicySubscriptionsTable = nil
+	rd.cachedPolicyInitiativesTable = nil
+	rd.cachedPolicyAssignmentsTable = nil
+	rd.cachedPolicyTopTable = nil
+	rd.cachedPolicyExemptionsTable = nil
 	rd.cachedArcSQLTable = nil
+	rd.cachedArcServersTable = nil
 	rd.cachedRecommendationsTable = nil
 	rd.cachedResourceTypesTable = nil
 	rd.cachedDefenderRecommendationsTable = nil
@@ -461,26 +935,38 @@ func NewReportData(outputFile string, mask bool, stages *models.StageConfigs) Re
 		Graph:                   []*models.GraphResult{},
 		Defender:                []*models.DefenderResult{},
 		DefenderRecommendations: []*models.DefenderRecommendation{},
+		SecureScores:            []*models.SecureScoreResult{},
 		Advisor:                 []*models.AdvisorResult{},
 		AzurePolicy:             []*models.AzurePolicyResult{},
+		AzurePolicyCompliance:   []*models.AzurePolicyComplianceResult{},
+		AzurePolicyExemptions:   []*models.AzurePolicyExemptionResult{},
 		ArcSQL:                  []*models.ArcSQLResult{},
+		ArcServers:              []*models.ArcServerResult{},
 		Cost:                    []*models.CostResult{},
+		Quota:                   []*models.QuotaResult{},
+		ResourceCosts:           []*models.ResourceCost{},
 		ResourceTypeCount:       []*models.ResourceTypeCount{},
 		Stages:                  stages,
 	}
 }
 
-func MaskSubscriptionID(subscriptionID string, mask bool) string {
-	if len(subscriptionID) < 36 {
-		return ""
+// costEnabled reports whether the cost stage queried per-resource costs, which
+// adds the cost columns to the impacted resources and inventory tables.
+func (rd *ReportData) costEnabled() bool {
+	if rd.Stages == nil || !rd.Stages.IsStageEnabled(models.StageNameCost) {
+		return false
 	}
+	resourceCosts, _ := rd.Stages.GetStageOptionsWithDefaults(models.StageNameCost)["resource-costs"].(bool)
+	return resourceCosts || len(rd.ResourceCosts) > 0
+}
 
-	if !mask {
-		return subscriptionID
-	}
+// formatAmount formats a cost amount with two decimals.
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
 
-	// Show only last 7 chars of the subscription ID
-	return "xxxxxxxx-xxxx-xxxx-xxxx-xxxxx" + subscriptionID[29:]
+func MaskSubscriptionID(subscriptionID string, mask bool) string {
+	return models.MaskSubscriptionID(subscriptionID, mask)
 }
 
 func MaskSubscriptionIDInResourceID(resourceID string, mask bool) string {
@@ -502,6 +988,15 @@ func MaskSubscriptionIDInResourceID(resourceID string, mask bool) string {
 
 func (rd *ReportData) resourcesTable(resources []*models.Resource) [][]string {
 	headers := []string{"Subscription Id", "Resource Group", "Location", "Resource Type", "Resource Name", "Sku Name", "Sku Tier", "Capacity", "Kind", "SLA", "Resource Id"}
+	withCost := rd.costEnabled()
+	var costByID map[string]*models.ResourceCost
+	if withCost {
+		headers = append(headers, "Monthly Cost", "Currency")
+		costByID = make(map[string]*models.ResourceCost, len(rd.ResourceCosts))
+		for _, c := range rd.ResourceCosts {
+			costByID[strings.ToLower(c.ResourceID)] = c
+		}
+	}
 
 	// Pre-allocate with capacity to avoid reallocations
 	rows := make([][]string, 1, len(resources)+1)
@@ -542,6 +1037,13 @@ func (rd *ReportData) resourcesTable(resources []*models.Resource) [][]string {
 			sla,
 			MaskSubscriptionIDInResourceID(r.ID, rd.Mask),
 		}
+		if withCost {
+			if c, ok := costByID[strings.ToLower(r.ID)]; ok {
+				row = append(row, formatAmount(c.Cost), c.Currency)
+			} else {
+				row = append(row, "", "")
+			}
+		}
 		rows = append(rows, row)
 	}
 
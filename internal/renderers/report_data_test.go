@@ -4,6 +4,7 @@
 package renderers
 
 import (
+	"reflect"
 	"strings"
 	"testing"
 
@@ -433,3 +434,181 @@ func TestFeatureFlagsCombinations(t *testing.T) {
 		}
 	}
 }
+
+func TestPluginResultNormalizedTable(t *testing.T) {
+	result := &PluginResult{
+		Table: [][]string{
+			{"Name", "Cost", "Date"},
+			{"a", "$1,234.50", "2024-07-09T00:00:00Z"},
+			{"b", "N/A", ""},
+		},
+		Columns: []models.ColumnFormat{
+			{Name: "Name"},
+			{Name: "Cost", Type: models.ColumnTypeCurrency, Unit: "USD"},
+			{Name: "Date", Type: models.ColumnTypeDate},
+		},
+	}
+
+	want := [][]string{
+		{"Name", "Cost", "Date"},
+		{"a", "1234.5", "2024-07-09"},
+		{"b", "N/A", ""},
+	}
+	if got := result.NormalizedTable(); !reflect.DeepEqual(got, want) {
+		t.Errorf("NormalizedTable() = %v, want %v", got, want)
+	}
+
+	untyped := &PluginResult{Table: [][]string{{"A"}, {"1,000"}}}
+	if got := untyped.NormalizedTable(); !reflect.DeepEqual(got, untyped.Table) {
+		t.Errorf("untyped tables should be unchanged, got %v", got)
+	}
+}
+
+func TestReportDataCostColumns(t *testing.T) {
+	const id = "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.Compute/disks/d1"
+	newData := func(cost, resourceCosts bool) ReportData {
+		stages := models.NewStageConfigs()
+		_ = stages.EnableStage(models.StageNameGraph)
+		if cost {
+			_ = stages.EnableStage(models.StageNameCost)
+		}
+		if resourceCosts {
+			_ = stages.ApplyStageParams([]string{"cost.resource-costs=true"})
+		}
+		rd := NewReportData("test", false, stages)
+		rd.Graph = []*models.GraphResult{
+			{ResourceID: id, RecommendationID: "rec", Category: models.CategoryGovernance},
+		}
+		rd.Resources = []*models.Resource{{ID: id}}
+		if resourceCosts {
+			rd.Graph[0].MonthlyCost, rd.Graph[0].EstimatedSavings = 12.346, 12.346
+			rd.Graph[0].CostCurrency, rd.Graph[0].CostSource = "EUR", "Cost Management"
+			rd.ResourceCosts = []*models.ResourceCost{{ResourceID: strings.ToLower(id), Cost: 12.346, Currency: "EUR"}}
+		}
+		return rd
+	}
+
+	// The cost stage alone only queries subscription costs, so the
+	// per-resource columns would stay empty.
+	for _, cost := range []bool{false, true} {
+		rd := newData(cost, false)
+		if got := len(rd.ImpactedTable()[0]); got != 18 {
+			t.Errorf("impacted columns without resource costs (cost stage %v) = %d, want 18", cost, got)
+		}
+		if got := len(rd.ResourcesTable()[0]); got != 11 {
+			t.Errorf("resource columns without resource costs (cost stage %v) = %d, want 11", cost, got)
+		}
+	}
+
+	rd := newData(true, true)
+	impacted := rd.ImpactedTable()
+	if got := impacted[1][18:]; !reflect.DeepEqual(got, []string{"12.35", "12.35", "EUR", "Cost Management"}) {
+		t.Errorf("impacted cost columns = %v", got)
+	}
+	resources := rd.ResourcesTable()
+	if got := resources[1][11:]; !reflect.DeepEqual(got, []string{"12.35", "EUR"}) {
+		t.Errorf("resource cost columns = %v", got)
+	}
+}
+
+func TestReportDataSecureScoreTables(t *testing.T) {
+	const sub = "00000000-0000-0000-0000-000000000000"
+	rd := NewReportData("test", false, models.NewStageConfigsWithDefaults())
+	if got := len(rd.SecureScoreTable()); got != 1 {
+		t.Errorf("secure score rows without scores = %d, want only headers", got)
+	}
+
+	rd = NewReportData("test", false, models.NewStageConfigsWithDefaults())
+	rd.SecureScores = []*models.SecureScoreResult{
+		{SubscriptionID: sub, SubscriptionName: "Sub One", CurrentScore: 30, MaxScore: 40, Percentage: 75, Weight: 40,
+			Controls: []*models.SecureScoreControl{
+				{Name: "mfa", DisplayName: "Enable MFA", CurrentScore: 5, MaxScore: 10, Percentage: 50, HealthyResources: 1, UnhealthyResources: 1},
+			}},
+	}
+
+	scores := rd.SecureScoreTable()
+	want := [][]string{
+		{"Subscription Id", "Subscription Name", "Current Score", "Max Score", "Percentage", "Weight"},
+		{"", "Tenant", "30.00", "40.00", "75.0", "40"},
+		{sub, "Sub One", "30.00", "40.00", "75.0", "40"},
+	}
+	if !reflect.DeepEqual(scores, want) {
+		t.Errorf("SecureScoreTable() = %v, want %v", scores, want)
+	}
+
+	controls := rd.SecureScoreControlsTable()
+	if len(controls) != 3 {
+		t.Fatalf("control rows = %d, want headers, tenant and subscription", len(controls))
+	}
+	if want := []string{sub, "Sub One", "Enable MFA", "5.00", "10.00", "50.0", "1", "1", "0"}; !reflect.DeepEqual(controls[2], want) {
+		t.Errorf("subscription control row = %v, want %v", controls[2], want)
+	}
+}
+
+func TestReportDataPolicyRollUpTables(t *testing.T) {
+	const sub1, sub2 = "00000000-0000-0000-0000-000000000001", "00000000-0000-0000-0000-000000000002"
+	const mcsb = "/providers/microsoft.authorization/policysetdefinitions/mcsb"
+	stages := models.NewStageConfigs()
+	_ = stages.EnableStage(models.StageNamePolicy)
+	_ = stages.SetStageOptions(models.StageNamePolicy, map[string]any{"top-policies": 1})
+
+	rd := NewReportData("test", false, stages)
+	rd.AzurePolicyCompliance = []*models.AzurePolicyComplianceResult{
+		{SubscriptionID: sub1, SubscriptionName: "Sub One", CompliantResources: 3, NonCompliantResources: 1},
+		{SubscriptionID: sub1, SubscriptionName: "Sub One", PolicyAssignmentID: "a1", PolicyAssignmentName: "ASC Default", InitiativeID: mcsb, InitiativeName: "MCSB", CompliantResources: 3, NonCompliantResources: 1},
+		{SubscriptionID: sub2, SubscriptionName: "Sub Two", CompliantResources: 0, NonCompliantResources: 0},
+		{SubscriptionID: sub2, SubscriptionName: "Sub Two", PolicyAssignmentID: "a2", PolicyAssignmentName: "ASC Default", InitiativeID: mcsb, InitiativeName: "MCSB", CompliantResources: 5, NonCompliantResources: 1},
+		{SubscriptionID: sub2, SubscriptionName: "Sub Two", PolicyAssignmentID: "a3", PolicyAssignmentName: "Tags"},
+	}
+	rd.AzurePolicy = []*models.AzurePolicyResult{
+		{SubscriptionID: sub1, PolicyDefinitionID: "p1", PolicyDisplayName: "Require tags", ResourceID: "r1"},
+		{SubscriptionID: sub2, PolicyDefinitionID: "P1", PolicyDisplayName: "Require tags", ResourceID: "r2"},
+		{SubscriptionID: sub2, PolicyDefinitionID: "p2", PolicyDisplayName: "Disable public access", ResourceID: "r2"},
+	}
+
+	wantSubs := [][]string{
+		{"Subscription Id", "Subscription Name", "Compliant Resources", "Non-Compliant Resources", "Compliance (%)"},
+		{sub1, "Sub One", "3", "1", "75.0"},
+		{sub2, "Sub Two", "0", "0", ""},
+	}
+	if got := rd.AzurePolicySubscriptionsTable(); !reflect.DeepEqual(got, wantSubs) {
+		t.Errorf("AzurePolicySubscriptionsTable() = %v, want %v", got, wantSubs)
+	}
+
+	if got := len(rd.AzurePolicyAssignmentsTable()); got != 4 {
+		t.Errorf("assignment rows = %d, want headers and 3 assignments", got)
+	}
+
+	initiatives := rd.AzurePolicyInitiativesTable()
+	if want := []string{"MCSB", "2", "2", "8", "2", "80.0", mcsb}; len(initiatives) != 2 || !reflect.DeepEqual(initiatives[1], want) {
+		t.Errorf("AzurePolicyInitiativesTable() = %v, want a row %v", initiatives, want)
+	}
+
+	top := rd.AzurePolicyTopTable()
+	if want := []string{"Require tags", "2", "2", "p1"}; len(top) != 2 || !reflect.DeepEqual(top[1], want) {
+		t.Errorf("AzurePolicyTopTable() = %v, want a row %v", top, want)
+	}
+}
+
+func TestReportDataArcServersTable(t *testing.T) {
+	const sub1 = "00000000-0000-0000-0000-000000000001"
+	rd := NewReportData("test", true, models.NewStageConfigs())
+	rd.ArcServers = []*models.ArcServerResult{
+		{SubscriptionID: sub1, SubscriptionName: "Sub One", ResourceGroup: "rg1", Name: "db01", Location: "westeurope",
+			Status: "Disconnected", LastStatusChange: "2026-02-19T12:00:00Z", DisconnectedDays: 10,
+			AgentVersion: "1.40.02669.1", AgentStatus: "Outdated", OSType: "windows", OSName: "Windows Server 2012 R2 Standard",
+			OSVersion: "6.3.9600", OSEndOfLife: "2023-10-10", OSStatus: "End of Life", UpdateManager: true,
+			ESUEligibility: "Eligible", ESULicenseStatus: "Assigned", Extensions: []string{"MDE.Windows", "WindowsOsUpdateExtension"}},
+	}
+
+	got := rd.ArcServersTable()
+	if len(got) != 2 || len(got[0]) != len(got[1]) {
+		t.Fatalf("ArcServersTable() = %v, want headers and 1 row of the same width", got)
+	}
+	want := []string{"xxxxxxxx-xxxx-xxxx-xxxx-xxxxx0000001", "Sub One", "rg1", "db01", "westeurope",
+		"Disconnected", "2026-02-19T12:00:00Z", "10", "1.40.02669.1", "Outdated", "windows", "Windows Server 2012 R2 Standard",
+		"6.3.9600", "2023-10-10", "End of Life", "false", "false", "true", "Eligible", "Assigned", "MDE.Windows, WindowsOsUpdateExtension"}
+	if !reflect.DeepEqual(got[1], want) {
+		t.Errorf("ArcServersTable() row = %v, want %v", got[1], want)
+	}
+}
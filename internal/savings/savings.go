@@ -0,0 +1,300 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+// Package savings attributes monthly cost to impacted resources and estimates
+// the savings of fixing cost-related findings.
+package savings
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/azqr/internal/models"
+	"github.com/Azure/azqr/internal/scanners/plugins/region/cost"
+	"github.com/Azure/azqr/internal/scanners/plugins/region/types"
+	"github.com/rs/zerolog/log"
+)
+
+// Sources of the monthly cost of an impacted resource
+const (
+	SourceCostManagement = "Cost Management"
+	SourceRetailPrice    = "Retail Price"
+)
+
+// retailCurrency is the currency of retail price estimates
+const retailCurrency = "USD"
+
+// RetailPrices returns the retail price items matching the filters, given as
+// Retail Prices API field names and values.
+type RetailPrices func(ctx context.Context, filters map[string]string) ([]types.RetailPriceItem, error)
+
+// retailQuery describes the retail price of a resource: the filters of the
+// Retail Prices API query and, optionally, the items to keep.
+type retailQuery struct {
+	filters map[string]string
+	match   func(types.RetailPriceItem) bool
+}
+
+// Estimate sets the monthly cost of the impacted resources found in costs, and
+// the estimated savings of the findings whose recommendation declares a
+// savings percentage. Resources of such findings without cost data are priced
+// from retail prices when prices is not nil and their type is supported.
+func Estimate(ctx context.Context, results []*models.GraphResult, recommendations map[string]map[string]*models.GraphRecommendation, resources []*models.Resource, costs []*models.ResourceCost, prices RetailPrices) {
+	costByID := make(map[string]*models.ResourceCost, len(costs))
+	for _, c := range costs {
+		costByID[strings.ToLower(c.ResourceID)] = c
+	}
+	resourceByID := make(map[string]*models.Resource, len(resources))
+	for _, r := range resources {
+		resourceByID[strings.ToLower(r.ID)] = r
+	}
+
+	// Retail prices by query, shared by the resources with the same SKU and region
+	cache := map[string]*float64{}
+	retailPrice := func(q *retailQuery) (float64, bool) {
+		key := queryKey(q.filters)
+		if price, ok := cache[key]; ok {
+			return derefPrice(price)
+		}
+		items, err := prices(ctx, q.filters)
+		if err != nil {
+			log.Debug().Err(err).Str("query", key).Msg("Failed to fetch retail prices")
+		}
+		price, found := lowestMonthlyPrice(items, q.match)
+		if found {
+			cache[key] = &price
+		} else {
+			cache[key] = nil
+		}
+		return price, found
+	}
+
+	estimated := 0
+	for _, r := range results {
+		id := strings.ToLower(r.ResourceID)
+		pct := savingsPct(recommendations, r)
+
+		if c, ok := costByID[id]; ok {
+			r.MonthlyCost, r.CostCurrency, r.CostSource = c.Cost, c.Currency, SourceCostManagement
+		} else if pct > 0 {
+			price, known, q := retailEstimate(r, resourceByID[id])
+			if !known && q != nil && prices != nil {
+				price, known = retailPrice(q)
+			}
+			if known {
+				r.MonthlyCost, r.CostCurrency, r.CostSource = price, retailCurrency, SourceRetailPrice
+			}
+		}
+
+		if pct > 0 && r.CostSource != "" {
+			r.EstimatedSavings = r.MonthlyCost * pct / 100
+			estimated++
+		}
+	}
+
+	log.Debug().
+		Int("costs", len(costs)).
+		Int("savings_estimates", estimated).
+		Msg("Savings estimation completed")
+}
+
+// savingsPct returns the savings percentage of the recommendation of a finding.
+func savingsPct(recommendations map[string]map[string]*models.GraphRecommendation, r *models.GraphResult) float64 {
+	if rec, ok := recommendations[strings.ToLower(r.ResourceType)][r.RecommendationID]; ok && rec != nil {
+		return rec.EstimatedSavingsPct
+	}
+	return 0
+}
+
+// retailEstimate returns the monthly price of an impacted resource when it is
+// known without a query (e.g., free resources), or the retail price query of
+// the resource. It returns neither for unsupported resource types.
+func retailEstimate(r *models.GraphResult, resource *models.Resource) (float64, bool, *retailQuery) {
+	resourceType := strings.ToLower(r.ResourceType)
+	if resourceType == "microsoft.network/networkinterfaces" {
+		// Network interfaces are free
+		return 0, true, nil
+	}
+	if resource == nil || resource.Location == "" {
+		return 0, false, nil
+	}
+	region := strings.ToLower(strings.ReplaceAll(resource.Location, " ", ""))
+	sku := param(r, "Sku")
+	if sku == "" {
+		sku = resource.SkuName
+	}
+
+	switch resourceType {
+	case "microsoft.compute/disks":
+		meter, product, ok := diskMeter(sku, param(r, "diskSizeGB"))
+		if !ok {
+			return 0, false, nil
+		}
+		return 0, false, &retailQuery{filters: map[string]string{
+			"serviceName":   "Storage",
+			"productName":   product,
+			"meterName":     meter,
+			"armRegionName": region,
+		}}
+
+	case "microsoft.network/publicipaddresses":
+		if sku == "" {
+			sku = "Basic"
+		}
+		if strings.EqualFold(sku, "Basic") && strings.EqualFold(param(r, "AllocationMethod"), "Dynamic") {
+			// Dynamic Basic public IPs are not billed while unassociated
+			return 0, true, nil
+		}
+		return 0, false, &retailQuery{filters: map[string]string{
+			"serviceName":   "Virtual Network",
+			"productName":   "IP Addresses",
+			"meterName":     fmt.Sprintf("%s IPv4 Static Public IP", titleCase(sku)),
+			"armRegionName": region,
+		}}
+
+	case "microsoft.web/serverfarms":
+		if strings.HasPrefix(strings.ToUpper(sku), "F") {
+			// Free plans
+			return 0, true, nil
+		}
+		linux := strings.Contains(strings.ToLower(resource.Kind), "linux")
+		return 0, false, &retailQuery{
+			filters: map[string]string{
+				"serviceName":   "Azure App Service",
+				"skuName":       appServiceSkuName(sku),
+				"armRegionName": region,
+			},
+			match: func(item types.RetailPriceItem) bool {
+				return strings.Contains(item.ProductName, "Linux") == linux
+			},
+		}
+
+	case "microsoft.network/natgateways":
+		return 0, false, &retailQuery{filters: map[string]string{
+			"serviceName":   "NAT Gateway",
+			"meterName":     "Standard Gateway",
+			"armRegionName": region,
+		}}
+
+	case "microsoft.network/privateendpoints":
+		return 0, false, &retailQuery{filters: map[string]string{
+			"serviceName":   "Virtual Network",
+			"meterName":     "Standard Private Endpoint",
+			"armRegionName": region,
+		}}
+	}
+	return 0, false, nil
+}
+
+// Managed disk tiers: the largest size (GiB) of each tier number
+var (
+	diskTierSizes   = []int{4, 8, 16, 32, 64, 128, 256, 512, 1024, 2048, 4096, 8192, 16384, 32767}
+	diskTierNumbers = []int{1, 2, 3, 4, 6, 10, 15, 20, 30, 40, 50, 60, 70, 80}
+)
+
+// diskMeter returns the retail meter and product names of a managed disk SKU
+// (e.g., Premium_LRS) and size, e.g. "P10 LRS Disk" for a 128 GiB disk.
+func diskMeter(sku, sizeGB string) (string, string, bool) {
+	size, err := strconv.Atoi(strings.TrimSpace(sizeGB))
+	if err != nil || size <= 0 {
+		return "", "", false
+	}
+	kind, redundancy, ok := strings.Cut(sku, "_")
+	if !ok {
+		return "", "", false
+	}
+
+	var prefix, product string
+	minTier := 1
+	switch strings.ToLower(kind) {
+	case "premium":
+		prefix, product = "P", "Premium SSD Managed Disks"
+	case "standardssd":
+		prefix, product = "E", "Standard SSD Managed Disks"
+	case "standard":
+		// Standard HDD tiers start at S4
+		prefix, product, minTier = "S", "Standard HDD Managed Disks", 4
+	default:
+		// Premium SSD v2 and Ultra disks are billed by provisioned capacity and performance
+		return "", "", false
+	}
+
+	for i, limit := range diskTierSizes {
+		if size <= limit {
+			tier := max(diskTierNumbers[i], minTier)
+			return fmt.Sprintf("%s%d %s Disk", prefix, tier, strings.ToUpper(redundancy)), product, true
+		}
+	}
+	return "", "", false
+}
+
+// appServiceSkuName converts an App Service plan SKU (e.g., P1v3) to the retail
+// SKU name (P1 v3).
+func appServiceSkuName(sku string) string {
+	if i := strings.LastIndex(strings.ToLower(sku), "v"); i > 0 && i < len(sku)-1 {
+		if _, err := strconv.Atoi(sku[i+1:]); err == nil {
+			return sku[:i] + " " + strings.ToLower(sku[i:])
+		}
+	}
+	return sku
+}
+
+// lowestMonthlyPrice returns the lowest monthly price of the matching items,
+// using the price of the first tier of tiered meters.
+func lowestMonthlyPrice(items []types.RetailPriceItem, match func(types.RetailPriceItem) bool) (float64, bool) {
+	var lowest float64
+	found := false
+	for _, item := range items {
+		if item.TierMinimumUnits > 0 || (match != nil && !match(item)) {
+			continue
+		}
+		price, ok := cost.MonthlyRetailPrice(item)
+		if !ok || (found && price >= lowest) {
+			continue
+		}
+		lowest, found = price, true
+	}
+	return lowest, found
+}
+
+// param returns the value of a "Name: value" parameter of a finding.
+func param(r *models.GraphResult, name string) string {
+	for _, p := range []string{r.Param1, r.Param2, r.Param3, r.Param4, r.Param5} {
+		if key, value, ok := strings.Cut(p, ":"); ok && strings.EqualFold(strings.TrimSpace(key), name) {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// titleCase upper-cases the first letter of a SKU name (e.g., standard).
+func titleCase(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + strings.ToLower(s[1:])
+}
+
+// queryKey returns a stable key for retail price filters.
+func queryKey(filters map[string]string) string {
+	keys := make([]string, 0, len(filters))
+	for k := range filters {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + "=" + strings.ToLower(filters[k])
+	}
+	return strings.Join(parts, "&")
+}
+
+func derefPrice(price *float64) (float64, bool) {
+	if price == nil {
+		return 0, false
+	}
+	return *price, true
+}
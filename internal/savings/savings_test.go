@@ -0,0 +1,156 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package savings
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Azure/azqr/internal/models"
+	"github.com/Azure/azqr/internal/scanners/plugins/region/types"
+)
+
+func TestEstimate(t *testing.T) {
+	const (
+		diskID   = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/disks/d1"
+		ipID     = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/ip"
+		ip2ID    = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/ip2"
+		nicID    = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/networkInterfaces/nic"
+		planID   = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Web/serverFarms/plan"
+		vmID     = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/virtualMachines/vm"
+		unpriced = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/loadBalancers/lb"
+	)
+
+	recommendations := map[string]map[string]*models.GraphRecommendation{
+		"microsoft.compute/disks":             {"disk-rec": {EstimatedSavingsPct: 100}},
+		"microsoft.network/publicipaddresses": {"ip-rec": {EstimatedSavingsPct: 100}},
+		"microsoft.network/networkinterfaces": {"nic-rec": {EstimatedSavingsPct: 100}},
+		"microsoft.web/serverfarms":           {"plan-rec": {EstimatedSavingsPct: 50}},
+		"microsoft.compute/virtualmachines":   {"vm-rec": {}},
+		"microsoft.network/loadbalancers":     {"lb-rec": {EstimatedSavingsPct: 100}},
+	}
+	resources := []*models.Resource{
+		{ID: ipID, Location: "West Europe", SkuName: "Standard"},
+		{ID: ip2ID, Location: "westeurope", SkuName: "Standard"},
+		{ID: planID, Location: "westeurope", SkuName: "P1v3", Kind: "linux"},
+		{ID: unpriced, Location: "westeurope"},
+	}
+	costs := []*models.ResourceCost{
+		{ResourceID: "/subscriptions/sub/resourcegroups/rg/providers/microsoft.compute/disks/d1", Cost: 20, Currency: "EUR"},
+		{ResourceID: "/subscriptions/sub/resourcegroups/rg/providers/microsoft.compute/virtualmachines/vm", Cost: 100, Currency: "EUR"},
+	}
+	results := []*models.GraphResult{
+		{ResourceID: diskID, ResourceType: "Microsoft.Compute/disks", RecommendationID: "disk-rec"},
+		{ResourceID: ipID, ResourceType: "Microsoft.Network/publicIPAddresses", RecommendationID: "ip-rec", Param1: "Sku: Standard", Param2: "AllocationMethod: Static"},
+		{ResourceID: ip2ID, ResourceType: "Microsoft.Network/publicIPAddresses", RecommendationID: "ip-rec", Param1: "Sku: Standard"},
+		{ResourceID: nicID, ResourceType: "Microsoft.Network/networkInterfaces", RecommendationID: "nic-rec"},
+		{ResourceID: planID, ResourceType: "Microsoft.Web/serverFarms", RecommendationID: "plan-rec"},
+		{ResourceID: vmID, ResourceType: "Microsoft.Compute/virtualMachines", RecommendationID: "vm-rec"},
+		{ResourceID: unpriced, ResourceType: "Microsoft.Network/loadBalancers", RecommendationID: "lb-rec"},
+	}
+
+	var queries []map[string]string
+	prices := func(_ context.Context, filters map[string]string) ([]types.RetailPriceItem, error) {
+		queries = append(queries, filters)
+		switch filters["serviceName"] {
+		case "Virtual Network":
+			return []types.RetailPriceItem{{RetailPrice: 0.005, UnitOfMeasure: "1 Hour"}}, nil
+		case "Azure App Service":
+			return []types.RetailPriceItem{
+				{ProductName: "Azure App Service Premium v3 Plan", RetailPrice: 0.2, UnitOfMeasure: "1 Hour"},
+				{ProductName: "Azure App Service Premium v3 Plan - Linux", RetailPrice: 0.1, UnitOfMeasure: "1 Hour"},
+			}, nil
+		}
+		return nil, errors.New("unexpected query")
+	}
+
+	Estimate(context.Background(), results, recommendations, resources, costs, prices)
+
+	tests := []struct {
+		name             string
+		result           *models.GraphResult
+		cost, savings    float64
+		currency, source string
+	}{
+		{"cost management", results[0], 20, 20, "EUR", SourceCostManagement},
+		{"retail price", results[1], 3.65, 3.65, "USD", SourceRetailPrice},
+		{"cached retail price", results[2], 3.65, 3.65, "USD", SourceRetailPrice},
+		{"free resource", results[3], 0, 0, "USD", SourceRetailPrice},
+		{"partial savings", results[4], 73, 36.5, "USD", SourceRetailPrice},
+		{"cost without savings", results[5], 100, 0, "EUR", SourceCostManagement},
+		{"no price", results[6], 0, 0, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := tt.result
+			if !near(r.MonthlyCost, tt.cost) || !near(r.EstimatedSavings, tt.savings) || r.CostCurrency != tt.currency || r.CostSource != tt.source {
+				t.Errorf("result = cost %v, savings %v, %q, %q, want %v, %v, %q, %q",
+					r.MonthlyCost, r.EstimatedSavings, r.CostCurrency, r.CostSource, tt.cost, tt.savings, tt.currency, tt.source)
+			}
+		})
+	}
+
+	// Both public IPs share one query; the plan query uses the retail SKU name
+	if len(queries) != 2 {
+		t.Fatalf("got %d retail price queries, want 2: %v", len(queries), queries)
+	}
+	if queries[0]["meterName"] != "Standard IPv4 Static Public IP" || queries[0]["armRegionName"] != "westeurope" {
+		t.Errorf("public IP query = %v", queries[0])
+	}
+	if queries[1]["skuName"] != "P1 v3" {
+		t.Errorf("plan query = %v", queries[1])
+	}
+}
+
+func TestEstimate_NoRetailPrices(t *testing.T) {
+	results := []*models.GraphResult{
+		{ResourceID: "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/natGateways/nat", ResourceType: "Microsoft.Network/natGateways", RecommendationID: "nat-rec"},
+	}
+	recommendations := map[string]map[string]*models.GraphRecommendation{
+		"microsoft.network/natgateways": {"nat-rec": {EstimatedSavingsPct: 100}},
+	}
+	resources := []*models.Resource{{ID: results[0].ResourceID, Location: "westeurope"}}
+
+	Estimate(context.Background(), results, recommendations, resources, nil, nil)
+	if results[0].CostSource != "" || results[0].EstimatedSavings != 0 {
+		t.Errorf("result = %+v, want no cost", results[0])
+	}
+}
+
+func TestDiskMeter(t *testing.T) {
+	tests := []struct {
+		sku, size      string
+		meter, product string
+		ok             bool
+	}{
+		{"Premium_LRS", "128", "P10 LRS Disk", "Premium SSD Managed Disks", true},
+		{"Premium_ZRS", "100", "P10 ZRS Disk", "Premium SSD Managed Disks", true},
+		{"StandardSSD_LRS", "4", "E1 LRS Disk", "Standard SSD Managed Disks", true},
+		{"Standard_LRS", "8", "S4 LRS Disk", "Standard HDD Managed Disks", true},
+		{"Standard_LRS", "1024", "S30 LRS Disk", "Standard HDD Managed Disks", true},
+		{"PremiumV2_LRS", "128", "", "", false},
+		{"Premium_LRS", "", "", "", false},
+		{"Premium_LRS", "65536", "", "", false},
+	}
+	for _, tt := range tests {
+		meter, product, ok := diskMeter(tt.sku, tt.size)
+		if meter != tt.meter || product != tt.product || ok != tt.ok {
+			t.Errorf("diskMeter(%q, %q) = %q, %q, %v, want %q, %q, %v", tt.sku, tt.size, meter, product, ok, tt.meter, tt.product, tt.ok)
+		}
+	}
+}
+
+func TestAppServiceSkuName(t *testing.T) {
+	for sku, want := range map[string]string{"P1v3": "P1 v3", "P0v3": "P0 v3", "S1": "S1", "I2v2": "I2 v2", "EP1": "EP1"} {
+		if got := appServiceSkuName(sku); got != want {
+			t.Errorf("appServiceSkuName(%q) = %q, want %q", sku, got, want)
+		}
+	}
+}
+
+func near(a, b float64) bool {
+	d := a - b
+	return d < 1e-9 && d > -1e-9
+}
@@ -0,0 +1,431 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Azure/azqr/internal/graph"
+	"github.com/Azure/azqr/internal/models"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/rs/zerolog/log"
+)
+
+// Arc-enabled server agent statuses
+const (
+	ArcAgentLatest   = "Latest"
+	ArcAgentCurrent  = "Current"
+	ArcAgentOutdated = "Outdated"
+	ArcAgentUnknown  = "Unknown"
+)
+
+// ArcMinSupportedAgentVersion is the oldest Connected Machine agent version
+// released in the last year, the support window of the agent. Raise it with
+// the agent releases listed in
+// https://learn.microsoft.com/en-us/azure/azure-arc/servers/agent-release-notes
+const ArcMinSupportedAgentVersion = "1.58"
+
+// Operating system support statuses
+const (
+	OSSupported     = "Supported"
+	OSEndOfLifeSoon = "End of Life Soon"
+	OSEndOfLife     = "End of Life"
+	OSUnknown       = "Unknown"
+)
+
+// Arc-enabled server recommendation IDs
+const (
+	arcDisconnectedRecommendation  = "arc-001"
+	arcOutdatedAgentRecommendation = "arc-002"
+	arcMonitoringRecommendation    = "arc-003"
+)
+
+const arcMachineResourceType = "Microsoft.HybridCompute/machines"
+
+// Extension types of the Azure Monitor Agent, Defender for Endpoint and Update
+// Manager
+var (
+	arcMonitoringExtensions = []string{"AzureMonitorWindowsAgent", "AzureMonitorLinuxAgent"}
+	arcDefenderExtensions   = []string{"MDE.Windows", "MDE.Linux"}
+	arcUpdateExtensions     = []string{"WindowsOsUpdateExtension", "LinuxOsUpdateExtension", "WindowsPatchExtension", "LinuxPatchExtension"}
+)
+
+// osEndOfLife lists the end of support dates of the operating systems, matched
+// against the OS SKU reported by the Connected Machine agent. Windows Server
+// dates are the end of extended support and Linux dates the end of standard or
+// LTS support, without paid extensions.
+var osEndOfLife = []struct {
+	prefix string
+	date   string
+}{
+	{"windows server 2008", "2020-01-14"},
+	{"windows server 2012", "2023-10-10"},
+	{"windows server 2016", "2027-01-12"},
+	{"windows server 2019", "2029-01-09"},
+	{"windows server 2022", "2031-10-14"},
+	{"windows server 2025", "2034-10-10"},
+	{"ubuntu 16.04", "2021-04-30"},
+	{"ubuntu 18.04", "2023-05-31"},
+	{"ubuntu 20.04", "2025-05-31"},
+	{"ubuntu 22.04", "2027-06-30"},
+	{"ubuntu 24.04", "2029-06-30"},
+	{"red hat enterprise linux 7", "2024-06-30"},
+	{"red hat enterprise linux 8", "2029-05-31"},
+	{"red hat enterprise linux 9", "2032-05-31"},
+	{"centos linux 7", "2024-06-30"},
+	{"centos linux 8", "2021-12-31"},
+	{"centos stream 8", "2024-05-31"},
+	{"centos stream 9", "2027-05-31"},
+	{"suse linux enterprise server 12", "2024-10-31"},
+	{"suse linux enterprise server 15", "2031-07-31"},
+	{"debian gnu/linux 10", "2024-06-30"},
+	{"debian gnu/linux 11", "2026-08-31"},
+	{"debian gnu/linux 12", "2028-06-30"},
+}
+
+// ArcServerScanner scans Azure Arc-enabled servers: connection, agent, operating
+// system, extensions and Extended Security Updates
+type ArcServerScanner struct{}
+
+// Scan queries Azure Resource Graph for the Arc-enabled servers and their
+// extensions. Agents older than minAgentVersion, or more than
+// agentVersionsBehind minor versions behind the newest agent found, are
+// reported as outdated.
+func (s *ArcServerScanner) Scan(ctx context.Context, cred azcore.TokenCredential, subscriptions map[string]string, filters *models.Filters, minAgentVersion string, agentVersionsBehind int) []*models.ArcServerResult {
+	models.LogResourceTypeScan("Azure Arc-enabled Servers")
+
+	graphClient := graph.NewGraphQuery(cred)
+	query := `
+		resources
+		| where type == 'microsoft.hybridcompute/machines'
+		| extend machineId = tolower(id)
+		| join kind=leftouter (
+			resources
+			| where type == 'microsoft.hybridcompute/machines/extensions'
+			| extend machineId = tolower(tostring(split(id, '/extensions/')[0]))
+			| summarize extensions = make_set(tostring(properties.type)) by machineId
+		) on machineId
+		| project id, name, subscriptionId, resourceGroup, location,
+			status = tostring(properties.status),
+			lastStatusChange = tostring(properties.lastStatusChange),
+			agentVersion = tostring(properties.agentVersion),
+			osType = tostring(properties.osType),
+			osName = tostring(properties.osName),
+			osSku = tostring(properties.osSku),
+			osVersion = tostring(properties.osVersion),
+			esuEligibility = tostring(properties.licenseProfile.esuProfile.esuEligibility),
+			esuLicenseStatus = tostring(properties.licenseProfile.esuProfile.licenseAssignmentState),
+			extensions
+	`
+	log.Debug().Msg(query)
+
+	result, err := graphClient.Query(ctx, query, subscriptions)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to query Azure Resource Graph for Arc-enabled servers")
+		return nil
+	}
+	return buildArcServerResults(result.Data, subscriptions, filters, time.Now().UTC(), minAgentVersion, agentVersionsBehind)
+}
+
+// buildArcServerResults maps raw Arc-enabled server rows to ArcServerResult
+// records, applying subscription and service exclusion filters. Results are
+// sorted by subscription and server name.
+func buildArcServerResults(data []json.RawMessage, subscriptions map[string]string, filters *models.Filters, now time.Time, minAgentVersion string, agentVersionsBehind int) []*models.ArcServerResult {
+	type arcServerRow struct {
+		ID               string   `json:"id"`
+		Name             string   `json:"name"`
+		SubscriptionID   string   `json:"subscriptionId"`
+		ResourceGroup    string   `json:"resourceGroup"`
+		Location         string   `json:"location"`
+		Status           string   `json:"status"`
+		LastStatusChange string   `json:"lastStatusChange"`
+		AgentVersion     string   `json:"agentVersion"`
+		OSType           string   `json:"osType"`
+		OSName           string   `json:"osName"`
+		OSSku            string   `json:"osSku"`
+		OSVersion        string   `json:"osVersion"`
+		ESUEligibility   string   `json:"esuEligibility"`
+		ESULicenseStatus string   `json:"esuLicenseStatus"`
+		Extensions       []string `json:"extensions"`
+	}
+
+	results := []*models.ArcServerResult{}
+	for _, r := range graph.UnmarshalRows[arcServerRow](data, "Arc-enabled server") {
+		if filters.Azqr.IsSubscriptionExcluded(r.SubscriptionID) {
+			continue
+		}
+		if filters.Azqr.IsServiceExcluded(r.ID) {
+			continue
+		}
+
+		extensions := append([]string{}, r.Extensions...)
+		sort.Strings(extensions)
+
+		osName := r.OSSku
+		if osName == "" {
+			osName = r.OSName
+		}
+		eol, osStatus := osSupport(osName, now)
+
+		result := &models.ArcServerResult{
+			SubscriptionID:      r.SubscriptionID,
+			SubscriptionName:    subscriptions[r.SubscriptionID],
+			ResourceGroup:       r.ResourceGroup,
+			Name:                r.Name,
+			ResourceID:          r.ID,
+			Location:            r.Location,
+			Status:              r.Status,
+			AgentVersion:        r.AgentVersion,
+			OSType:              r.OSType,
+			OSName:              osName,
+			OSVersion:           r.OSVersion,
+			OSEndOfLife:         eol,
+			OSStatus:            osStatus,
+			Extensions:          extensions,
+			MonitoringAgent:     hasExtension(extensions, arcMonitoringExtensions),
+			DefenderForEndpoint: hasExtension(extensions, arcDefenderExtensions),
+			UpdateManager:       hasExtension(extensions, arcUpdateExtensions),
+			ESUEligibility:      r.ESUEligibility,
+			ESULicenseStatus:    r.ESULicenseStatus,
+		}
+		if r.LastStatusChange != "" {
+			changed, err := time.Parse(time.RFC3339, r.LastStatusChange)
+			if err != nil {
+				log.Debug().Err(err).Str("server", r.ID).Msg("Failed to parse Arc-enabled server status change")
+				result.LastStatusChange = r.LastStatusChange
+			} else {
+				result.LastStatusChange = changed.UTC().Format(time.RFC3339)
+				if !strings.EqualFold(r.Status, "Connected") && now.After(changed) {
+					result.DisconnectedDays = int(now.Sub(changed).Hours() / 24)
+				}
+			}
+		}
+		results = append(results, result)
+	}
+
+	setAgentStatus(results, minAgentVersion, agentVersionsBehind)
+
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.SubscriptionName != b.SubscriptionName {
+			return a.SubscriptionName < b.SubscriptionName
+		}
+		if a.SubscriptionID != b.SubscriptionID {
+			return a.SubscriptionID < b.SubscriptionID
+		}
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	})
+	return results
+}
+
+// Recommendations returns the embedded Arc-enabled servers recommendations
+// grouped by lowercase resource type.
+func (s *ArcServerScanner) Recommendations() map[string]map[string]models.GraphRecommendation {
+	recommendations, err := graph.ArcServerRecommendations()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load the Arc-enabled servers recommendations")
+		return map[string]map[string]models.GraphRecommendation{}
+	}
+	return recommendations
+}
+
+// Evaluate returns the findings of the Arc-enabled servers: servers
+// disconnected for at least disconnectedDays days, outdated agents and servers
+// without the Azure Monitor Agent. Excluded recommendations are skipped.
+func (s *ArcServerScanner) Evaluate(servers []*models.ArcServerResult, recommendations map[string]map[string]models.GraphRecommendation, filters *models.Filters, disconnectedDays int, minAgentVersion string) []*models.GraphResult {
+	recs := recommendations[strings.ToLower(arcMachineResourceType)]
+	newest := newestAgentVersion(servers)
+
+	results := []*models.GraphResult{}
+	add := func(server *models.ArcServerResult, id string, params ...string) {
+		rec, ok := recs[id]
+		if !ok || filters.Azqr.IsRecommendationExcluded(id) {
+			return
+		}
+		result := newArcServerResult(server, rec)
+		for i, p := range params {
+			switch i {
+			case 0:
+				result.Param1 = p
+			case 1:
+				result.Param2 = p
+			case 2:
+				result.Param3 = p
+			}
+		}
+		results = append(results, result)
+	}
+
+	for _, server := range servers {
+		if !strings.EqualFold(server.Status, "Connected") && server.LastStatusChange != "" && server.DisconnectedDays >= disconnectedDays {
+			add(server, arcDisconnectedRecommendation,
+				fmt.Sprintf("Status: %s", server.Status),
+				fmt.Sprintf("Last Status Change: %s", server.LastStatusChange),
+				fmt.Sprintf("Disconnected Days: %d", server.DisconnectedDays))
+		}
+		if server.AgentStatus == ArcAgentOutdated {
+			add(server, arcOutdatedAgentRecommendation,
+				fmt.Sprintf("Agent Version: %s", server.AgentVersion),
+				fmt.Sprintf("Minimum Supported Agent Version: %s", minAgentVersion),
+				fmt.Sprintf("Newest Agent Found: %s", newest))
+		}
+		if !server.MonitoringAgent {
+			add(server, arcMonitoringRecommendation,
+				fmt.Sprintf("OS Type: %s", server.OSType))
+		}
+	}
+	return results
+}
+
+// newArcServerResult creates the GraphResult of an Arc-enabled server finding.
+func newArcServerResult(server *models.ArcServerResult, rec models.GraphRecommendation) *models.GraphResult {
+	learnURL := ""
+	if len(rec.LearnMoreLink) > 0 {
+		learnURL = rec.LearnMoreLink[0].Url
+	}
+
+	return &models.GraphResult{
+		RecommendationID:    rec.RecommendationID,
+		ResourceType:        arcMachineResourceType,
+		Recommendation:      rec.Recommendation,
+		LongDescription:     rec.LongDescription,
+		PotentialBenefits:   rec.PotentialBenefits,
+		ResourceID:          server.ResourceID,
+		SubscriptionID:      server.SubscriptionID,
+		SubscriptionName:    server.SubscriptionName,
+		ResourceGroup:       server.ResourceGroup,
+		Name:                server.Name,
+		Category:            models.RecommendationCategory(rec.Category),
+		Impact:              models.RecommendationImpact(rec.Impact),
+		Learn:               learnURL,
+		AutomationAvailable: rec.AutomationAvailable,
+		Source:              rec.Source,
+	}
+}
+
+// setAgentStatus compares the agent version of each server to the minimum
+// supported agent version and to the newest agent version of the servers.
+// Agents older than minVersion are outdated, whatever the other servers run.
+// Agents more than versionsBehind minor versions behind the newest one, or of
+// an older major version, are outdated too. An invalid minVersion is ignored.
+func setAgentStatus(servers []*models.ArcServerResult, minVersion string, versionsBehind int) {
+	if _, _, ok := agentVersion(minVersion); !ok {
+		if minVersion != "" {
+			log.Warn().Msgf("Ignoring invalid minimum Connected Machine agent version: %s", minVersion)
+		}
+		minVersion = ""
+	}
+
+	latest := newestAgentVersion(servers)
+	latestMajor, latestMinor, _ := agentVersion(latest)
+	for _, server := range servers {
+		major, minor, ok := agentVersion(server.AgentVersion)
+		switch {
+		case !ok:
+			server.AgentStatus = ArcAgentUnknown
+		case minVersion != "" && compareVersions(server.AgentVersion, minVersion) < 0:
+			server.AgentStatus = ArcAgentOutdated
+		case server.AgentVersion == latest:
+			server.AgentStatus = ArcAgentLatest
+		case major < latestMajor || latestMinor-minor > versionsBehind:
+			server.AgentStatus = ArcAgentOutdated
+		default:
+			server.AgentStatus = ArcAgentCurrent
+		}
+	}
+}
+
+// newestAgentVersion returns the newest valid agent version of the servers.
+func newestAgentVersion(servers []*models.ArcServerResult) string {
+	latest := ""
+	for _, server := range servers {
+		if _, _, ok := agentVersion(server.AgentVersion); ok && (latest == "" || compareVersions(server.AgentVersion, latest) > 0) {
+			latest = server.AgentVersion
+		}
+	}
+	return latest
+}
+
+// agentVersion returns the major and minor numbers of an agent version such as
+// 1.45.02649.11.
+func agentVersion(version string) (int, int, bool) {
+	parts := strings.Split(version, ".")
+	if len(parts) < 2 {
+		return 0, 0, false
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, false
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, false
+	}
+	return major, minor, true
+}
+
+// compareVersions compares two dotted versions numerically, part by part.
+func compareVersions(a, b string) int {
+	pa, pb := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var na, nb int
+		if i < len(pa) {
+			na, _ = strconv.Atoi(pa[i])
+		}
+		if i < len(pb) {
+			nb, _ = strconv.Atoi(pb[i])
+		}
+		if na != nb {
+			if na < nb {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// osSupport returns the end of support date and the support status of an
+// operating system. Systems reaching their end of support within a year are
+// reported as end of life soon.
+func osSupport(osName string, now time.Time) (string, string) {
+	name := strings.ToLower(osName)
+	for _, os := range osEndOfLife {
+		i := strings.Index(name, os.prefix)
+		if i < 0 {
+			continue
+		}
+		// Do not match Ubuntu 20.04 against 20.041 or RHEL 8 against RHEL 80
+		if end := i + len(os.prefix); end < len(name) && name[end] >= '0' && name[end] <= '9' {
+			continue
+		}
+		eol, _ := time.Parse("2006-01-02", os.date)
+		switch {
+		case !eol.After(now):
+			return os.date, OSEndOfLife
+		case eol.Before(now.AddDate(1, 0, 0)):
+			return os.date, OSEndOfLifeSoon
+		}
+		return os.date, OSSupported
+	}
+	return "", OSUnknown
+}
+
+// hasExtension reports whether any of the extension types is installed.
+func hasExtension(installed, types []string) bool {
+	for _, e := range installed {
+		for _, t := range types {
+			if strings.EqualFold(e, t) {
+				return true
+			}
+		}
+	}
+	return false
+}
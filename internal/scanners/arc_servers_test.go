@@ -0,0 +1,175 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Azure/azqr/internal/models"
+)
+
+const (
+	arcServer1 = "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.HybridCompute/machines/web01"
+	arcServer2 = "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.HybridCompute/machines/db01"
+	arcServer3 = "/subscriptions/sub1/resourceGroups/rg2/providers/Microsoft.HybridCompute/machines/app01"
+)
+
+func arcServerRows() []string {
+	return []string{
+		`{"id":"` + arcServer1 + `","name":"web01","subscriptionId":"sub1","resourceGroup":"rg1","location":"westeurope","status":"Connected","lastStatusChange":"2026-02-28T10:00:00Z","agentVersion":"1.50.03010.1","osType":"windows","osName":"windows","osSku":"Windows Server 2022 Datacenter","osVersion":"10.0.20348","extensions":["MDE.Windows","AzureMonitorWindowsAgent","WindowsOsUpdateExtension"]}`,
+		`{"id":"` + arcServer2 + `","name":"db01","subscriptionId":"sub1","resourceGroup":"rg1","location":"westeurope","status":"Disconnected","lastStatusChange":"2026-02-19T12:00:00.0000000Z","agentVersion":"1.40.02669.1","osType":"windows","osName":"windows","osSku":"Windows Server 2012 R2 Standard","osVersion":"6.3.9600","esuEligibility":"Eligible","esuLicenseStatus":"Assigned"}`,
+		`{"id":"` + arcServer3 + `","name":"app01","subscriptionId":"sub1","resourceGroup":"rg2","location":"westeurope","status":"Connected","agentVersion":"1.48.02881.5","osType":"linux","osName":"linux","osSku":"Ubuntu 20.04.6 LTS","osVersion":"5.15.0","extensions":["AzureMonitorLinuxAgent"]}`,
+		`{"id":"/subscriptions/sub3/resourceGroups/rg1/providers/Microsoft.HybridCompute/machines/x","name":"x","subscriptionId":"sub3","status":"Connected"}`,
+		`{bad}`,
+	}
+}
+
+func TestBuildArcServerResults(t *testing.T) {
+	filters := filtersFromYAML(t, "azqr:\n  exclude:\n    subscriptions:\n      - sub3\n")
+	subscriptions := map[string]string{"sub1": "Sub One"}
+	now := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	got := buildArcServerResults(rawRows(arcServerRows()...), subscriptions, filters, now, "1.45", 6)
+	if len(got) != 3 {
+		t.Fatalf("expected 3 results (sub3 excluded), got %d", len(got))
+	}
+
+	// Sorted by name
+	app, db, web := got[0], got[1], got[2]
+	if app.Name != "app01" || db.Name != "db01" || web.Name != "web01" {
+		t.Fatalf("unexpected order: %s, %s, %s", app.Name, db.Name, web.Name)
+	}
+
+	if web.SubscriptionName != "Sub One" || web.Status != "Connected" || web.DisconnectedDays != 0 {
+		t.Errorf("web01 connection = %+v", web)
+	}
+	if !web.MonitoringAgent || !web.DefenderForEndpoint || !web.UpdateManager {
+		t.Errorf("web01 extensions = %+v", web)
+	}
+	if len(web.Extensions) != 3 || web.Extensions[0] != "AzureMonitorWindowsAgent" {
+		t.Errorf("web01 extensions not sorted: %v", web.Extensions)
+	}
+	if web.AgentStatus != ArcAgentLatest || web.OSEndOfLife != "2031-10-14" || web.OSStatus != OSSupported {
+		t.Errorf("web01 agent/OS = %+v", web)
+	}
+
+	if db.DisconnectedDays != 10 || db.LastStatusChange != "2026-02-19T12:00:00Z" {
+		t.Errorf("db01 disconnection = %+v", db)
+	}
+	if db.AgentStatus != ArcAgentOutdated || db.OSStatus != OSEndOfLife || db.OSEndOfLife != "2023-10-10" {
+		t.Errorf("db01 agent/OS = %+v", db)
+	}
+	if db.ESUEligibility != "Eligible" || db.ESULicenseStatus != "Assigned" || db.MonitoringAgent {
+		t.Errorf("db01 ESU/extensions = %+v", db)
+	}
+
+	if app.AgentStatus != ArcAgentCurrent || app.OSStatus != OSEndOfLife || !app.MonitoringAgent || app.DefenderForEndpoint {
+		t.Errorf("app01 = %+v", app)
+	}
+}
+
+func TestArcServerScannerEvaluate(t *testing.T) {
+	filters := includeAllFilters()
+	now := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	servers := buildArcServerResults(rawRows(arcServerRows()[:3]...), map[string]string{"sub1": "Sub One"}, filters, now, "1.45", 6)
+
+	scanner := &ArcServerScanner{}
+	recs := scanner.Recommendations()
+
+	got := scanner.Evaluate(servers, recs, filters, 7, "1.45")
+	ids := map[string][]string{}
+	for _, r := range got {
+		ids[r.Name] = append(ids[r.Name], r.RecommendationID)
+		if r.ResourceType != "Microsoft.HybridCompute/machines" || r.SubscriptionName != "Sub One" || r.Source != "AZQR" || r.Learn == "" {
+			t.Errorf("finding metadata = %+v", r)
+		}
+	}
+	if len(ids["web01"]) != 0 || len(ids["app01"]) != 0 {
+		t.Errorf("unexpected findings: %v", ids)
+	}
+	if want := []string{"arc-001", "arc-002", "arc-003"}; len(ids["db01"]) != 3 || ids["db01"][0] != want[0] || ids["db01"][1] != want[1] || ids["db01"][2] != want[2] {
+		t.Errorf("db01 findings = %v, want %v", ids["db01"], want)
+	}
+	if got[0].Param3 != "Disconnected Days: 10" || got[1].Param2 != "Minimum Supported Agent Version: 1.45" || got[1].Param3 != "Newest Agent Found: 1.50.03010.1" {
+		t.Errorf("finding params = %+v, %+v", got[0], got[1])
+	}
+
+	// Servers disconnected for less than the threshold are not reported
+	if got := scanner.Evaluate(servers, recs, filters, 30, "1.45"); len(got) != 2 {
+		t.Errorf("expected 2 findings with a 30 day threshold, got %d", len(got))
+	}
+
+	excluded := filtersFromYAML(t, "azqr:\n  exclude:\n    recommendations:\n      - arc-003\n")
+	for _, r := range scanner.Evaluate(servers, recs, excluded, 7, "1.45") {
+		if r.RecommendationID == "arc-003" {
+			t.Errorf("excluded recommendation reported: %+v", r)
+		}
+	}
+}
+
+func TestOSSupport(t *testing.T) {
+	now := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		os, eol, status string
+	}{
+		{"Windows Server 2016 Datacenter", "2027-01-12", OSEndOfLifeSoon},
+		{"Windows Server 2025 Standard", "2034-10-10", OSSupported},
+		{"Red Hat Enterprise Linux 9.4 (Plow)", "2032-05-31", OSSupported},
+		{"CentOS Linux 7 (Core)", "2024-06-30", OSEndOfLife},
+		{"Ubuntu 20.041", "", OSUnknown},
+		{"Windows 11 Enterprise", "", OSUnknown},
+		{"", "", OSUnknown},
+	}
+	for _, tt := range tests {
+		eol, status := osSupport(tt.os, now)
+		if eol != tt.eol || status != tt.status {
+			t.Errorf("osSupport(%q) = %q, %q; want %q, %q", tt.os, eol, status, tt.eol, tt.status)
+		}
+	}
+}
+
+func TestSetAgentStatus(t *testing.T) {
+	servers := []*models.ArcServerResult{
+		{AgentVersion: "1.9.1"},
+		{AgentVersion: "1.10.2"},
+		{AgentVersion: "1.10.10"},
+		{AgentVersion: "0.50.1"},
+		{AgentVersion: ""},
+	}
+	setAgentStatus(servers, "", 0)
+	want := []string{ArcAgentOutdated, ArcAgentCurrent, ArcAgentLatest, ArcAgentOutdated, ArcAgentUnknown}
+	for i, s := range servers {
+		if s.AgentStatus != want[i] {
+			t.Errorf("server %s status = %s, want %s", s.AgentVersion, s.AgentStatus, want[i])
+		}
+	}
+
+	// Agents older than the minimum supported version are outdated even when
+	// the whole estate runs them.
+	servers = []*models.ArcServerResult{
+		{AgentVersion: "1.40.02669.1"},
+		{AgentVersion: "1.41.02700.2"},
+		{AgentVersion: "1.58.03100.1"},
+	}
+	setAgentStatus(servers[:2], "1.58", 6)
+	for _, s := range servers[:2] {
+		if s.AgentStatus != ArcAgentOutdated {
+			t.Errorf("server %s status = %s, want %s", s.AgentVersion, s.AgentStatus, ArcAgentOutdated)
+		}
+	}
+	setAgentStatus(servers, "1.58", 100)
+	want = []string{ArcAgentOutdated, ArcAgentOutdated, ArcAgentLatest}
+	for i, s := range servers {
+		if s.AgentStatus != want[i] {
+			t.Errorf("server %s status = %s, want %s", s.AgentVersion, s.AgentStatus, want[i])
+		}
+	}
+
+	// An invalid minimum is ignored
+	setAgentStatus(servers[:2], "latest", 6)
+	if servers[0].AgentStatus != ArcAgentCurrent || servers[1].AgentStatus != ArcAgentLatest {
+		t.Errorf("statuses with an invalid minimum = %s, %s", servers[0].AgentStatus, servers[1].AgentStatus)
+	}
+}
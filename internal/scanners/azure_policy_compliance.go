@@ -0,0 +1,275 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Azure/azqr/internal/graph"
+	"github.com/Azure/azqr/internal/models"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/rs/zerolog/log"
+)
+
+// Azure Policy exemption statuses
+const (
+	ExemptionActive       = "Active"
+	ExemptionExpiringSoon = "Expiring Soon"
+	ExemptionExpired      = "Expired"
+)
+
+// policyStatesByResource counts the compliant and non-compliant resources per
+// subscription and the groupings given as format arguments: a resource is
+// non-compliant when any of its states is non-compliant, and compliant when all
+// of them are compliant or exempt.
+const policyStatesByResource = `
+		PolicyResources
+		| where type == 'microsoft.policyinsights/policystates'
+		| extend
+			subscriptionId = tolower(tostring(properties.subscriptionId)),
+			resourceId = tolower(tostring(properties.resourceId)),
+			policyAssignmentId = tolower(tostring(properties.policyAssignmentId)),
+			policySetDefinitionId = tolower(tostring(properties.policySetDefinitionId)),
+			complianceState = tostring(properties.complianceState)
+		| summarize
+			nonCompliant = countif(complianceState == 'NonCompliant'),
+			compliant = countif(complianceState in ('Compliant', 'Exempt'))
+			by subscriptionId, %s resourceId
+		| summarize
+			compliantResources = countif(nonCompliant == 0 and compliant > 0),
+			nonCompliantResources = countif(nonCompliant > 0)
+			by subscriptionId %s
+`
+
+// ScanCompliance returns the resource compliance of each subscription and of
+// each policy assignment in each subscription. The counts are computed by
+// Azure Resource Graph, so only the subscription exclusion filter applies.
+func (s *AzurePolicyScanner) ScanCompliance(ctx context.Context, cred azcore.TokenCredential, subscriptions map[string]string, filters *models.Filters) []*models.AzurePolicyComplianceResult {
+	models.LogResourceTypeScan("Azure Policy (Compliance)")
+
+	graphClient := graph.NewGraphQuery(cred)
+	bySubscription := fmt.Sprintf(policyStatesByResource, "", "")
+	byAssignment := fmt.Sprintf(policyStatesByResource, "policyAssignmentId, policySetDefinitionId,", ", policyAssignmentId, policySetDefinitionId") + `
+		| join kind=leftouter (
+			PolicyResources
+			| where type == 'microsoft.authorization/policyassignments'
+			| project policyAssignmentId = tolower(id), policyAssignmentName = tostring(properties.displayName)
+		) on policyAssignmentId
+		| join kind=leftouter (
+			PolicyResources
+			| where type == 'microsoft.authorization/policysetdefinitions'
+			| project policySetDefinitionId = tolower(id), initiativeName = tostring(properties.displayName)
+		) on policySetDefinitionId
+		| project subscriptionId, policyAssignmentId, policyAssignmentName, policySetDefinitionId, initiativeName, compliantResources, nonCompliantResources
+`
+
+	var data []json.RawMessage
+	for _, query := range []string{bySubscription, byAssignment} {
+		log.Debug().Msg(query)
+		result, err := graphClient.Query(ctx, query, subscriptions, graph.QueryOptions{ManagementGroupScope: true})
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to query Azure Resource Graph for Azure Policy compliance")
+			return nil
+		}
+		data = append(data, result.Data...)
+	}
+	return buildAzurePolicyCompliance(data, subscriptions, filters)
+}
+
+// buildAzurePolicyCompliance maps raw compliance summary rows to
+// AzurePolicyComplianceResult records, applying the subscription exclusion
+// filter. Results are sorted by subscription, with the subscription total
+// first, then by assignment.
+func buildAzurePolicyCompliance(data []json.RawMessage, subscriptions map[string]string, filters *models.Filters) []*models.AzurePolicyComplianceResult {
+	type complianceRow struct {
+		SubscriptionID        string `json:"subscriptionId"`
+		PolicyAssignmentID    string `json:"policyAssignmentId"`
+		PolicyAssignmentName  string `json:"policyAssignmentName"`
+		InitiativeID          string `json:"policySetDefinitionId"`
+		InitiativeName        string `json:"initiativeName"`
+		CompliantResources    int    `json:"compliantResources"`
+		NonCompliantResources int    `json:"nonCompliantResources"`
+	}
+
+	results := []*models.AzurePolicyComplianceResult{}
+	for _, r := range graph.UnmarshalRows[complianceRow](data, "Azure Policy compliance") {
+		if filters.Azqr.IsSubscriptionExcluded(r.SubscriptionID) {
+			continue
+		}
+		name := r.PolicyAssignmentName
+		if name == "" {
+			name = assignmentName(r.PolicyAssignmentID)
+		}
+		results = append(results, &models.AzurePolicyComplianceResult{
+			SubscriptionID:        r.SubscriptionID,
+			SubscriptionName:      subscriptions[r.SubscriptionID],
+			PolicyAssignmentID:    r.PolicyAssignmentID,
+			PolicyAssignmentName:  name,
+			InitiativeID:          r.InitiativeID,
+			InitiativeName:        r.InitiativeName,
+			CompliantResources:    r.CompliantResources,
+			NonCompliantResources: r.NonCompliantResources,
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.SubscriptionName != b.SubscriptionName {
+			return a.SubscriptionName < b.SubscriptionName
+		}
+		if a.SubscriptionID != b.SubscriptionID {
+			return a.SubscriptionID < b.SubscriptionID
+		}
+		if (a.PolicyAssignmentID == "") != (b.PolicyAssignmentID == "") {
+			return a.PolicyAssignmentID == ""
+		}
+		return a.PolicyAssignmentName < b.PolicyAssignmentName
+	})
+	return results
+}
+
+// ScanExemptions returns the policy exemptions of the subscriptions and of the
+// management groups above them. Exemptions expiring within expiryDays are
+// flagged as expiring soon.
+func (s *AzurePolicyScanner) ScanExemptions(ctx context.Context, cred azcore.TokenCredential, subscriptions map[string]string, filters *models.Filters, expiryDays int) []*models.AzurePolicyExemptionResult {
+	models.LogResourceTypeScan("Azure Policy (Exemptions)")
+
+	graphClient := graph.NewGraphQuery(cred)
+	query := `
+		PolicyResources
+		| where type == 'microsoft.authorization/policyexemptions'
+		| extend policyAssignmentId = tolower(tostring(properties.policyAssignmentId))
+		| join kind=leftouter (
+			PolicyResources
+			| where type == 'microsoft.authorization/policyassignments'
+			| project policyAssignmentId = tolower(id), policyAssignmentName = tostring(properties.displayName)
+		) on policyAssignmentId
+		| project id, name, subscriptionId,
+			displayName = tostring(properties.displayName),
+			category = tostring(properties.exemptionCategory),
+			expiresOn = tostring(properties.expiresOn),
+			description = tostring(properties.description),
+			policyAssignmentId, policyAssignmentName
+	`
+	log.Debug().Msg(query)
+
+	result, err := graphClient.Query(ctx, query, subscriptions, graph.QueryOptions{ManagementGroupScope: true})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to query Azure Resource Graph for Azure Policy exemptions")
+		return nil
+	}
+	return buildAzurePolicyExemptions(result.Data, subscriptions, filters, time.Now().UTC(), expiryDays)
+}
+
+// buildAzurePolicyExemptions maps raw exemption rows to AzurePolicyExemptionResult
+// records, applying the subscription exclusion filter to subscription-scoped
+// exemptions. Expired exemptions come first, then the ones expiring soonest.
+func buildAzurePolicyExemptions(data []json.RawMessage, subscriptions map[string]string, filters *models.Filters, now time.Time, expiryDays int) []*models.AzurePolicyExemptionResult {
+	type exemptionRow struct {
+		ID                   string `json:"id"`
+		Name                 string `json:"name"`
+		SubscriptionID       string `json:"subscriptionId"`
+		DisplayName          string `json:"displayName"`
+		Category             string `json:"category"`
+		ExpiresOn            string `json:"expiresOn"`
+		Description          string `json:"description"`
+		PolicyAssignmentID   string `json:"policyAssignmentId"`
+		PolicyAssignmentName string `json:"policyAssignmentName"`
+	}
+
+	type exemption struct {
+		result  *models.AzurePolicyExemptionResult
+		expires time.Time
+	}
+
+	exemptions := []exemption{}
+	seen := map[string]struct{}{}
+	for _, r := range graph.UnmarshalRows[exemptionRow](data, "Azure Policy exemption") {
+		if r.SubscriptionID != "" && filters.Azqr.IsSubscriptionExcluded(r.SubscriptionID) {
+			continue
+		}
+		// Management group exemptions are returned once per subscription batch
+		if _, ok := seen[strings.ToLower(r.ID)]; ok {
+			continue
+		}
+		seen[strings.ToLower(r.ID)] = struct{}{}
+
+		e := exemption{result: &models.AzurePolicyExemptionResult{
+			SubscriptionID:       r.SubscriptionID,
+			SubscriptionName:     subscriptions[r.SubscriptionID],
+			Name:                 r.Name,
+			DisplayName:          r.DisplayName,
+			Category:             r.Category,
+			Scope:                exemptionScope(r.ID),
+			PolicyAssignmentID:   r.PolicyAssignmentID,
+			PolicyAssignmentName: r.PolicyAssignmentName,
+			Description:          r.Description,
+			Status:               ExemptionActive,
+		}}
+		if e.result.PolicyAssignmentName == "" {
+			e.result.PolicyAssignmentName = assignmentName(r.PolicyAssignmentID)
+		}
+		if r.ExpiresOn != "" {
+			expires, err := time.Parse(time.RFC3339, r.ExpiresOn)
+			if err != nil {
+				log.Debug().Err(err).Str("exemption", r.ID).Msg("Failed to parse exemption expiry")
+				e.result.ExpiresOn = r.ExpiresOn
+			} else {
+				e.expires = expires.UTC()
+				e.result.ExpiresOn = e.expires.Format(time.RFC3339)
+				e.result.Status = exemptionStatus(e.expires, now, expiryDays)
+			}
+		}
+		exemptions = append(exemptions, e)
+	}
+
+	// Exemptions with an expiry first, by expiry, then the others by name
+	sort.SliceStable(exemptions, func(i, j int) bool {
+		a, b := exemptions[i], exemptions[j]
+		if a.expires.IsZero() != b.expires.IsZero() {
+			return !a.expires.IsZero()
+		}
+		if !a.expires.Equal(b.expires) {
+			return a.expires.Before(b.expires)
+		}
+		return a.result.DisplayName < b.result.DisplayName
+	})
+
+	results := make([]*models.AzurePolicyExemptionResult, len(exemptions))
+	for i, e := range exemptions {
+		results[i] = e.result
+	}
+	return results
+}
+
+// exemptionStatus returns the status of an exemption expiring at expires.
+func exemptionStatus(expires, now time.Time, expiryDays int) string {
+	switch {
+	case !expires.After(now):
+		return ExemptionExpired
+	case expires.Before(now.AddDate(0, 0, expiryDays)):
+		return ExemptionExpiringSoon
+	}
+	return ExemptionActive
+}
+
+// exemptionScope returns the scope of an exemption from its resource ID: a
+// management group, subscription, resource group or resource ID.
+func exemptionScope(id string) string {
+	if i := strings.Index(strings.ToLower(id), "/providers/microsoft.authorization/policyexemptions/"); i >= 0 {
+		return id[:i]
+	}
+	return id
+}
+
+// assignmentName returns the name of a policy assignment from its ID, used
+// when the assignment has no display name.
+func assignmentName(id string) string {
+	return id[strings.LastIndex(id, "/")+1:]
+}
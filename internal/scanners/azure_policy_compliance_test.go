@@ -0,0 +1,90 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBuildAzurePolicyCompliance(t *testing.T) {
+	filters := filtersFromYAML(t, "azqr:\n  exclude:\n    subscriptions:\n      - sub3\n")
+	subscriptions := map[string]string{"sub1": "Sub One", "sub2": "Sub Two"}
+
+	const mcsb = "/providers/microsoft.authorization/policysetdefinitions/1f3afdf9-d0c9-4c3d-847f-89da613e70a8"
+	data := rawRows(
+		`{"subscriptionId":"sub2","compliantResources":1,"nonCompliantResources":1}`,
+		`{"subscriptionId":"sub1","policyAssignmentId":"/subscriptions/sub1/providers/microsoft.authorization/policyassignments/tags","compliantResources":4,"nonCompliantResources":0}`,
+		`{"subscriptionId":"sub1","policyAssignmentId":"/subscriptions/sub1/providers/microsoft.authorization/policyassignments/asc","policyAssignmentName":"ASC Default","policySetDefinitionId":"`+mcsb+`","initiativeName":"Microsoft cloud security benchmark","compliantResources":6,"nonCompliantResources":4}`,
+		`{"subscriptionId":"sub1","compliantResources":6,"nonCompliantResources":4}`,
+		`{"subscriptionId":"sub3","compliantResources":1,"nonCompliantResources":0}`,
+		`{bad}`,
+	)
+
+	got := buildAzurePolicyCompliance(data, subscriptions, filters)
+	if len(got) != 4 {
+		t.Fatalf("expected 4 results (sub3 excluded), got %d", len(got))
+	}
+	// Sorted by subscription, with the subscription total first
+	if got[0].SubscriptionName != "Sub One" || got[0].PolicyAssignmentID != "" || got[0].CompliantResources != 6 {
+		t.Errorf("result[0] = %+v, want the Sub One total", got[0])
+	}
+	if got[1].PolicyAssignmentName != "ASC Default" || got[1].InitiativeID != mcsb || got[1].InitiativeName != "Microsoft cloud security benchmark" || got[1].NonCompliantResources != 4 {
+		t.Errorf("result[1] = %+v", got[1])
+	}
+	// Assignments without a display name use the assignment name
+	if got[2].PolicyAssignmentName != "tags" || got[2].InitiativeID != "" {
+		t.Errorf("result[2] = %+v", got[2])
+	}
+	if got[3].SubscriptionName != "Sub Two" {
+		t.Errorf("result[3] = %+v", got[3])
+	}
+}
+
+func TestBuildAzurePolicyExemptions(t *testing.T) {
+	filters := filtersFromYAML(t, "azqr:\n  exclude:\n    subscriptions:\n      - sub3\n")
+	subscriptions := map[string]string{"sub1": "Sub One"}
+	now := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	const (
+		mgExemption  = "/providers/Microsoft.Management/managementGroups/mg1/providers/Microsoft.Authorization/policyExemptions/mg-waiver"
+		rgExemption  = "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Authorization/policyExemptions/rg-waiver"
+		subExemption = "/subscriptions/sub1/providers/Microsoft.Authorization/policyExemptions/sub-waiver"
+	)
+	data := rawRows(
+		`{"id":"`+mgExemption+`","name":"mg-waiver","displayName":"MG waiver","category":"Waiver","policyAssignmentId":"/providers/microsoft.management/managementgroups/mg1/providers/microsoft.authorization/policyassignments/mcsb","policyAssignmentName":"MCSB"}`,
+		`{"id":"`+mgExemption+`","name":"mg-waiver","displayName":"MG waiver","category":"Waiver"}`,
+		`{"id":"`+rgExemption+`","name":"rg-waiver","subscriptionId":"sub1","displayName":"RG waiver","category":"Mitigated","expiresOn":"2026-03-20T00:00:00Z"}`,
+		`{"id":"`+subExemption+`","name":"sub-waiver","subscriptionId":"sub1","displayName":"Sub waiver","category":"Waiver","expiresOn":"2026-02-01T00:00:00.0000000Z","policyAssignmentId":"/subscriptions/sub1/providers/microsoft.authorization/policyassignments/tags"}`,
+		`{"id":"/subscriptions/sub1/providers/Microsoft.Authorization/policyExemptions/later","name":"later","subscriptionId":"sub1","displayName":"Later","expiresOn":"2027-01-01T00:00:00Z"}`,
+		`{"id":"/subscriptions/sub3/providers/Microsoft.Authorization/policyExemptions/x","name":"x","subscriptionId":"sub3"}`,
+		`{bad}`,
+	)
+
+	got := buildAzurePolicyExemptions(data, subscriptions, filters, now, 30)
+	if len(got) != 4 {
+		t.Fatalf("expected 4 exemptions (duplicate and sub3 skipped), got %d", len(got))
+	}
+
+	tests := []struct {
+		name, status, expiresOn, scope string
+	}{
+		{"sub-waiver", ExemptionExpired, "2026-02-01T00:00:00Z", "/subscriptions/sub1"},
+		{"rg-waiver", ExemptionExpiringSoon, "2026-03-20T00:00:00Z", "/subscriptions/sub1/resourceGroups/rg1"},
+		{"later", ExemptionActive, "2027-01-01T00:00:00Z", "/subscriptions/sub1"},
+		{"mg-waiver", ExemptionActive, "", "/providers/Microsoft.Management/managementGroups/mg1"},
+	}
+	for i, tt := range tests {
+		e := got[i]
+		if e.Name != tt.name || e.Status != tt.status || e.ExpiresOn != tt.expiresOn || e.Scope != tt.scope {
+			t.Errorf("exemption[%d] = %+v, want %s %s %q %s", i, e, tt.name, tt.status, tt.expiresOn, tt.scope)
+		}
+	}
+	if got[0].SubscriptionName != "Sub One" || got[0].PolicyAssignmentName != "tags" {
+		t.Errorf("exemption[0] = %+v", got[0])
+	}
+	if got[3].PolicyAssignmentName != "MCSB" || got[3].Category != "Waiver" {
+		t.Errorf("exemption[3] = %+v", got[3])
+	}
+}
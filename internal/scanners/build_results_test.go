@@ -13,7 +13,7 @@ import (
 )
 
 // fakeScanner registers the resource types used by these tests so that
-// LoadFilters marks them as included (IsServiceExcluded only includes
+// ParseFilters marks them as included (IsServiceExcluded only includes
 // resource types exposed by loaded scanners).
 type fakeScanner struct{}
 
@@ -22,6 +22,7 @@ func (fakeScanner) ResourceTypes() []string {
 	return []string{
 		"Microsoft.Storage/storageAccounts",
 		"Microsoft.AzureArcData/sqlServerInstances",
+		"Microsoft.HybridCompute/machines",
 	}
 }
 
@@ -35,7 +36,8 @@ func registerTestScanners() {
 // resource types and excludes nothing.
 func includeAllFilters() *models.Filters {
 	registerTestScanners()
-	return models.LoadFilters("", []string{})
+	filters, _ := models.ParseFilters("", []string{})
+	return filters
 }
 
 // filtersFromYAML writes a filter YAML to a temp file and loads it.
@@ -46,7 +48,11 @@ func filtersFromYAML(t *testing.T, yaml string) *models.Filters {
 	if err := os.WriteFile(path, []byte(yaml), 0o600); err != nil {
 		t.Fatalf("failed to write filter file: %v", err)
 	}
-	return models.LoadFilters(path, []string{})
+	filters, err := models.ParseFilters(path, []string{})
+	if err != nil {
+		t.Fatalf("failed to load filters: %v", err)
+	}
+	return filters
 }
 
 func rawRows(rows ...string) []json.RawMessage {
@@ -146,6 +152,74 @@ func TestBuildDefenderRecommendations(t *testing.T) {
 	}
 }
 
+func TestBuildSecureScores(t *testing.T) {
+	filters := filtersFromYAML(t, "azqr:\n  exclude:\n    subscriptions:\n      - sub3\n")
+	subscriptions := map[string]string{"sub1": "Sub One", "sub2": "Sub Two"}
+
+	data := rawRows(
+		`{"SubscriptionId":"sub2","Type":"microsoft.security/securescores","Name":"ascScore","Current":10,"Max":40,"Percentage":0.25,"Weight":40}`,
+		`{"SubscriptionId":"sub1","Type":"microsoft.security/securescores","Name":"ascScore","Current":30,"Max":40,"Percentage":0.75,"Weight":40}`,
+		`{"SubscriptionId":"sub1","Type":"microsoft.security/securescores/securescorecontrols","Name":"mfa","DisplayName":"Enable MFA","Current":10,"Max":10,"Percentage":1,"Healthy":3}`,
+		`{"SubscriptionId":"sub1","Type":"microsoft.security/securescores/securescorecontrols","Name":"encrypt","DisplayName":"Encrypt data in transit","Current":2,"Max":4,"Percentage":0.5,"Healthy":2,"Unhealthy":2,"NotApplicable":1}`,
+		`{"SubscriptionId":"sub3","Type":"microsoft.security/securescores","Name":"ascScore","Current":1,"Max":1,"Percentage":1}`,
+		`{bad}`,
+	)
+
+	got := buildSecureScores(data, subscriptions, filters)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 subscriptions (sub3 excluded), got %d", len(got))
+	}
+	s := got[0]
+	if s.SubscriptionID != "sub1" || s.SubscriptionName != "Sub One" || s.CurrentScore != 30 || s.MaxScore != 40 || s.Percentage != 75 || s.Weight != 40 {
+		t.Errorf("score mapping wrong: %+v", s)
+	}
+	if len(s.Controls) != 2 || s.Controls[0].DisplayName != "Enable MFA" {
+		t.Fatalf("controls should be sorted by display name: %+v", s.Controls)
+	}
+	c := s.Controls[1]
+	if c.Name != "encrypt" || c.Percentage != 50 || c.HealthyResources != 2 || c.UnhealthyResources != 2 || c.NotApplicableResources != 1 {
+		t.Errorf("control mapping wrong: %+v", c)
+	}
+	if got[1].SubscriptionID != "sub2" || len(got[1].Controls) != 0 {
+		t.Errorf("second subscription wrong: %+v", got[1])
+	}
+}
+
+func TestTenantSecureScore(t *testing.T) {
+	if TenantSecureScore(nil) != nil {
+		t.Error("expected no tenant score without subscription scores")
+	}
+
+	scores := []*models.SecureScoreResult{
+		{SubscriptionID: "sub1", CurrentScore: 30, MaxScore: 40, Percentage: 75, Weight: 30,
+			Controls: []*models.SecureScoreControl{
+				{Name: "mfa", DisplayName: "Enable MFA", CurrentScore: 10, MaxScore: 10, HealthyResources: 3},
+			}},
+		// Subscriptions without a weight count once
+		{SubscriptionID: "sub2", CurrentScore: 10, MaxScore: 40, Percentage: 25, Weight: 0,
+			Controls: []*models.SecureScoreControl{
+				{Name: "mfa", DisplayName: "Enable MFA", CurrentScore: 0, MaxScore: 10, UnhealthyResources: 2},
+				{Name: "encrypt", DisplayName: "Encrypt data in transit", CurrentScore: 2, MaxScore: 4},
+			}},
+	}
+
+	tenant := TenantSecureScore(scores)
+	if tenant.SubscriptionID != "" || tenant.SubscriptionName != TenantSecureScoreName {
+		t.Errorf("tenant identity wrong: %+v", tenant)
+	}
+	// (30 * 75 + 1 * 25) / 31
+	if tenant.CurrentScore != 40 || tenant.MaxScore != 80 || tenant.Weight != 31 || tenant.Percentage != 2275.0/31 {
+		t.Errorf("tenant score wrong: %+v", tenant)
+	}
+	if len(tenant.Controls) != 2 {
+		t.Fatalf("expected 2 tenant controls, got %d", len(tenant.Controls))
+	}
+	mfa := tenant.Controls[0]
+	if mfa.Name != "mfa" || mfa.CurrentScore != 10 || mfa.MaxScore != 20 || mfa.Percentage != 50 || mfa.HealthyResources != 3 || mfa.UnhealthyResources != 2 {
+		t.Errorf("tenant control wrong: %+v", mfa)
+	}
+}
+
 func TestBuildAzurePolicyResults(t *testing.T) {
 	filters := includeAllFilters()
 
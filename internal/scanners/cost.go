@@ -3,96 +3,231 @@
 package scanners
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/Azure/azqr/internal/az"
 	"github.com/Azure/azqr/internal/models"
-	"github.com/Azure/azqr/internal/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/costmanagement/armcostmanagement"
 	"github.com/rs/zerolog/log"
 )
 
 // CostScanner - Cost scanner
 type CostScanner struct {
-	config *models.ScannerConfig
-	client *armcostmanagement.QueryClient
+	// Query is the cost query to run; the last month by service when nil
+	Query *CostQuery
+	// ResourceCosts enables the query of the cost of each resource in the last calendar month
+	ResourceCosts bool
+	config        *models.ScannerConfig
+	client        *armcostmanagement.QueryClient
+	httpClient    *az.HttpClient
 }
 
 func (s *CostScanner) init(config *models.ScannerConfig) error {
 	s.config = config
+	if s.Query == nil {
+		s.Query = DefaultCostQuery(time.Now().UTC())
+	}
 	var err error
 	s.client, err = armcostmanagement.NewQueryClient(config.Cred, config.ClientOptions)
 	if err != nil {
 		return err
 	}
+	s.httpClient = az.NewHttpClient(config.Cred, nil)
 	return nil
 }
 
 // QueryCosts - Query Costs.
 func (s *CostScanner) QueryCosts() ([]*models.CostResult, error) {
 	models.LogSubscriptionScan(s.config.SubscriptionID, "Costs")
-	timeframeType := armcostmanagement.TimeframeTypeCustom
-	etype := armcostmanagement.ExportTypeActualCost
-	fromTime, toTime := costTimeRange(time.Now().UTC())
-	sum := armcostmanagement.FunctionTypeSum
-	dimension := armcostmanagement.QueryColumnTypeDimension
-	qd := armcostmanagement.QueryDefinition{
-		Type:      &etype,
-		Timeframe: &timeframeType,
-		TimePeriod: &armcostmanagement.QueryTimePeriod{
-			From: &fromTime,
-			To:   &toTime,
-		},
-		Dataset: &armcostmanagement.QueryDataset{
-			// Granularity: &daily,
-			Aggregation: map[string]*armcostmanagement.QueryAggregation{
-				"TotalCost": {
-					Name:     to.Ptr("Cost"),
-					Function: &sum,
-				},
-			},
-			Grouping: []*armcostmanagement.QueryGrouping{
-				{
-					Name: to.Ptr("ServiceName"),
-					Type: &dimension,
-				},
-			},
-		},
-	}
-
-	resp, err := s.client.Usage(s.config.Ctx, fmt.Sprintf("/subscriptions/%s", s.config.SubscriptionID), qd, nil)
+
+	properties, err := s.usage(s.Query.Scope, s.Query.definition())
 	if err != nil {
 		return nil, err
 	}
+	return s.Query.parseCostRows(properties.Columns, properties.Rows, s.config.SubscriptionID, s.config.SubscriptionName), nil
+}
 
-	result := []*models.CostResult{}
+// QueryResourceCosts returns the actual cost of each resource of the
+// subscription, or of the query scope, in the last calendar month.
+func (s *CostScanner) QueryResourceCosts() ([]*models.ResourceCost, error) {
+	q := DefaultCostQuery(time.Now().UTC())
+	q.GroupBy = []string{CostGroupResource}
+	q.Scope = s.Query.Scope
+
+	properties, err := s.usage(q.Scope, q.definition())
+	if err != nil {
+		return nil, err
+	}
+	return resourceCosts(q.parseCostRows(properties.Columns, properties.Rows, s.config.SubscriptionID, s.config.SubscriptionName)), nil
+}
+
+// usage runs a cost query at a scope, the subscription when empty, and follows
+// the next links of the response, returning the rows of all pages.
+func (s *CostScanner) usage(scope string, definition armcostmanagement.QueryDefinition) (*armcostmanagement.QueryProperties, error) {
+	if scope == "" {
+		scope = fmt.Sprintf("/subscriptions/%s", s.config.SubscriptionID)
+	}
+	resp, err := s.client.Usage(s.config.Ctx, scope, definition, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Properties == nil {
+		return &armcostmanagement.QueryProperties{}, nil
+	}
+	properties := resp.Properties
 
-	for _, v := range resp.Properties.Rows {
-		result = append(result, &models.CostResult{
-			From:             fromTime,
-			To:               toTime,
-			SubscriptionID:   s.config.SubscriptionID,
-			SubscriptionName: s.config.SubscriptionName,
-			ServiceName:      fmt.Sprintf("%v", v[1]),
-			Value:            fmt.Sprintf("%v", v[0]),
-			Currency:         fmt.Sprintf("%v", v[2]),
-		})
+	body, err := json.Marshal(definition)
+	if err != nil {
+		return nil, err
+	}
+	for next := properties.NextLink; next != nil && *next != ""; {
+		responseBody, _, err := s.httpClient.DoPost(s.config.Ctx, *next, az.NopReadSeekCloser{Reader: bytes.NewReader(body)})
+		if err != nil {
+			return nil, fmt.Errorf("failed to query the next page of costs: %w", err)
+		}
+		var page armcostmanagement.QueryResult
+		if err := json.Unmarshal(responseBody, &page); err != nil {
+			return nil, fmt.Errorf("failed to parse the next page of costs: %w", err)
+		}
+		if page.Properties == nil {
+			break
+		}
+		properties.Rows = append(properties.Rows, page.Properties.Rows...)
+		next = page.Properties.NextLink
 	}
-	return result, nil
+	return properties, nil
 }
 
-func (s *CostScanner) Scan(config *models.ScannerConfig) []*models.CostResult {
+// Scan queries the costs of the subscription and, when ResourceCosts is set,
+// the cost of each of its resources.
+func (s *CostScanner) Scan(config *models.ScannerConfig) ([]*models.CostResult, []*models.ResourceCost, error) {
 	costResult := []*models.CostResult{}
 	err := s.init(config)
 	if err != nil {
-		log.Fatal().Err(err).Msg("Failed to initialize Cost Scanner")
+		return nil, nil, fmt.Errorf("failed to initialize Cost Scanner: %w", err)
 	}
 	costs, err := s.QueryCosts()
 	if err != nil && !models.ShouldSkipError(err) {
-		log.Fatal().Err(err).Msg("Failed to query costs")
+		return nil, nil, fmt.Errorf("failed to query costs: %w", err)
 	}
 	costResult = append(costResult, costs...)
-	return costResult
+
+	if !s.ResourceCosts {
+		return costResult, nil, nil
+	}
+	resourceCosts, err := s.QueryResourceCosts()
+	if err != nil && !models.ShouldSkipError(err) {
+		return nil, nil, fmt.Errorf("failed to query resource costs: %w", err)
+	}
+	return costResult, resourceCosts, nil
+}
+
+// ScanScope queries the costs of all subscriptions at the billing or
+// management group scope of the query, and the cost of their resources when
+// ResourceCosts is set, splitting them per subscription. Costs of
+// subscriptions outside subscriptions are dropped; shared charges, which have
+// no subscription, are kept.
+func (s *CostScanner) ScanScope(config *models.ScannerConfig, subscriptions map[string]string) ([]*models.CostResult, []*models.ResourceCost, error) {
+	if s.Query == nil || s.Query.Scope == "" {
+		return nil, nil, fmt.Errorf("cost query has no billing or management group scope")
+	}
+	if err := s.init(config); err != nil {
+		return nil, nil, fmt.Errorf("failed to initialize Cost Scanner: %w", err)
+	}
+	log.Info().Str("scope", s.Query.Scope).Msg("Scanning costs")
+
+	costs, err := s.QueryCosts()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query costs at scope %s: %w", s.Query.Scope, err)
+	}
+	costs = splitCostsBySubscription(costs, subscriptions)
+
+	if !s.ResourceCosts {
+		return costs, nil, nil
+	}
+	rows, err := s.QueryResourceCosts()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query resource costs at scope %s: %w", s.Query.Scope, err)
+	}
+	names := subscriptionNames(subscriptions)
+	resourceCosts := make([]*models.ResourceCost, 0, len(rows))
+	for _, c := range rows {
+		if _, ok := names[strings.ToLower(c.SubscriptionID)]; ok {
+			resourceCosts = append(resourceCosts, c)
+		}
+	}
+	return costs, resourceCosts, nil
+}
+
+// splitCostsBySubscription keeps the cost rows of the scanned subscriptions,
+// setting their names, and the shared charges.
+func splitCostsBySubscription(rows []*models.CostResult, subscriptions map[string]string) []*models.CostResult {
+	names := subscriptionNames(subscriptions)
+	result := make([]*models.CostResult, 0, len(rows))
+	dropped := 0
+	for _, r := range rows {
+		if r.SubscriptionID == "" {
+			result = append(result, r)
+			continue
+		}
+		name, ok := names[strings.ToLower(r.SubscriptionID)]
+		if !ok {
+			dropped++
+			continue
+		}
+		r.SubscriptionName = name
+		result = append(result, r)
+	}
+	if dropped > 0 {
+		log.Debug().Int("rows", dropped).Msg("Skipping costs of subscriptions outside the scan scope")
+	}
+	return result
+}
+
+// subscriptionNames returns the subscription names by lowercase ID.
+func subscriptionNames(subscriptions map[string]string) map[string]string {
+	names := make(map[string]string, len(subscriptions))
+	for id, name := range subscriptions {
+		names[strings.ToLower(id)] = name
+	}
+	return names
+}
+
+// resourceCosts sums cost rows grouped by resource into one cost per
+// lowercase resource ID. Rows without a resource ID are skipped.
+func resourceCosts(rows []*models.CostResult) []*models.ResourceCost {
+	byID := map[string]*models.ResourceCost{}
+	result := []*models.ResourceCost{}
+	for _, r := range rows {
+		id := strings.ToLower(r.ResourceID)
+		if id == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(r.Value, 64)
+		if err != nil {
+			continue
+		}
+		c, ok := byID[id]
+		if !ok {
+			subscriptionID := r.SubscriptionID
+			if subscriptionID == "" {
+				// Resource IDs start with /subscriptions/<id>/
+				if parts := strings.Split(id, "/"); len(parts) > 2 && parts[1] == "subscriptions" {
+					subscriptionID = parts[2]
+				}
+			}
+			c = &models.ResourceCost{SubscriptionID: subscriptionID, ResourceID: id, Currency: r.Currency}
+			byID[id] = c
+			result = append(result, c)
+		}
+		c.Cost += value
+	}
+	return result
 }
 
 func costTimeRange(now time.Time) (time.Time, time.Time) {
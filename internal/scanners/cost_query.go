@@ -0,0 +1,359 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Azure/azqr/internal/models"
+	"github.com/Azure/azqr/internal/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/costmanagement/armcostmanagement"
+)
+
+// Cost granularities
+const (
+	CostGranularityNone    = "none"
+	CostGranularityDaily   = "daily"
+	CostGranularityMonthly = "monthly"
+)
+
+// Cost query grouping dimensions, as accepted by the cost.group-by stage option.
+// Tag groupings are written as tag:<key>.
+const (
+	CostGroupService       = "service"
+	CostGroupResourceGroup = "resource-group"
+	CostGroupResource      = "resource"
+	CostGroupMeterCategory = "meter-category"
+	costGroupTagPrefix     = "tag:"
+)
+
+// Cost query scopes, as accepted by the cost.scope stage option. Subscriptions
+// are queried one at a time; the other scopes query all subscriptions at once.
+const (
+	CostScopeSubscription    = "subscription"
+	CostScopeBillingAccount  = "billing-account"
+	CostScopeBillingProfile  = "billing-profile"
+	CostScopeEnrollment      = "enrollment"
+	CostScopeManagementGroup = "management-group"
+)
+
+// costSubscriptionDimension splits the costs of a billing or management group
+// scope per subscription
+const costSubscriptionDimension = "SubscriptionId"
+
+// maxCostGroupings is the number of groupings the Cost Management query API accepts.
+const maxCostGroupings = 2
+
+// costGroupDimensions maps the group-by values to Cost Management dimensions
+var costGroupDimensions = map[string]string{
+	CostGroupService:       "ServiceName",
+	CostGroupResourceGroup: "ResourceGroupName",
+	CostGroupResource:      "ResourceId",
+	CostGroupMeterCategory: "MeterCategory",
+}
+
+var relativeCostPeriodPattern = regexp.MustCompile(`^last-(\d+)-(days|months)$`)
+
+// CostQuery holds the time range, granularity, grouping and cost type of a cost query.
+type CostQuery struct {
+	From, To    time.Time
+	Granularity string   // CostGranularityNone, CostGranularityDaily or CostGranularityMonthly
+	GroupBy     []string // group-by values, e.g. "service" or "tag:env"
+	CostType    string   // ActualCost or AmortizedCost
+	// Scope is the billing or management group scope (ARM path) queried for all
+	// subscriptions at once; empty to query each subscription
+	Scope string
+}
+
+// DefaultCostQuery returns the query used when no cost options are set: the
+// last calendar month of actual cost by service.
+func DefaultCostQuery(now time.Time) *CostQuery {
+	from, to := costTimeRange(now)
+	return &CostQuery{
+		From:        from,
+		To:          to,
+		Granularity: CostGranularityNone,
+		GroupBy:     []string{CostGroupService},
+		CostType:    string(armcostmanagement.ExportTypeActualCost),
+	}
+}
+
+// NewCostQuery builds a cost query from the cost stage options.
+func NewCostQuery(options map[string]any, now time.Time) (*CostQuery, error) {
+	q := DefaultCostQuery(now)
+	str := func(key string) string {
+		s, _ := options[key].(string)
+		return strings.TrimSpace(s)
+	}
+
+	from, until := str("from"), str("to")
+	switch {
+	case from != "" || until != "":
+		if from == "" || until == "" {
+			return nil, fmt.Errorf("cost.from and cost.to must be set together")
+		}
+		start, err := time.Parse(time.DateOnly, from)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cost.from %q: expected YYYY-MM-DD", from)
+		}
+		end, err := time.Parse(time.DateOnly, until)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cost.to %q: expected YYYY-MM-DD", until)
+		}
+		if end.Before(start) {
+			return nil, fmt.Errorf("cost.to %s is before cost.from %s", until, from)
+		}
+		q.From, q.To = start, end.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	case str("period") != "":
+		start, end, err := costPeriodRange(strings.ToLower(str("period")), now)
+		if err != nil {
+			return nil, err
+		}
+		q.From, q.To = start, end
+	}
+
+	if granularity := strings.ToLower(str("granularity")); granularity != "" {
+		switch granularity {
+		case CostGranularityNone, CostGranularityDaily, CostGranularityMonthly:
+			q.Granularity = granularity
+		default:
+			return nil, fmt.Errorf("invalid cost.granularity %q: expected none, daily or monthly", granularity)
+		}
+	}
+
+	if groupBy := str("group-by"); groupBy != "" {
+		q.GroupBy = nil
+		for _, g := range strings.Split(groupBy, ",") {
+			g = strings.TrimSpace(g)
+			if key, ok := strings.CutPrefix(strings.ToLower(g), costGroupTagPrefix); ok {
+				// Tag keys keep their case
+				if key == "" {
+					return nil, fmt.Errorf("invalid cost.group-by %q: tag key is empty", g)
+				}
+				q.GroupBy = append(q.GroupBy, costGroupTagPrefix+g[len(costGroupTagPrefix):])
+				continue
+			}
+			if _, ok := costGroupDimensions[strings.ToLower(g)]; !ok {
+				return nil, fmt.Errorf("invalid cost.group-by %q: expected service, resource-group, resource, meter-category or tag:<key>", g)
+			}
+			q.GroupBy = append(q.GroupBy, strings.ToLower(g))
+		}
+		if len(q.GroupBy) > maxCostGroupings {
+			return nil, fmt.Errorf("cost.group-by accepts at most %d groupings", maxCostGroupings)
+		}
+	}
+
+	if scope := strings.ToLower(str("scope")); scope != "" && scope != CostScopeSubscription {
+		path, err := costScopePath(scope, str("scope-id"))
+		if err != nil {
+			return nil, err
+		}
+		q.Scope = path
+		// The subscription grouping takes one of the groupings
+		if len(q.GroupBy) > maxCostGroupings-1 {
+			return nil, fmt.Errorf("cost.group-by accepts at most %d grouping with cost.scope %s", maxCostGroupings-1, scope)
+		}
+	}
+
+	if costType := str("type"); costType != "" {
+		switch {
+		case strings.EqualFold(costType, string(armcostmanagement.ExportTypeActualCost)):
+			q.CostType = string(armcostmanagement.ExportTypeActualCost)
+		case strings.EqualFold(costType, string(armcostmanagement.ExportTypeAmortizedCost)):
+			q.CostType = string(armcostmanagement.ExportTypeAmortizedCost)
+		default:
+			return nil, fmt.Errorf("invalid cost.type %q: expected ActualCost or AmortizedCost", costType)
+		}
+	}
+
+	return q, nil
+}
+
+// costScopePath returns the ARM path of a billing or management group scope.
+// Billing profiles are identified as <billing account id>/<billing profile id>.
+func costScopePath(scope, id string) (string, error) {
+	if id == "" {
+		return "", fmt.Errorf("cost.scope-id is required with cost.scope %s", scope)
+	}
+	single := func() error {
+		if strings.Contains(id, "/") {
+			return fmt.Errorf("invalid cost.scope-id %q for cost.scope %s", id, scope)
+		}
+		return nil
+	}
+
+	switch scope {
+	case CostScopeBillingAccount, CostScopeEnrollment:
+		// EA enrollments are billing accounts identified by the enrollment number
+		if err := single(); err != nil {
+			return "", err
+		}
+		return "/providers/Microsoft.Billing/billingAccounts/" + id, nil
+	case CostScopeBillingProfile:
+		account, profile, ok := strings.Cut(id, "/")
+		if !ok || account == "" || profile == "" || strings.Contains(profile, "/") {
+			return "", fmt.Errorf("invalid cost.scope-id %q for cost.scope billing-profile: expected <billing account id>/<billing profile id>", id)
+		}
+		return fmt.Sprintf("/providers/Microsoft.Billing/billingAccounts/%s/billingProfiles/%s", account, profile), nil
+	case CostScopeManagementGroup:
+		if err := single(); err != nil {
+			return "", err
+		}
+		return "/providers/Microsoft.Management/managementGroups/" + id, nil
+	default:
+		return "", fmt.Errorf("invalid cost.scope %q: expected subscription, billing-account, billing-profile, enrollment or management-group", scope)
+	}
+}
+
+// costPeriodRange returns the time range of a relative period: last-month,
+// month-to-date, last-<n>-days (ending yesterday) or last-<n>-months (whole
+// calendar months ending last month).
+func costPeriodRange(period string, now time.Time) (time.Time, time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	thisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	switch period {
+	case "last-month":
+		start, end := costTimeRange(now)
+		return start, end, nil
+	case "month-to-date":
+		return thisMonth, today.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+	}
+
+	m := relativeCostPeriodPattern.FindStringSubmatch(period)
+	if m == nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid cost.period %q: expected last-month, month-to-date, last-<n>-days or last-<n>-months", period)
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil || n < 1 {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid cost.period %q: the number of %s must be at least 1", period, m[2])
+	}
+	if m[2] == "days" {
+		return today.AddDate(0, 0, -n), today.Add(-time.Nanosecond), nil
+	}
+	return thisMonth.AddDate(0, -n, 0), thisMonth.Add(-time.Nanosecond), nil
+}
+
+// definition returns the Cost Management query definition.
+func (q *CostQuery) definition() armcostmanagement.QueryDefinition {
+	timeframeType := armcostmanagement.TimeframeTypeCustom
+	etype := armcostmanagement.ExportType(q.CostType)
+	sum := armcostmanagement.FunctionTypeSum
+	dimension := armcostmanagement.QueryColumnTypeDimension
+	tag := armcostmanagement.QueryColumnTypeTag
+
+	dataset := &armcostmanagement.QueryDataset{
+		Aggregation: map[string]*armcostmanagement.QueryAggregation{
+			"TotalCost": {
+				Name:     to.Ptr("Cost"),
+				Function: &sum,
+			},
+		},
+	}
+	switch q.Granularity {
+	case CostGranularityDaily:
+		dataset.Granularity = to.Ptr(armcostmanagement.GranularityTypeDaily)
+	case CostGranularityMonthly:
+		// The query API accepts Monthly, the SDK only declares Daily
+		dataset.Granularity = to.Ptr(armcostmanagement.GranularityType("Monthly"))
+	}
+	if q.Scope != "" {
+		dataset.Grouping = append(dataset.Grouping, &armcostmanagement.QueryGrouping{Name: to.Ptr(costSubscriptionDimension), Type: &dimension})
+	}
+	for _, g := range q.GroupBy {
+		if key, ok := strings.CutPrefix(g, costGroupTagPrefix); ok {
+			dataset.Grouping = append(dataset.Grouping, &armcostmanagement.QueryGrouping{Name: to.Ptr(key), Type: &tag})
+			continue
+		}
+		dataset.Grouping = append(dataset.Grouping, &armcostmanagement.QueryGrouping{Name: to.Ptr(costGroupDimensions[g]), Type: &dimension})
+	}
+
+	start, end := q.From, q.To
+	return armcostmanagement.QueryDefinition{
+		Type:      &etype,
+		Timeframe: &timeframeType,
+		TimePeriod: &armcostmanagement.QueryTimePeriod{
+			From: &start,
+			To:   &end,
+		},
+		Dataset: dataset,
+	}
+}
+
+// parseCostRows converts the rows of a cost query response, locating the
+// values by column name since the columns depend on granularity and grouping.
+// Rows of scoped queries carry their subscription; the others get subscriptionID.
+func (q *CostQuery) parseCostRows(columns []*armcostmanagement.QueryColumn, rows [][]any, subscriptionID, subscriptionName string) []*models.CostResult {
+	index := map[string]int{}
+	for i, c := range columns {
+		if c != nil && c.Name != nil {
+			index[strings.ToLower(*c.Name)] = i
+		}
+	}
+	value := func(row []any, names ...string) string {
+		for _, name := range names {
+			if i, ok := index[strings.ToLower(name)]; ok && i < len(row) && row[i] != nil {
+				return fmt.Sprintf("%v", row[i])
+			}
+		}
+		return ""
+	}
+	costColumns := []string{"TotalCost", "Cost", "PreTaxCost", "CostUSD"}
+	if len(index) == 0 {
+		// Column metadata is missing: fall back to the default query layout
+		index = map[string]int{"totalcost": 0, "servicename": 1, "currency": 2}
+	}
+
+	result := make([]*models.CostResult, 0, len(rows))
+	for _, row := range rows {
+		r := &models.CostResult{
+			From:             q.From,
+			To:               q.To,
+			SubscriptionID:   subscriptionID,
+			SubscriptionName: subscriptionName,
+			CostType:         q.CostType,
+			Date:             formatCostDate(value(row, "UsageDate", "BillingMonth"), q.Granularity),
+			ServiceName:      value(row, "ServiceName"),
+			ResourceGroup:    value(row, "ResourceGroupName", "ResourceGroup"),
+			ResourceID:       value(row, "ResourceId"),
+			MeterCategory:    value(row, "MeterCategory"),
+			TagKey:           value(row, "TagKey"),
+			TagValue:         value(row, "TagValue"),
+			Value:            value(row, costColumns...),
+			Currency:         value(row, "Currency"),
+		}
+		if id := value(row, costSubscriptionDimension); id != "" {
+			r.SubscriptionID = strings.ToLower(id)
+		}
+		result = append(result, r)
+	}
+	return result
+}
+
+// formatCostDate formats the UsageDate (yyyyMMdd number) or BillingMonth
+// (date time) of a cost row as YYYY-MM-DD or YYYY-MM.
+func formatCostDate(value, granularity string) string {
+	if value == "" {
+		return ""
+	}
+	layout := time.DateOnly
+	if granularity == CostGranularityMonthly {
+		layout = "2006-01"
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		if t, err := time.Parse("20060102", strconv.FormatInt(int64(f), 10)); err == nil {
+			return t.Format(layout)
+		}
+	}
+	for _, l := range []string{time.RFC3339, "2006-01-02T15:04:05", time.DateOnly} {
+		if t, err := time.Parse(l, value); err == nil {
+			return t.Format(layout)
+		}
+	}
+	return value
+}
@@ -0,0 +1,238 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azqr/internal/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/costmanagement/armcostmanagement"
+)
+
+func TestNewCostQuery(t *testing.T) {
+	now := time.Date(2026, time.March, 15, 10, 0, 0, 0, time.UTC)
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+	}
+	endOf := func(t time.Time) time.Time { return t.Add(-time.Nanosecond) }
+
+	tests := []struct {
+		name     string
+		options  map[string]any
+		from, to time.Time
+		check    func(t *testing.T, q *CostQuery)
+		wantErr  string
+	}{
+		{
+			name:    "defaults",
+			options: map[string]any{"period": "last-month", "granularity": "none", "group-by": "service", "type": "ActualCost"},
+			from:    day(2026, time.February, 1),
+			to:      endOf(day(2026, time.March, 1)),
+			check: func(t *testing.T, q *CostQuery) {
+				if q.Granularity != CostGranularityNone || !reflect.DeepEqual(q.GroupBy, []string{CostGroupService}) || q.CostType != "ActualCost" {
+					t.Errorf("query = %+v", q)
+				}
+			},
+		},
+		{
+			name:    "month to date",
+			options: map[string]any{"period": "month-to-date"},
+			from:    day(2026, time.March, 1),
+			to:      endOf(day(2026, time.March, 16)),
+		},
+		{
+			name:    "last days",
+			options: map[string]any{"period": "last-7-days"},
+			from:    day(2026, time.March, 8),
+			to:      endOf(day(2026, time.March, 15)),
+		},
+		{
+			name:    "last months",
+			options: map[string]any{"period": "Last-3-Months", "granularity": "monthly"},
+			from:    day(2025, time.December, 1),
+			to:      endOf(day(2026, time.March, 1)),
+			check: func(t *testing.T, q *CostQuery) {
+				if q.Granularity != CostGranularityMonthly {
+					t.Errorf("granularity = %q, want monthly", q.Granularity)
+				}
+			},
+		},
+		{
+			name:    "custom range overrides period",
+			options: map[string]any{"period": "last-month", "from": "2025-10-01", "to": "2025-12-31"},
+			from:    day(2025, time.October, 1),
+			to:      endOf(day(2026, time.January, 1)),
+		},
+		{
+			name:    "grouping and amortized cost",
+			options: map[string]any{"group-by": "Resource-Group, tag:CostCenter", "type": "amortizedcost"},
+			from:    day(2026, time.February, 1),
+			to:      endOf(day(2026, time.March, 1)),
+			check: func(t *testing.T, q *CostQuery) {
+				if !reflect.DeepEqual(q.GroupBy, []string{CostGroupResourceGroup, "tag:CostCenter"}) || q.CostType != "AmortizedCost" {
+					t.Errorf("query = %+v", q)
+				}
+			},
+		},
+		{
+			name:    "billing profile scope",
+			options: map[string]any{"scope": "billing-profile", "scope-id": "acc:123_2019-05-31/prof-1", "group-by": "service"},
+			from:    day(2026, time.February, 1),
+			to:      endOf(day(2026, time.March, 1)),
+			check: func(t *testing.T, q *CostQuery) {
+				if q.Scope != "/providers/Microsoft.Billing/billingAccounts/acc:123_2019-05-31/billingProfiles/prof-1" {
+					t.Errorf("scope = %q", q.Scope)
+				}
+			},
+		},
+		{
+			name:    "management group scope",
+			options: map[string]any{"scope": "Management-Group", "scope-id": "mg-root"},
+			from:    day(2026, time.February, 1),
+			to:      endOf(day(2026, time.March, 1)),
+			check: func(t *testing.T, q *CostQuery) {
+				if q.Scope != "/providers/Microsoft.Management/managementGroups/mg-root" {
+					t.Errorf("scope = %q", q.Scope)
+				}
+			},
+		},
+		{
+			name:    "subscription scope",
+			options: map[string]any{"scope": "subscription", "scope-id": "ignored"},
+			from:    day(2026, time.February, 1),
+			to:      endOf(day(2026, time.March, 1)),
+			check: func(t *testing.T, q *CostQuery) {
+				if q.Scope != "" {
+					t.Errorf("scope = %q, want none", q.Scope)
+				}
+			},
+		},
+		{name: "scope without id", options: map[string]any{"scope": "enrollment"}, wantErr: "cost.scope-id is required"},
+		{name: "invalid scope id", options: map[string]any{"scope": "billing-account", "scope-id": "a/b"}, wantErr: "invalid cost.scope-id"},
+		{name: "invalid billing profile", options: map[string]any{"scope": "billing-profile", "scope-id": "acc"}, wantErr: "billing-profile"},
+		{name: "unknown scope", options: map[string]any{"scope": "tenant", "scope-id": "t"}, wantErr: "invalid cost.scope"},
+		{name: "scope with two groupings", options: map[string]any{"scope": "enrollment", "scope-id": "1234", "group-by": "service,resource-group"}, wantErr: "at most 1 grouping"},
+		{name: "from without to", options: map[string]any{"from": "2025-10-01"}, wantErr: "set together"},
+		{name: "invalid date", options: map[string]any{"from": "2025-10", "to": "2025-12-31"}, wantErr: "invalid cost.from"},
+		{name: "reversed range", options: map[string]any{"from": "2025-12-01", "to": "2025-10-01"}, wantErr: "before"},
+		{name: "invalid period", options: map[string]any{"period": "last-0-days"}, wantErr: "at least 1"},
+		{name: "unknown period", options: map[string]any{"period": "yesterday"}, wantErr: "invalid cost.period"},
+		{name: "invalid granularity", options: map[string]any{"granularity": "hourly"}, wantErr: "invalid cost.granularity"},
+		{name: "invalid grouping", options: map[string]any{"group-by": "location"}, wantErr: "invalid cost.group-by"},
+		{name: "empty tag key", options: map[string]any{"group-by": "tag:"}, wantErr: "tag key is empty"},
+		{name: "too many groupings", options: map[string]any{"group-by": "service,resource,meter-category"}, wantErr: "at most 2"},
+		{name: "invalid type", options: map[string]any{"type": "Usage"}, wantErr: "invalid cost.type"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := NewCostQuery(tt.options, now)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("NewCostQuery() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewCostQuery() error = %v", err)
+			}
+			if !q.From.Equal(tt.from) || !q.To.Equal(tt.to) {
+				t.Errorf("range = %v - %v, want %v - %v", q.From, q.To, tt.from, tt.to)
+			}
+			if tt.check != nil {
+				tt.check(t, q)
+			}
+		})
+	}
+}
+
+func TestCostQueryDefinition(t *testing.T) {
+	q := DefaultCostQuery(time.Date(2026, time.March, 15, 0, 0, 0, 0, time.UTC))
+	q.Granularity = CostGranularityMonthly
+	q.GroupBy = []string{CostGroupMeterCategory, "tag:env"}
+	q.CostType = "AmortizedCost"
+
+	def := q.definition()
+	if *def.Type != armcostmanagement.ExportTypeAmortizedCost {
+		t.Errorf("type = %v, want AmortizedCost", *def.Type)
+	}
+	if *def.Dataset.Granularity != "Monthly" {
+		t.Errorf("granularity = %v, want Monthly", *def.Dataset.Granularity)
+	}
+	if len(def.Dataset.Grouping) != 2 ||
+		*def.Dataset.Grouping[0].Name != "MeterCategory" || *def.Dataset.Grouping[0].Type != armcostmanagement.QueryColumnTypeDimension ||
+		*def.Dataset.Grouping[1].Name != "env" || *def.Dataset.Grouping[1].Type != armcostmanagement.QueryColumnTypeTag {
+		t.Errorf("grouping = %+v", def.Dataset.Grouping)
+	}
+	if !def.TimePeriod.From.Equal(q.From) || !def.TimePeriod.To.Equal(q.To) {
+		t.Errorf("time period = %v - %v", def.TimePeriod.From, def.TimePeriod.To)
+	}
+
+	scoped := DefaultCostQuery(time.Now())
+	scoped.Scope = "/providers/Microsoft.Billing/billingAccounts/1234"
+	if grouping := scoped.definition().Dataset.Grouping; len(grouping) != 2 || *grouping[0].Name != "SubscriptionId" || *grouping[1].Name != "ServiceName" {
+		t.Errorf("scoped grouping = %+v", grouping)
+	}
+
+	if DefaultCostQuery(time.Now()).definition().Dataset.Granularity != nil {
+		t.Error("default query must not set a granularity")
+	}
+}
+
+func TestCostQueryParseCostRows(t *testing.T) {
+	q := DefaultCostQuery(time.Date(2026, time.March, 15, 0, 0, 0, 0, time.UTC))
+	q.Granularity = CostGranularityDaily
+
+	columns := []*armcostmanagement.QueryColumn{
+		{Name: to.Ptr("Cost")},
+		{Name: to.Ptr("UsageDate")},
+		{Name: to.Ptr("ResourceGroupName")},
+		{Name: to.Ptr("TagKey")},
+		{Name: to.Ptr("TagValue")},
+		{Name: to.Ptr("Currency")},
+	}
+	rows := [][]any{
+		{12.5, float64(20260203), "rg-app", "env", "prod", "EUR"},
+		{1.25, float64(20260204), "rg-app", nil, nil, "EUR"},
+	}
+
+	got := q.parseCostRows(columns, rows, "sub", "Sub One")
+	if len(got) != 2 {
+		t.Fatalf("got %d rows, want 2", len(got))
+	}
+	r := got[0]
+	if r.Value != "12.5" || r.Date != "2026-02-03" || r.ResourceGroup != "rg-app" || r.TagKey != "env" || r.TagValue != "prod" ||
+		r.Currency != "EUR" || r.CostType != "ActualCost" || r.SubscriptionName != "Sub One" || !r.From.Equal(q.From) {
+		t.Errorf("row = %+v", r)
+	}
+	if got[1].TagKey != "" || got[1].Date != "2026-02-04" {
+		t.Errorf("row = %+v", got[1])
+	}
+
+	// Rows without column metadata use the default layout
+	legacy := DefaultCostQuery(time.Now()).parseCostRows(nil, [][]any{{3.0, "Storage", "USD"}}, "sub", "")
+	if legacy[0].Value != "3" || legacy[0].ServiceName != "Storage" || legacy[0].Currency != "USD" {
+		t.Errorf("legacy row = %+v", legacy[0])
+	}
+}
+
+func TestFormatCostDate(t *testing.T) {
+	tests := []struct {
+		value, granularity, want string
+	}{
+		{"", CostGranularityDaily, ""},
+		{"20260203", CostGranularityDaily, "2026-02-03"},
+		{"2.0260203e+07", CostGranularityDaily, "2026-02-03"},
+		{"2026-02-01T00:00:00", CostGranularityMonthly, "2026-02"},
+		{"2026-02-01T00:00:00Z", CostGranularityMonthly, "2026-02"},
+		{"unknown", CostGranularityDaily, "unknown"},
+	}
+	for _, tt := range tests {
+		if got := formatCostDate(tt.value, tt.granularity); got != tt.want {
+			t.Errorf("formatCostDate(%q, %q) = %q, want %q", tt.value, tt.granularity, got, tt.want)
+		}
+	}
+}
@@ -6,6 +6,10 @@ package scanners
 import (
 	"testing"
 	"time"
+
+	"github.com/Azure/azqr/internal/models"
+	"github.com/Azure/azqr/internal/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/costmanagement/armcostmanagement"
 )
 
 func TestCostTimeRangePreviousMonth(t *testing.T) {
@@ -23,3 +27,55 @@ func TestCostTimeRangePreviousMonth(t *testing.T) {
 		t.Fatalf("end = %v, want %v", end, wantEnd)
 	}
 }
+
+func TestResourceCosts(t *testing.T) {
+	rows := []*models.CostResult{
+		{SubscriptionID: "sub", ResourceID: "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/disks/d1", Value: "10.5", Currency: "EUR"},
+		{SubscriptionID: "sub", ResourceID: "/subscriptions/sub/resourcegroups/rg/providers/microsoft.compute/disks/d1", Value: "1.5", Currency: "EUR"},
+		{SubscriptionID: "sub", ResourceID: "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/ip", Value: "3", Currency: "EUR"},
+		{SubscriptionID: "sub", ResourceID: "", Value: "7", Currency: "EUR"},
+		{ResourceID: "/subscriptions/other/resourceGroups/rg/providers/Microsoft.Network/natGateways/nat", Value: "4", Currency: "EUR"},
+		{SubscriptionID: "sub", ResourceID: "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Web/sites/app", Value: "n/a", Currency: "EUR"},
+	}
+
+	got := resourceCosts(rows)
+	if len(got) != 3 {
+		t.Fatalf("got %d resource costs, want 3", len(got))
+	}
+	if got[0].ResourceID != "/subscriptions/sub/resourcegroups/rg/providers/microsoft.compute/disks/d1" || got[0].Cost != 12 || got[0].Currency != "EUR" {
+		t.Errorf("disk cost = %+v", got[0])
+	}
+	if got[1].Cost != 3 {
+		t.Errorf("public IP cost = %+v", got[1])
+	}
+	if got[2].SubscriptionID != "other" {
+		t.Errorf("subscription from resource ID = %+v", got[2])
+	}
+}
+
+func TestSplitCostsBySubscription(t *testing.T) {
+	q := DefaultCostQuery(time.Date(2026, time.March, 15, 0, 0, 0, 0, time.UTC))
+	q.Scope = "/providers/Microsoft.Billing/billingAccounts/1234"
+	columns := []*armcostmanagement.QueryColumn{
+		{Name: to.Ptr("Cost")},
+		{Name: to.Ptr("SubscriptionId")},
+		{Name: to.Ptr("ServiceName")},
+		{Name: to.Ptr("Currency")},
+	}
+	rows := [][]any{
+		{10.0, "AAAA", "Storage", "USD"},
+		{5.0, "bbbb", "Storage", "USD"},
+		{2.0, nil, "Reservations", "USD"},
+	}
+
+	got := splitCostsBySubscription(q.parseCostRows(columns, rows, "", ""), map[string]string{"aaaa": "Sub A"})
+	if len(got) != 2 {
+		t.Fatalf("got %d rows, want 2: %+v", len(got), got)
+	}
+	if got[0].SubscriptionID != "aaaa" || got[0].SubscriptionName != "Sub A" || got[0].Value != "10" {
+		t.Errorf("subscription row = %+v", got[0])
+	}
+	if got[1].SubscriptionID != "" || got[1].ServiceName != "Reservations" {
+		t.Errorf("shared charge row = %+v", got[1])
+	}
+}
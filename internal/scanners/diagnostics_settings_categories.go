@@ -0,0 +1,150 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Azure/azqr/internal/models"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/monitor/armmonitor"
+	"github.com/rs/zerolog/log"
+)
+
+// diagnosticCategories holds the diagnostic settings categories of a resource type.
+type diagnosticCategories struct {
+	supported bool
+	logs      []string // log categories
+	auditLogs []string // log categories in the audit category group
+	metrics   []string // metric categories
+}
+
+var (
+	// diagnosticCategoriesCache holds the discovered categories by lowercase
+	// resource type for the lifetime of the process.
+	diagnosticCategoriesCache   = map[string]*diagnosticCategories{}
+	diagnosticCategoriesCacheMu sync.Mutex
+)
+
+// discoverCategories returns the diagnostic settings categories of the types
+// of the resources, keyed by lowercase resource type. Each type is queried
+// once per process through the diagnosticSettingsCategories API of one of its
+// resources. When discovery fails, types with a catalog recommendation are
+// assumed to support diagnostic settings.
+func (d *DiagnosticSettingsScanner) discoverCategories(resources []*models.Resource) map[string]*diagnosticCategories {
+	if d.categories == nil {
+		d.categories = map[string]*diagnosticCategories{}
+	}
+
+	// One sample resource per type not resolved yet
+	samples := map[string]string{}
+	diagnosticCategoriesCacheMu.Lock()
+	for _, r := range resources {
+		t := strings.ToLower(r.Type)
+		if _, ok := d.categories[t]; ok {
+			continue
+		}
+		if c, ok := diagnosticCategoriesCache[t]; ok {
+			d.categories[t] = c
+			continue
+		}
+		if _, ok := samples[t]; !ok {
+			samples[t] = r.ID
+		}
+	}
+	diagnosticCategoriesCacheMu.Unlock()
+
+	types := make([]string, 0, len(samples))
+	for t := range samples {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	recommendations := d.Recommendations()
+	fallback := func(t string) *diagnosticCategories {
+		return &diagnosticCategories{supported: len(recommendations[t]) > 0}
+	}
+
+	const batchSize = 20
+	for i := 0; i < len(types); i += batchSize {
+		batch := types[i:min(i+batchSize, len(types))]
+		urls := make([]string, len(batch))
+		for j, t := range batch {
+			urls[j] = samples[t] + "/providers/microsoft.insights/diagnosticSettingsCategories?api-version=2021-05-01-preview"
+		}
+
+		resp, err := d.doBatch(d.ctx, urls)
+		if err != nil || len(resp.Responses) != len(batch) {
+			log.Warn().Err(err).Msg("Failed to discover diagnostic settings categories, using the recommendation catalog")
+			for _, t := range batch {
+				d.categories[t] = fallback(t)
+			}
+			continue
+		}
+
+		for j, t := range batch {
+			c, ok := parseDiagnosticCategories(resp.Responses[j])
+			if !ok {
+				log.Debug().Msgf("Diagnostic settings categories unavailable for %s (HTTP %d), using the recommendation catalog", t, resp.Responses[j].HttpStatusCode)
+				d.categories[t] = fallback(t)
+				continue
+			}
+			d.categories[t] = c
+			diagnosticCategoriesCacheMu.Lock()
+			diagnosticCategoriesCache[t] = c
+			diagnosticCategoriesCacheMu.Unlock()
+		}
+	}
+
+	result := make(map[string]*diagnosticCategories, len(d.categories))
+	for t, c := range d.categories {
+		result[t] = c
+	}
+	return result
+}
+
+// parseDiagnosticCategories parses a diagnosticSettingsCategories response. It
+// returns false when the response is a transient failure that says nothing
+// about the resource type.
+func parseDiagnosticCategories(response ArmBatchResponseItem) (*diagnosticCategories, bool) {
+	switch response.HttpStatusCode {
+	case http.StatusOK:
+	case http.StatusBadRequest, http.StatusNotFound, http.StatusMethodNotAllowed:
+		// The resource provider does not support diagnostic settings
+		return &diagnosticCategories{}, true
+	default:
+		return nil, false
+	}
+
+	var categories struct {
+		Value []*armmonitor.DiagnosticSettingsCategoryResource `json:"value"`
+	}
+	if err := json.Unmarshal(response.Content, &categories); err != nil {
+		return nil, false
+	}
+
+	c := &diagnosticCategories{}
+	for _, category := range categories.Value {
+		if category == nil || category.Name == nil || category.Properties == nil || category.Properties.CategoryType == nil {
+			continue
+		}
+		switch *category.Properties.CategoryType {
+		case armmonitor.CategoryTypeLogs:
+			c.logs = append(c.logs, *category.Name)
+			for _, group := range category.Properties.CategoryGroups {
+				if group != nil && strings.EqualFold(*group, "audit") {
+					c.auditLogs = append(c.auditLogs, *category.Name)
+					break
+				}
+			}
+		case armmonitor.CategoryTypeMetrics:
+			c.metrics = append(c.metrics, *category.Name)
+		}
+	}
+	c.supported = len(c.logs) > 0 || len(c.metrics) > 0
+	return c, true
+}
@@ -0,0 +1,67 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDiagnosticCategories(t *testing.T) {
+	content := `{
+		"value": [
+			{"name": "SQLSecurityAuditEvents", "properties": {"categoryType": "Logs", "categoryGroups": ["audit", "allLogs"]}},
+			{"name": "Errors", "properties": {"categoryType": "Logs", "categoryGroups": ["allLogs"]}},
+			{"name": "Basic", "properties": {"categoryType": "Metrics"}},
+			{"name": "Unknown", "properties": {}}
+		]
+	}`
+
+	tests := []struct {
+		name     string
+		response ArmBatchResponseItem
+		want     *diagnosticCategories
+		ok       bool
+	}{
+		{
+			name:     "categories",
+			response: ArmBatchResponseItem{HttpStatusCode: 200, Content: []byte(content)},
+			want: &diagnosticCategories{
+				supported: true,
+				logs:      []string{"SQLSecurityAuditEvents", "Errors"},
+				auditLogs: []string{"SQLSecurityAuditEvents"},
+				metrics:   []string{"Basic"},
+			},
+			ok: true,
+		},
+		{
+			name:     "no categories",
+			response: ArmBatchResponseItem{HttpStatusCode: 200, Content: []byte(`{"value": []}`)},
+			want:     &diagnosticCategories{},
+			ok:       true,
+		},
+		{
+			name:     "not supported",
+			response: ArmBatchResponseItem{HttpStatusCode: 400, Content: []byte(`{"error": {}}`)},
+			want:     &diagnosticCategories{},
+			ok:       true,
+		},
+		{
+			name:     "throttled",
+			response: ArmBatchResponseItem{HttpStatusCode: 429},
+		},
+		{
+			name:     "invalid content",
+			response: ArmBatchResponseItem{HttpStatusCode: 200, Content: []byte(`not json`)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseDiagnosticCategories(tt.response)
+			if ok != tt.ok || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseDiagnosticCategories() = %+v, %v, want %+v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/monitor/armmonitor"
+)
+
+// Recommendation IDs of the diagnostic settings depth checks
+const (
+	diagApprovedWorkspaceID = "diag-001"
+	diagAuditLogsID         = "diag-002"
+	diagMetricsOnlyID       = "diag-003"
+	// diagMissingSettingsID is reported for resources without diagnostic
+	// settings whose type supports them but has no catalog recommendation
+	diagMissingSettingsID = "diag-004"
+)
+
+// diagnosticDepthResourceType is the resource type the depth checks are
+// reported under: they apply to every resource with diagnostic settings.
+const diagnosticDepthResourceType = "Microsoft.Resources"
+
+// DiagnosticSetting holds the destinations and enabled categories of a diagnostic setting.
+type DiagnosticSetting struct {
+	Name                        string
+	WorkspaceID                 string
+	StorageAccountID            string
+	EventHubAuthorizationRuleID string
+	EventHubName                string
+	MarketplacePartnerID        string
+	// Logs holds the enabled log categories and category groups
+	Logs []string
+	// DisabledLogs holds the log categories and category groups listed but disabled
+	DisabledLogs []string
+	// Metrics holds the enabled metric categories
+	Metrics []string
+}
+
+// newDiagnosticSetting converts an ARM diagnostic setting.
+func newDiagnosticSetting(r *armmonitor.DiagnosticSettingsResource) *DiagnosticSetting {
+	s := &DiagnosticSetting{Name: deref(r.Name)}
+	p := r.Properties
+	if p == nil {
+		return s
+	}
+	s.WorkspaceID = deref(p.WorkspaceID)
+	s.StorageAccountID = deref(p.StorageAccountID)
+	s.EventHubAuthorizationRuleID = deref(p.EventHubAuthorizationRuleID)
+	s.EventHubName = deref(p.EventHubName)
+	s.MarketplacePartnerID = deref(p.MarketplacePartnerID)
+
+	for _, l := range p.Logs {
+		if l == nil {
+			continue
+		}
+		name := deref(l.Category)
+		if name == "" {
+			name = deref(l.CategoryGroup)
+		}
+		if name == "" {
+			continue
+		}
+		if l.Enabled != nil && *l.Enabled {
+			s.Logs = append(s.Logs, name)
+		} else {
+			s.DisabledLogs = append(s.DisabledLogs, name)
+		}
+	}
+	for _, m := range p.Metrics {
+		if m != nil && m.Enabled != nil && *m.Enabled {
+			s.Metrics = append(s.Metrics, deref(m.Category))
+		}
+	}
+	return s
+}
+
+// HasDestination reports whether the setting sends data anywhere.
+func (s *DiagnosticSetting) HasDestination() bool {
+	return s.WorkspaceID != "" || s.StorageAccountID != "" || s.EventHubAuthorizationRuleID != "" || s.MarketplacePartnerID != ""
+}
+
+// IsEffective reports whether the setting sends at least one log or metric
+// category to a destination.
+func (s *DiagnosticSetting) IsEffective() bool {
+	return s.HasDestination() && (len(s.Logs) > 0 || len(s.Metrics) > 0)
+}
+
+// isAuditCategory reports whether a log category or category group holds audit logs.
+func isAuditCategory(name string) bool {
+	name = strings.ToLower(name)
+	return name == "alllogs" || strings.Contains(name, "audit")
+}
+
+// hasEffectiveDiagnosticSettings reports whether any setting sends data to a destination.
+func hasEffectiveDiagnosticSettings(settings []*DiagnosticSetting) bool {
+	for _, s := range settings {
+		if s.IsEffective() {
+			return true
+		}
+	}
+	return false
+}
+
+// evaluateDiagnosticSettings returns the IDs of the depth recommendations the
+// effective settings of a resource fail. The approved workspace check only
+// runs when approved workspaces (lowercase resource IDs) are configured.
+// categories holds the discovered categories of the resource type, when known;
+// otherwise audit categories listed as disabled in a setting are used.
+func evaluateDiagnosticSettings(settings []*DiagnosticSetting, approvedWorkspaces map[string]bool, categories *diagnosticCategories) []string {
+	var auditLogs []string
+	if categories != nil {
+		auditLogs = categories.auditLogs
+	}
+	auditCategories := make(map[string]bool, len(auditLogs))
+	for _, l := range auditLogs {
+		auditCategories[strings.ToLower(l)] = true
+	}
+	isAudit := func(name string) bool {
+		return isAuditCategory(name) || auditCategories[strings.ToLower(name)]
+	}
+
+	var (
+		sendsToApproved, sendsLogs, sendsMetrics bool
+		auditEnabled                             bool
+		auditDisabled                            = len(auditLogs) > 0
+	)
+	for _, s := range settings {
+		for _, l := range s.DisabledLogs {
+			auditDisabled = auditDisabled || isAudit(l)
+		}
+		if !s.IsEffective() {
+			continue
+		}
+		if approvedWorkspaces[strings.ToLower(s.WorkspaceID)] {
+			sendsToApproved = true
+		}
+		sendsLogs = sendsLogs || len(s.Logs) > 0
+		sendsMetrics = sendsMetrics || len(s.Metrics) > 0
+		for _, l := range s.Logs {
+			auditEnabled = auditEnabled || isAudit(l)
+		}
+	}
+
+	var failed []string
+	if len(approvedWorkspaces) > 0 && !sendsToApproved {
+		failed = append(failed, diagApprovedWorkspaceID)
+	}
+	if auditDisabled && !auditEnabled {
+		failed = append(failed, diagAuditLogsID)
+	}
+	// Types that only expose metrics (network interfaces and the like) cannot
+	// send logs. The catalog fallback lists no categories at all, so it still
+	// gets the check.
+	metricsOnlyType := categories != nil && len(categories.logs) == 0 && len(categories.metrics) > 0
+	if sendsMetrics && !sendsLogs && !metricsOnlyType {
+		failed = append(failed, diagMetricsOnlyID)
+	}
+	return failed
+}
+
+// parseApprovedWorkspaces parses a comma-separated list of Log Analytics
+// workspace resource IDs into a set of lowercase IDs.
+func parseApprovedWorkspaces(value string) map[string]bool {
+	approved := map[string]bool{}
+	for _, id := range strings.Split(value, ",") {
+		if id = strings.ToLower(strings.TrimSpace(id)); id != "" {
+			approved[id] = true
+		}
+	}
+	return approved
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,242 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/Azure/azqr/internal/models"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/monitor/armmonitor"
+)
+
+const testWorkspaceID = "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.OperationalInsights/workspaces/central"
+
+func TestNewDiagnosticSetting(t *testing.T) {
+	content := `{
+		"id": "/subscriptions/x/resourceGroups/rg/providers/Microsoft.KeyVault/vaults/kv/providers/microsoft.insights/diagnosticSettings/toLaw",
+		"name": "toLaw",
+		"properties": {
+			"workspaceId": "` + testWorkspaceID + `",
+			"eventHubName": "logs",
+			"logs": [
+				{"category": "AuditEvent", "enabled": false},
+				{"categoryGroup": "allLogs", "enabled": true},
+				{"category": "", "enabled": true}
+			],
+			"metrics": [
+				{"category": "AllMetrics", "enabled": true}
+			]
+		}
+	}`
+	var r armmonitor.DiagnosticSettingsResource
+	if err := json.Unmarshal([]byte(content), &r); err != nil {
+		t.Fatal(err)
+	}
+
+	got := newDiagnosticSetting(&r)
+	want := &DiagnosticSetting{
+		Name:         "toLaw",
+		WorkspaceID:  testWorkspaceID,
+		EventHubName: "logs",
+		Logs:         []string{"allLogs"},
+		DisabledLogs: []string{"AuditEvent"},
+		Metrics:      []string{"AllMetrics"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("newDiagnosticSetting() = %+v, want %+v", got, want)
+	}
+}
+
+func TestHasEffectiveDiagnosticSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings []*DiagnosticSetting
+		want     bool
+	}{
+		{"no settings", nil, false},
+		{"no destination", []*DiagnosticSetting{{Logs: []string{"audit"}}}, false},
+		{"nothing enabled", []*DiagnosticSetting{{WorkspaceID: testWorkspaceID, DisabledLogs: []string{"audit"}}}, false},
+		{"metrics to storage", []*DiagnosticSetting{{StorageAccountID: "st", Metrics: []string{"AllMetrics"}}}, true},
+		{"one effective of two", []*DiagnosticSetting{{}, {EventHubAuthorizationRuleID: "rule", Logs: []string{"allLogs"}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasEffectiveDiagnosticSettings(tt.settings); got != tt.want {
+				t.Errorf("hasEffectiveDiagnosticSettings() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateDiagnosticSettings(t *testing.T) {
+	approved := parseApprovedWorkspaces(" " + testWorkspaceID + " ,")
+
+	tests := []struct {
+		name       string
+		settings   []*DiagnosticSetting
+		approved   map[string]bool
+		categories *diagnosticCategories
+		want       []string
+	}{
+		{
+			name:     "logs and audit to approved workspace",
+			settings: []*DiagnosticSetting{{WorkspaceID: testWorkspaceID, Logs: []string{"audit", "allLogs"}, Metrics: []string{"AllMetrics"}}},
+			approved: approved,
+		},
+		{
+			name:     "other workspace",
+			settings: []*DiagnosticSetting{{WorkspaceID: "/subscriptions/x/workspaces/other", Logs: []string{"allLogs"}}},
+			approved: approved,
+			want:     []string{diagApprovedWorkspaceID},
+		},
+		{
+			name:     "no approved workspaces configured",
+			settings: []*DiagnosticSetting{{StorageAccountID: "st", Logs: []string{"allLogs"}}},
+		},
+		{
+			name:     "audit category disabled",
+			settings: []*DiagnosticSetting{{WorkspaceID: testWorkspaceID, Logs: []string{"Operational"}, DisabledLogs: []string{"AuditEvent"}}},
+			want:     []string{diagAuditLogsID},
+		},
+		{
+			name: "audit enabled by another setting",
+			settings: []*DiagnosticSetting{
+				{WorkspaceID: testWorkspaceID, Logs: []string{"Operational"}, DisabledLogs: []string{"AuditEvent"}},
+				{StorageAccountID: "st", Logs: []string{"AuditEvent"}},
+			},
+		},
+		{
+			name:     "metrics only",
+			settings: []*DiagnosticSetting{{WorkspaceID: testWorkspaceID, Metrics: []string{"AllMetrics"}, DisabledLogs: []string{"audit"}}},
+			approved: approved,
+			want:     []string{diagAuditLogsID, diagMetricsOnlyID},
+		},
+		{
+			name:       "metrics only for a type without log categories",
+			settings:   []*DiagnosticSetting{{WorkspaceID: testWorkspaceID, Metrics: []string{"AllMetrics"}}},
+			categories: &diagnosticCategories{supported: true, metrics: []string{"AllMetrics"}},
+		},
+		{
+			name:       "metrics only with catalog fallback categories",
+			settings:   []*DiagnosticSetting{{WorkspaceID: testWorkspaceID, Metrics: []string{"AllMetrics"}}},
+			categories: &diagnosticCategories{supported: true},
+			want:       []string{diagMetricsOnlyID},
+		},
+		{
+			name:       "discovered audit category not sent",
+			settings:   []*DiagnosticSetting{{WorkspaceID: testWorkspaceID, Logs: []string{"Operational"}}},
+			categories: &diagnosticCategories{supported: true, logs: []string{"Operational", "SQLSecurityAuditEvents"}, auditLogs: []string{"SQLSecurityAuditEvents"}},
+			want:       []string{diagAuditLogsID},
+		},
+		{
+			name:       "discovered audit category sent",
+			settings:   []*DiagnosticSetting{{WorkspaceID: testWorkspaceID, Logs: []string{"sqlsecurityauditevents"}}},
+			categories: &diagnosticCategories{supported: true, logs: []string{"SQLSecurityAuditEvents"}, auditLogs: []string{"SQLSecurityAuditEvents"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := evaluateDiagnosticSettings(tt.settings, tt.approved, tt.categories); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("evaluateDiagnosticSettings() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiagnosticSettingsScanner_Recommendations(t *testing.T) {
+	depthType := "microsoft.resources"
+
+	d := &DiagnosticSettingsScanner{}
+	recs := d.Recommendations()[depthType]
+	if _, ok := recs[diagApprovedWorkspaceID]; ok {
+		t.Error("approved workspace check listed without approved workspaces")
+	}
+	if _, ok := recs[diagMetricsOnlyID]; !ok {
+		t.Error("metrics-only check missing")
+	}
+	if rec := recs[diagAuditLogsID]; rec.Category != string(models.CategorySecurity) {
+		t.Errorf("audit check category = %q, want Security", rec.Category)
+	}
+
+	d.approvedWorkspaces = parseApprovedWorkspaces(testWorkspaceID)
+	if _, ok := d.Recommendations()[depthType][diagApprovedWorkspaceID]; !ok {
+		t.Error("approved workspace check missing when approved workspaces are configured")
+	}
+	// The cached recommendations are not modified.
+	if _, ok := GetRecommendations()[depthType][diagApprovedWorkspaceID]; !ok {
+		t.Error("GetRecommendations() lost the approved workspace check")
+	}
+}
+
+func TestDiagnosticSettingsScanner_RegisterRecommendation(t *testing.T) {
+	depthType := "microsoft.resources"
+
+	d := &DiagnosticSettingsScanner{}
+	d.RegisterRecommendation(models.GraphRecommendation{
+		RecommendationID: "custom-kv-001",
+		ResourceType:     "Microsoft.KeyVault/vaults",
+		Recommendation:   "Send Key Vault logs to the SOC workspace",
+	})
+	d.RegisterRecommendation(models.GraphRecommendation{
+		RecommendationID: diagMetricsOnlyID,
+		ResourceType:     diagnosticDepthResourceType,
+		Impact:           string(models.ImpactHigh),
+	})
+
+	recs := d.Recommendations()
+	kv := recs["microsoft.keyvault/vaults"]
+	if _, ok := kv["kv-001"]; ok || len(kv) != 1 {
+		t.Errorf("Key Vault recommendations = %v, want only the plugin recommendation", kv)
+	}
+	if rec := recs[depthType][diagMetricsOnlyID]; rec.Impact != string(models.ImpactHigh) {
+		t.Errorf("metrics-only impact = %q, want High", rec.Impact)
+	}
+	if _, ok := recs[depthType][diagAuditLogsID]; !ok {
+		t.Error("audit check missing after depth override")
+	}
+	if _, ok := GetRecommendations()["microsoft.keyvault/vaults"]["kv-001"]; !ok {
+		t.Error("GetRecommendations() lost kv-001")
+	}
+}
+
+func TestDiagnosticSettingsScanner_MissingSettingsRecommendations(t *testing.T) {
+	d := &DiagnosticSettingsScanner{categories: map[string]*diagnosticCategories{
+		"microsoft.keyvault/vaults":    {supported: true},
+		"microsoft.contoso/widgets":    {supported: true},
+		"microsoft.contoso/gadgets":    {supported: true},
+		"microsoft.contoso/nodiagnose": {supported: false},
+	}}
+	d.RegisterRecommendation(models.GraphRecommendation{
+		RecommendationID: "custom-gadget-001",
+		ResourceType:     "Microsoft.Contoso/gadgets",
+	})
+	recs := d.Recommendations()
+
+	tests := []struct {
+		resourceType string
+		want         []string
+	}{
+		{resourceType: "microsoft.keyvault/vaults", want: []string{"kv-001"}},
+		{resourceType: "microsoft.contoso/widgets", want: []string{diagMissingSettingsID}},
+		{resourceType: "microsoft.contoso/gadgets", want: []string{"custom-gadget-001"}},
+		{resourceType: "microsoft.contoso/nodiagnose"},
+		{resourceType: "microsoft.contoso/undiscovered"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.resourceType, func(t *testing.T) {
+			got := []string{}
+			for id := range d.missingSettingsRecommendations(tt.resourceType, recs) {
+				got = append(got, id)
+			}
+			if tt.want == nil {
+				tt.want = []string{}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("missingSettingsRecommendations() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package scanners
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/Azure/azqr/internal/models"
 	"github.com/Azure/azqr/internal/to"
@@ -14,10 +15,10 @@ import (
 
 type ManagementGroupDiscovery struct{}
 
-func (sc ManagementGroupDiscovery) ListSubscriptions(ctx context.Context, cred azcore.TokenCredential, groups []string, filters *models.Filters, options *arm.ClientOptions) map[string]string {
+func (sc ManagementGroupDiscovery) ListSubscriptions(ctx context.Context, cred azcore.TokenCredential, groups []string, filters *models.Filters, options *arm.ClientOptions) (map[string]string, error) {
 	client, err := armmanagementgroups.NewClientFactory(cred, options)
 	if err != nil {
-		log.Fatal().Err(err).Msg("Failed to create management groups client")
+		return nil, fmt.Errorf("failed to create management groups client: %w", err)
 	}
 	result := map[string]string{}
 
@@ -28,7 +29,7 @@ func (sc ManagementGroupDiscovery) ListSubscriptions(ctx context.Context, cred a
 		for resultPager.More() {
 			pageResp, err := resultPager.NextPage(ctx)
 			if err != nil {
-				log.Fatal().Err(err).Msg("Failed to list management group subscriptions")
+				return nil, fmt.Errorf("failed to list subscriptions of management group %s: %w", group, err)
 			}
 
 			for _, s := range pageResp.Value {
@@ -54,7 +55,7 @@ func (sc ManagementGroupDiscovery) ListSubscriptions(ctx context.Context, cred a
 		for decendantsPager.More() {
 			pageResp, err := decendantsPager.NextPage(ctx)
 			if err != nil {
-				log.Fatal().Err(err).Msg("Failed to list management group descendants")
+				return nil, fmt.Errorf("failed to list descendants of management group %s: %w", group, err)
 			}
 
 			for _, s := range pageResp.Value {
@@ -64,12 +65,15 @@ func (sc ManagementGroupDiscovery) ListSubscriptions(ctx context.Context, cred a
 			}
 		}
 		if len(decendants) > 0 {
-			subscriptions := sc.ListSubscriptions(ctx, cred, decendants, filters, options)
+			subscriptions, err := sc.ListSubscriptions(ctx, cred, decendants, filters, options)
+			if err != nil {
+				return nil, err
+			}
 			for k, v := range subscriptions {
 				result[k] = v
 			}
 		}
 	}
 
-	return result
+	return result, nil
 }
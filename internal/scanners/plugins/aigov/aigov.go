@@ -25,6 +25,9 @@ import (
 // maxConcurrentDeploymentFetches limits parallel ARM calls when listing deployments.
 const maxConcurrentDeploymentFetches = 5
 
+// defaultLookbackHours is the default metrics window, just under 7 days.
+const defaultLookbackHours = 167
+
 // AIGovScanner is an internal plugin that monitors AI governance throttling
 type AIGovScanner struct{}
 
@@ -46,19 +49,22 @@ func (s *AIGovScanner) GetMetadata() plugins.PluginMetadata {
 			{Name: "Subscription"},
 			{Name: "Resource Group"},
 			{Name: "Account Name"},
-			{Name: "Kind"},
-			{Name: "SKU"},
+			{Name: "Kind", Type: models.ColumnTypeEnum},
+			{Name: "SKU", Type: models.ColumnTypeEnum},
 			{Name: "Deployment Name"},
 			{Name: "Model Name"},
 			{Name: "Model Version"},
-			{Name: "Model Format"},
-			{Name: "SKU Capacity"},
-			{Name: "Version Upgrade Option"},
-			{Name: "Spillover Enabled"},
+			{Name: "Model Format", Type: models.ColumnTypeEnum},
+			{Name: "SKU Capacity", Type: models.ColumnTypeNumber},
+			{Name: "Version Upgrade Option", Type: models.ColumnTypeEnum},
+			{Name: "Spillover Enabled", Type: models.ColumnTypeEnum},
 			{Name: "Spillover Deployment"},
-			{Name: "Hour"},
-			{Name: "Status Code"},
-			{Name: "Request Count"},
+			{Name: "Hour", Type: models.ColumnTypeDate},
+			{Name: "Status Code", Type: models.ColumnTypeEnum},
+			{Name: "Request Count", Type: models.ColumnTypeNumber},
+		},
+		Options: []plugins.PluginOption{
+			{Name: "lookback-hours", Type: "int", Default: defaultLookbackHours, Description: "Number of hours of request metrics to analyze"},
 		},
 	}
 }
@@ -113,6 +119,15 @@ func (s *AIGovScanner) Scan(ctx context.Context, cred azcore.TokenCredential, su
 	// Build header row from ColumnMetadata (single source of truth).
 	table := [][]string{s.GetMetadata().HeaderRow()}
 
+	hours := defaultLookbackHours
+	if n, ok := params.Stages.GetPluginOptions(s.GetMetadata().Name)["lookback-hours"].(int); ok {
+		if n >= 1 {
+			hours = n
+		} else {
+			log.Warn().Msgf("lookback-hours %d must be positive; using default %d", n, defaultLookbackHours)
+		}
+	}
+
 	// Use a targeted Resource Graph query instead of fetching all resources.
 	// This avoids downloading the entire resource inventory when only
 	// CognitiveServices/accounts with OpenAI or AI Services kind are needed.
@@ -176,7 +191,7 @@ func (s *AIGovScanner) Scan(ctx context.Context, cred azcore.TokenCredential, su
 			log.Debug().Int("start", i).Int("end", end).Int("total", len(group.Resources)).Msg("Processing batch")
 
 			// Collect throttling data for batch
-			results, err := s.processBatch(ctx, cred, deploymentsClient, batch, subscriptionName)
+			results, err := s.processBatch(ctx, cred, deploymentsClient, batch, subscriptionName, hours)
 			if err != nil {
 				log.Debug().Err(err).Msg("Error processing batch")
 				continue
@@ -291,8 +306,8 @@ type accountDeployments struct {
 	Err           error
 }
 
-// processBatch processes a batch of resources using batch metrics API
-func (s *AIGovScanner) processBatch(ctx context.Context, cred azcore.TokenCredential, deploymentsClient *armcognitiveservices.DeploymentsClient, resources []*models.Resource, subscriptionName string) ([][]string, error) {
+// processBatch processes a batch of resources using batch metrics API over the last hours
+func (s *AIGovScanner) processBatch(ctx context.Context, cred azcore.TokenCredential, deploymentsClient *armcognitiveservices.DeploymentsClient, resources []*models.Resource, subscriptionName string, hours int) ([][]string, error) {
 	if len(resources) == 0 {
 		return nil, nil
 	}
@@ -309,7 +324,7 @@ func (s *AIGovScanner) processBatch(ctx context.Context, cred azcore.TokenCreden
 	}
 
 	// Query metrics using batch API
-	batchMetrics, err := s.getBatchMetricsWithStatusCodeSplit(ctx, cred, subscriptionID, region, resourceIDs, 167)
+	batchMetrics, err := s.getBatchMetricsWithStatusCodeSplit(ctx, cred, subscriptionID, region, resourceIDs, hours)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get batch metrics: %w", err)
 	}
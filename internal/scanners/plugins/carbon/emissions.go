@@ -11,6 +11,7 @@ import (
 	"github.com/Azure/azqr/internal/az"
 	"github.com/Azure/azqr/internal/models"
 	"github.com/Azure/azqr/internal/plugins"
+	"github.com/Azure/azqr/internal/progress"
 	"github.com/Azure/azqr/internal/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/carbonoptimization/armcarbonoptimization"
@@ -35,14 +36,18 @@ func (s *EmissionsScanner) GetMetadata() plugins.PluginMetadata {
 		License:     "MIT",
 		Type:        plugins.PluginTypeInternal,
 		ColumnMetadata: []plugins.ColumnMetadata{
-			{Name: "Period From"},
-			{Name: "Period To"},
+			{Name: "Period From", Type: models.ColumnTypeDate},
+			{Name: "Period To", Type: models.ColumnTypeDate},
 			{Name: "Resource Type"},
-			{Name: "Latest Month Emissions"},
-			{Name: "Previous Month Emissions"},
-			{Name: "Month-over-Month Change Ratio"},
-			{Name: "Monthly Change Value"},
-			{Name: "Unit"},
+			{Name: "Latest Month Emissions", Type: models.ColumnTypeNumber, Unit: "kgCO2e"},
+			{Name: "Previous Month Emissions", Type: models.ColumnTypeNumber, Unit: "kgCO2e"},
+			{Name: "Month-over-Month Change Ratio", Type: models.ColumnTypePercent},
+			{Name: "Monthly Change Value", Type: models.ColumnTypeNumber, Unit: "kgCO2e"},
+			{Name: "Unit", Type: models.ColumnTypeEnum},
+		},
+		Options: []plugins.PluginOption{
+			{Name: "from", Type: "string", Default: "", Description: "Start of the reporting period (YYYY-MM-DD, default: latest available month)"},
+			{Name: "to", Type: "string", Default: "", Description: "End of the reporting period (YYYY-MM-DD, default: latest available month)"},
 		},
 	}
 }
@@ -72,7 +77,16 @@ func (s *EmissionsScanner) Scan(ctx context.Context, cred azcore.TokenCredential
 	if err != nil {
 		return nil, fmt.Errorf("failed to get available date range: %w", err)
 	}
-	log.Info().Msgf("Carbon emissions available date range: %s to %s", fromTime.Format("2006-01-02"), toTime.Format("2006-01-02"))
+
+	// Narrow or move the period with the from/to plugin options
+	options := params.Stages.GetPluginOptions(s.GetMetadata().Name)
+	fromOpt, _ := options["from"].(string)
+	toOpt, _ := options["to"].(string)
+	fromTime, toTime, err = resolveDateRange(fromOpt, toOpt, fromTime, toTime)
+	if err != nil {
+		return nil, err
+	}
+	log.Info().Msgf("Carbon emissions date range: %s to %s", fromTime.Format("2006-01-02"), toTime.Format("2006-01-02"))
 
 	// Build subscription list
 	subscriptionList := make([]*string, 0, len(subscriptions))
@@ -83,6 +97,7 @@ func (s *EmissionsScanner) Scan(ctx context.Context, cred azcore.TokenCredential
 	// Process subscriptions in batches of 100 to avoid API limits
 	const batchSize = 100
 	aggregatedResults := make(map[string]*aggregatedEmissions)
+	tracker := progress.NewTracker(ctx, "subscriptions", len(subscriptionList))
 
 	for i := 0; i < len(subscriptionList); i += batchSize {
 		// Check for context cancellation between batches
@@ -95,6 +110,7 @@ func (s *EmissionsScanner) Scan(ctx context.Context, cred azcore.TokenCredential
 			end = len(subscriptionList)
 		}
 		batch := subscriptionList[i:end]
+		tracker.Add(len(batch), "")
 
 		log.Info().Msgf("Processing carbon emissions for batch %d-%d of %d subscriptions", i+1, end, len(subscriptionList))
 
@@ -251,6 +267,26 @@ func parseAvailableDateRange(startStr, endStr string) (time.Time, time.Time, err
 	return endDate, endDate, nil
 }
 
+// resolveDateRange returns the reporting period from the from/to options,
+// falling back to the latest available period for options that are not set.
+func resolveDateRange(fromStr, toStr string, fromTime, toTime time.Time) (time.Time, time.Time, error) {
+	var err error
+	if fromStr != "" {
+		if fromTime, err = time.Parse("2006-01-02", fromStr); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid from date %q: expected YYYY-MM-DD", fromStr)
+		}
+	}
+	if toStr != "" {
+		if toTime, err = time.Parse("2006-01-02", toStr); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid to date %q: expected YYYY-MM-DD", toStr)
+		}
+	}
+	if fromTime.After(toTime) {
+		return time.Time{}, time.Time{}, fmt.Errorf("from date %s is after to date %s", fromTime.Format("2006-01-02"), toTime.Format("2006-01-02"))
+	}
+	return fromTime, toTime, nil
+}
+
 // init registers the plugin automatically
 func init() {
 	plugins.RegisterInternalPlugin("carbon-emissions", NewScanner())
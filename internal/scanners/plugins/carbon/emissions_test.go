@@ -120,3 +120,27 @@ func TestParseAvailableDateRange_Invalid(t *testing.T) {
 		t.Error("expected error for invalid start date, got nil")
 	}
 }
+
+func TestResolveDateRange(t *testing.T) {
+	latest := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	from, to, err := resolveDateRange("", "", latest, latest)
+	if err != nil || !from.Equal(latest) || !to.Equal(latest) {
+		t.Errorf("defaults: got (%s, %s, %v), want latest month", from, to, err)
+	}
+
+	from, to, err = resolveDateRange("2026-01-01", "", latest, latest)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !from.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) || !to.Equal(latest) {
+		t.Errorf("from only: got (%s, %s)", from, to)
+	}
+
+	if _, _, err := resolveDateRange("2026-03-01", "2026-01-01", latest, latest); err == nil {
+		t.Error("expected error when from is after to, got nil")
+	}
+	if _, _, err := resolveDateRange("January", "", latest, latest); err == nil {
+		t.Error("expected error for invalid from date, got nil")
+	}
+}
@@ -0,0 +1,439 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package costanomaly
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Azure/azqr/internal/az"
+	"github.com/Azure/azqr/internal/models"
+	"github.com/Azure/azqr/internal/plugins"
+	"github.com/Azure/azqr/internal/progress"
+	"github.com/Azure/azqr/internal/renderers"
+	"github.com/Azure/azqr/internal/scanners"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/rs/zerolog/log"
+)
+
+// Anomaly kinds
+const (
+	AnomalySpike           = "Spike"
+	AnomalyNewCost         = "New Cost"
+	AnomalySustainedGrowth = "Sustained Growth"
+)
+
+// maxContributors is the number of contributing resources listed per anomaly
+const maxContributors = 5
+
+// AnomalyScanner is an internal plugin that detects cost anomalies and trends
+type AnomalyScanner struct{}
+
+// NewScanner creates a new cost anomaly scanner
+func NewScanner() *AnomalyScanner {
+	return &AnomalyScanner{}
+}
+
+// GetMetadata returns plugin metadata
+func (s *AnomalyScanner) GetMetadata() plugins.PluginMetadata {
+	return plugins.PluginMetadata{
+		Name:        "cost-anomalies",
+		Version:     "1.0.0",
+		Description: "Detects cost spikes, new costs and sustained growth by service and resource group from daily cost",
+		Author:      "Azure Quick Review Team",
+		License:     "MIT",
+		Type:        plugins.PluginTypeInternal,
+		ColumnMetadata: []plugins.ColumnMetadata{
+			{Name: "Subscription Id"},
+			{Name: "Subscription Name"},
+			{Name: "Service Name"},
+			{Name: "Resource Group"},
+			{Name: "Anomaly", Type: models.ColumnTypeEnum},
+			{Name: "Period From", Type: models.ColumnTypeDate},
+			{Name: "Period To", Type: models.ColumnTypeDate},
+			{Name: "Current Cost", Type: models.ColumnTypeNumber},
+			{Name: "Previous Cost", Type: models.ColumnTypeNumber},
+			{Name: "Change", Type: models.ColumnTypePercent},
+			{Name: "Change Value", Type: models.ColumnTypeNumber},
+			{Name: "Currency", Type: models.ColumnTypeEnum},
+			{Name: "Trend"},
+			{Name: "Contributing Resources"},
+		},
+		Options: []plugins.PluginOption{
+			{Name: "months", Type: "int", Default: 3, Description: "Months of daily cost history to analyze"},
+			{Name: "period-days", Type: "int", Default: 7, Description: "Length in days of the periods compared, the latest ending yesterday"},
+			{Name: "spike-threshold", Type: "float64", Default: 50.0, Description: "Period-over-period increase (percent) reported as a spike"},
+			{Name: "min-cost", Type: "float64", Default: 10.0, Description: "Minimum cost of the latest period for a service and resource group to be reported"},
+			{Name: "growth-periods", Type: "int", Default: 3, Description: "Consecutive periods of growth reported as sustained growth"},
+		},
+	}
+}
+
+// settings holds the detection options
+type settings struct {
+	months         int
+	periodDays     int
+	spikeThreshold float64
+	minCost        float64
+	growthPeriods  int
+}
+
+// newSettings reads and validates the plugin options.
+func newSettings(options map[string]any) (settings, error) {
+	cfg := settings{months: 3, periodDays: 7, spikeThreshold: 50, minCost: 10, growthPeriods: 3}
+	if v, ok := options["months"].(int); ok {
+		cfg.months = v
+	}
+	if v, ok := options["period-days"].(int); ok {
+		cfg.periodDays = v
+	}
+	if v, ok := options["spike-threshold"].(float64); ok {
+		cfg.spikeThreshold = v
+	}
+	if v, ok := options["min-cost"].(float64); ok {
+		cfg.minCost = v
+	}
+	if v, ok := options["growth-periods"].(int); ok {
+		cfg.growthPeriods = v
+	}
+
+	switch {
+	case cfg.months < 1 || cfg.months > 12:
+		return cfg, fmt.Errorf("cost-anomalies: months must be between 1 and 12, got %d", cfg.months)
+	case cfg.periodDays < 1:
+		return cfg, fmt.Errorf("cost-anomalies: period-days must be at least 1, got %d", cfg.periodDays)
+	case cfg.spikeThreshold <= 0:
+		return cfg, fmt.Errorf("cost-anomalies: spike-threshold must be positive, got %g", cfg.spikeThreshold)
+	case cfg.minCost < 0:
+		return cfg, fmt.Errorf("cost-anomalies: min-cost must not be negative, got %g", cfg.minCost)
+	case cfg.growthPeriods < 2:
+		return cfg, fmt.Errorf("cost-anomalies: growth-periods must be at least 2, got %d", cfg.growthPeriods)
+	}
+	return cfg, nil
+}
+
+// periods splits the history ending at end (exclusive) into consecutive
+// periods of periodDays, the latest first.
+type periods struct {
+	end   time.Time
+	days  int
+	count int
+}
+
+func newPeriods(cfg settings, end time.Time) (periods, error) {
+	historyDays := int(end.Sub(end.AddDate(0, -cfg.months, 0)).Hours() / 24)
+	p := periods{end: end, days: cfg.periodDays, count: historyDays / cfg.periodDays}
+	if p.count < 2 {
+		return p, fmt.Errorf("cost-anomalies: %d months of history hold fewer than 2 periods of %d days", cfg.months, cfg.periodDays)
+	}
+	return p, nil
+}
+
+// start returns the first day of period k (0 is the latest).
+func (p periods) start(k int) time.Time {
+	return p.end.AddDate(0, 0, -(k+1)*p.days)
+}
+
+// index returns the period of a day, or -1 when the day is outside the history.
+func (p periods) index(day time.Time) int {
+	if !day.Before(p.end) {
+		return -1
+	}
+	k := int(p.end.Sub(day).Hours()/24-1) / p.days
+	if k >= p.count {
+		return -1
+	}
+	return k
+}
+
+// seriesKey identifies the cost of a service in a resource group
+type seriesKey struct {
+	subscriptionID, service, resourceGroup string
+}
+
+// anomaly is a cost anomaly of a service in a resource group
+type anomaly struct {
+	key      seriesKey
+	kind     string
+	costs    []float64 // cost per period, the latest first
+	currency string
+	// contributors lists the resources with the largest cost increase
+	contributors []string
+}
+
+// detectAnomalies groups daily cost rows per service and resource group and
+// returns the series with a new cost, a spike or sustained growth in the
+// latest period, in this order of precedence.
+func detectAnomalies(rows []*models.CostResult, p periods, cfg settings) []*anomaly {
+	series := map[seriesKey]*anomaly{}
+	var keys []seriesKey
+	for _, r := range rows {
+		day, err := time.Parse(time.DateOnly, r.Date)
+		if err != nil {
+			continue
+		}
+		k := p.index(day)
+		value, err := strconv.ParseFloat(r.Value, 64)
+		if k < 0 || err != nil {
+			continue
+		}
+		key := seriesKey{subscriptionID: r.SubscriptionID, service: r.ServiceName, resourceGroup: strings.ToLower(r.ResourceGroup)}
+		a, ok := series[key]
+		if !ok {
+			a = &anomaly{key: key, costs: make([]float64, p.count), currency: r.Currency}
+			series[key] = a
+			keys = append(keys, key)
+		}
+		a.costs[k] += value
+	}
+
+	var result []*anomaly
+	for _, key := range keys {
+		a := series[key]
+		if a.kind = classify(a.costs, cfg); a.kind != "" {
+			result = append(result, a)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].costs[0]-result[i].costs[1] > result[j].costs[0]-result[j].costs[1]
+	})
+	return result
+}
+
+// classify returns the anomaly kind of a cost series (the latest period
+// first), or an empty string.
+func classify(costs []float64, cfg settings) string {
+	current, previous := costs[0], costs[1]
+	if current < cfg.minCost || current == 0 {
+		return ""
+	}
+
+	history := 0.0
+	for _, c := range costs[1:] {
+		history += c
+	}
+	if history == 0 {
+		return AnomalyNewCost
+	}
+	if previous > 0 && (current-previous)/previous*100 >= cfg.spikeThreshold {
+		return AnomalySpike
+	}
+	if len(costs) > cfg.growthPeriods && costs[cfg.growthPeriods] > 0 {
+		for k := 0; k < cfg.growthPeriods; k++ {
+			if costs[k] <= costs[k+1] {
+				return ""
+			}
+		}
+		return AnomalySustainedGrowth
+	}
+	return ""
+}
+
+// resourceDelta is the cost of a resource in the latest and previous periods
+type resourceDelta struct {
+	name              string
+	current, previous float64
+}
+
+// contributors sets the resources with the largest cost increase of each
+// anomaly from the cost per resource and service of the latest and previous
+// periods.
+func contributors(anomalies []*anomaly, current, previous []*models.CostResult) {
+	byKey := map[seriesKey]map[string]*resourceDelta{}
+	add := func(rows []*models.CostResult, latest bool) {
+		for _, r := range rows {
+			value, err := strconv.ParseFloat(r.Value, 64)
+			if err != nil || r.ResourceID == "" {
+				continue
+			}
+			key := seriesKey{subscriptionID: r.SubscriptionID, service: r.ServiceName, resourceGroup: resourceGroupOf(r.ResourceID)}
+			if byKey[key] == nil {
+				byKey[key] = map[string]*resourceDelta{}
+			}
+			id := strings.ToLower(r.ResourceID)
+			d, ok := byKey[key][id]
+			if !ok {
+				d = &resourceDelta{name: r.ResourceID[strings.LastIndex(r.ResourceID, "/")+1:]}
+				byKey[key][id] = d
+			}
+			if latest {
+				d.current += value
+			} else {
+				d.previous += value
+			}
+		}
+	}
+	add(current, true)
+	add(previous, false)
+
+	for _, a := range anomalies {
+		deltas := make([]*resourceDelta, 0, len(byKey[a.key]))
+		for _, d := range byKey[a.key] {
+			if d.current-d.previous > 0 {
+				deltas = append(deltas, d)
+			}
+		}
+		sort.Slice(deltas, func(i, j int) bool {
+			di, dj := deltas[i].current-deltas[i].previous, deltas[j].current-deltas[j].previous
+			if di != dj {
+				return di > dj
+			}
+			return deltas[i].name < deltas[j].name
+		})
+		for i, d := range deltas {
+			if i == maxContributors {
+				break
+			}
+			a.contributors = append(a.contributors, fmt.Sprintf("%s (+%.2f)", d.name, d.current-d.previous))
+		}
+	}
+}
+
+// resourceGroupOf returns the lowercase resource group of a resource ID.
+func resourceGroupOf(resourceID string) string {
+	parts := strings.Split(strings.ToLower(resourceID), "/")
+	for i := 0; i < len(parts)-1; i++ {
+		if parts[i] == "resourcegroups" {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
+
+// Scan executes the plugin and returns table data
+func (s *AnomalyScanner) Scan(ctx context.Context, cred azcore.TokenCredential, subscriptions map[string]string, params *models.ScanParams) ([]plugins.ExternalPluginOutput, error) {
+	cfg, err := newSettings(params.Stages.GetPluginOptions(s.GetMetadata().Name))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	p, err := newPeriods(cfg, time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		return nil, err
+	}
+	log.Info().Msgf("Scanning cost anomalies from %s to %s", p.start(p.count-1).Format(time.DateOnly), p.end.AddDate(0, 0, -1).Format(time.DateOnly))
+
+	clientOptions := az.NewDefaultClientOptions()
+	table := [][]string{s.GetMetadata().HeaderRow()}
+	tracker := progress.NewTracker(ctx, "subscriptions", len(subscriptions))
+
+	subscriptionIDs := make([]string, 0, len(subscriptions))
+	for id := range subscriptions {
+		subscriptionIDs = append(subscriptionIDs, id)
+	}
+	sort.Strings(subscriptionIDs)
+
+	for _, subscriptionID := range subscriptionIDs {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("scan cancelled: %w", err)
+		}
+		config := &models.ScannerConfig{
+			Ctx:              ctx,
+			Cred:             cred,
+			ClientOptions:    clientOptions,
+			SubscriptionID:   subscriptionID,
+			SubscriptionName: subscriptions[subscriptionID],
+		}
+		anomalies, err := scanSubscription(config, p, cfg)
+		tracker.Step(subscriptions[subscriptionID])
+		if err != nil {
+			// Cost data might not be available in all subscriptions
+			log.Info().Err(err).Msgf("Cost anomalies not available for subscription %s", renderers.MaskSubscriptionID(subscriptionID, true))
+			continue
+		}
+		for _, a := range anomalies {
+			table = append(table, buildAnomalyRow(a, subscriptions[subscriptionID], p, cfg.growthPeriods+1, params.Mask))
+		}
+	}
+
+	log.Info().Msgf("Cost anomaly scan completed with %d anomalies", len(table)-1)
+
+	return []plugins.ExternalPluginOutput{{
+		Metadata:    s.GetMetadata(),
+		SheetName:   "Cost Anomalies",
+		Description: "Cost spikes, new costs and sustained growth by service and resource group",
+		Table:       table,
+	}}, nil
+}
+
+// scanSubscription queries the daily cost of a subscription by service and
+// resource group, and the cost per resource of the latest two periods when
+// it has anomalies.
+func scanSubscription(config *models.ScannerConfig, p periods, cfg settings) ([]*anomaly, error) {
+	query := func(from, until time.Time, granularity string, groupBy ...string) ([]*models.CostResult, error) {
+		scanner := scanners.CostScanner{Query: &scanners.CostQuery{
+			From:        from,
+			To:          until.Add(-time.Nanosecond),
+			Granularity: granularity,
+			GroupBy:     groupBy,
+			CostType:    "ActualCost",
+		}}
+		costs, _, err := scanner.Scan(config)
+		return costs, err
+	}
+
+	daily, err := query(p.start(p.count-1), p.end, scanners.CostGranularityDaily, scanners.CostGroupService, scanners.CostGroupResourceGroup)
+	if err != nil {
+		return nil, err
+	}
+	anomalies := detectAnomalies(daily, p, cfg)
+	if len(anomalies) == 0 {
+		return nil, nil
+	}
+
+	current, err := query(p.start(0), p.end, scanners.CostGranularityNone, scanners.CostGroupResource, scanners.CostGroupService)
+	if err != nil {
+		return nil, err
+	}
+	previous, err := query(p.start(1), p.start(0), scanners.CostGranularityNone, scanners.CostGroupResource, scanners.CostGroupService)
+	if err != nil {
+		return nil, err
+	}
+	contributors(anomalies, current, previous)
+	return anomalies, nil
+}
+
+// buildAnomalyRow formats an anomaly into a table row, with the cost of the
+// latest trendPeriods periods as trend. The change ratio is empty when the
+// previous period has no cost. The subscription ID is masked when mask is set.
+func buildAnomalyRow(a *anomaly, subscriptionName string, p periods, trendPeriods int, mask bool) []string {
+	current, previous := a.costs[0], a.costs[1]
+	change := ""
+	if previous != 0 {
+		change = fmt.Sprintf("%.2f", (current-previous)/previous*100)
+	}
+
+	// Oldest period first
+	n := min(len(a.costs), trendPeriods)
+	trend := make([]string, 0, n)
+	for k := n - 1; k >= 0; k-- {
+		trend = append(trend, fmt.Sprintf("%.2f", a.costs[k]))
+	}
+
+	return []string{
+		renderers.MaskSubscriptionID(a.key.subscriptionID, mask),
+		subscriptionName,
+		a.key.service,
+		a.key.resourceGroup,
+		a.kind,
+		p.start(0).Format(time.DateOnly),
+		p.end.AddDate(0, 0, -1).Format(time.DateOnly),
+		fmt.Sprintf("%.2f", current),
+		fmt.Sprintf("%.2f", previous),
+		change,
+		fmt.Sprintf("%.2f", current-previous),
+		a.currency,
+		strings.Join(trend, " -> "),
+		strings.Join(a.contributors, "; "),
+	}
+}
+
+// init registers the plugin automatically
+func init() {
+	plugins.RegisterInternalPlugin("cost-anomalies", NewScanner())
+}
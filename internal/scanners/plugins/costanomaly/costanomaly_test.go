@@ -0,0 +1,194 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package costanomaly
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azqr/internal/models"
+	"github.com/Azure/azqr/internal/renderers"
+)
+
+var testEnd = time.Date(2026, time.March, 15, 0, 0, 0, 0, time.UTC)
+
+func testPeriods(t *testing.T) periods {
+	t.Helper()
+	p, err := newPeriods(settings{months: 1, periodDays: 7}, testEnd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestPeriods(t *testing.T) {
+	p := testPeriods(t)
+	if p.count != 4 {
+		t.Fatalf("count = %d, want 4 (28 days of February)", p.count)
+	}
+	if got := p.start(0); !got.Equal(time.Date(2026, time.March, 8, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("start(0) = %v", got)
+	}
+
+	tests := []struct {
+		day  time.Time
+		want int
+	}{
+		{testEnd.AddDate(0, 0, -1), 0},
+		{testEnd.AddDate(0, 0, -7), 0},
+		{testEnd.AddDate(0, 0, -8), 1},
+		{testEnd.AddDate(0, 0, -28), 3},
+		{testEnd.AddDate(0, 0, -29), -1},
+		{testEnd, -1},
+	}
+	for _, tt := range tests {
+		if got := p.index(tt.day); got != tt.want {
+			t.Errorf("index(%s) = %d, want %d", tt.day.Format(time.DateOnly), got, tt.want)
+		}
+	}
+
+	if _, err := newPeriods(settings{months: 1, periodDays: 20}, testEnd); err == nil {
+		t.Error("expected an error for fewer than 2 periods")
+	}
+}
+
+func TestClassify(t *testing.T) {
+	cfg := settings{spikeThreshold: 50, minCost: 10, growthPeriods: 3}
+	tests := []struct {
+		name  string
+		costs []float64
+		want  string
+	}{
+		{"new cost", []float64{40, 0, 0, 0}, AnomalyNewCost},
+		{"spike", []float64{30, 20, 20, 20}, AnomalySpike},
+		{"below threshold", []float64{29, 20, 20, 20}, ""},
+		{"sustained growth", []float64{25, 20, 15, 10}, AnomalySustainedGrowth},
+		{"growth interrupted", []float64{25, 20, 20, 10}, ""},
+		{"growth from zero", []float64{25, 20, 15, 0}, ""},
+		{"below minimum cost", []float64{9, 0, 0, 0}, ""},
+		{"decrease", []float64{10, 40, 40, 40}, ""},
+	}
+	for _, tt := range tests {
+		if got := classify(tt.costs, cfg); got != tt.want {
+			t.Errorf("%s: classify(%v) = %q, want %q", tt.name, tt.costs, got, tt.want)
+		}
+	}
+}
+
+func TestDetectAnomalies(t *testing.T) {
+	p := testPeriods(t)
+	cfg := settings{spikeThreshold: 50, minCost: 10, growthPeriods: 3}
+
+	// Daily cost rows: the storage cost doubles in the latest period, the
+	// compute cost is stable, and the AI cost is new
+	var rows []*models.CostResult
+	for d := 1; d <= 28; d++ {
+		day := testEnd.AddDate(0, 0, -d).Format(time.DateOnly)
+		storage := 1.0
+		if d <= 7 {
+			storage = 2
+		}
+		rows = append(rows,
+			&models.CostResult{SubscriptionID: "sub", Date: day, ServiceName: "Storage", ResourceGroup: "RG-Data", Value: fmt.Sprint(storage), Currency: "USD"},
+			&models.CostResult{SubscriptionID: "sub", Date: day, ServiceName: "Virtual Machines", ResourceGroup: "rg-app", Value: "5", Currency: "USD"},
+		)
+		if d <= 7 {
+			rows = append(rows, &models.CostResult{SubscriptionID: "sub", Date: day, ServiceName: "Foundry Models", ResourceGroup: "rg-ai", Value: "10", Currency: "USD"})
+		}
+	}
+	// Outside the history and unparsable rows are skipped
+	rows = append(rows,
+		&models.CostResult{SubscriptionID: "sub", Date: testEnd.Format(time.DateOnly), ServiceName: "Storage", ResourceGroup: "rg-data", Value: "100"},
+		&models.CostResult{SubscriptionID: "sub", Date: "", ServiceName: "Storage", ResourceGroup: "rg-data", Value: "100"},
+	)
+
+	got := detectAnomalies(rows, p, cfg)
+	if len(got) != 2 {
+		t.Fatalf("got %d anomalies, want 2: %+v", len(got), got)
+	}
+	// Sorted by cost increase
+	if got[0].kind != AnomalyNewCost || got[0].key.service != "Foundry Models" || got[0].costs[0] != 70 {
+		t.Errorf("anomaly[0] = %+v", got[0])
+	}
+	if got[1].kind != AnomalySpike || got[1].key.resourceGroup != "rg-data" || got[1].costs[0] != 14 || got[1].costs[1] != 7 {
+		t.Errorf("anomaly[1] = %+v", got[1])
+	}
+}
+
+func TestContributors(t *testing.T) {
+	a := &anomaly{key: seriesKey{subscriptionID: "sub", service: "Storage", resourceGroup: "rg-data"}, costs: []float64{14, 7}}
+	id := func(name string) string {
+		return "/subscriptions/sub/resourceGroups/RG-Data/providers/Microsoft.Storage/storageAccounts/" + name
+	}
+	current := []*models.CostResult{
+		{SubscriptionID: "sub", ServiceName: "Storage", ResourceID: id("grown"), Value: "10"},
+		{SubscriptionID: "sub", ServiceName: "Storage", ResourceID: id("new"), Value: "3"},
+		{SubscriptionID: "sub", ServiceName: "Storage", ResourceID: id("shrunk"), Value: "1"},
+		{SubscriptionID: "sub", ServiceName: "Bandwidth", ResourceID: id("grown"), Value: "50"},
+	}
+	previous := []*models.CostResult{
+		{SubscriptionID: "sub", ServiceName: "Storage", ResourceID: id("grown"), Value: "4"},
+		{SubscriptionID: "sub", ServiceName: "Storage", ResourceID: id("shrunk"), Value: "3"},
+	}
+
+	contributors([]*anomaly{a}, current, previous)
+	if want := []string{"grown (+6.00)", "new (+3.00)"}; !reflect.DeepEqual(a.contributors, want) {
+		t.Errorf("contributors = %v, want %v", a.contributors, want)
+	}
+}
+
+func TestBuildAnomalyRow(t *testing.T) {
+	p := testPeriods(t)
+	a := &anomaly{
+		key:          seriesKey{subscriptionID: "00000000-0000-0000-0000-000000000001", service: "Storage", resourceGroup: "rg-data"},
+		kind:         AnomalySpike,
+		costs:        []float64{14, 7, 7, 6},
+		currency:     "USD",
+		contributors: []string{"grown (+6.00)"},
+	}
+
+	row := buildAnomalyRow(a, "Sub One", p, 3, false)
+	want := []string{"00000000-0000-0000-0000-000000000001", "Sub One", "Storage", "rg-data", AnomalySpike, "2026-03-08", "2026-03-14", "14.00", "7.00", "100.00", "7.00", "USD", "7.00 -> 7.00 -> 14.00", "grown (+6.00)"}
+	if !reflect.DeepEqual(row, want) {
+		t.Errorf("row = %v, want %v", row, want)
+	}
+	if got := len(NewScanner().GetMetadata().ColumnMetadata); got != len(row) {
+		t.Errorf("row has %d columns, metadata %d", len(row), got)
+	}
+
+	if row := buildAnomalyRow(a, "Sub One", p, 3, true); row[0] != renderers.MaskSubscriptionID(a.key.subscriptionID, true) || row[0] == a.key.subscriptionID {
+		t.Errorf("masked subscription ID = %q", row[0])
+	}
+
+	a.costs[1] = 0
+	if row := buildAnomalyRow(a, "Sub One", p, 3, false); row[9] != "" {
+		t.Errorf("change without previous cost = %q, want empty", row[9])
+	}
+}
+
+func TestNewSettings(t *testing.T) {
+	cfg, err := newSettings(map[string]any{"months": 6, "period-days": 14, "spike-threshold": 25.0, "min-cost": 0.0, "growth-periods": 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg != (settings{months: 6, periodDays: 14, spikeThreshold: 25, minCost: 0, growthPeriods: 4}) {
+		t.Errorf("settings = %+v", cfg)
+	}
+
+	for _, options := range []map[string]any{
+		{"months": 0},
+		{"months": 13},
+		{"period-days": 0},
+		{"spike-threshold": 0.0},
+		{"min-cost": -1.0},
+		{"growth-periods": 1},
+	} {
+		if _, err := newSettings(options); err == nil || !strings.HasPrefix(err.Error(), "cost-anomalies:") {
+			t.Errorf("newSettings(%v) error = %v", options, err)
+		}
+	}
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/Azure/azqr/internal/plugins"
 	"github.com/Azure/azqr/internal/scanners/plugins/carbon"
+	"github.com/Azure/azqr/internal/scanners/plugins/costanomaly"
 	"github.com/Azure/azqr/internal/scanners/plugins/aigov"
 	regionplugin "github.com/Azure/azqr/internal/scanners/plugins/region"
 	"github.com/Azure/azqr/internal/scanners/plugins/sqleol"
@@ -65,6 +66,10 @@ func TestCarbonPlugin_HeaderRow_MatchesColumnMetadata(t *testing.T) {
 	assertHeaderRowMatchesColumnMetadata(t, carbon.NewScanner())
 }
 
+func TestCostAnomalyPlugin_HeaderRow_MatchesColumnMetadata(t *testing.T) {
+	assertHeaderRowMatchesColumnMetadata(t, costanomaly.NewScanner())
+}
+
 func TestSQLESUPlugin_HeaderRow_MatchesColumnMetadata(t *testing.T) {
 	assertHeaderRowMatchesColumnMetadata(t, sqleol.NewScanner())
 }
@@ -10,6 +10,7 @@ import (
 	"sync"
 
 	"github.com/Azure/azqr/internal/az"
+	"github.com/Azure/azqr/internal/progress"
 	"github.com/Azure/azqr/internal/renderers"
 	"github.com/Azure/azqr/internal/scanners/plugins/region/sku"
 	"github.com/Azure/azqr/internal/scanners/plugins/region/types"
@@ -96,9 +97,11 @@ func CheckRegionsInParallel(ctx context.Context, cred azcore.TokenCredential, ta
 	// Collect results from all workers
 	regionResults := make([]types.RegionComparison, 0, len(regionPairs))
 	processedCount := 0
+	tracker := progress.NewTracker(ctx, "region comparisons", len(regionPairs))
 	for result := range results {
 		regionResults = append(regionResults, result)
 		processedCount++
+		tracker.Step(subscriptionName)
 		if processedCount%10 == 0 {
 			log.Debug().Msgf("Progress: completed %d/%d region pairs", processedCount, len(regionPairs))
 		}
@@ -162,7 +165,7 @@ func fetchAllProviders(ctx context.Context, cred azcore.TokenCredential, subscri
 				if rt.Locations != nil {
 					for _, loc := range rt.Locations {
 						if loc != nil {
-							normalizedLoc := types.NormalizeRegionName(*loc)
+							normalizedLoc := az.NormalizeRegionName(*loc)
 							locationSet[normalizedLoc] = struct{}{}
 						}
 					}
@@ -0,0 +1,185 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package region
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Azure/azqr/internal/az"
+	"github.com/Azure/azqr/internal/plugins"
+	"github.com/Azure/azqr/internal/scanners/plugins/region/types"
+)
+
+// Plugin options of the target region constraints
+const (
+	allowedGeographiesOption  = "allowed-geographies"
+	dataBoundaryOption        = "data-boundary"
+	requirePairedRegionOption = "require-paired-region"
+	minZonesOption            = "min-zones"
+	excludedRegionsOption     = "excluded-regions"
+)
+
+// dataBoundaries maps a data-residency boundary to the regions inside it.
+// "eu" is the EU Data Boundary (EU and EFTA regions); the others are the
+// sovereign clouds.
+var dataBoundaries = map[string]map[string]bool{
+	"eu": setOf(
+		"austriaeast", "belgiumcentral", "denmarkeast", "francecentral", "francesouth",
+		"germanynorth", "germanywestcentral", "italynorth", "northeurope", "norwayeast",
+		"norwaywest", "polandcentral", "spaincentral", "swedencentral", "swedensouth",
+		"switzerlandnorth", "switzerlandwest", "westeurope",
+	),
+	"usgov": setOf(
+		"usgovarizona", "usgovtexas", "usgovvirginia", "usdodcentral", "usdodeast",
+	),
+	"china": setOf(
+		"chinaeast", "chinaeast2", "chinaeast3", "chinanorth", "chinanorth2", "chinanorth3",
+	),
+}
+
+// regionConstraints are the hard constraints a target region must meet.
+// Regions violating any of them are excluded before scoring.
+type regionConstraints struct {
+	allowedGeographies  map[string]bool // lowercase geography, geography group or physical location
+	dataBoundary        string
+	requirePairedRegion bool
+	minZones            int
+	excludedRegions     map[string]bool
+}
+
+// constraintPluginOptions returns the plugin options of the region constraints.
+func constraintPluginOptions() []plugins.PluginOption {
+	return []plugins.PluginOption{
+		{Name: allowedGeographiesOption, Type: "string", Default: "", Description: "Allowed geographies or countries of target regions (comma-separated, e.g., Europe,Sweden)"},
+		{Name: dataBoundaryOption, Type: "string", Default: "", Description: "Required data-residency boundary of target regions (eu, usgov, china)"},
+		{Name: requirePairedRegionOption, Type: "bool", Default: false, Description: "Only consider target regions that have a paired region"},
+		{Name: minZonesOption, Type: "int", Default: 0, Description: "Minimum number of availability zones of target regions"},
+		{Name: excludedRegionsOption, Type: "string", Default: "", Description: "Regions never to consider as targets (comma-separated)"},
+	}
+}
+
+// resolveRegionConstraints reads the region constraints from the plugin options.
+func resolveRegionConstraints(options map[string]any) (regionConstraints, error) {
+	c := regionConstraints{
+		allowedGeographies: map[string]bool{},
+		excludedRegions:    map[string]bool{},
+	}
+	if v, _ := options[allowedGeographiesOption].(string); v != "" {
+		for _, g := range splitList(v) {
+			c.allowedGeographies[strings.ToLower(g)] = true
+		}
+	}
+	if v, _ := options[excludedRegionsOption].(string); v != "" {
+		for _, r := range splitList(v) {
+			c.excludedRegions[az.NormalizeRegionName(r)] = true
+		}
+	}
+	if v, _ := options[dataBoundaryOption].(string); v != "" {
+		c.dataBoundary = strings.ToLower(strings.TrimSpace(v))
+		if _, ok := dataBoundaries[c.dataBoundary]; !ok {
+			return c, fmt.Errorf("unsupported %s %q (supported: eu, usgov, china)", dataBoundaryOption, v)
+		}
+	}
+	c.requirePairedRegion, _ = options[requirePairedRegionOption].(bool)
+	c.minZones, _ = options[minZonesOption].(int)
+	if c.minZones < 0 {
+		return c, fmt.Errorf("%s must not be negative, got %d", minZonesOption, c.minZones)
+	}
+	return c, nil
+}
+
+// isEmpty reports whether no constraint is set.
+func (c regionConstraints) isEmpty() bool {
+	return len(c.allowedGeographies) == 0 && c.dataBoundary == "" && !c.requirePairedRegion &&
+		c.minZones == 0 && len(c.excludedRegions) == 0
+}
+
+// violations returns the reasons a region violates the constraints, or nil
+// when it meets all of them.
+func (c regionConstraints) violations(info types.RegionInfo) []string {
+	var reasons []string
+	if c.excludedRegions[info.Name] {
+		reasons = append(reasons, "Excluded region")
+	}
+	if len(c.allowedGeographies) > 0 &&
+		!c.allowedGeographies[strings.ToLower(info.Geography)] &&
+		!c.allowedGeographies[strings.ToLower(info.GeographyGroup)] &&
+		!c.allowedGeographies[strings.ToLower(info.PhysicalLocation)] {
+		geography := info.Geography
+		if geography == "" {
+			geography = "unknown"
+		}
+		reasons = append(reasons, fmt.Sprintf("Geography %s not allowed", geography))
+	}
+	if c.dataBoundary != "" && !dataBoundaries[c.dataBoundary][info.Name] {
+		reasons = append(reasons, fmt.Sprintf("Outside the %s data boundary", strings.ToUpper(c.dataBoundary)))
+	}
+	if c.requirePairedRegion && len(info.PairedRegions) == 0 {
+		reasons = append(reasons, "No paired region")
+	}
+	if info.ZoneCount < c.minZones {
+		reasons = append(reasons, fmt.Sprintf("%d availability zones (minimum %d)", info.ZoneCount, c.minZones))
+	}
+	return reasons
+}
+
+// filterTargetRegions removes the regions violating the constraints and
+// returns the remaining regions and the reasons of each excluded region.
+func (c regionConstraints) filterTargetRegions(regions []string, regionInfo map[string]types.RegionInfo) ([]string, map[string][]string) {
+	if c.isEmpty() {
+		return regions, nil
+	}
+	kept := make([]string, 0, len(regions))
+	excluded := make(map[string][]string)
+	for _, region := range regions {
+		info, ok := regionInfo[region]
+		if !ok {
+			info = types.RegionInfo{Name: region}
+		}
+		if reasons := c.violations(info); len(reasons) > 0 {
+			excluded[region] = reasons
+			continue
+		}
+		kept = append(kept, region)
+	}
+	return kept, excluded
+}
+
+// excludedRegionRows returns the rows of the Excluded Regions sheet, sorted by region.
+func excludedRegionRows(excluded map[string][]string, regionInfo map[string]types.RegionInfo) [][]string {
+	regions := make([]string, 0, len(excluded))
+	for region := range excluded {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+
+	rows := make([][]string, 0, len(regions))
+	for _, region := range regions {
+		info := regionInfo[region]
+		rows = append(rows, []string{region, info.DisplayName, info.Geography, strings.Join(excluded[region], "; ")})
+	}
+	return rows
+}
+
+// splitList splits a comma-separated option value, dropping empty entries
+func splitList(v string) []string {
+	var out []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// setOf returns a set of the given strings
+func setOf(values ...string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
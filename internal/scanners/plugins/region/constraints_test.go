@@ -0,0 +1,97 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package region
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Azure/azqr/internal/scanners/plugins/region/types"
+)
+
+var testRegionInfo = map[string]types.RegionInfo{
+	"swedencentral": {Name: "swedencentral", DisplayName: "Sweden Central", Geography: "Sweden", GeographyGroup: "Europe", PairedRegions: []string{"swedensouth"}, ZoneCount: 3},
+	"swedensouth":   {Name: "swedensouth", DisplayName: "Sweden South", Geography: "Sweden", GeographyGroup: "Europe", PairedRegions: []string{"swedencentral"}},
+	"westeurope":    {Name: "westeurope", DisplayName: "West Europe", Geography: "Netherlands", GeographyGroup: "Europe", PairedRegions: []string{"northeurope"}, ZoneCount: 3},
+	"uksouth":       {Name: "uksouth", DisplayName: "UK South", Geography: "United Kingdom", GeographyGroup: "Europe", PairedRegions: []string{"ukwest"}, ZoneCount: 3},
+	"eastus":        {Name: "eastus", DisplayName: "East US", Geography: "United States", GeographyGroup: "US", PairedRegions: []string{"westus"}, ZoneCount: 3},
+	"italynorth":    {Name: "italynorth", DisplayName: "Italy North", Geography: "Italy", GeographyGroup: "Europe", ZoneCount: 3},
+}
+
+var testRegions = []string{"eastus", "italynorth", "swedencentral", "swedensouth", "uksouth", "westeurope"}
+
+func TestResolveRegionConstraints(t *testing.T) {
+	c, err := resolveRegionConstraints(map[string]any{
+		allowedGeographiesOption:  "Europe, United States",
+		dataBoundaryOption:        "EU",
+		requirePairedRegionOption: true,
+		minZonesOption:            2,
+		excludedRegionsOption:     "West Europe,",
+	})
+	if err != nil {
+		t.Fatalf("resolveRegionConstraints: %v", err)
+	}
+	if !c.allowedGeographies["europe"] || !c.allowedGeographies["united states"] || len(c.allowedGeographies) != 2 {
+		t.Errorf("allowedGeographies = %v", c.allowedGeographies)
+	}
+	if c.dataBoundary != "eu" || !c.requirePairedRegion || c.minZones != 2 || !c.excludedRegions["westeurope"] {
+		t.Errorf("constraints = %+v", c)
+	}
+
+	if _, err := resolveRegionConstraints(map[string]any{dataBoundaryOption: "mars"}); err == nil {
+		t.Error("expected an error for an unknown data boundary")
+	}
+	if _, err := resolveRegionConstraints(map[string]any{minZonesOption: -1}); err == nil {
+		t.Error("expected an error for a negative zone count")
+	}
+	if c, err := resolveRegionConstraints(map[string]any{}); err != nil || !c.isEmpty() {
+		t.Errorf("empty options = %+v, %v; want no constraints", c, err)
+	}
+}
+
+func TestFilterTargetRegions(t *testing.T) {
+	tests := []struct {
+		name        string
+		constraints regionConstraints
+		want        []string
+	}{
+		{"none", regionConstraints{}, testRegions},
+		{"geography group", regionConstraints{allowedGeographies: setOf("us")}, []string{"eastus"}},
+		{"country", regionConstraints{allowedGeographies: setOf("sweden")}, []string{"swedencentral", "swedensouth"}},
+		{"eu data boundary", regionConstraints{dataBoundary: "eu"}, []string{"italynorth", "swedencentral", "swedensouth", "westeurope"}},
+		{"paired region", regionConstraints{requirePairedRegion: true}, []string{"eastus", "swedencentral", "swedensouth", "uksouth", "westeurope"}},
+		{"min zones", regionConstraints{minZones: 3}, []string{"eastus", "italynorth", "swedencentral", "uksouth", "westeurope"}},
+		{"excluded", regionConstraints{excludedRegions: setOf("eastus", "uksouth")}, []string{"italynorth", "swedencentral", "swedensouth", "westeurope"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, excluded := tt.constraints.filterTargetRegions(testRegions, testRegionInfo)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("kept = %v, want %v", got, tt.want)
+			}
+			if len(got)+len(excluded) != len(testRegions) {
+				t.Errorf("kept %d + excluded %d != %d regions", len(got), len(excluded), len(testRegions))
+			}
+		})
+	}
+}
+
+func TestRegionConstraints_Reasons(t *testing.T) {
+	c := regionConstraints{dataBoundary: "eu", minZones: 3, excludedRegions: setOf("uksouth")}
+	_, excluded := c.filterTargetRegions([]string{"uksouth", "swedensouth", "unknownregion"}, testRegionInfo)
+
+	want := map[string][]string{
+		"uksouth":       {"Excluded region", "Outside the EU data boundary"},
+		"swedensouth":   {"0 availability zones (minimum 3)"},
+		"unknownregion": {"Outside the EU data boundary", "0 availability zones (minimum 3)"},
+	}
+	if !reflect.DeepEqual(excluded, want) {
+		t.Errorf("excluded = %v, want %v", excluded, want)
+	}
+
+	rows := excludedRegionRows(excluded, testRegionInfo)
+	if len(rows) != 3 || rows[0][0] != "swedensouth" || rows[1][1] != "UK South" || rows[1][3] != "Excluded region; Outside the EU data boundary" {
+		t.Errorf("rows = %v", rows)
+	}
+}
@@ -77,13 +77,13 @@ func ApplyCostDiffs(results []types.RegionComparison, subMeterCosts []types.Mete
 	}
 
 	for i := range results {
-		targetRegion := types.NormalizeRegionName(results[i].TargetRegion)
-		sourceRegion := types.NormalizeRegionName(results[i].SourceRegion)
+		targetRegion := az.NormalizeRegionName(results[i].TargetRegion)
+		sourceRegion := az.NormalizeRegionName(results[i].SourceRegion)
 
-		if !types.IsPhysicalRegion(sourceRegion) {
+		if !az.IsPhysicalRegion(sourceRegion) {
 			continue
 		}
-		if !types.IsPhysicalRegion(targetRegion) {
+		if !az.IsPhysicalRegion(targetRegion) {
 			continue
 		}
 
@@ -159,8 +159,8 @@ func EnrichWithCostData(ctx context.Context, cred azcore.TokenCredential, httpCl
 
 	regionsMap := make(map[string]bool)
 	for _, r := range results {
-		regionsMap[types.NormalizeRegionName(r.SourceRegion)] = true
-		regionsMap[types.NormalizeRegionName(r.TargetRegion)] = true
+		regionsMap[az.NormalizeRegionName(r.SourceRegion)] = true
+		regionsMap[az.NormalizeRegionName(r.TargetRegion)] = true
 	}
 
 	shared := BuildRetailPricing(ctx, httpClient, meterCosts, regionsMap)
@@ -372,7 +372,11 @@ func parseCostManagementRows(properties *armcostmanagement.QueryProperties, subs
 			continue
 		}
 
-		meterID := to.String(row[resourceGuidIdx])
+		meterID, err := to.String(row[resourceGuidIdx])
+		if err != nil {
+			log.Warn().Err(err).Msg("Skipping Cost Management row with an invalid ResourceGuid")
+			continue
+		}
 		cost := to.Float(row[costIdx])
 		if meterID == "" || cost <= 0 {
 			continue
@@ -580,7 +584,7 @@ func getMeterPricingAcrossRegions(ctx context.Context, meters []types.MeterCostD
 							continue
 						}
 						// Filter to only relevant regions in-memory.
-						region := types.NormalizeRegionName(item.ArmRegionName)
+						region := az.NormalizeRegionName(item.ArmRegionName)
 						if len(relevantRegions) > 0 && !relevantRegions[region] {
 							continue
 						}
@@ -686,7 +690,7 @@ func BuildCostDetailsForOutput(meterMetadata []types.MeterCostData, meterPricing
 	// Create a map of original meter IDs to their regions for marking
 	origMeterRegions := make(map[string]string)
 	for _, meter := range meterMetadata {
-		origMeterRegions[meter.MeterID] = types.NormalizeRegionName(meter.ArmRegionName)
+		origMeterRegions[meter.MeterID] = az.NormalizeRegionName(meter.ArmRegionName)
 	}
 
 	// Process all price items
@@ -706,7 +710,7 @@ func BuildCostDetailsForOutput(meterMetadata []types.MeterCostData, meterPricing
 
 		// Check if this is the original region
 		origRegionMarker := ""
-		itemRegion := types.NormalizeRegionName(item.ArmRegionName)
+		itemRegion := az.NormalizeRegionName(item.ArmRegionName)
 		if origReg, ok := origMeterRegions[origMeterID]; ok && origReg == itemRegion {
 			origRegionMarker = "X"
 		}
@@ -788,7 +792,7 @@ func BuildCostDetailsForOutput(meterMetadata []types.MeterCostData, meterPricing
 	for _, meter := range meterMetadata {
 		if pricing, ok := meterPricing[meter.MeterID]; ok {
 			// Get original region price
-			origRegion := types.NormalizeRegionName(meter.ArmRegionName)
+			origRegion := az.NormalizeRegionName(meter.ArmRegionName)
 			origPrice, hasOrigPrice := pricing[origRegion]
 			if !hasOrigPrice {
 				continue
@@ -0,0 +1,69 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package cost
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/Azure/azqr/internal/az"
+	"github.com/Azure/azqr/internal/scanners/plugins/region/types"
+)
+
+// HoursPerMonth is the number of hours the Azure pricing calculator uses for a month.
+const HoursPerMonth = 730
+
+// retailPricesURL is the endpoint of the Azure Retail Prices API
+const retailPricesURL = "https://prices.azure.com/api/retail/prices"
+
+// FetchRetailPrices returns the USD consumption prices matching the filters,
+// given as Retail Prices API field names and values (e.g., serviceName,
+// armRegionName, meterName), following the pages of the response.
+func FetchRetailPrices(ctx context.Context, httpClient *az.HttpClient, filters map[string]string) ([]types.RetailPriceItem, error) {
+	fields := make([]string, 0, len(filters))
+	for field := range filters {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	conditions := []string{"currencyCode eq 'USD'", "type eq 'Consumption'"}
+	for _, field := range fields {
+		conditions = append(conditions, fmt.Sprintf("%s eq '%s'", field, odataEscape(filters[field])))
+	}
+	pageURL := retailPricesURL + "?$filter=" + url.QueryEscape(strings.Join(conditions, " and "))
+
+	var items []types.RetailPriceItem
+	for pageURL != "" {
+		body, err := httpClient.Do(ctx, pageURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query retail prices: %w", err)
+		}
+		var page types.RetailPriceResponse
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("failed to parse retail prices: %w", err)
+		}
+		items = append(items, page.Items...)
+		pageURL = page.NextPageLink
+	}
+	return items, nil
+}
+
+// MonthlyRetailPrice returns the monthly price of a retail price item billed
+// per hour, day or month, and false for other units of measure.
+func MonthlyRetailPrice(item types.RetailPriceItem) (float64, bool) {
+	switch strings.ToLower(strings.TrimSpace(item.UnitOfMeasure)) {
+	case "1 hour", "1/hour":
+		return item.RetailPrice * HoursPerMonth, true
+	case "1 day", "1/day":
+		return item.RetailPrice * HoursPerMonth / 24, true
+	case "1 month", "1/month":
+		return item.RetailPrice, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package cost
+
+import (
+	"testing"
+
+	"github.com/Azure/azqr/internal/scanners/plugins/region/types"
+)
+
+func TestMonthlyRetailPrice(t *testing.T) {
+	tests := []struct {
+		unit   string
+		price  float64
+		want   float64
+		wantOK bool
+	}{
+		{"1 Hour", 0.005, 3.65, true},
+		{"1/Day", 2.4, 73, true},
+		{"1/Month", 19.71, 19.71, true},
+		{"1 GB", 0.02, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := MonthlyRetailPrice(types.RetailPriceItem{UnitOfMeasure: tt.unit, RetailPrice: tt.price})
+		if ok != tt.wantOK || diff(got, tt.want) > 1e-9 {
+			t.Errorf("MonthlyRetailPrice(%q, %v) = %v, %v, want %v, %v", tt.unit, tt.price, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func diff(a, b float64) float64 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
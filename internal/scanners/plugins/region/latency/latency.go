@@ -4,6 +4,10 @@
 package latency
 
 import (
+	"slices"
+	"strings"
+
+	"github.com/Azure/azqr/internal/az"
 	"github.com/Azure/azqr/internal/scanners/plugins/region/types"
 	"github.com/rs/zerolog/log"
 )
@@ -52,18 +56,54 @@ func computeClusterAverages(matrix map[string]map[string]float64) map[string]flo
 	return averages
 }
 
-// EnrichWithLatencyData populates avgLatencyMs and latencyEstimated on each result.
-func EnrichWithLatencyData(results []types.RegionComparison) {
+// EnrichWithLatencyData populates avgLatencyMs, latencyEstimated and latencySource on each result.
+// User-supplied overrides, when not nil, take precedence over the published statistics:
+// with user locations, the latency of a target is the weighted average latency from
+// those locations, and targets without a user measurement get no latency (their
+// source is SourceNoUserMeasurement); otherwise overridden region pairs replace the
+// published values.
+func EnrichWithLatencyData(results []types.RegionComparison, overrides *Overrides) {
 	log.Debug().Msg("Starting latency calculation using Azure published RTT statistics")
 
 	var unknownPairs, estimatedPairs int
+	var unmeasured []string
 	for i := range results {
-		sourceRegion := types.NormalizeRegionName(results[i].SourceRegion)
-		targetRegion := types.NormalizeRegionName(results[i].TargetRegion)
+		sourceRegion := az.NormalizeRegionName(results[i].SourceRegion)
+		targetRegion := az.NormalizeRegionName(results[i].TargetRegion)
+
+		if latency, ok := overrides.userLatency(targetRegion); ok {
+			results[i].AvgLatencyMs = latency
+			results[i].LatencyEstimated = false
+			results[i].LatencySource = SourceUserLocations
+			log.Debug().Msgf("Latency from user locations to %s: %.1f ms (override)", targetRegion, latency)
+			continue
+		}
+		if overrides.hasUserLocations() {
+			results[i].AvgLatencyMs = 0
+			results[i].LatencyEstimated = false
+			results[i].LatencySource = SourceNoUserMeasurement
+			if !slices.Contains(unmeasured, targetRegion) {
+				unmeasured = append(unmeasured, targetRegion)
+			}
+			continue
+		}
+		if latency, ok := overrides.pairLatency(sourceRegion, targetRegion); ok && sourceRegion != targetRegion {
+			results[i].AvgLatencyMs = latency
+			results[i].LatencyEstimated = false
+			results[i].LatencySource = SourceOverride
+			log.Debug().Msgf("Latency from %s to %s: %.1f ms (override)", sourceRegion, targetRegion, latency)
+			continue
+		}
 
 		latency, estimated := getRegionLatency(sourceRegion, targetRegion)
 		results[i].AvgLatencyMs = latency
 		results[i].LatencyEstimated = estimated
+		switch {
+		case estimated:
+			results[i].LatencySource = SourceEstimated
+		case latency > 0:
+			results[i].LatencySource = SourcePublished
+		}
 
 		switch {
 		case latency == 0 && sourceRegion != targetRegion:
@@ -80,6 +120,10 @@ func EnrichWithLatencyData(results []types.RegionComparison) {
 		log.Warn().Msgf("Direct latency data missing for %d region pair(s) — using cluster-based estimates (shown as 'X.X (est.)' in table)",
 			estimatedPairs)
 	}
+	if len(unmeasured) > 0 {
+		log.Warn().Msgf("No latency from the user locations to %d target region(s): %s — shown as 'N/A' with a midpoint latency score; add measurements to the latency file to compare them",
+			len(unmeasured), strings.Join(unmeasured, ", "))
+	}
 	if unknownPairs > 0 {
 		log.Warn().Msgf("No latency data or estimate for %d region pair(s) — shown as 'N/A', scored as neutral",
 			unknownPairs)
@@ -6,6 +6,7 @@ package latency
 import (
 	"testing"
 
+	"github.com/Azure/azqr/internal/az"
 	"github.com/Azure/azqr/internal/scanners/plugins/region/types"
 )
 
@@ -30,7 +31,7 @@ func TestEnrichWithLatencyData_KnownPair(t *testing.T) {
 	results := []types.RegionComparison{
 		{SourceRegion: "eastus", TargetRegion: "westeurope"},
 	}
-	EnrichWithLatencyData(results)
+	EnrichWithLatencyData(results, nil)
 	if results[0].AvgLatencyMs != 85.0 {
 		t.Errorf("expected 85.0 ms, got %.1f", results[0].AvgLatencyMs)
 	}
@@ -45,7 +46,7 @@ func TestEnrichWithLatencyData_SymmetricFallback(t *testing.T) {
 	results := []types.RegionComparison{
 		{SourceRegion: "eastus", TargetRegion: "westeurope"},
 	}
-	EnrichWithLatencyData(results)
+	EnrichWithLatencyData(results, nil)
 	if results[0].AvgLatencyMs != 85.0 {
 		t.Errorf("expected symmetric fallback 85.0 ms, got %.1f", results[0].AvgLatencyMs)
 	}
@@ -57,7 +58,7 @@ func TestEnrichWithLatencyData_SameRegion(t *testing.T) {
 	results := []types.RegionComparison{
 		{SourceRegion: "eastus", TargetRegion: "eastus"},
 	}
-	EnrichWithLatencyData(results)
+	EnrichWithLatencyData(results, nil)
 	if results[0].AvgLatencyMs != 0 {
 		t.Errorf("expected 0 ms for same region, got %.1f", results[0].AvgLatencyMs)
 	}
@@ -69,7 +70,7 @@ func TestEnrichWithLatencyData_UnknownPair(t *testing.T) {
 	results := []types.RegionComparison{
 		{SourceRegion: "eastus", TargetRegion: "brazilsouth"},
 	}
-	EnrichWithLatencyData(results)
+	EnrichWithLatencyData(results, nil)
 	if results[0].AvgLatencyMs != 0 {
 		t.Errorf("expected 0 ms (N/A) for unknown pair, got %.1f", results[0].AvgLatencyMs)
 	}
@@ -91,7 +92,7 @@ func TestEnrichWithLatencyData_MultipleResults(t *testing.T) {
 		{SourceRegion: "eastus", TargetRegion: "swedencentral"},
 		{SourceRegion: "eastus", TargetRegion: "unknown"},
 	}
-	EnrichWithLatencyData(results)
+	EnrichWithLatencyData(results, nil)
 
 	if results[0].AvgLatencyMs != 85.0 {
 		t.Errorf("row 0: expected 85.0, got %.1f", results[0].AvgLatencyMs)
@@ -113,7 +114,7 @@ func TestEnrichWithLatencyData_RegionNamesNormalized(t *testing.T) {
 	results := []types.RegionComparison{
 		{SourceRegion: "East US", TargetRegion: "West Europe"},
 	}
-	EnrichWithLatencyData(results)
+	EnrichWithLatencyData(results, nil)
 	if results[0].AvgLatencyMs != 85.0 {
 		t.Errorf("expected normalization to yield 85.0 ms, got %.1f", results[0].AvgLatencyMs)
 	}
@@ -167,7 +168,7 @@ func TestEnrichWithLatencyData_ClusterEstimate(t *testing.T) {
 	results := []types.RegionComparison{
 		{SourceRegion: "westus", TargetRegion: "francesouth"}, // americas→europe, not directly measured
 	}
-	EnrichWithLatencyData(results)
+	EnrichWithLatencyData(results, nil)
 
 	if results[0].AvgLatencyMs != 100.0 {
 		t.Errorf("expected cluster estimate 100.0 ms, got %.1f", results[0].AvgLatencyMs)
@@ -192,7 +193,7 @@ func TestNormalizeRegionName(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.in, func(t *testing.T) {
-			if got := types.NormalizeRegionName(tt.in); got != tt.want {
+			if got := az.NormalizeRegionName(tt.in); got != tt.want {
 				t.Errorf("normalizeRegionName(%q) = %q, want %q", tt.in, got, tt.want)
 			}
 		})
@@ -0,0 +1,249 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package latency
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/azqr/internal/az"
+)
+
+// Origins of a latency value, reported in RegionComparison.LatencySource
+const (
+	SourcePublished     = "Published"
+	SourceEstimated     = "Estimated"
+	SourceOverride      = "Override"
+	SourceUserLocations = "User Locations"
+	// SourceNoUserMeasurement marks targets without a measurement from the
+	// user locations. Region to region latency is not comparable with user
+	// latency, so these targets get no latency value.
+	SourceNoUserMeasurement = "No User Measurement"
+)
+
+// OverrideEntry is one user-supplied latency measurement. Source or Target may
+// be a user location (e.g. an office or on-premises datacenter) that is not an
+// Azure region.
+type OverrideEntry struct {
+	Source    string  `json:"source"`
+	Target    string  `json:"target"`
+	LatencyMs float64 `json:"latencyMs"`
+	// Weight is the relative importance of a user location (e.g. its number of
+	// users). Ignored for region pairs. Defaults to 1.
+	Weight float64 `json:"weight,omitempty"`
+}
+
+// userLocation holds the measured latency from one user location to Azure regions.
+type userLocation struct {
+	name    string
+	weight  float64
+	targets map[string]float64 // normalized region → ms
+}
+
+// Overrides holds user-supplied latency measurements. Region pairs take
+// precedence over the published matrix; when user locations are present, the
+// latency of a target region is the weighted average latency from the user
+// locations instead of the region-to-region latency.
+type Overrides struct {
+	pairs map[string]map[string]float64 // normalized source → target → ms
+	users map[string]*userLocation      // normalized name → location
+}
+
+// LoadOverrides reads a latency override file. Files ending in .json hold an
+// array of OverrideEntry objects; other files are CSV with a header row of
+// source, target, latency_ms and an optional weight column.
+func LoadOverrides(path string) (*Overrides, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read latency file: %w", err)
+	}
+
+	var entries []OverrideEntry
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return nil, fmt.Errorf("failed to parse latency file %s: %w", path, err)
+		}
+	} else {
+		entries, err = parseOverridesCSV(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse latency file %s: %w", path, err)
+		}
+	}
+
+	overrides, err := NewOverrides(entries)
+	if err != nil {
+		return nil, fmt.Errorf("latency file %s: %w", path, err)
+	}
+	return overrides, nil
+}
+
+// parseOverridesCSV reads override entries from CSV. Columns are matched by
+// header name, case-insensitively.
+func parseOverridesCSV(r io.Reader) ([]OverrideEntry, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	columns := map[string]int{}
+	for i, h := range records[0] {
+		name := strings.ToLower(strings.TrimSpace(h))
+		switch name {
+		case "latency", "latencyms", "latency_ms", "latency (ms)":
+			name = "latency_ms"
+		}
+		columns[name] = i
+	}
+	for _, required := range []string{"source", "target", "latency_ms"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("missing %q column", required)
+		}
+	}
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	entries := make([]OverrideEntry, 0, len(records)-1)
+	for n, record := range records[1:] {
+		line := n + 2
+		entry := OverrideEntry{Source: field(record, "source"), Target: field(record, "target")}
+		if entry.LatencyMs, err = strconv.ParseFloat(field(record, "latency_ms"), 64); err != nil {
+			return nil, fmt.Errorf("line %d: invalid latency %q", line, field(record, "latency_ms"))
+		}
+		if w := field(record, "weight"); w != "" {
+			if entry.Weight, err = strconv.ParseFloat(w, 64); err != nil {
+				return nil, fmt.Errorf("line %d: invalid weight %q", line, w)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// NewOverrides builds the overrides from a list of measurements. An entry
+// whose source (or target) is not a known Azure region is a user location.
+func NewOverrides(entries []OverrideEntry) (*Overrides, error) {
+	o := &Overrides{
+		pairs: make(map[string]map[string]float64),
+		users: make(map[string]*userLocation),
+	}
+	for i, e := range entries {
+		source := az.NormalizeRegionName(strings.TrimSpace(e.Source))
+		target := az.NormalizeRegionName(strings.TrimSpace(e.Target))
+		if source == "" || target == "" {
+			return nil, fmt.Errorf("entry %d: source and target are required", i+1)
+		}
+		if e.LatencyMs <= 0 {
+			return nil, fmt.Errorf("entry %d (%s → %s): latency must be positive, got %g", i+1, e.Source, e.Target, e.LatencyMs)
+		}
+		if e.Weight < 0 {
+			return nil, fmt.Errorf("entry %d (%s → %s): weight must not be negative, got %g", i+1, e.Source, e.Target, e.Weight)
+		}
+
+		sourceIsRegion, targetIsRegion := isAzureRegion(source), isAzureRegion(target)
+		if sourceIsRegion == targetIsRegion {
+			if o.pairs[source] == nil {
+				o.pairs[source] = make(map[string]float64)
+			}
+			o.pairs[source][target] = e.LatencyMs
+			continue
+		}
+
+		name := strings.TrimSpace(e.Source)
+		if sourceIsRegion {
+			// region → user location: RTT is near-symmetric
+			source, target = target, source
+			name = strings.TrimSpace(e.Target)
+		}
+
+		loc := o.users[source]
+		if loc == nil {
+			loc = &userLocation{name: name, weight: 1, targets: make(map[string]float64)}
+			o.users[source] = loc
+		}
+		if e.Weight > 0 {
+			loc.weight = e.Weight
+		}
+		loc.targets[target] = e.LatencyMs
+	}
+	return o, nil
+}
+
+// UserLocations returns the names of the user locations, sorted.
+func (o *Overrides) UserLocations() []string {
+	if o == nil {
+		return nil
+	}
+	names := make([]string, 0, len(o.users))
+	for _, loc := range o.users {
+		names = append(names, loc.name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// hasUserLocations reports whether latency is measured from user locations.
+func (o *Overrides) hasUserLocations() bool {
+	return o != nil && len(o.users) > 0
+}
+
+// pairLatency returns the overridden latency of a region pair, in either direction.
+func (o *Overrides) pairLatency(source, target string) (float64, bool) {
+	if o == nil {
+		return 0, false
+	}
+	if ms, ok := o.pairs[source][target]; ok {
+		return ms, true
+	}
+	if ms, ok := o.pairs[target][source]; ok {
+		return ms, true
+	}
+	return 0, false
+}
+
+// userLatency returns the weighted average latency from the user locations to
+// a target region. Locations without a measurement for the target are skipped.
+func (o *Overrides) userLatency(target string) (float64, bool) {
+	if o == nil {
+		return 0, false
+	}
+	var sum, weights float64
+	for _, loc := range o.users {
+		if ms, ok := loc.targets[target]; ok {
+			sum += ms * loc.weight
+			weights += loc.weight
+		}
+	}
+	if weights == 0 {
+		return 0, false
+	}
+	return sum / weights, true
+}
+
+// isAzureRegion reports whether name is an Azure region known to the latency data
+func isAzureRegion(name string) bool {
+	if _, ok := azureRegionLatency[name]; ok {
+		return true
+	}
+	_, ok := regionCluster[name]
+	return ok
+}
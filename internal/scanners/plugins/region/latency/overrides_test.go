@@ -0,0 +1,130 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package latency
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azqr/internal/scanners/plugins/region/types"
+)
+
+func TestParseOverridesCSV(t *testing.T) {
+	entries, err := parseOverridesCSV(strings.NewReader("Source,Target,Latency (ms),Weight\neastus,westeurope,80\nContoso HQ, swedencentral, 12.5, 200\n"))
+	if err != nil {
+		t.Fatalf("parseOverridesCSV: %v", err)
+	}
+	want := []OverrideEntry{
+		{Source: "eastus", Target: "westeurope", LatencyMs: 80},
+		{Source: "Contoso HQ", Target: "swedencentral", LatencyMs: 12.5, Weight: 200},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("entries = %+v, want %+v", entries, want)
+	}
+
+	if _, err := parseOverridesCSV(strings.NewReader("source,target\neastus,westeurope\n")); err == nil {
+		t.Error("expected an error for a missing latency column")
+	}
+	if _, err := parseOverridesCSV(strings.NewReader("source,target,latency_ms\neastus,westeurope,fast\n")); err == nil {
+		t.Error("expected an error for a non-numeric latency")
+	}
+}
+
+func TestNewOverrides_Validation(t *testing.T) {
+	if _, err := NewOverrides([]OverrideEntry{{Source: "eastus", Target: "westeurope", LatencyMs: 0}}); err == nil {
+		t.Error("expected an error for a zero latency")
+	}
+	if _, err := NewOverrides([]OverrideEntry{{Source: "", Target: "westeurope", LatencyMs: 10}}); err == nil {
+		t.Error("expected an error for a missing source")
+	}
+	if _, err := NewOverrides([]OverrideEntry{{Source: "Office", Target: "westeurope", LatencyMs: 10, Weight: -1}}); err == nil {
+		t.Error("expected an error for a negative weight")
+	}
+}
+
+func TestEnrichWithLatencyData_PairOverride(t *testing.T) {
+	defer setTestLatencyMatrix(map[string]map[string]float64{
+		"eastus":     {"westeurope": 85.0, "northeurope": 75.0},
+		"westeurope": {"northeurope": 20.0},
+	})()
+
+	overrides, err := NewOverrides([]OverrideEntry{{Source: "westeurope", Target: "eastus", LatencyMs: 92}})
+	if err != nil {
+		t.Fatalf("NewOverrides: %v", err)
+	}
+	results := []types.RegionComparison{
+		{SourceRegion: "eastus", TargetRegion: "westeurope"},
+		{SourceRegion: "eastus", TargetRegion: "northeurope"},
+	}
+	EnrichWithLatencyData(results, overrides)
+
+	if results[0].AvgLatencyMs != 92 || results[0].LatencySource != SourceOverride {
+		t.Errorf("overridden pair = %.1f ms (%s), want 92 ms (Override)", results[0].AvgLatencyMs, results[0].LatencySource)
+	}
+	if results[1].AvgLatencyMs != 75 || results[1].LatencySource != SourcePublished {
+		t.Errorf("published pair = %.1f ms (%s), want 75 ms (Published)", results[1].AvgLatencyMs, results[1].LatencySource)
+	}
+}
+
+func TestEnrichWithLatencyData_UserLocations(t *testing.T) {
+	defer setTestLatencyMatrix(map[string]map[string]float64{
+		"eastus":     {"westeurope": 85.0, "northeurope": 75.0},
+		"westeurope": {"northeurope": 20.0},
+	})()
+
+	overrides, err := NewOverrides([]OverrideEntry{
+		{Source: "Amsterdam Office", Target: "westeurope", LatencyMs: 10, Weight: 3},
+		{Source: "New York Office", Target: "westeurope", LatencyMs: 90},
+		// Reverse direction: region → user location
+		{Source: "northeurope", Target: "Amsterdam Office", LatencyMs: 30},
+	})
+	if err != nil {
+		t.Fatalf("NewOverrides: %v", err)
+	}
+	if got := overrides.UserLocations(); !reflect.DeepEqual(got, []string{"Amsterdam Office", "New York Office"}) {
+		t.Errorf("UserLocations = %v", got)
+	}
+
+	results := []types.RegionComparison{
+		{SourceRegion: "eastus", TargetRegion: "westeurope"},
+		{SourceRegion: "eastus", TargetRegion: "northeurope"},
+		{SourceRegion: "westeurope", TargetRegion: "eastus"},
+	}
+	EnrichWithLatencyData(results, overrides)
+
+	// (10×3 + 90×1) / 4 = 30
+	if results[0].AvgLatencyMs != 30 || results[0].LatencySource != SourceUserLocations {
+		t.Errorf("westeurope = %.1f ms (%s), want 30 ms (User Locations)", results[0].AvgLatencyMs, results[0].LatencySource)
+	}
+	if results[1].AvgLatencyMs != 30 || results[1].LatencySource != SourceUserLocations {
+		t.Errorf("northeurope = %.1f ms (%s), want 30 ms (User Locations)", results[1].AvgLatencyMs, results[1].LatencySource)
+	}
+	// No user measurement for eastus: region to region latency is not
+	// comparable, so it gets no latency rather than the published pair.
+	if results[2].AvgLatencyMs != 0 || results[2].LatencySource != SourceNoUserMeasurement {
+		t.Errorf("eastus = %.1f ms (%s), want 0 ms (No User Measurement)", results[2].AvgLatencyMs, results[2].LatencySource)
+	}
+}
+
+func TestLoadOverrides_JSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "latency.json")
+	data := `[{"source": "On-prem DC", "target": "swedencentral", "latencyMs": 8, "weight": 2}]`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	overrides, err := LoadOverrides(path)
+	if err != nil {
+		t.Fatalf("LoadOverrides: %v", err)
+	}
+	if ms, ok := overrides.userLatency("swedencentral"); !ok || ms != 8 {
+		t.Errorf("userLatency = %.1f, %v; want 8", ms, ok)
+	}
+
+	if _, err := LoadOverrides(filepath.Join(t.TempDir(), "missing.csv")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
@@ -0,0 +1,369 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package region
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Azure/azqr/internal/az"
+	"github.com/Azure/azqr/internal/models"
+	"github.com/Azure/azqr/internal/quota"
+	"github.com/Azure/azqr/internal/scanners/plugins/region/output"
+	"github.com/Azure/azqr/internal/scanners/plugins/region/sku"
+	"github.com/Azure/azqr/internal/scanners/plugins/region/types"
+	"github.com/Azure/azqr/internal/skus"
+	"github.com/rs/zerolog/log"
+)
+
+// migrationTargetOption is the plugin option selecting the Migration Plan target region
+const migrationTargetOption = "migration-target-region"
+
+// Availability states shown in the Type Status and SKU Status columns
+const (
+	availAvailable      = "Available"
+	availUnavailable    = "Unavailable"
+	availRestricted     = "Restricted"
+	availZoneRestricted = "Zone Restricted"
+	availUnknown        = "Unknown"
+	availNotApplicable  = "N/A"
+)
+
+// Values of the Migration Status column
+const (
+	migrationReady          = "Ready"
+	migrationActionRequired = "Action Required"
+	migrationBlocked        = "Blocked"
+	migrationReview         = "Review"
+	migrationInTarget       = "Already in Target"
+)
+
+// substituteCandidates is the number of FindAlternatives results checked for a VM substitute
+const substituteCandidates = 10
+
+// vmResourceTypes are the resource types whose SKUs are VM sizes
+var vmResourceTypes = map[string]bool{
+	"microsoft.compute/virtualmachines":         true,
+	"microsoft.compute/virtualmachinescalesets": true,
+}
+
+// migrationQuota holds the target-region quota of one subscription.
+type migrationQuota struct {
+	vm      []quota.UsageEntry
+	network []quota.UsageEntry
+	storage []quota.UsageEntry
+	sql     []quota.UsageEntry
+}
+
+// selectMigrationTarget returns the target region of the Migration Plan: the
+// requested region when it was analysed, otherwise the top-scored target.
+// results must be sorted by score. It returns false when the requested region
+// was not analysed.
+func selectMigrationTarget(requested string, results []types.RegionComparison) (string, bool) {
+	if requested == "" {
+		if len(results) == 0 {
+			return "", false
+		}
+		return results[0].TargetRegion, true
+	}
+	requested = az.NormalizeRegionName(requested)
+	for _, r := range results {
+		if r.TargetRegion == requested {
+			return requested, true
+		}
+	}
+	return "", false
+}
+
+// buildMigrationPlan returns one Migration Plan row per resource for the given
+// target region, using the availability results, the target-region quota of
+// each subscription and the shared retail pricing.
+func (s *RegionSelectorScanner) buildMigrationPlan(
+	ctx context.Context,
+	targetRegion string,
+	resources []*models.Resource,
+	results []types.RegionComparison,
+	quotaBySub map[string]migrationQuota,
+	costData *types.CostComparisonData,
+) []output.MigrationPlanRow {
+	comparisons := make(map[string]*types.RegionComparison)
+	for i := range results {
+		if results[i].TargetRegion == targetRegion {
+			comparisons[results[i].SubscriptionID+"|"+results[i].SourceRegion] = &results[i]
+		}
+	}
+
+	rows := make([]output.MigrationPlanRow, 0, len(resources))
+	for _, r := range resources {
+		resourceType := strings.ToLower(r.Type)
+		source := az.NormalizeRegionName(r.Location)
+		row := output.MigrationPlanRow{
+			ResourceID:     r.ID,
+			SubscriptionID: r.SubscriptionID,
+			ResourceGroup:  r.ResourceGroup,
+			ResourceName:   r.Name,
+			ResourceType:   r.Type,
+			SourceRegion:   r.Location,
+			TargetRegion:   targetRegion,
+			SKU:            r.SkuName,
+		}
+
+		if source == targetRegion {
+			row.TypeStatus = availNotApplicable
+			row.SKUStatus = availNotApplicable
+			row.Status = migrationInTarget
+			rows = append(rows, row)
+			continue
+		}
+
+		comparison, ok := comparisons[r.SubscriptionID+"|"+source]
+		if !ok {
+			row.TypeStatus = availUnknown
+			row.SKUStatus = availUnknown
+			row.Status = migrationReview
+			row.Notes = "Source region was not analysed"
+			rows = append(rows, row)
+			continue
+		}
+
+		row.TypeStatus = availAvailable
+		if slices.Contains(comparison.MissingResourceTypes, resourceType) {
+			row.TypeStatus = availUnavailable
+		}
+		row.SKUStatus = skuStatus(comparison, resourceType, r.SkuName)
+
+		if row.SKUStatus == availUnavailable || row.SKUStatus == availRestricted {
+			row.SubstituteSKU = s.findSubstituteSKU(ctx, r.SubscriptionID, resourceType, r.SkuName, targetRegion)
+		}
+
+		// Quota is checked for the SKU that would be deployed.
+		deployedSKU := r.SkuName
+		if row.SubstituteSKU != "" {
+			deployedSKU = row.SubstituteSKU
+		}
+		required := 0
+		if entry, need, ok := quotaForResource(resourceType, deployedSKU, r.SkuCapacity, quotaBySub[r.SubscriptionID]); ok {
+			row.QuotaName = entry.LocalizedName
+			row.QuotaAvailable = entry.Available
+			row.QuotaLimit = entry.Limit
+			row.HeadroomPct = entry.HeadroomPct
+			row.HasQuota = true
+			required = need
+		}
+
+		row.CostDiffPct, row.HasCost = skuCostDifference(costData, r.SkuName, source, targetRegion)
+
+		row.Status, row.Notes = migrationStatus(row, required)
+		rows = append(rows, row)
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].SubscriptionID != rows[j].SubscriptionID {
+			return rows[i].SubscriptionID < rows[j].SubscriptionID
+		}
+		if rows[i].ResourceGroup != rows[j].ResourceGroup {
+			return rows[i].ResourceGroup < rows[j].ResourceGroup
+		}
+		return rows[i].ResourceName < rows[j].ResourceName
+	})
+	return rows
+}
+
+// skuStatus returns the availability of a resource's SKU in the target region,
+// derived from the SKU lists of the region comparison.
+func skuStatus(c *types.RegionComparison, resourceType, skuName string) string {
+	if skuName == "" || sku.Get(resourceType) == nil {
+		return availNotApplicable
+	}
+	id := resourceType + ":" + skuName
+	for _, m := range c.MissingSKUs {
+		switch m {
+		case id:
+			return availUnavailable
+		case id + " (unknown)":
+			return availUnknown
+		}
+	}
+	if slices.Contains(c.RestrictedSKUs, id) {
+		return availRestricted
+	}
+	for _, z := range c.ZoneRestrictedSKUs {
+		if z == id || strings.HasPrefix(z, id+" (") {
+			return availZoneRestricted
+		}
+	}
+	return availAvailable
+}
+
+// findSubstituteSKU returns a SKU available in the target region that can
+// replace skuName, or "" when none is found. VM sizes are ranked with
+// skus.FindAlternatives; other SKUs are matched by the longest common name prefix.
+func (s *RegionSelectorScanner) findSubstituteSKU(ctx context.Context, subscriptionID, resourceType, skuName, targetRegion string) string {
+	if s.skuCache == nil {
+		return ""
+	}
+	available, err := s.skuCache.GetSKUAvailability(ctx, subscriptionID, resourceType, targetRegion, s.httpClient)
+	if err != nil {
+		log.Debug().Err(err).Msgf("No substitute SKU lookup for %s in %s", resourceType, targetRegion)
+		return ""
+	}
+	return suggestSubstituteSKU(resourceType, skuName, available)
+}
+
+// suggestSubstituteSKU picks the best available substitute for skuName from
+// the target region's SKU availability map (keyed by lowercase SKU name).
+func suggestSubstituteSKU(resourceType, skuName string, available map[string]types.SKUAvailability) string {
+	isAvailable := func(name string) bool {
+		a, ok := available[strings.ToLower(name)]
+		return ok && a.State == types.SKUAvailable
+	}
+
+	if vmResourceTypes[resourceType] {
+		target, ok := skus.Lookup(skuName)
+		if !ok {
+			return ""
+		}
+		for _, rec := range skus.FindAlternatives(target, substituteCandidates) {
+			if isAvailable(rec.SKU.Name) {
+				return rec.SKU.Name
+			}
+		}
+		return ""
+	}
+
+	source := strings.ToLower(skuName)
+	best, bestLen := "", 0
+	for name := range available {
+		if name == source || !isAvailable(name) {
+			continue
+		}
+		n := commonPrefixLen(source, name)
+		if n > bestLen || (n == bestLen && n > 0 && name < best) {
+			best, bestLen = name, n
+		}
+	}
+	return best
+}
+
+// commonPrefixLen returns the length of the common prefix of a and b
+func commonPrefixLen(a, b string) int {
+	n := min(len(a), len(b))
+	for i := 0; i < n; i++ {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	return n
+}
+
+// quotaForResource returns the target-region quota entry that limits a
+// resource and the amount of it the resource needs: vCPUs of the VM family
+// for VMs and scale sets, one storage account for storage accounts.
+func quotaForResource(resourceType, skuName string, capacity int, q migrationQuota) (quota.UsageEntry, int, bool) {
+	var name string
+	var entries []quota.UsageEntry
+	required := 1
+
+	switch {
+	case vmResourceTypes[resourceType]:
+		vmSKU, ok := skus.Lookup(skuName)
+		if !ok || vmSKU.Family == "" {
+			return quota.UsageEntry{}, 0, false
+		}
+		name, entries = vmSKU.Family, q.vm
+		required = vmSKU.VCPUs
+		if resourceType == "microsoft.compute/virtualmachinescalesets" && capacity > 1 {
+			required *= capacity
+		}
+	case resourceType == "microsoft.storage/storageaccounts":
+		name, entries = "StorageAccounts", q.storage
+	default:
+		return quota.UsageEntry{}, 0, false
+	}
+
+	for _, e := range entries {
+		if strings.EqualFold(e.ResourceName, name) {
+			return e, required, true
+		}
+	}
+	return quota.UsageEntry{}, 0, false
+}
+
+// skuCostDifference returns the retail price difference in percent between the
+// source and target regions for the meters of a SKU in the source region.
+func skuCostDifference(costData *types.CostComparisonData, skuName, source, target string) (float64, bool) {
+	if costData == nil || skuName == "" {
+		return 0, false
+	}
+	var sourceTotal, targetTotal float64
+	for _, m := range costData.MeterInputs {
+		if !strings.EqualFold(m.ArmSkuName, skuName) || az.NormalizeRegionName(m.ArmRegionName) != source {
+			continue
+		}
+		prices := costData.RegionPricing[m.MeterID]
+		sourcePrice, okSource := prices[source]
+		targetPrice, okTarget := prices[target]
+		if !okSource || !okTarget || sourcePrice <= 0 {
+			continue
+		}
+		sourceTotal += sourcePrice
+		targetTotal += targetPrice
+	}
+	if sourceTotal == 0 {
+		return 0, false
+	}
+	return (targetTotal - sourceTotal) / sourceTotal * 100, true
+}
+
+// migrationStatus returns the Migration Status and Notes of a row. required is
+// the quota the resource needs in the target region.
+func migrationStatus(row output.MigrationPlanRow, required int) (string, string) {
+	status := migrationReady
+	var notes []string
+	raise := func(s string) {
+		if s == migrationBlocked || (s == migrationActionRequired && status != migrationBlocked) ||
+			(s == migrationReview && status == migrationReady) {
+			status = s
+		}
+	}
+
+	if row.TypeStatus == availUnavailable {
+		raise(migrationBlocked)
+		notes = append(notes, "Resource type not offered in target region")
+	}
+
+	switch row.SKUStatus {
+	case availUnavailable:
+		if row.SubstituteSKU != "" {
+			raise(migrationActionRequired)
+			notes = append(notes, fmt.Sprintf("Resize to %s", row.SubstituteSKU))
+		} else {
+			raise(migrationBlocked)
+			notes = append(notes, "SKU not available and no substitute found")
+		}
+	case availRestricted:
+		raise(migrationActionRequired)
+		note := "SKU restricted for subscription; request access"
+		if row.SubstituteSKU != "" {
+			note += fmt.Sprintf(" or resize to %s", row.SubstituteSKU)
+		}
+		notes = append(notes, note)
+	case availZoneRestricted:
+		raise(migrationActionRequired)
+		notes = append(notes, "SKU restricted in some zones; review zonal placement")
+	case availUnknown:
+		raise(migrationReview)
+		notes = append(notes, "SKU availability could not be determined")
+	}
+
+	if row.HasQuota && row.QuotaAvailable < required {
+		raise(migrationActionRequired)
+		notes = append(notes, fmt.Sprintf("Request %s quota increase (needs %d, available %d)", row.QuotaName, required, row.QuotaAvailable))
+	}
+
+	return status, strings.Join(notes, "; ")
+}
@@ -0,0 +1,196 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package region
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azqr/internal/models"
+	"github.com/Azure/azqr/internal/quota"
+	"github.com/Azure/azqr/internal/scanners/plugins/region/output"
+	"github.com/Azure/azqr/internal/scanners/plugins/region/types"
+	"github.com/Azure/azqr/internal/skus"
+)
+
+const vmType = "microsoft.compute/virtualmachines"
+
+func TestSelectMigrationTarget(t *testing.T) {
+	results := []types.RegionComparison{{TargetRegion: "westeurope"}, {TargetRegion: "swedencentral"}}
+
+	if got, ok := selectMigrationTarget("", results); !ok || got != "westeurope" {
+		t.Errorf("default target = %q, %v; want westeurope", got, ok)
+	}
+	if got, ok := selectMigrationTarget("Sweden Central", results); !ok || got != "swedencentral" {
+		t.Errorf("requested target = %q, %v; want swedencentral", got, ok)
+	}
+	if _, ok := selectMigrationTarget("eastus", results); ok {
+		t.Error("expected a target that was not analysed to be rejected")
+	}
+}
+
+func TestSKUStatus(t *testing.T) {
+	c := &types.RegionComparison{
+		MissingSKUs:        []string{vmType + ":Standard_M8ms", vmType + ":Standard_X1 (unknown)"},
+		RestrictedSKUs:     []string{vmType + ":Standard_D2s_v3"},
+		ZoneRestrictedSKUs: []string{vmType + ":Standard_E2s_v5 (zones blocked: 1)"},
+	}
+	tests := map[string]string{
+		"Standard_M8ms":   availUnavailable,
+		"Standard_X1":     availUnknown,
+		"Standard_D2s_v3": availRestricted,
+		"Standard_E2s_v5": availZoneRestricted,
+		"Standard_B2s":    availAvailable,
+		"":                availNotApplicable,
+	}
+	for skuName, want := range tests {
+		if got := skuStatus(c, vmType, skuName); got != want {
+			t.Errorf("skuStatus(%q) = %q, want %q", skuName, got, want)
+		}
+	}
+	if got := skuStatus(c, "microsoft.web/sites", "P1v3"); got != availNotApplicable {
+		t.Errorf("type without SKU provider = %q, want %q", got, availNotApplicable)
+	}
+}
+
+func TestSuggestSubstituteSKU_VM(t *testing.T) {
+	target, ok := skus.Lookup("Standard_D2s_v3")
+	if !ok {
+		t.Skip("Standard_D2s_v3 not in the embedded SKU table")
+	}
+	alternatives := skus.FindAlternatives(target, substituteCandidates)
+	if len(alternatives) < 2 {
+		t.Skip("not enough alternatives in the embedded SKU table")
+	}
+
+	// Only the second-ranked alternative is available in the target region.
+	available := map[string]types.SKUAvailability{
+		strings.ToLower(alternatives[0].SKU.Name): {State: types.SKUUnavailable},
+		strings.ToLower(alternatives[1].SKU.Name): {State: types.SKUAvailable},
+	}
+	if got := suggestSubstituteSKU(vmType, "Standard_D2s_v3", available); got != alternatives[1].SKU.Name {
+		t.Errorf("substitute = %q, want %q", got, alternatives[1].SKU.Name)
+	}
+	if got := suggestSubstituteSKU(vmType, "Standard_Unknown", available); got != "" {
+		t.Errorf("substitute for unknown SKU = %q, want empty", got)
+	}
+}
+
+func TestSuggestSubstituteSKU_PrefixMatch(t *testing.T) {
+	available := map[string]types.SKUAvailability{
+		"premium_zrs":     {State: types.SKUAvailable},
+		"standard_grs":    {State: types.SKUAvailable},
+		"standard_lrs":    {State: types.SKUAvailable},
+		"standard_ragzrs": {State: types.SKURestricted},
+	}
+	if got := suggestSubstituteSKU("microsoft.storage/storageaccounts", "Standard_RAGRS", available); got != "standard_grs" {
+		t.Errorf("substitute = %q, want standard_grs", got)
+	}
+}
+
+func TestQuotaForResource(t *testing.T) {
+	q := migrationQuota{
+		vm:      []quota.UsageEntry{{ResourceName: "standardDSv3Family", LocalizedName: "Standard DSv3 Family vCPUs", Available: 4, Limit: 10}},
+		storage: []quota.UsageEntry{{ResourceName: "StorageAccounts", LocalizedName: "Storage Accounts", Available: 200, Limit: 250}},
+	}
+
+	entry, required, ok := quotaForResource("microsoft.compute/virtualmachinescalesets", "Standard_D2s_v3", 3, q)
+	if !ok || entry.ResourceName != "standardDSv3Family" || required != 6 {
+		t.Errorf("VMSS quota = %+v, %d, %v; want DSv3 family needing 6 vCPUs", entry, required, ok)
+	}
+	if entry, required, ok := quotaForResource("microsoft.storage/storageaccounts", "Standard_LRS", 0, q); !ok || entry.Limit != 250 || required != 1 {
+		t.Errorf("storage quota = %+v, %d, %v", entry, required, ok)
+	}
+	if _, _, ok := quotaForResource("microsoft.web/sites", "P1v3", 0, q); ok {
+		t.Error("expected no quota for a type without quota mapping")
+	}
+}
+
+func TestSKUCostDifference(t *testing.T) {
+	costData := &types.CostComparisonData{
+		MeterInputs: []types.MeterCostData{
+			{MeterID: "m1", ArmSkuName: "Standard_D2s_v3", ArmRegionName: "westeurope"},
+			{MeterID: "m2", ArmSkuName: "Standard_D2s_v3", ArmRegionName: "eastus"},
+		},
+		RegionPricing: map[string]map[string]float64{
+			"m1": {"westeurope": 0.10, "swedencentral": 0.09},
+			"m2": {"eastus": 0.08, "swedencentral": 0.09},
+		},
+	}
+	got, ok := skuCostDifference(costData, "standard_d2s_v3", "westeurope", "swedencentral")
+	if !ok || !approxEqual(got, -10, 1e-9) {
+		t.Errorf("cost difference = %v, %v; want -10", got, ok)
+	}
+	if _, ok := skuCostDifference(costData, "Standard_E2s_v5", "westeurope", "swedencentral"); ok {
+		t.Error("expected no cost data for an unpriced SKU")
+	}
+	if _, ok := skuCostDifference(nil, "Standard_D2s_v3", "westeurope", "swedencentral"); ok {
+		t.Error("expected no cost data without pricing")
+	}
+}
+
+func TestMigrationStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		row      output.MigrationPlanRow
+		required int
+		want     string
+	}{
+		{"ready", output.MigrationPlanRow{TypeStatus: availAvailable, SKUStatus: availAvailable}, 0, migrationReady},
+		{"type missing", output.MigrationPlanRow{TypeStatus: availUnavailable, SKUStatus: availRestricted}, 0, migrationBlocked},
+		{"no substitute", output.MigrationPlanRow{TypeStatus: availAvailable, SKUStatus: availUnavailable}, 0, migrationBlocked},
+		{"substitute", output.MigrationPlanRow{TypeStatus: availAvailable, SKUStatus: availUnavailable, SubstituteSKU: "Standard_D2s_v5"}, 0, migrationActionRequired},
+		{"unknown sku", output.MigrationPlanRow{TypeStatus: availAvailable, SKUStatus: availUnknown}, 0, migrationReview},
+		{"quota short", output.MigrationPlanRow{TypeStatus: availAvailable, SKUStatus: availAvailable, HasQuota: true, QuotaAvailable: 1}, 2, migrationActionRequired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, notes := migrationStatus(tt.row, tt.required)
+			if got != tt.want {
+				t.Errorf("status = %q (%s), want %q", got, notes, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildMigrationPlan(t *testing.T) {
+	resources := []*models.Resource{
+		{SubscriptionID: "sub1", ResourceGroup: "rg", Name: "vm1", Type: "Microsoft.Compute/virtualMachines", Location: "westeurope", SkuName: "Standard_D2s_v3"},
+		{SubscriptionID: "sub1", ResourceGroup: "rg", Name: "app1", Type: "Microsoft.Web/sites", Location: "westeurope"},
+		{SubscriptionID: "sub1", ResourceGroup: "rg", Name: "vm2", Type: "Microsoft.Compute/virtualMachines", Location: "swedencentral", SkuName: "Standard_D2s_v3"},
+		{SubscriptionID: "sub1", ResourceGroup: "rg", Name: "vm3", Type: "Microsoft.Compute/virtualMachines", Location: "northeurope", SkuName: "Standard_D2s_v3"},
+	}
+	results := []types.RegionComparison{{
+		SubscriptionID:       "sub1",
+		SourceRegion:         "westeurope",
+		TargetRegion:         "swedencentral",
+		MissingResourceTypes: []string{"microsoft.web/sites"},
+	}}
+	quotaBySub := map[string]migrationQuota{"sub1": {
+		vm: []quota.UsageEntry{{ResourceName: "standardDSv3Family", LocalizedName: "Standard DSv3 Family vCPUs", Available: 8, Limit: 10, HeadroomPct: 80}},
+	}}
+
+	rows := NewScanner().buildMigrationPlan(context.Background(), "swedencentral", resources, results, quotaBySub, nil)
+	if len(rows) != len(resources) {
+		t.Fatalf("got %d rows, want %d", len(rows), len(resources))
+	}
+
+	byName := map[string]output.MigrationPlanRow{}
+	for _, r := range rows {
+		byName[r.ResourceName] = r
+	}
+	if r := byName["vm1"]; r.Status != migrationReady || !r.HasQuota || r.QuotaAvailable != 8 || r.HasCost {
+		t.Errorf("vm1 = %+v", r)
+	}
+	if r := byName["app1"]; r.TypeStatus != availUnavailable || r.Status != migrationBlocked {
+		t.Errorf("app1 = %+v", r)
+	}
+	if r := byName["vm2"]; r.Status != migrationInTarget {
+		t.Errorf("vm2 = %+v", r)
+	}
+	if r := byName["vm3"]; r.Status != migrationReview {
+		t.Errorf("vm3 = %+v", r)
+	}
+}
@@ -333,6 +333,23 @@ func BuildCRGSheetFromRows(rows [][]string) *plugins.ExternalPluginOutput {
 	}
 }
 
+// BuildExcludedRegionsSheet lists the target regions excluded by the region
+// constraints. Each row is (region, display name, geography, reasons).
+// Returns nil when rows is empty.
+func BuildExcludedRegionsSheet(rows [][]string) *plugins.ExternalPluginOutput {
+	if len(rows) == 0 {
+		return nil
+	}
+	table := make([][]string, 0, len(rows)+1)
+	table = append(table, []string{"Region", "Display Name", "Geography", "Reason"})
+	table = append(table, rows...)
+	return &plugins.ExternalPluginOutput{
+		SheetName:   "Excluded Regions",
+		Description: "Target regions excluded from scoring because they violate a region constraint",
+		Table:       table,
+	}
+}
+
 // QuotaSheetRow is one raw quota entry passed from selection.go to avoid importing the quota package here.
 // Fields: Subscription, Region, QuotaType, ResourceName, Current, Limit, Available, HeadroomPct, Status.
 type QuotaSheetRow struct {
@@ -388,3 +405,197 @@ func BuildQuotaSheet(rows []QuotaSheetRow) *plugins.ExternalPluginOutput {
 		Table:       table,
 	}
 }
+
+// MigrationPlanRow is one resource of the Migration Plan sheet, built in selection.go.
+// Quota and cost fields are only meaningful when the matching Has* flag is set.
+type MigrationPlanRow struct {
+	ResourceID     string // not shown; links the row to its resource
+	SubscriptionID string
+	ResourceGroup  string
+	ResourceName   string
+	ResourceType   string
+	SourceRegion   string
+	TargetRegion   string
+	SKU            string
+	TypeStatus     string
+	SKUStatus      string
+	SubstituteSKU  string
+	QuotaName      string
+	QuotaAvailable int
+	QuotaLimit     int
+	HeadroomPct    float64
+	HasQuota       bool
+	CostDiffPct    float64
+	HasCost        bool
+	Status         string
+	Notes          string
+}
+
+// MigrationPlanColumns are the typed columns of the Migration Plan sheet.
+var MigrationPlanColumns = []plugins.ColumnMetadata{
+	{Name: "Subscription Id"},
+	{Name: "Resource Group"},
+	{Name: "Resource Name"},
+	{Name: "Resource Type"},
+	{Name: "Source Region"},
+	{Name: "Target Region"},
+	{Name: "Sku Name"},
+	{Name: "Type Status", Type: models.ColumnTypeEnum},
+	{Name: "SKU Status", Type: models.ColumnTypeEnum},
+	{Name: "Substitute SKU"},
+	{Name: "Quota"},
+	{Name: "Quota Available", Type: models.ColumnTypeNumber},
+	{Name: "Quota Limit", Type: models.ColumnTypeNumber},
+	{Name: "Quota Headroom %", Type: models.ColumnTypePercent},
+	{Name: "Est. Cost Difference %", Type: models.ColumnTypePercent},
+	{Name: "Migration Status", Type: models.ColumnTypeEnum},
+	{Name: "Notes"},
+}
+
+// BuildMigrationPlanSheet converts per-resource migration rows for one target region
+// into a "Migration Plan" sheet. Returns nil when rows is empty.
+func BuildMigrationPlanSheet(targetRegion string, rows []MigrationPlanRow, mask bool) *plugins.ExternalPluginOutput {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	headers := make([]string, len(MigrationPlanColumns))
+	for i, col := range MigrationPlanColumns {
+		headers[i] = col.Name
+	}
+	table := make([][]string, 0, len(rows)+1)
+	table = append(table, headers)
+
+	for _, r := range rows {
+		quotaAvailable, quotaLimit, headroom := "N/A", "N/A", "N/A"
+		if r.HasQuota {
+			quotaAvailable = strconv.Itoa(r.QuotaAvailable)
+			quotaLimit = strconv.Itoa(r.QuotaLimit)
+			headroom = fmt.Sprintf("%.1f%%", r.HeadroomPct)
+		}
+		costDiff := "N/A"
+		if r.HasCost {
+			costDiff = fmt.Sprintf("%.2f%%", r.CostDiffPct)
+		}
+		table = append(table, []string{
+			renderers.MaskSubscriptionID(r.SubscriptionID, mask),
+			r.ResourceGroup,
+			r.ResourceName,
+			r.ResourceType,
+			r.SourceRegion,
+			r.TargetRegion,
+			r.SKU,
+			r.TypeStatus,
+			r.SKUStatus,
+			r.SubstituteSKU,
+			r.QuotaName,
+			quotaAvailable,
+			quotaLimit,
+			headroom,
+			costDiff,
+			r.Status,
+			r.Notes,
+		})
+	}
+
+	return &plugins.ExternalPluginOutput{
+		SheetName:   "Migration Plan",
+		Description: fmt.Sprintf("Per-resource migration plan to %s: type and SKU availability, substitute SKUs, quota headroom and estimated cost difference", targetRegion),
+		Table:       table,
+		Columns:     MigrationPlanColumns,
+	}
+}
+
+// QuotaPreflightRow is one quota checked by the migration pre-flight, built in preflight.go.
+// Limit, Current and Available are only meaningful when HasQuota is set.
+type QuotaPreflightRow struct {
+	SubscriptionID string
+	TargetRegion   string
+	QuotaType      string // VM, Network, Storage or SQL
+	ResourceName   string // quota API identifier, e.g. "standardDSv3Family"
+	LocalizedName  string
+	Resources      int // number of migrated resources counted against the quota
+	Required       int
+	Reserved       int // part of Required covered by existing capacity reservations
+	Current        int
+	Limit          int
+	Available      int
+	HasQuota       bool
+	Shortfall      int
+	RequestedLimit int
+}
+
+// QuotaPreflightColumns are the typed columns of the Quota Pre-flight sheet.
+var QuotaPreflightColumns = []plugins.ColumnMetadata{
+	{Name: "Subscription Id"},
+	{Name: "Target Region"},
+	{Name: "Quota Type", Type: models.ColumnTypeEnum},
+	{Name: "Quota"},
+	{Name: "Resources", Type: models.ColumnTypeNumber},
+	{Name: "Required", Type: models.ColumnTypeNumber},
+	{Name: "Covered by Reservations", Type: models.ColumnTypeNumber},
+	{Name: "Current", Type: models.ColumnTypeNumber},
+	{Name: "Limit", Type: models.ColumnTypeNumber},
+	{Name: "Available", Type: models.ColumnTypeNumber},
+	{Name: "Shortfall", Type: models.ColumnTypeNumber},
+	{Name: "Requested Limit", Type: models.ColumnTypeNumber},
+	{Name: "Status", Type: models.ColumnTypeEnum},
+}
+
+// BuildQuotaPreflightSheet converts the pre-flight rows into a "Quota Pre-flight" sheet.
+// Returns nil when rows is empty.
+func BuildQuotaPreflightSheet(rows []QuotaPreflightRow, mask bool) *plugins.ExternalPluginOutput {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	headers := make([]string, len(QuotaPreflightColumns))
+	for i, col := range QuotaPreflightColumns {
+		headers[i] = col.Name
+	}
+	table := make([][]string, 0, len(rows)+1)
+	table = append(table, headers)
+
+	for _, r := range rows {
+		current, limit, available, requested := "N/A", "N/A", "N/A", ""
+		status := "OK"
+		switch {
+		case !r.HasQuota:
+			status = "No Quota Data"
+		case r.Shortfall > 0:
+			status = "Increase Required"
+			requested = strconv.Itoa(r.RequestedLimit)
+		}
+		if r.HasQuota {
+			current = strconv.Itoa(r.Current)
+			limit = strconv.Itoa(r.Limit)
+			available = strconv.Itoa(r.Available)
+		}
+		name := r.LocalizedName
+		if name == "" {
+			name = r.ResourceName
+		}
+		table = append(table, []string{
+			renderers.MaskSubscriptionID(r.SubscriptionID, mask),
+			r.TargetRegion,
+			r.QuotaType,
+			name,
+			strconv.Itoa(r.Resources),
+			strconv.Itoa(r.Required),
+			strconv.Itoa(r.Reserved),
+			current,
+			limit,
+			available,
+			strconv.Itoa(r.Shortfall),
+			requested,
+			status,
+		})
+	}
+
+	return &plugins.ExternalPluginOutput{
+		SheetName:   "Quota Pre-flight",
+		Description: fmt.Sprintf("Quota needed to migrate the inventory to %s compared with target-region headroom and capacity reservations", rows[0].TargetRegion),
+		Table:       table,
+		Columns:     QuotaPreflightColumns,
+	}
+}
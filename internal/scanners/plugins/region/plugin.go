@@ -5,11 +5,12 @@ package region
 
 import (
 	"github.com/Azure/azqr/internal/az"
+	"github.com/Azure/azqr/internal/models"
 	"github.com/Azure/azqr/internal/plugins"
 	"github.com/Azure/azqr/internal/scanners/plugins/region/sku"
+	"github.com/Azure/azqr/internal/scanners/plugins/region/types"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
-	"github.com/spf13/cobra"
 )
 
 // RegionSelectorScanner is an internal plugin that analyzes optimal Azure region selection
@@ -20,6 +21,7 @@ type RegionSelectorScanner struct {
 	cred              azcore.TokenCredential // Azure credential for typed ARM SDK clients
 	clientOpts        *arm.ClientOptions     // ARM client options shared by all typed SDK clients
 	costHistoryMonths int                    // Number of full calendar months to include in Cost Management query (default: 1)
+	weights           types.ScoringWeights   // Scoring weights and thresholds
 }
 
 // NewScanner creates a new region selector scanner
@@ -28,6 +30,7 @@ func NewScanner() *RegionSelectorScanner {
 		skuCache:          sku.NewCache(),
 		targetRegions:     []string{}, // Empty means analyze all regions
 		costHistoryMonths: 1,
+		weights:           types.DefaultScoringWeights(),
 	}
 }
 
@@ -44,37 +47,42 @@ func (s *RegionSelectorScanner) GetMetadata() plugins.PluginMetadata {
 			{Name: "Subscription"},
 			{Name: "Source Region"},
 			{Name: "Target Region"},
-			{Name: "Source Resource Type Count"},
-			{Name: "Available Resource Types"},
-			{Name: "Unavailable Resource Types"},
-			{Name: "Availability %"},
-			{Name: "Total SKUs Checked"},
-			{Name: "Available SKUs"},
-			{Name: "Unavailable SKUs"},
-			{Name: "Restricted SKUs"},
-			{Name: "Zone-Restricted SKUs"},
-			{Name: "Unknown SKUs"},
-			{Name: "SKU Availability %"},
+			{Name: "Source Resource Type Count", Type: models.ColumnTypeNumber},
+			{Name: "Available Resource Types", Type: models.ColumnTypeNumber},
+			{Name: "Unavailable Resource Types", Type: models.ColumnTypeNumber},
+			{Name: "Availability %", Type: models.ColumnTypePercent},
+			{Name: "Total SKUs Checked", Type: models.ColumnTypeNumber},
+			{Name: "Available SKUs", Type: models.ColumnTypeNumber},
+			{Name: "Unavailable SKUs", Type: models.ColumnTypeNumber},
+			{Name: "Restricted SKUs", Type: models.ColumnTypeNumber},
+			{Name: "Zone-Restricted SKUs", Type: models.ColumnTypeNumber},
+			{Name: "Unknown SKUs", Type: models.ColumnTypeNumber},
+			{Name: "SKU Availability %", Type: models.ColumnTypePercent},
 			{Name: "Availability Zones"},
 			{Name: "Target AZ Mapping"},
-			{Name: "Avg Latency (ms)"},
-			{Name: "Avg Cost Difference %"},
-			{Name: "Recommendation Score"},
-			{Name: "Score Quality"},
-			{Name: "Recommendation"},
+			{Name: "Avg Latency (ms)", Type: models.ColumnTypeNumber, Unit: "ms"},
+			{Name: "Avg Cost Difference %", Type: models.ColumnTypePercent},
+			{Name: "Recommendation Score", Type: models.ColumnTypeNumber},
+			{Name: "Score Quality", Type: models.ColumnTypeEnum},
+			{Name: "Recommendation", Type: models.ColumnTypeEnum},
 			{Name: "Missing Resource Types"},
 			{Name: "Unavailable SKUs (detail)"},
 			{Name: "Restricted SKUs (detail)"},
 			{Name: "Zone-Restricted SKUs (detail)"},
+			{Name: "Latency Source", Type: models.ColumnTypeEnum},
 		},
+		Options: append([]plugins.PluginOption{
+			{Name: "target-regions", Type: "string", Default: "", Description: "Target regions to analyze (comma-separated, e.g., eastus,westeurope)"},
+			{Name: "cost-history-months", Type: "int", Default: 1, Description: "Number of full calendar months of Cost Management history to use for pricing weights (1–12)"},
+			{Name: latencyFileOption, Type: "string", Default: "", Description: "CSV or JSON file of measured latencies (source, target, latency_ms, optional weight) overriding the published statistics; sources that are not Azure regions are user locations"},
+			{Name: migrationTargetOption, Type: "string", Default: "", Description: "Target region of the Migration Plan sheet (default: the top-scored target region)"},
+			{Name: quotaRequestsFileOption, Type: "string", Default: "", Description: "CSV file for the quota increase requests of the Migration Plan target (default: <output name>_quota_increase_requests.csv)"},
+		}, append(constraintPluginOptions(), scoringPluginOptions()...)...),
 	}
 }
 
-// RegisterFlags registers plugin-specific flags (implements FlagProvider interface)
-func (s *RegionSelectorScanner) RegisterFlags(cmd *cobra.Command) {
-	cmd.Flags().StringSlice("target-regions", []string{}, "Target regions to analyze (comma-separated, e.g., eastus,westeurope)")
-	cmd.Flags().Int("cost-history-months", 1, "Number of full calendar months of Cost Management history to use for pricing weights (1–12, default: 1)")
-}
+// latencyFileOption is the plugin option naming a latency override file
+const latencyFileOption = "latency-file"
 
 // init registers the plugin automatically
 func init() {
@@ -0,0 +1,280 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package region
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/azqr/internal/az"
+	"github.com/Azure/azqr/internal/models"
+	"github.com/Azure/azqr/internal/quota"
+	"github.com/Azure/azqr/internal/scanners/plugins/region/crg"
+	"github.com/Azure/azqr/internal/scanners/plugins/region/output"
+	"github.com/Azure/azqr/internal/skus"
+)
+
+// quotaRequestsFileOption is the plugin option naming the quota increase requests file
+const quotaRequestsFileOption = "quota-requests-file"
+
+// Quota API identifiers checked by the pre-flight besides the VM families
+const (
+	quotaTotalVCPUs      = quota.RegionalVCPUs
+	quotaPublicIPs       = "PublicIPAddresses"
+	quotaStandardIPs     = "StandardSkuPublicIpAddresses"
+	quotaStorageAccounts = "StorageAccounts"
+	quotaSQLServers      = "ServerQuota"
+	quotaSQLVCores       = "RegionalVCoreQuotaForSQLDBAndDW"
+)
+
+// Quota types of the pre-flight, matching the Quota sheet
+const (
+	quotaTypeVM      = "VM"
+	quotaTypeNetwork = "Network"
+	quotaTypeStorage = "Storage"
+	quotaTypeSQL     = "SQL"
+)
+
+// Resource types counted by the pre-flight besides VMs and storage accounts
+const (
+	vmScaleSetType        = "microsoft.compute/virtualmachinescalesets"
+	publicIPAddressesType = "microsoft.network/publicipaddresses"
+	sqlServerType         = "microsoft.sql/servers"
+	sqlDatabaseType       = "microsoft.sql/servers/databases"
+	sqlElasticPoolType    = "microsoft.sql/servers/elasticpools"
+)
+
+// quotaProviders maps a quota type to the resource provider of its usages API
+var quotaProviders = map[string]string{
+	quotaTypeVM:      "Microsoft.Compute",
+	quotaTypeNetwork: "Microsoft.Network",
+	quotaTypeStorage: "Microsoft.Storage",
+	quotaTypeSQL:     "Microsoft.Sql",
+}
+
+// quotaDemand is the amount of one quota the migration needs in the target
+// region for one subscription.
+type quotaDemand struct {
+	quotaType string
+	name      string
+	resources int
+	required  int
+	reserved  int
+}
+
+// buildQuotaDemand sums the quota needed to move every resource outside the
+// target region into it, per subscription: vCPUs per VM family and in total,
+// public IPs, storage accounts, SQL servers and SQL vCores. VMs use their
+// substitute SKU when the Migration Plan found one (keyed by resource ID).
+// vCPUs covered by free slots of capacity reservations for the same SKU in the
+// target region are recorded as reserved.
+func buildQuotaDemand(resources []*models.Resource, targetRegion string, substitutes map[string]string, reservations []crg.ReservationEntry) map[string][]*quotaDemand {
+	demands := make(map[string]map[string]*quotaDemand)
+	add := func(subID, quotaType, name string, resources, required int) *quotaDemand {
+		if demands[subID] == nil {
+			demands[subID] = make(map[string]*quotaDemand)
+		}
+		key := quotaType + "|" + strings.ToLower(name)
+		d := demands[subID][key]
+		if d == nil {
+			d = &quotaDemand{quotaType: quotaType, name: name}
+			demands[subID][key] = d
+		}
+		d.resources += resources
+		d.required += required
+		return d
+	}
+
+	// Free capacity reservation slots in the target region, per subscription and SKU.
+	freeSlots := make(map[string]int)
+	for _, e := range reservations {
+		if az.NormalizeRegionName(e.Location) == targetRegion && e.Available > 0 {
+			freeSlots[e.SubscriptionID+"|"+strings.ToLower(e.SKU)] += e.Available
+		}
+	}
+
+	for _, r := range resources {
+		if az.NormalizeRegionName(r.Location) == targetRegion {
+			continue // already consumes target-region quota
+		}
+		resourceType := strings.ToLower(r.Type)
+		switch {
+		case vmResourceTypes[resourceType]:
+			skuName := r.SkuName
+			if sub := substitutes[r.ID]; sub != "" {
+				skuName = sub
+			}
+			vmSKU, ok := skus.Lookup(skuName)
+			if !ok || vmSKU.Family == "" {
+				continue
+			}
+			instances := 1
+			if resourceType == vmScaleSetType {
+				instances = max(r.SkuCapacity, 0)
+			}
+			slotKey := r.SubscriptionID + "|" + strings.ToLower(skuName)
+			covered := min(instances, freeSlots[slotKey])
+			freeSlots[slotKey] -= covered
+
+			vcpus := instances * vmSKU.VCPUs
+			reserved := covered * vmSKU.VCPUs
+			add(r.SubscriptionID, quotaTypeVM, vmSKU.Family, 1, vcpus).reserved += reserved
+			add(r.SubscriptionID, quotaTypeVM, quotaTotalVCPUs, 1, vcpus).reserved += reserved
+		case resourceType == publicIPAddressesType:
+			add(r.SubscriptionID, quotaTypeNetwork, quotaPublicIPs, 1, 1)
+			if strings.EqualFold(r.SkuName, "Standard") {
+				add(r.SubscriptionID, quotaTypeNetwork, quotaStandardIPs, 1, 1)
+			}
+		case resourceType == "microsoft.storage/storageaccounts":
+			add(r.SubscriptionID, quotaTypeStorage, quotaStorageAccounts, 1, 1)
+		case resourceType == sqlServerType:
+			add(r.SubscriptionID, quotaTypeSQL, quotaSQLServers, 1, 1)
+		case resourceType == sqlDatabaseType || resourceType == sqlElasticPoolType:
+			if vcores := sqlVCores(r.SkuName, r.SkuCapacity); vcores > 0 {
+				add(r.SubscriptionID, quotaTypeSQL, quotaSQLVCores, 1, vcores)
+			}
+		}
+	}
+
+	result := make(map[string][]*quotaDemand, len(demands))
+	for subID, bySub := range demands {
+		list := make([]*quotaDemand, 0, len(bySub))
+		for _, d := range bySub {
+			list = append(list, d)
+		}
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].quotaType != list[j].quotaType {
+				return list[i].quotaType < list[j].quotaType
+			}
+			return list[i].name < list[j].name
+		})
+		result[subID] = list
+	}
+	return result
+}
+
+// sqlVCores returns the vCores of a vCore-model SQL database or elastic pool
+// (General Purpose, Business Critical or Hyperscale SKUs), or 0 for DTU SKUs.
+func sqlVCores(skuName string, capacity int) int {
+	name := strings.ToUpper(skuName)
+	for _, prefix := range []string{"GP_", "BC_", "HS_"} {
+		if strings.HasPrefix(name, prefix) {
+			return capacity
+		}
+	}
+	return 0
+}
+
+// buildQuotaPreflight compares the quota demand with the target-region quota
+// of each subscription and computes the increase each quota needs.
+func buildQuotaPreflight(targetRegion string, demands map[string][]*quotaDemand, quotaBySub map[string]migrationQuota) []output.QuotaPreflightRow {
+	subIDs := make([]string, 0, len(demands))
+	for subID := range demands {
+		subIDs = append(subIDs, subID)
+	}
+	sort.Strings(subIDs)
+
+	var rows []output.QuotaPreflightRow
+	for _, subID := range subIDs {
+		q := quotaBySub[subID]
+		entriesByType := map[string][]quota.UsageEntry{
+			quotaTypeVM:      q.vm,
+			quotaTypeNetwork: q.network,
+			quotaTypeStorage: q.storage,
+			quotaTypeSQL:     q.sql,
+		}
+		for _, d := range demands[subID] {
+			row := output.QuotaPreflightRow{
+				SubscriptionID: subID,
+				TargetRegion:   targetRegion,
+				QuotaType:      d.quotaType,
+				ResourceName:   d.name,
+				Resources:      d.resources,
+				Required:       d.required,
+				Reserved:       d.reserved,
+			}
+			for _, e := range entriesByType[d.quotaType] {
+				if strings.EqualFold(e.ResourceName, d.name) {
+					row.LocalizedName = e.LocalizedName
+					row.Current = e.CurrentValue
+					row.Limit = e.Limit
+					row.Available = e.Available
+					row.HasQuota = true
+					break
+				}
+			}
+			if row.HasQuota {
+				row.Shortfall = max(d.required-d.reserved-max(row.Available, 0), 0)
+				if row.Shortfall > 0 {
+					row.RequestedLimit = max(row.Limit, row.Current) + row.Shortfall
+				}
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// quotaRequestsPath returns the path of the quota increase requests file: the
+// quota-requests-file option, or a name derived from the report name.
+func quotaRequestsPath(option, outputName string) string {
+	if option != "" {
+		return option
+	}
+	if outputName == "" {
+		return "quota_increase_requests.csv"
+	}
+	return outputName + "_quota_increase_requests.csv"
+}
+
+// writeQuotaRequests writes one CSV line per quota needing an increase, with
+// the quota scope and, for quotas managed by the Azure Quota API, the Azure CLI
+// command that requests it. It returns the number of requests written; no file
+// is created when there are none.
+func writeQuotaRequests(path string, rows []output.QuotaPreflightRow) (int, error) {
+	records := [][]string{{"Subscription Id", "Region", "Provider", "Quota", "Quota Name", "Current Limit", "Requested Limit", "Increase", "Scope", "Command"}}
+	for _, r := range rows {
+		if r.Shortfall <= 0 {
+			continue
+		}
+		provider := quotaProviders[r.QuotaType]
+		scope := fmt.Sprintf("/subscriptions/%s/providers/%s/locations/%s", r.SubscriptionID, provider, r.TargetRegion)
+		command := "Open an Azure support request (service and subscription limits)"
+		if r.QuotaType == quotaTypeVM || r.QuotaType == quotaTypeNetwork {
+			command = fmt.Sprintf("az quota update --resource-name %s --scope %s --limit-object value=%d", r.ResourceName, scope, r.RequestedLimit)
+		}
+		records = append(records, []string{
+			r.SubscriptionID,
+			r.TargetRegion,
+			provider,
+			r.ResourceName,
+			r.LocalizedName,
+			strconv.Itoa(r.Limit),
+			strconv.Itoa(r.RequestedLimit),
+			strconv.Itoa(r.Shortfall),
+			scope,
+			command,
+		})
+	}
+	if len(records) == 1 {
+		return 0, nil
+	}
+
+	f, err := os.Create(filepath.Clean(path))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create quota requests file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	w := csv.NewWriter(f)
+	if err := w.WriteAll(records); err != nil {
+		return 0, fmt.Errorf("failed to write quota requests file: %w", err)
+	}
+	return len(records) - 1, nil
+}
@@ -0,0 +1,202 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package region
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azqr/internal/models"
+	"github.com/Azure/azqr/internal/quota"
+	"github.com/Azure/azqr/internal/scanners/plugins/region/crg"
+	"github.com/Azure/azqr/internal/scanners/plugins/region/output"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// findDemand returns the demand of a quota, or nil
+func findDemand(demands []*quotaDemand, name string) *quotaDemand {
+	for _, d := range demands {
+		if d.name == name {
+			return d
+		}
+	}
+	return nil
+}
+
+func TestBuildQuotaDemand(t *testing.T) {
+	resources := []*models.Resource{
+		{ID: "vm1", SubscriptionID: "sub1", Type: "Microsoft.Compute/virtualMachines", Location: "westeurope", SkuName: "Standard_D2s_v3"},
+		{ID: "vm2", SubscriptionID: "sub1", Type: "Microsoft.Compute/virtualMachines", Location: "westeurope", SkuName: "Standard_D2s_v3"},
+		{ID: "vmss", SubscriptionID: "sub1", Type: "Microsoft.Compute/virtualMachineScaleSets", Location: "westeurope", SkuName: "Standard_D2s_v3", SkuCapacity: 3},
+		{ID: "vm-in-target", SubscriptionID: "sub1", Type: "Microsoft.Compute/virtualMachines", Location: "swedencentral", SkuName: "Standard_D2s_v3"},
+		{ID: "pip", SubscriptionID: "sub1", Type: "Microsoft.Network/publicIPAddresses", Location: "westeurope", SkuName: "Standard"},
+		{ID: "st", SubscriptionID: "sub1", Type: "Microsoft.Storage/storageAccounts", Location: "westeurope"},
+		{ID: "sql", SubscriptionID: "sub2", Type: "Microsoft.Sql/servers", Location: "westeurope"},
+		{ID: "db", SubscriptionID: "sub2", Type: "Microsoft.Sql/servers/databases", Location: "westeurope", SkuName: "GP_Gen5", SkuCapacity: 8},
+		{ID: "dtu", SubscriptionID: "sub2", Type: "Microsoft.Sql/servers/databases", Location: "westeurope", SkuName: "S0", SkuCapacity: 10},
+	}
+	reservations := []crg.ReservationEntry{
+		{SubscriptionID: "sub1", Location: "swedencentral", SKU: "Standard_D2s_v3", Available: 2},
+		{SubscriptionID: "sub1", Location: "westeurope", SKU: "Standard_D2s_v3", Available: 5},
+	}
+
+	demands := buildQuotaDemand(resources, "swedencentral", nil, reservations)
+
+	// 2 VMs + 3 scale set instances of a 2-vCPU SKU; 2 instances covered by the reservation.
+	family := findDemand(demands["sub1"], "standardDSv3Family")
+	if family == nil || family.required != 10 || family.reserved != 4 || family.resources != 3 {
+		t.Errorf("DSv3 family demand = %+v, want 10 vCPUs with 4 reserved from 3 resources", family)
+	}
+	if total := findDemand(demands["sub1"], quotaTotalVCPUs); total == nil || total.required != 10 {
+		t.Errorf("total vCPU demand = %+v, want 10", total)
+	}
+	if d := findDemand(demands["sub1"], quotaStandardIPs); d == nil || d.required != 1 {
+		t.Errorf("standard public IP demand = %+v", d)
+	}
+	if d := findDemand(demands["sub1"], quotaStorageAccounts); d == nil || d.required != 1 {
+		t.Errorf("storage account demand = %+v", d)
+	}
+	if d := findDemand(demands["sub2"], quotaSQLVCores); d == nil || d.required != 8 {
+		t.Errorf("SQL vCore demand = %+v, want 8 (DTU databases excluded)", d)
+	}
+	if d := findDemand(demands["sub2"], quotaSQLServers); d == nil || d.required != 1 {
+		t.Errorf("SQL server demand = %+v", d)
+	}
+}
+
+func TestBuildQuotaDemand_Substitute(t *testing.T) {
+	resources := []*models.Resource{
+		{ID: "vm1", SubscriptionID: "sub1", Type: "Microsoft.Compute/virtualMachines", Location: "westeurope", SkuName: "Standard_Unknown"},
+	}
+	demands := buildQuotaDemand(resources, "swedencentral", map[string]string{"vm1": "Standard_D2s_v3"}, nil)
+	if d := findDemand(demands["sub1"], "standardDSv3Family"); d == nil || d.required != 2 {
+		t.Errorf("substitute SKU demand = %+v, want 2 vCPUs of DSv3", d)
+	}
+}
+
+// stubTransport answers every request with the same JSON body
+type stubTransport struct{ body string }
+
+func (s stubTransport) Do(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(s.body)),
+		Request:    req,
+	}, nil
+}
+
+type stubCredential struct{}
+
+func (stubCredential) GetToken(context.Context, policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: "token", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+// fetchTestVMQuota runs the VM quota fetch used by the plugin against a canned usages response.
+func fetchTestVMQuota(t *testing.T, body string) []quota.UsageEntry {
+	t.Helper()
+	clientOpts := &arm.ClientOptions{ClientOptions: policy.ClientOptions{Transport: stubTransport{body: body}}}
+	entries, err := quota.FetchVMQuotaWithRegional(context.Background(), stubCredential{}, clientOpts, "sub1", "swedencentral")
+	if err != nil {
+		t.Fatalf("FetchVMQuotaWithRegional() error = %v", err)
+	}
+	return entries
+}
+
+func TestBuildQuotaPreflight(t *testing.T) {
+	demands := map[string][]*quotaDemand{"sub1": {
+		{quotaType: quotaTypeVM, name: "standardDSv3Family", resources: 3, required: 10, reserved: 4},
+		{quotaType: quotaTypeVM, name: quotaTotalVCPUs, resources: 3, required: 10, reserved: 4},
+		{quotaType: quotaTypeStorage, name: quotaStorageAccounts, resources: 1, required: 1},
+		{quotaType: quotaTypeNetwork, name: quotaPublicIPs, resources: 1, required: 1},
+	}}
+	quotaBySub := map[string]migrationQuota{"sub1": {
+		vm: fetchTestVMQuota(t, `{"value":[
+			{"name":{"value":"cores","localizedValue":"Total Regional vCPUs"},"currentValue":12,"limit":10},
+			{"name":{"value":"standardDSv3Family","localizedValue":"Standard DSv3 Family vCPUs"},"currentValue":8,"limit":10}
+		]}`),
+		storage: []quota.UsageEntry{{ResourceName: "StorageAccounts", CurrentValue: 10, Limit: 250, Available: 240}},
+	}}
+
+	rows := buildQuotaPreflight("swedencentral", demands, quotaBySub)
+	if len(rows) != 4 {
+		t.Fatalf("got %d rows, want 4", len(rows))
+	}
+	// 10 required - 4 reserved - 2 available = 4 short → 10 + 4
+	if r := rows[0]; r.Shortfall != 4 || r.RequestedLimit != 14 {
+		t.Errorf("family row = %+v, want shortfall 4 and requested limit 14", r)
+	}
+	// Over limit: 6 needed on top of the current 12
+	if r := rows[1]; !r.HasQuota || r.Shortfall != 6 || r.RequestedLimit != 18 {
+		t.Errorf("total vCPU row = %+v, want shortfall 6 and requested limit 18", r)
+	}
+	if r := rows[2]; !r.HasQuota || r.Shortfall != 0 {
+		t.Errorf("storage row = %+v, want no shortfall", r)
+	}
+	if r := rows[3]; r.HasQuota {
+		t.Errorf("network row = %+v, want no quota data", r)
+	}
+}
+
+func TestWriteQuotaRequests(t *testing.T) {
+	rows := []output.QuotaPreflightRow{
+		{SubscriptionID: "sub1", TargetRegion: "swedencentral", QuotaType: quotaTypeVM, ResourceName: "standardDSv3Family", Limit: 10, Shortfall: 4, RequestedLimit: 14},
+		{SubscriptionID: "sub1", TargetRegion: "swedencentral", QuotaType: quotaTypeSQL, ResourceName: quotaSQLVCores, Limit: 40, Shortfall: 8, RequestedLimit: 48},
+		{SubscriptionID: "sub1", TargetRegion: "swedencentral", QuotaType: quotaTypeStorage, ResourceName: quotaStorageAccounts, Limit: 250},
+	}
+	path := filepath.Join(t.TempDir(), "requests.csv")
+
+	n, err := writeQuotaRequests(path, rows)
+	if err != nil || n != 2 {
+		t.Fatalf("writeQuotaRequests = %d, %v; want 2 requests", n, err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want header + 2", len(records))
+	}
+	want := "az quota update --resource-name standardDSv3Family --scope /subscriptions/sub1/providers/Microsoft.Compute/locations/swedencentral --limit-object value=14"
+	if records[1][9] != want {
+		t.Errorf("command = %q, want %q", records[1][9], want)
+	}
+	if !strings.Contains(records[2][9], "support request") {
+		t.Errorf("SQL request = %q, want a support request", records[2][9])
+	}
+
+	empty := filepath.Join(t.TempDir(), "none.csv")
+	if n, err := writeQuotaRequests(empty, rows[2:]); err != nil || n != 0 {
+		t.Errorf("writeQuotaRequests without shortfall = %d, %v", n, err)
+	}
+	if _, err := os.Stat(empty); !os.IsNotExist(err) {
+		t.Error("expected no file when no increase is needed")
+	}
+}
+
+func TestQuotaRequestsPath(t *testing.T) {
+	if got := quotaRequestsPath("custom.csv", "report"); got != "custom.csv" {
+		t.Errorf("explicit path = %q", got)
+	}
+	if got := quotaRequestsPath("", "azqr_action_plan_2026"); got != "azqr_action_plan_2026_quota_increase_requests.csv" {
+		t.Errorf("derived path = %q", got)
+	}
+	if got := quotaRequestsPath("", ""); got != "quota_increase_requests.csv" {
+		t.Errorf("default path = %q", got)
+	}
+}
@@ -15,6 +15,8 @@ import (
 	"github.com/Azure/azqr/internal/az"
 	"github.com/Azure/azqr/internal/models"
 	"github.com/Azure/azqr/internal/plugins"
+	"github.com/Azure/azqr/internal/progress"
+	"github.com/Azure/azqr/internal/quota"
 	"github.com/Azure/azqr/internal/renderers"
 	"github.com/Azure/azqr/internal/scanners"
 	"github.com/Azure/azqr/internal/scanners/plugins/region/availability"
@@ -22,7 +24,6 @@ import (
 	"github.com/Azure/azqr/internal/scanners/plugins/region/crg"
 	"github.com/Azure/azqr/internal/scanners/plugins/region/latency"
 	"github.com/Azure/azqr/internal/scanners/plugins/region/output"
-	"github.com/Azure/azqr/internal/scanners/plugins/region/quota"
 	"github.com/Azure/azqr/internal/scanners/plugins/region/types"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/rs/zerolog/log"
@@ -37,34 +38,52 @@ func (s *RegionSelectorScanner) Scan(ctx context.Context, cred azcore.TokenCrede
 	s.cred = cred
 	s.clientOpts = az.NewDefaultClientOptions()
 
-	// Get target regions from stage options if provided
-	if params != nil && params.Stages != nil {
-		stageOptions := params.Stages.GetStageOptions(models.StageNamePlugin)
-		if targetRegions, exists := stageOptions["target-regions"]; exists {
-			if regionsStr, ok := targetRegions.(string); ok && regionsStr != "" {
-				s.targetRegions = strings.Split(regionsStr, ",")
-				for i := range s.targetRegions {
-					s.targetRegions[i] = types.NormalizeRegionName(s.targetRegions[i])
-				}
-			}
+	// Get target regions and cost history from the plugin options
+	var stages *models.StageConfigs
+	if params != nil {
+		stages = params.Stages
+	}
+	options := stages.GetPluginOptions(s.GetMetadata().Name)
+	if regionsStr, ok := options["target-regions"].(string); ok && regionsStr != "" {
+		s.targetRegions = strings.Split(regionsStr, ",")
+		for i := range s.targetRegions {
+			s.targetRegions[i] = az.NormalizeRegionName(s.targetRegions[i])
 		}
-		if historyMonths, exists := stageOptions["cost-history-months"]; exists {
-			var n int
-			switch v := historyMonths.(type) {
-			case int:
-				n = v
-			case float64:
-				n = int(v)
-			}
-			if n >= 1 && n <= 12 {
-				s.costHistoryMonths = n
-			} else {
-				log.Warn().Msgf("cost-history-months %d out of range [1–12]; using default 1", n)
-			}
+	}
+	if n, ok := options["cost-history-months"].(int); ok {
+		if n >= 1 && n <= 12 {
+			s.costHistoryMonths = n
+		} else {
+			log.Warn().Msgf("cost-history-months %d out of range [1–12]; using default 1", n)
 		}
 	}
 
-	if len(s.targetRegions) == 0 {
+	weights, err := resolveScoringWeights(stages, s.GetMetadata().Name)
+	if err != nil {
+		return nil, err
+	}
+	s.weights = weights
+	log.Info().Msgf("Region scoring weights: %s; thresholds: %s", weights, weights.Thresholds())
+
+	constraints, err := resolveRegionConstraints(options)
+	if err != nil {
+		return nil, err
+	}
+
+	var latencyOverrides *latency.Overrides
+	if path, _ := options[latencyFileOption].(string); path != "" {
+		latencyOverrides, err = latency.LoadOverrides(path)
+		if err != nil {
+			return nil, err
+		}
+		if users := latencyOverrides.UserLocations(); len(users) > 0 {
+			log.Info().Msgf("Scoring latency from %d user location(s): %s", len(users), strings.Join(users, ", "))
+		}
+	}
+
+	// With region constraints and no explicit targets, every physical region
+	// meeting the constraints is a candidate.
+	if len(s.targetRegions) == 0 && constraints.isEmpty() {
 		s.targetRegions = []string{"swedencentral"}
 		log.Info().Msg("No target regions specified, defaulting to Sweden Central")
 	}
@@ -96,7 +115,7 @@ func (s *RegionSelectorScanner) Scan(ctx context.Context, cred azcore.TokenCrede
 		subName                 string
 		regionResults           []types.RegionComparison
 		meterCosts              []types.MeterCostData
-		quotaByRegion           map[string][]quota.VMFamilyUsage // targetRegion → VM family quotas
+		quotaByRegion           map[string][]quota.VMFamilyUsage // targetRegion → VM family and regional vCPU quotas
 		networkQuotaByRegion    map[string][]quota.UsageEntry    // targetRegion → network resource quotas
 		sqlQuotaByRegion        map[string][]quota.UsageEntry    // targetRegion → SQL quotas
 		appServiceQuotaByRegion map[string][]quota.UsageEntry    // targetRegion → App Service quotas
@@ -119,12 +138,19 @@ func (s *RegionSelectorScanner) Scan(ctx context.Context, cred azcore.TokenCrede
 	}
 	var globalInventoryMu sync.Mutex
 
+	// Target regions excluded by the region constraints, with the reasons, across subscriptions.
+	excludedRegions := make(map[string][]string)
+	excludedRegionInfo := make(map[string]types.RegionInfo)
+	var excludedMu sync.Mutex
+
 	var wg sync.WaitGroup
 
+	tracker := progress.NewTracker(ctx, "subscriptions", len(subscriptions))
 	for subscriptionID, subscriptionName := range subscriptions {
 		wg.Add(1)
 		go func(subID, subName string) {
 			defer wg.Done()
+			defer tracker.Step(subName)
 
 			log.Debug().Msgf("Analyzing subscription for Region Selection: %s (%s)", subName, renderers.MaskSubscriptionID(subID, true))
 
@@ -146,7 +172,7 @@ func (s *RegionSelectorScanner) Scan(ctx context.Context, cred azcore.TokenCrede
 
 			// Step 2: Get list of all Azure regions for this subscription
 			log.Debug().Msgf("Discovering available Azure regions for subscription %s...", renderers.MaskSubscriptionID(subID, true))
-			allRegions, regionZoneCount, zoneMappingsByRegion, err := s.getAllAzureRegions(ctx, subID)
+			allRegions, regionZoneCount, zoneMappingsByRegion, regionInfo, err := s.getAllAzureRegions(ctx, subID)
 			if err != nil {
 				log.Warn().Err(err).Msgf("Failed to get Azure regions for subscription %s, skipping", renderers.MaskSubscriptionID(subID, true))
 				return
@@ -161,13 +187,13 @@ func (s *RegionSelectorScanner) Scan(ctx context.Context, cred azcore.TokenCrede
 				// Normalize target regions to lowercase for comparison
 				targetRegionMap := make(map[string]bool)
 				for _, r := range s.targetRegions {
-					targetRegionMap[types.NormalizeRegionName(r)] = true
+					targetRegionMap[az.NormalizeRegionName(r)] = true
 				}
 
 				// Filter to only the specified target regions
 				filteredRegions := []string{}
 				for _, region := range allRegions {
-					if targetRegionMap[types.NormalizeRegionName(region)] {
+					if targetRegionMap[az.NormalizeRegionName(region)] {
 						filteredRegions = append(filteredRegions, region)
 					}
 				}
@@ -183,6 +209,22 @@ func (s *RegionSelectorScanner) Scan(ctx context.Context, cred azcore.TokenCrede
 				log.Debug().Msgf("No target regions specified, analyzing all %d Azure regions for subscription %s", len(allRegions), renderers.MaskSubscriptionID(subID, true))
 			}
 
+			// Drop target regions violating a hard constraint before any availability check or scoring.
+			targetRegions, excluded := constraints.filterTargetRegions(targetRegions, regionInfo)
+			if len(excluded) > 0 {
+				excludedMu.Lock()
+				for region, reasons := range excluded {
+					excludedRegions[region] = reasons
+					excludedRegionInfo[region] = regionInfo[region]
+				}
+				excludedMu.Unlock()
+				log.Info().Msgf("Excluded %d target region(s) by region constraints for subscription %s", len(excluded), renderers.MaskSubscriptionID(subID, true))
+			}
+			if len(targetRegions) == 0 {
+				log.Warn().Msgf("No target region meets the region constraints for subscription %s", renderers.MaskSubscriptionID(subID, true))
+				return
+			}
+
 			// Step 3: Check availability for each source->target region pair
 			// Source regions come from where resources actually exist
 			log.Debug().Msgf("Checking resource availability from source regions to %d target regions for subscription %s...", len(targetRegions), renderers.MaskSubscriptionID(subID, true))
@@ -225,10 +267,11 @@ func (s *RegionSelectorScanner) Scan(ctx context.Context, cred azcore.TokenCrede
 			}
 
 			for region := range quotaRegions {
-				if !types.IsPhysicalRegion(region) {
+				if !az.IsPhysicalRegion(region) {
 					continue
 				}
-				vmUsages, qErr := quota.FetchVMQuota(ctx, s.cred, s.clientOpts, subID, region)
+				// Includes Total Regional vCPUs for the migration quota pre-flight
+				vmUsages, qErr := quota.FetchVMQuotaWithRegional(ctx, s.cred, s.clientOpts, subID, region)
 				if qErr != nil {
 					log.Warn().Err(qErr).Msgf("VM quota unavailable for %s in %s — quota check skipped", renderers.MaskSubscriptionID(subID, true), region)
 				} else {
@@ -278,8 +321,8 @@ func (s *RegionSelectorScanner) Scan(ctx context.Context, cred azcore.TokenCrede
 			meterCostByID[mc.MeterID] += mc.HistoricalCost
 		}
 		for _, r := range p1.regionResults {
-			allRegionsMap[types.NormalizeRegionName(r.SourceRegion)] = true
-			allRegionsMap[types.NormalizeRegionName(r.TargetRegion)] = true
+			allRegionsMap[az.NormalizeRegionName(r.SourceRegion)] = true
+			allRegionsMap[az.NormalizeRegionName(r.TargetRegion)] = true
 		}
 	}
 
@@ -311,7 +354,7 @@ func (s *RegionSelectorScanner) Scan(ctx context.Context, cred azcore.TokenCrede
 
 		// Step 6: Calculate network latency scores
 		log.Debug().Msgf("Calculating network latency for subscription %s...", renderers.MaskSubscriptionID(p1.subID, true))
-		latency.EnrichWithLatencyData(p1.regionResults)
+		latency.EnrichWithLatencyData(p1.regionResults, latencyOverrides)
 
 		// Step 7: Calculate recommendation scores
 		log.Info().Msgf("Calculating recommendation scores for subscription %s", renderers.MaskSubscriptionID(p1.subID, true))
@@ -380,7 +423,7 @@ func (s *RegionSelectorScanner) Scan(ctx context.Context, cred azcore.TokenCrede
 	outputs := []plugins.ExternalPluginOutput{{
 		Metadata:    s.GetMetadata(),
 		SheetName:   "Region Selection",
-		Description: "Analysis of optimal Azure region selection based on service availability, network latency, and cost factors",
+		Description: fmt.Sprintf("Analysis of optimal Azure region selection based on service availability, network latency, and cost factors (weights: %s; thresholds: %s)", s.weights, s.weights.Thresholds()),
 		Table:       table,
 	}}
 	outputs = append(outputs, output.BuildSvcAvailSheets(allResults, globalInventory)...)
@@ -390,6 +433,10 @@ func (s *RegionSelectorScanner) Scan(ctx context.Context, cred azcore.TokenCrede
 		}
 	}
 
+	if excludedSheet := output.BuildExcludedRegionsSheet(excludedRegionRows(excludedRegions, excludedRegionInfo)); excludedSheet != nil {
+		outputs = append(outputs, *excludedSheet)
+	}
+
 	// Collect quota rows across all subscriptions and regions for the Quota sheet.
 	var allQuotaRows []output.QuotaSheetRow
 	for _, p1 := range phase1Results {
@@ -410,7 +457,14 @@ func (s *RegionSelectorScanner) Scan(ctx context.Context, cred azcore.TokenCrede
 			}
 		}
 		for region, entries := range p1.quotaByRegion {
-			addQuotaRows(region, "VM", entries)
+			// The sheet reports VM family headroom; regional vCPUs are only checked by the pre-flight
+			families := make([]quota.UsageEntry, 0, len(entries))
+			for _, e := range entries {
+				if e.ResourceName != quota.RegionalVCPUs {
+					families = append(families, e)
+				}
+			}
+			addQuotaRows(region, "VM", families)
 		}
 		for region, entries := range p1.networkQuotaByRegion {
 			addQuotaRows(region, "Network", entries)
@@ -451,6 +505,50 @@ func (s *RegionSelectorScanner) Scan(ctx context.Context, cred azcore.TokenCrede
 		outputs = append(outputs, *crgSheet)
 	}
 
+	// Build the per-resource Migration Plan for the chosen target region.
+	requestedTarget, _ := options[migrationTargetOption].(string)
+	if migrationTarget, ok := selectMigrationTarget(requestedTarget, allResults); ok {
+		quotaBySub := make(map[string]migrationQuota, len(phase1Results))
+		for _, p1 := range phase1Results {
+			quotaBySub[p1.subID] = migrationQuota{
+				vm:      p1.quotaByRegion[migrationTarget],
+				network: p1.networkQuotaByRegion[migrationTarget],
+				storage: p1.storageQuotaByRegion[migrationTarget],
+				sql:     p1.sqlQuotaByRegion[migrationTarget],
+			}
+		}
+		planRows := s.buildMigrationPlan(ctx, migrationTarget, allResources, allResults, quotaBySub, sharedCostData)
+		if planSheet := output.BuildMigrationPlanSheet(migrationTarget, planRows, params.Mask); planSheet != nil {
+			outputs = append(outputs, *planSheet)
+		}
+
+		// Quota pre-flight: the quota the migration needs against target headroom and reservations.
+		substitutes := make(map[string]string)
+		for _, row := range planRows {
+			if row.SubstituteSKU != "" {
+				substitutes[row.ResourceID] = row.SubstituteSKU
+			}
+		}
+		var allCRGEntries []crg.ReservationEntry
+		for _, p1 := range phase1Results {
+			allCRGEntries = append(allCRGEntries, p1.crgEntries...)
+		}
+		demands := buildQuotaDemand(allResources, migrationTarget, substitutes, allCRGEntries)
+		preflightRows := buildQuotaPreflight(migrationTarget, demands, quotaBySub)
+		if preflightSheet := output.BuildQuotaPreflightSheet(preflightRows, params.Mask); preflightSheet != nil {
+			outputs = append(outputs, *preflightSheet)
+		}
+		requestsOption, _ := options[quotaRequestsFileOption].(string)
+		requestsPath := quotaRequestsPath(requestsOption, params.OutputName)
+		if n, err := writeQuotaRequests(requestsPath, preflightRows); err != nil {
+			log.Warn().Err(err).Msg("Failed to write quota increase requests")
+		} else if n > 0 {
+			log.Warn().Msgf("%d quota increase(s) needed to migrate to %s — requests written to %s", n, migrationTarget, requestsPath)
+		}
+	} else if requestedTarget != "" {
+		log.Warn().Msgf("%s %q was not analysed — Migration Plan and Quota Pre-flight sheets skipped", migrationTargetOption, requestedTarget)
+	}
+
 	// Append Inventory sheet last.
 	outputs = append(outputs, output.BuildInventorySheet(allResources, params.Mask))
 
@@ -503,29 +601,31 @@ var azureZoneCapableRegions = map[string]int{
 }
 
 // getAllAzureRegions gets a list of all available Azure regions, the Availability Zone count
-// per region, and the per-subscription logical→physical AZ mapping per region.
+// per region, the per-subscription logical→physical AZ mapping per region and the
+// geography metadata per region.
 // Queries the Azure Locations API to get the authoritative Physical region list.
-// Returns (regions, regionZoneCount, zoneMappingsByRegion, error).
+// Returns (regions, regionZoneCount, zoneMappingsByRegion, regionInfo, error).
 // Zone counts first use availabilityZoneMappings from the API (subscription-scoped);
 // falls back to a curated static list when the API returns no mappings for any region.
-func (s *RegionSelectorScanner) getAllAzureRegions(ctx context.Context, subscriptionID string) ([]string, map[string]int, map[string]map[string]string, error) {
+func (s *RegionSelectorScanner) getAllAzureRegions(ctx context.Context, subscriptionID string) ([]string, map[string]int, map[string]map[string]string, map[string]types.RegionInfo, error) {
 	log.Debug().Msgf("Getting regions for subscription %s", renderers.MaskSubscriptionID(subscriptionID, true))
 
 	url := fmt.Sprintf("https://management.azure.com/subscriptions/%s/locations?api-version=2022-12-01", subscriptionID)
 	body, err := s.httpClient.Do(ctx, url)
 	if err != nil {
-		return nil, nil, nil, fmt.Errorf("failed to query locations API: %w", err)
+		return nil, nil, nil, nil, fmt.Errorf("failed to query locations API: %w", err)
 	}
 
 	locations, err := az.ParseLocations(body)
 	if err != nil {
-		return nil, nil, nil, fmt.Errorf("failed to parse locations API response: %w", err)
+		return nil, nil, nil, nil, fmt.Errorf("failed to parse locations API response: %w", err)
 	}
 
 	// Filter to Physical regions only; build zone count and mapping tables in one pass.
 	regions := make([]string, 0)
 	regionZoneCount := make(map[string]int)
 	zoneMappingsByRegion := make(map[string]map[string]string)
+	regionInfo := make(map[string]types.RegionInfo)
 	apiZonesDetected := 0
 
 	for _, location := range locations {
@@ -535,6 +635,18 @@ func (s *RegionSelectorScanner) getAllAzureRegions(ctx context.Context, subscrip
 		name := strings.ToLower(location.Name)
 		regions = append(regions, name)
 
+		info := types.RegionInfo{
+			Name:             name,
+			DisplayName:      location.DisplayName,
+			Geography:        location.Metadata.Geography,
+			GeographyGroup:   location.Metadata.GeographyGroup,
+			PhysicalLocation: location.Metadata.PhysicalLocation,
+		}
+		for _, p := range location.Metadata.PairedRegion {
+			info.PairedRegions = append(info.PairedRegions, az.NormalizeRegionName(p.Name))
+		}
+		regionInfo[name] = info
+
 		zoneCount := len(location.AvailabilityZoneMappings)
 		regionZoneCount[name] = zoneCount
 		if zoneCount > 0 {
@@ -560,16 +672,20 @@ func (s *RegionSelectorScanner) getAllAzureRegions(ctx context.Context, subscrip
 		log.Debug().Msgf("Detected %d zone-capable regions from locations API", apiZonesDetected)
 	}
 
+	for name, info := range regionInfo {
+		info.ZoneCount = regionZoneCount[name]
+		regionInfo[name] = info
+	}
+
 	sort.Strings(regions)
 	log.Debug().Msgf("Found %d physical Azure regions", len(regions))
 
-	return regions, regionZoneCount, zoneMappingsByRegion, nil
+	return regions, regionZoneCount, zoneMappingsByRegion, regionInfo, nil
 }
 
 // calculateScores calculates recommendation scores for each region using configurable weights
 func (s *RegionSelectorScanner) calculateScores(results []types.RegionComparison) {
-	// Use default scoring weights
-	weights := types.DefaultScoringWeights()
+	weights := s.weights
 
 	for i := range results {
 		// Resource type availability score (0-100)
@@ -577,15 +693,15 @@ func (s *RegionSelectorScanner) calculateScores(results []types.RegionComparison
 
 		// SKU availability score (0-100).
 		// Denominator excludes unknowns (API errors) so they don't deflate the score.
-		// Zone-restricted SKUs (some zones blocked, liftable via support) count as 75%.
-		// Restricted SKUs (subscription-level regional block) count as 50%.
+		// Zone-restricted SKUs (some zones blocked, liftable via support) count as 75% by default.
+		// Restricted SKUs (subscription-level regional block) count as 50% by default.
 		// When all SKU checks are unknown (confirmedChecked==0), score stays 100 (neutral).
 		skuAvailabilityScore := 100.0
 		confirmedChecked := results[i].TotalSKUsChecked - results[i].UnknownSKUs
 		if confirmedChecked > 0 {
 			effectiveAvailable := float64(results[i].AvailableSKUs) +
-				float64(len(results[i].ZoneRestrictedSKUs))*0.75 +
-				float64(len(results[i].RestrictedSKUs))*0.5
+				float64(len(results[i].ZoneRestrictedSKUs))*weights.ZoneRestrictedSKUCredit +
+				float64(len(results[i].RestrictedSKUs))*weights.RestrictedSKUCredit
 			skuAvailabilityScore = (effectiveAvailable / float64(confirmedChecked)) * 100
 			if skuAvailabilityScore > 100 {
 				skuAvailabilityScore = 100
@@ -593,12 +709,12 @@ func (s *RegionSelectorScanner) calculateScores(results []types.RegionComparison
 		}
 
 		// Cost component: lower target-region cost = higher score.
-		// Scale: 0% diff → 100, +50% diff → 0, negative diff (cheaper) → capped at 100.
-		// A ×2 multiplier gives a ±50% range, which covers real inter-region price spreads
+		// Scale: 0% diff → 100, +CostRangePercent diff → 0, negative diff (cheaper) → capped at 100.
+		// The default ±50% range covers real inter-region price spreads
 		// without collapsing all large-but-valid deltas to the same zero score.
 		costScore := 100.0
 		if results[i].HasCostData {
-			costScore = 100 - (results[i].AvgCostDifference * 2)
+			costScore = 100 - (results[i].AvgCostDifference * 100 / weights.CostRangePercent)
 			if costScore > 100 {
 				costScore = 100
 			}
@@ -608,39 +724,43 @@ func (s *RegionSelectorScanner) calculateScores(results []types.RegionComparison
 		}
 
 		// Latency component: lower latency = higher score
-		// <50ms = 100 points, >200ms = 0 points, linear interpolation
+		// <LatencyBestMs (50ms) = 100 points, >LatencyWorstMs (200ms) = 0 points, linear interpolation
+		// Targets without a measurement from the user locations get the midpoint
+		// score: they are neither rewarded nor penalised against measured targets.
 		latencyScore := 100.0
-		if results[i].AvgLatencyMs > 0 {
-			if results[i].AvgLatencyMs < 50 {
+		if results[i].LatencySource == latency.SourceNoUserMeasurement {
+			latencyScore = 50.0
+		} else if results[i].AvgLatencyMs > 0 {
+			if results[i].AvgLatencyMs < weights.LatencyBestMs {
 				latencyScore = 100.0
-			} else if results[i].AvgLatencyMs > 200 {
+			} else if results[i].AvgLatencyMs > weights.LatencyWorstMs {
 				latencyScore = 0.0
 			} else {
-				// Linear interpolation between 50ms and 200ms
-				latencyScore = 100.0 - ((results[i].AvgLatencyMs - 50) / 150 * 100)
+				// Linear interpolation between the best and worst latency
+				latencyScore = 100.0 - ((results[i].AvgLatencyMs - weights.LatencyBestMs) / (weights.LatencyWorstMs - weights.LatencyBestMs) * 100)
 			}
 		}
 
-		// Calculate final weighted score with configurable weights
+		// Calculate final weighted score
 		// Default: 35% resource availability, 30% SKU availability, 15% cost, 20% latency
 		results[i].Score = (resourceAvailabilityScore * weights.ResourceAvailability) +
 			(skuAvailabilityScore * weights.SKUAvailability) +
 			(costScore * weights.Cost) +
 			(latencyScore * weights.Latency)
 
-		// Zone mismatch penalty: multiplicative reduction proportional to zones lost.
+		// Zone mismatch penalty: multiplicative reduction proportional to zones lost,
+		// up to ZoneLossPenalty (10% by default) for complete zone loss.
 		// Uses a multiplier so all degrees of loss remain distinguishable at any base score.
 		// - src=3, tgt=3 → ×1.000 (no reduction)
 		// - src=3, tgt=2 → ×0.967 (−3.3%)
 		// - src=3, tgt=0 → ×0.900 (−10%)
 		// - src=0 (any)  → ×1.000 (no zones to lose)
 		// - tgt > src    → ×1.000 (zone gain is not penalised)
-		const maxZoneFactor = 0.10 // max 10% score reduction for complete zone loss
 		src := results[i].SourceZoneCount
 		tgt := results[i].TargetZoneCount
 		if src > 0 && tgt < src {
 			zoneLossFraction := float64(src-tgt) / float64(src)
-			results[i].Score *= (1.0 - zoneLossFraction*maxZoneFactor)
+			results[i].Score *= (1.0 - zoneLossFraction*weights.ZoneLossPenalty)
 		}
 
 		log.Debug().Msgf("Region %s -> %s scores: resource_avail=%.2f (%.0f%%), sku_avail=%.2f (%.0f%%), cost=%.2f (%.0f%%), latency=%.2f (%.0f%%), final=%.2f",
@@ -668,9 +788,12 @@ func (s *RegionSelectorScanner) generateOutputTable(results []types.RegionCompar
 			costDiffStr = fmt.Sprintf("%+.2f%%", result.AvgCostDifference)
 		}
 
-		latencyStr := "N/A"
+		latencyStr, latencySource := "N/A", "N/A"
 		if result.AvgLatencyMs > 0 {
 			latencyStr = fmt.Sprintf("%.1f", result.AvgLatencyMs)
+			latencySource = result.LatencySource
+		} else if result.LatencySource == latency.SourceNoUserMeasurement {
+			latencySource = result.LatencySource
 		}
 
 		skuAvailabilityStr := "N/A"
@@ -723,7 +846,9 @@ func (s *RegionSelectorScanner) generateOutputTable(results []types.RegionCompar
 		if costDiffStr == "N/A" {
 			qualityParts = append(qualityParts, "no cost data")
 		}
-		if latencyStr == "N/A" {
+		if result.LatencySource == latency.SourceNoUserMeasurement {
+			qualityParts = append(qualityParts, "no user latency measurement")
+		} else if latencyStr == "N/A" {
 			qualityParts = append(qualityParts, "no latency data")
 		} else if result.LatencyEstimated {
 			qualityParts = append(qualityParts, "estimated latency")
@@ -770,6 +895,7 @@ func (s *RegionSelectorScanner) generateOutputTable(results []types.RegionCompar
 			unavailSKUs,
 			restrictedSKUs,
 			zoneRestrictedSKUs,
+			latencySource,
 		})
 	}
 
@@ -813,7 +939,7 @@ func (s *RegionSelectorScanner) buildInventoryForSubscription(subscriptionID str
 		skuName := resource.SkuName
 
 		// Normalize location once; reused for both SKU and region tracking.
-		location := types.NormalizeRegionName(resource.Location)
+		location := az.NormalizeRegionName(resource.Location)
 
 		// Track SKUs by resource type and by region.
 		// Only presence matters (consumers iterate keys, not values).
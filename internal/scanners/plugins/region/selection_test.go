@@ -7,6 +7,7 @@ import (
 	"math"
 	"testing"
 
+	"github.com/Azure/azqr/internal/scanners/plugins/region/latency"
 	"github.com/Azure/azqr/internal/scanners/plugins/region/types"
 )
 
@@ -88,6 +89,25 @@ func TestCalculateScores_NoLatencyData(t *testing.T) {
 	}
 }
 
+// TestCalculateScores_NoUserMeasurement: with user locations, a target without
+// a user measurement gets the midpoint latency score, not the no-data 100.
+func TestCalculateScores_NoUserMeasurement(t *testing.T) {
+	// expected = 100*0.35 + 100*0.30 + 100*0.15 + 50*0.20 = 90.0
+	results := []types.RegionComparison{
+		{
+			AvailabilityPercent: 100.0,
+			TotalSKUsChecked:    0,
+			AvgCostDifference:   0,
+			AvgLatencyMs:        0,
+			LatencySource:       latency.SourceNoUserMeasurement,
+		},
+	}
+	NewScanner().calculateScores(results)
+	if !approxEqual(results[0].Score, 90.0, 0.01) {
+		t.Errorf("expected 90 without a user measurement, got %.4f", results[0].Score)
+	}
+}
+
 // TestCalculateScores_HighLatency: latency > 200ms → latencyScore = 0.
 func TestCalculateScores_HighLatency(t *testing.T) {
 	// resourceAvail=100, skuAvail=100(neutral), cost=100(neutral), latency=0
@@ -304,3 +324,26 @@ func TestCalculateScores_CostScoreFloorAtZero(t *testing.T) {
 		t.Errorf("expected %.2f with cost score floored at 0, got %.4f", expected, results[0].Score)
 	}
 }
+
+// TestGenerateOutputTable_RowsMatchHeader: every row has one cell per column and
+// the scoring weights are not repeated per row.
+func TestGenerateOutputTable_RowsMatchHeader(t *testing.T) {
+	s := NewScanner()
+	table := s.generateOutputTable([]types.RegionComparison{
+		{SubscriptionName: "Sub", SourceRegion: "eastus", TargetRegion: "westus", Score: 85, AvgLatencyMs: 40, LatencySource: "published"},
+	})
+	if len(table) != 2 {
+		t.Fatalf("expected header and one row, got %d rows", len(table))
+	}
+	if len(table[1]) != len(table[0]) {
+		t.Errorf("row has %d cells, header has %d", len(table[1]), len(table[0]))
+	}
+	for _, cell := range table[1] {
+		if cell == s.weights.String() {
+			t.Errorf("scoring weights should be reported once in the sheet description, not per row")
+		}
+	}
+	if got := table[1][len(table[1])-1]; got != "published" {
+		t.Errorf("last column = %q, want latency source", got)
+	}
+}
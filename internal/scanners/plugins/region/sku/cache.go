@@ -48,7 +48,7 @@ func (c *Cache) GetSKUAvailability(
 	httpClient *az.HttpClient,
 ) (map[string]types.SKUAvailability, error) {
 	resourceType = strings.ToLower(resourceType)
-	targetRegion = types.NormalizeRegionName(targetRegion)
+	targetRegion = az.NormalizeRegionName(targetRegion)
 
 	provider := Get(resourceType)
 	if provider == nil {
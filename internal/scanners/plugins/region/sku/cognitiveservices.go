@@ -42,7 +42,7 @@ func (CognitiveServicesProvider) ResourceType() string {
 
 func (CognitiveServicesProvider) FetchSKUs(ctx context.Context, subID, region string, client *az.HttpClient) (map[string]types.SKUAvailability, error) {
 	url := "https://management.azure.com/subscriptions/" + subID + "/providers/Microsoft.CognitiveServices/skus?api-version=2024-10-01"
-	target := types.NormalizeRegionName(region)
+	target := az.NormalizeRegionName(region)
 
 	return FetchPagedSKUs(ctx, url, client, func(item cognitiveServicesSKUItem) (string, types.SKUAvailability, bool) {
 		if item.Name == "" {
@@ -51,7 +51,7 @@ func (CognitiveServicesProvider) FetchSKUs(ctx context.Context, subID, region st
 		// Filter: only keep items whose location list includes the target region.
 		found := len(item.Locations) == 0 // empty locations = globally available
 		for _, loc := range item.Locations {
-			if strings.EqualFold(types.NormalizeRegionName(loc), target) {
+			if strings.EqualFold(az.NormalizeRegionName(loc), target) {
 				found = true
 				break
 			}
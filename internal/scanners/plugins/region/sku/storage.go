@@ -30,14 +30,14 @@ func (StorageProvider) ResourceType() string { return "microsoft.storage/storage
 
 func (StorageProvider) FetchSKUs(ctx context.Context, subID, region string, client *az.HttpClient) (map[string]types.SKUAvailability, error) {
 	endpoint := "https://management.azure.com/subscriptions/" + subID + "/providers/Microsoft.Storage/skus?api-version=2023-01-01"
-	target := types.NormalizeRegionName(region)
+	target := az.NormalizeRegionName(region)
 
 	return FetchPagedSKUs(ctx, endpoint, client,
 		func(item storageSKUItem) (string, types.SKUAvailability, bool) {
 			// Filter: only accept items that list the target region.
 			appliesTo := len(item.Locations) == 0 // no locations = globally available
 			for _, loc := range item.Locations {
-				if strings.EqualFold(types.NormalizeRegionName(loc), target) {
+				if strings.EqualFold(az.NormalizeRegionName(loc), target) {
 					appliesTo = true
 					break
 				}
@@ -4,27 +4,101 @@
 package types
 
 import (
+	"fmt"
+	"math"
 	"strings"
 )
 
-// scoringWeights defines the weights for the scoring algorithm
-type scoringWeights struct {
+// ScoringWeights defines the weights and thresholds of the scoring algorithm
+type ScoringWeights struct {
 	ResourceAvailability float64 // Weight for resource type availability (default: 0.35)
 	SKUAvailability      float64 // Weight for SKU-level availability (default: 0.30)
 	Cost                 float64 // Weight for cost difference (default: 0.15)
 	Latency              float64 // Weight for network latency (default: 0.20)
+
+	LatencyBestMs           float64 // Latency at or below which the latency score is 100 (default: 50)
+	LatencyWorstMs          float64 // Latency at or above which the latency score is 0 (default: 200)
+	CostRangePercent        float64 // Cost increase that scores 0; the same decrease caps at 100 (default: 50)
+	ZoneLossPenalty         float64 // Maximum score reduction for losing all availability zones (default: 0.10)
+	ZoneRestrictedSKUCredit float64 // Credit for a zone-restricted SKU relative to an available one (default: 0.75)
+	RestrictedSKUCredit     float64 // Credit for a subscription-restricted SKU relative to an available one (default: 0.5)
 }
 
 // DefaultScoringWeights returns the default scoring weights
-func DefaultScoringWeights() scoringWeights {
-	return scoringWeights{
+func DefaultScoringWeights() ScoringWeights {
+	return ScoringWeights{
 		ResourceAvailability: 0.35,
 		SKUAvailability:      0.30,
 		Cost:                 0.15,
 		Latency:              0.20,
+
+		LatencyBestMs:           50,
+		LatencyWorstMs:          200,
+		CostRangePercent:        50,
+		ZoneLossPenalty:         0.10,
+		ZoneRestrictedSKUCredit: 0.75,
+		RestrictedSKUCredit:     0.5,
 	}
 }
 
+// Validate checks that the weights are between 0 and 1 and sum to 1, so the
+// weighted score stays in the 0-100 range, and that the thresholds are consistent.
+func (w ScoringWeights) Validate() error {
+	weights := []struct {
+		name  string
+		value float64
+	}{
+		{"resource availability weight", w.ResourceAvailability},
+		{"SKU availability weight", w.SKUAvailability},
+		{"cost weight", w.Cost},
+		{"latency weight", w.Latency},
+		{"zone loss penalty", w.ZoneLossPenalty},
+		{"zone-restricted SKU credit", w.ZoneRestrictedSKUCredit},
+		{"restricted SKU credit", w.RestrictedSKUCredit},
+	}
+	for _, weight := range weights {
+		if weight.value < 0 || weight.value > 1 {
+			return fmt.Errorf("%s must be between 0 and 1, got %g", weight.name, weight.value)
+		}
+	}
+
+	sum := w.ResourceAvailability + w.SKUAvailability + w.Cost + w.Latency
+	if math.Abs(sum-1) > 1e-6 {
+		return fmt.Errorf("weights must sum to 100%%, got %g%%", sum*100)
+	}
+	if w.LatencyBestMs < 0 {
+		return fmt.Errorf("best latency must not be negative, got %g ms", w.LatencyBestMs)
+	}
+	if w.LatencyWorstMs <= w.LatencyBestMs {
+		return fmt.Errorf("worst latency (%g ms) must be greater than best latency (%g ms)", w.LatencyWorstMs, w.LatencyBestMs)
+	}
+	if w.CostRangePercent <= 0 {
+		return fmt.Errorf("cost range must be greater than 0, got %g%%", w.CostRangePercent)
+	}
+	return nil
+}
+
+// String returns the weights as percentages, e.g.
+// "Resource Types 35%, SKUs 30%, Cost 15%, Latency 20%".
+func (w ScoringWeights) String() string {
+	return fmt.Sprintf("Resource Types %g%%, SKUs %g%%, Cost %g%%, Latency %g%%",
+		percent(w.ResourceAvailability), percent(w.SKUAvailability), percent(w.Cost), percent(w.Latency))
+}
+
+// Thresholds returns the scoring thresholds, e.g. "Latency 50-200 ms, Cost
+// Range 50%, Zone Loss Penalty 10%, Zone-Restricted SKU Credit 75%,
+// Restricted SKU Credit 50%".
+func (w ScoringWeights) Thresholds() string {
+	return fmt.Sprintf("Latency %g-%g ms, Cost Range %g%%, Zone Loss Penalty %g%%, Zone-Restricted SKU Credit %g%%, Restricted SKU Credit %g%%",
+		w.LatencyBestMs, w.LatencyWorstMs, w.CostRangePercent,
+		percent(w.ZoneLossPenalty), percent(w.ZoneRestrictedSKUCredit), percent(w.RestrictedSKUCredit))
+}
+
+// percent converts a fraction to a percentage rounded to two decimals
+func percent(v float64) float64 {
+	return math.Round(v*10000) / 100
+}
+
 // SKUAvailabilityState is the availability state for an individual SKU check.
 type SKUAvailabilityState int
 
@@ -64,6 +138,7 @@ type RegionComparison struct {
 	HasCostData             bool    // True when at least one meter was successfully priced in both regions
 	AvgLatencyMs            float64 // Average network latency in milliseconds
 	LatencyEstimated        bool    // True when AvgLatencyMs is a cluster-based estimate, not a direct measurement
+	LatencySource           string  // Origin of AvgLatencyMs: Published, Estimated, Override, User Locations or No User Measurement; empty when unknown
 	MissingResourceTypes    []string
 	MissingSKUs             []string          // Specific SKUs not available in target region (hard block)
 	RestrictedSKUs          []string          // SKUs regionally restricted for this subscription (NotAvailableForSubscription — quota-liftable)
@@ -79,6 +154,17 @@ type RegionComparison struct {
 	Score                   float64
 }
 
+// RegionInfo holds the geography metadata of a physical region from the Locations API.
+type RegionInfo struct {
+	Name             string
+	DisplayName      string
+	Geography        string   // e.g. "Sweden", "United States"
+	GeographyGroup   string   // e.g. "Europe", "US"
+	PhysicalLocation string   // e.g. "Gävle", "Virginia"
+	PairedRegions    []string // normalized names of the paired regions
+	ZoneCount        int
+}
+
 // ResourceTypeLocationData caches all provider information for fast lookup.
 // The innermost map is a set (struct{} value) for O(1) membership tests.
 type ResourceTypeLocationData struct {
@@ -225,41 +311,3 @@ type CostComparisonData struct {
 	PriceItems    []RetailPriceItem             // Full retail price items (for JSON debug output)
 	UomErrors     []UoMError                    // Unit-of-measure mismatches excluded from comparison
 }
-
-// NormalizeRegionName converts region display names to lowercase identifiers
-// by removing spaces and converting to lowercase. Use this everywhere a region
-// name needs to be compared or stored.
-func NormalizeRegionName(region string) string {
-	return strings.ToLower(strings.ReplaceAll(region, " ", ""))
-}
-
-// nonPhysicalRegions contains Azure meta/logical region identifiers that do not
-// correspond to a deployable physical region. Quota and cost APIs are meaningless for these.
-var nonPhysicalRegions = map[string]bool{
-	"":             true,
-	"unassigned":   true,
-	"global":       true,
-	"europe":       true,
-	"unitedstates": true,
-	"asia":         true,
-	"asiapacific":  true,
-	"australia":    true,
-	"brazil":       true,
-	"canada":       true,
-	"france":       true,
-	"germany":      true,
-	"india":        true,
-	"japan":        true,
-	"korea":        true,
-	"norway":       true,
-	"southafrica":  true,
-	"switzerland":  true,
-	"uae":          true,
-	"uk":           true,
-}
-
-// IsPhysicalRegion returns true when region is a real deployable Azure region
-// (not a meta/logical identifier like "global", "europe", etc.).
-func IsPhysicalRegion(region string) bool {
-	return !nonPhysicalRegions[NormalizeRegionName(region)]
-}
@@ -28,6 +28,39 @@ func TestDefaultScoringWeights(t *testing.T) {
 	if diff := sum - 1.0; diff > 1e-9 || diff < -1e-9 {
 		t.Errorf("weights sum to %v, want 1.0", sum)
 	}
+	if err := w.Validate(); err != nil {
+		t.Errorf("default weights are invalid: %v", err)
+	}
+	if got, want := w.String(), "Resource Types 35%, SKUs 30%, Cost 15%, Latency 20%"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if got, want := w.Thresholds(), "Latency 50-200 ms, Cost Range 50%, Zone Loss Penalty 10%, Zone-Restricted SKU Credit 75%, Restricted SKU Credit 50%"; got != want {
+		t.Errorf("Thresholds() = %q, want %q", got, want)
+	}
+}
+
+func TestScoringWeights_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(w *ScoringWeights)
+	}{
+		{"weights do not sum to 1", func(w *ScoringWeights) { w.Latency = 0.5 }},
+		{"negative weight", func(w *ScoringWeights) { w.Cost = -0.15; w.Latency = 0.5 }},
+		{"worst latency below best", func(w *ScoringWeights) { w.LatencyWorstMs = 40 }},
+		{"negative best latency", func(w *ScoringWeights) { w.LatencyBestMs = -1 }},
+		{"zero cost range", func(w *ScoringWeights) { w.CostRangePercent = 0 }},
+		{"zone loss penalty above 1", func(w *ScoringWeights) { w.ZoneLossPenalty = 1.5 }},
+		{"restricted credit above 1", func(w *ScoringWeights) { w.RestrictedSKUCredit = 2 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := DefaultScoringWeights()
+			tt.modify(&w)
+			if err := w.Validate(); err == nil {
+				t.Error("expected a validation error")
+			}
+		})
+	}
 }
 
 func TestResourceTypeLocationData_IsAvailable(t *testing.T) {
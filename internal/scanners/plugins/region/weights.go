@@ -0,0 +1,153 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package region
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Azure/azqr/internal/models"
+	"github.com/Azure/azqr/internal/plugins"
+	"github.com/Azure/azqr/internal/scanners/plugins/region/types"
+	"gopkg.in/yaml.v3"
+)
+
+// weightsFileOption is the plugin option naming a YAML file of scoring weights
+const weightsFileOption = "weights-file"
+
+// scoringOption maps a plugin option to a field of types.ScoringWeights.
+// Options with a scale of 100 are given in percent.
+type scoringOption struct {
+	name        string
+	description string
+	scale       float64
+	field       func(w *types.ScoringWeights) *float64
+}
+
+// scoringOptions are the scoring options, shared by the plugin options and the
+// keys of the weights file
+var scoringOptions = []scoringOption{
+	{"weight-resource-availability", "Weight of resource type availability in the score (percent)", 100,
+		func(w *types.ScoringWeights) *float64 { return &w.ResourceAvailability }},
+	{"weight-sku-availability", "Weight of SKU availability in the score (percent)", 100,
+		func(w *types.ScoringWeights) *float64 { return &w.SKUAvailability }},
+	{"weight-cost", "Weight of the cost difference in the score (percent)", 100,
+		func(w *types.ScoringWeights) *float64 { return &w.Cost }},
+	{"weight-latency", "Weight of network latency in the score (percent)", 100,
+		func(w *types.ScoringWeights) *float64 { return &w.Latency }},
+	{"latency-best-ms", "Latency in ms at or below which the latency score is 100", 1,
+		func(w *types.ScoringWeights) *float64 { return &w.LatencyBestMs }},
+	{"latency-worst-ms", "Latency in ms at or above which the latency score is 0", 1,
+		func(w *types.ScoringWeights) *float64 { return &w.LatencyWorstMs }},
+	{"cost-range-percent", "Cost increase in percent that scores 0 (the same decrease scores 100)", 1,
+		func(w *types.ScoringWeights) *float64 { return &w.CostRangePercent }},
+	{"zone-loss-penalty-percent", "Maximum score reduction in percent when all availability zones are lost", 100,
+		func(w *types.ScoringWeights) *float64 { return &w.ZoneLossPenalty }},
+	{"zone-restricted-sku-credit", "Credit (0-1) for a SKU restricted in some zones, relative to an available SKU", 1,
+		func(w *types.ScoringWeights) *float64 { return &w.ZoneRestrictedSKUCredit }},
+	{"restricted-sku-credit", "Credit (0-1) for a SKU restricted for the subscription, relative to an available SKU", 1,
+		func(w *types.ScoringWeights) *float64 { return &w.RestrictedSKUCredit }},
+}
+
+// scoringPluginOptions returns the plugin options of the scoring weights,
+// with the default weights as defaults.
+func scoringPluginOptions() []plugins.PluginOption {
+	defaults := types.DefaultScoringWeights()
+	options := []plugins.PluginOption{
+		{Name: weightsFileOption, Type: "string", Default: "", Description: "YAML file with scoring weights and thresholds, keyed by option name"},
+	}
+	for _, opt := range scoringOptions {
+		options = append(options, plugins.PluginOption{
+			Name:        opt.name,
+			Type:        "float64",
+			Default:     *opt.field(&defaults) * opt.scale,
+			Description: opt.description,
+		})
+	}
+	return options
+}
+
+// resolveScoringWeights returns the scoring weights for a scan: the defaults,
+// overridden by the weights file and then by options set explicitly.
+func resolveScoringWeights(stages *models.StageConfigs, pluginName string) (types.ScoringWeights, error) {
+	weights := types.DefaultScoringWeights()
+
+	options := stages.GetPluginOptions(pluginName)
+	if path, _ := options[weightsFileOption].(string); path != "" {
+		values, err := readWeightsFile(path)
+		if err != nil {
+			return weights, err
+		}
+		if err := applyScoringValues(&weights, values); err != nil {
+			return weights, fmt.Errorf("weights file %s: %w", path, err)
+		}
+	}
+
+	// Only options set on the command line override the file; the others
+	// hold their defaults.
+	explicit := map[string]any{}
+	if stages != nil {
+		prefix := pluginName + "."
+		for key, value := range stages.GetStageOptions(models.StageNamePlugin) {
+			name, ok := strings.CutPrefix(key, prefix)
+			if _, scoring := findScoringOption(name); ok && scoring {
+				explicit[name] = value
+			}
+		}
+	}
+	if err := applyScoringValues(&weights, explicit); err != nil {
+		return weights, err
+	}
+
+	if err := weights.Validate(); err != nil {
+		return weights, fmt.Errorf("invalid scoring weights: %w", err)
+	}
+	return weights, nil
+}
+
+// readWeightsFile reads a YAML weights file
+func readWeightsFile(path string) (map[string]any, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read weights file: %w", err)
+	}
+	values := map[string]any{}
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse weights file %s: %w", path, err)
+	}
+	return values, nil
+}
+
+// applyScoringValues sets the weights from values keyed by option name.
+func applyScoringValues(weights *types.ScoringWeights, values map[string]any) error {
+	for key, value := range values {
+		opt, ok := findScoringOption(key)
+		if !ok {
+			return fmt.Errorf("unknown scoring option %q", key)
+		}
+		var v float64
+		switch n := value.(type) {
+		case float64:
+			v = n
+		case int:
+			v = float64(n)
+		default:
+			return fmt.Errorf("scoring option %s must be a number, got %v", key, value)
+		}
+		*opt.field(weights) = v / opt.scale
+	}
+	return nil
+}
+
+// findScoringOption returns the scoring option with the given name
+func findScoringOption(name string) (scoringOption, bool) {
+	for _, opt := range scoringOptions {
+		if opt.name == name {
+			return opt, true
+		}
+	}
+	return scoringOption{}, false
+}
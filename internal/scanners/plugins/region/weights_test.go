@@ -0,0 +1,156 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package region
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Azure/azqr/internal/models"
+	"github.com/Azure/azqr/internal/scanners/plugins/region/types"
+)
+
+func TestResolveScoringWeights_Defaults(t *testing.T) {
+	weights, err := resolveScoringWeights(nil, "region-selection")
+	if err != nil {
+		t.Fatalf("resolveScoringWeights() error = %v", err)
+	}
+	if weights != types.DefaultScoringWeights() {
+		t.Errorf("expected default weights, got %+v", weights)
+	}
+}
+
+func TestResolveScoringWeights_FileAndOptions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "weights.yaml")
+	content := `weight-resource-availability: 25
+weight-sku-availability: 25
+weight-cost: 10
+weight-latency: 40
+latency-worst-ms: 100
+zone-loss-penalty-percent: 20
+`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	stages := models.NewStageConfigs()
+	if err := stages.SetPluginOptions("region-selection", map[string]any{
+		"weights-file":    path,
+		"latency-best-ms": 10.0,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	weights, err := resolveScoringWeights(stages, "region-selection")
+	if err != nil {
+		t.Fatalf("resolveScoringWeights() error = %v", err)
+	}
+
+	want := types.DefaultScoringWeights()
+	want.ResourceAvailability = 0.25
+	want.SKUAvailability = 0.25
+	want.Cost = 0.10
+	want.Latency = 0.40
+	want.LatencyBestMs = 10
+	want.LatencyWorstMs = 100
+	want.ZoneLossPenalty = 0.20
+	if weights != want {
+		t.Errorf("weights = %+v, want %+v", weights, want)
+	}
+}
+
+func TestResolveScoringWeights_OptionsOverrideFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "weights.yaml")
+	if err := os.WriteFile(path, []byte("weight-latency: 40\nweight-cost: 0\nweight-sku-availability: 25\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	stages := models.NewStageConfigs()
+	if err := stages.SetPluginOptions("region-selection", map[string]any{
+		"weights-file":   path,
+		"weight-cost":    5.0,
+		"weight-latency": 35.0,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	weights, err := resolveScoringWeights(stages, "region-selection")
+	if err != nil {
+		t.Fatalf("resolveScoringWeights() error = %v", err)
+	}
+	if weights.Cost != 0.05 || weights.Latency != 0.35 || weights.SKUAvailability != 0.25 {
+		t.Errorf("unexpected weights %+v", weights)
+	}
+}
+
+func TestResolveScoringWeights_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		options map[string]any
+	}{
+		{name: "weights do not sum to 100", options: map[string]any{"weight-latency": 50.0}},
+		{name: "inverted latency band", options: map[string]any{"latency-best-ms": 300.0}},
+		{name: "unknown key in file", file: "weight-latancy: 20\n"},
+		{name: "non-numeric value in file", file: "weight-latency: high\n"},
+		{name: "missing file", options: map[string]any{"weights-file": filepath.Join(t.TempDir(), "missing.yaml")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := map[string]any{}
+			for k, v := range tt.options {
+				options[k] = v
+			}
+			if tt.file != "" {
+				path := filepath.Join(t.TempDir(), "weights.yaml")
+				if err := os.WriteFile(path, []byte(tt.file), 0600); err != nil {
+					t.Fatal(err)
+				}
+				options["weights-file"] = path
+			}
+
+			stages := models.NewStageConfigs()
+			if err := stages.SetPluginOptions("region-selection", options); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := resolveScoringWeights(stages, "region-selection"); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+// TestCalculateScores_CustomWeights: the weights and thresholds of the scanner are used.
+func TestCalculateScores_CustomWeights(t *testing.T) {
+	// latency band 0–100ms → 50ms scores 50; cost range 20% → +10% scores 50
+	// expected = 100*0.25 + 100*0.25 + 50*0.10 + 50*0.40 = 75, zone loss 3→0 at 20% → 60
+	results := []types.RegionComparison{
+		{
+			AvailabilityPercent: 100.0,
+			AvgCostDifference:   10.0,
+			HasCostData:         true,
+			AvgLatencyMs:        50.0,
+			SourceZoneCount:     3,
+			TargetZoneCount:     0,
+		},
+	}
+	scanner := NewScanner()
+	scanner.weights = types.ScoringWeights{
+		ResourceAvailability:    0.25,
+		SKUAvailability:         0.25,
+		Cost:                    0.10,
+		Latency:                 0.40,
+		LatencyBestMs:           0,
+		LatencyWorstMs:          100,
+		CostRangePercent:        20,
+		ZoneLossPenalty:         0.20,
+		ZoneRestrictedSKUCredit: 0.75,
+		RestrictedSKUCredit:     0.5,
+	}
+	scanner.calculateScores(results)
+	if !approxEqual(results[0].Score, 60.0, 0.01) {
+		t.Errorf("expected score 60.00, got %.4f", results[0].Score)
+	}
+}
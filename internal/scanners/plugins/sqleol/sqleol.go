@@ -41,33 +41,33 @@ func (s *Scanner) GetMetadata() plugins.PluginMetadata {
 			{Name: "Name"},
 			{Name: "Location"},
 			{Name: "Arc Server Name"},
-			{Name: "Cloud Type"},
-			{Name: "Service Type"},
-			{Name: "SQL Version"},
-			{Name: "Edition"},
-			{Name: "EOL Status"},
-			{Name: "ESU Applicable"},
-			{Name: "ESU Enabled"},
-			{Name: "ESU Start Date"},
-			{Name: "ESU End Date"},
-			{Name: "Migration Target Tier"},
+			{Name: "Cloud Type", Type: models.ColumnTypeEnum},
+			{Name: "Service Type", Type: models.ColumnTypeEnum},
+			{Name: "SQL Version", Type: models.ColumnTypeEnum},
+			{Name: "Edition", Type: models.ColumnTypeEnum},
+			{Name: "EOL Status", Type: models.ColumnTypeEnum},
+			{Name: "ESU Applicable", Type: models.ColumnTypeEnum},
+			{Name: "ESU Enabled", Type: models.ColumnTypeEnum},
+			{Name: "ESU Start Date", Type: models.ColumnTypeDate},
+			{Name: "ESU End Date", Type: models.ColumnTypeDate},
+			{Name: "Migration Target Tier", Type: models.ColumnTypeEnum},
 			{Name: "Migration Recommendation"},
-			{Name: "vCores"},
-			{Name: "Billable Cores"},
-			{Name: "ESU Monthly Cost/Core"},
-			{Name: "SQL License Type"},
-			{Name: "SQL License Cost/Core/Month"},
-			{Name: "SQL License Monthly Cost"},
-			{Name: "VM Cost/Core/Month"},
-			{Name: "Est VM Compute Monthly Cost"},
-			{Name: "Est ESU Monthly Cost"},
-			{Name: "ESU Cost Basis"},
-			{Name: "Patch Ops Monthly Cost"},
-			{Name: "Current Monthly Cost"},
-			{Name: "Consolidation Ratio"},
-			{Name: "Est SQL MI Monthly Cost"},
-			{Name: "Est SQL MI Monthly Saving"},
-			{Name: "SQL MI Migration Verdict"},
+			{Name: "vCores", Type: models.ColumnTypeNumber},
+			{Name: "Billable Cores", Type: models.ColumnTypeNumber},
+			{Name: "ESU Monthly Cost/Core", Type: models.ColumnTypeCurrency, Unit: "USD"},
+			{Name: "SQL License Type", Type: models.ColumnTypeEnum},
+			{Name: "SQL License Cost/Core/Month", Type: models.ColumnTypeCurrency, Unit: "USD"},
+			{Name: "SQL License Monthly Cost", Type: models.ColumnTypeCurrency, Unit: "USD"},
+			{Name: "VM Cost/Core/Month", Type: models.ColumnTypeCurrency, Unit: "USD"},
+			{Name: "Est VM Compute Monthly Cost", Type: models.ColumnTypeCurrency, Unit: "USD"},
+			{Name: "Est ESU Monthly Cost", Type: models.ColumnTypeCurrency, Unit: "USD"},
+			{Name: "ESU Cost Basis", Type: models.ColumnTypeEnum},
+			{Name: "Patch Ops Monthly Cost", Type: models.ColumnTypeCurrency, Unit: "USD"},
+			{Name: "Current Monthly Cost", Type: models.ColumnTypeCurrency, Unit: "USD"},
+			{Name: "Consolidation Ratio", Type: models.ColumnTypeNumber},
+			{Name: "Est SQL MI Monthly Cost", Type: models.ColumnTypeCurrency, Unit: "USD"},
+			{Name: "Est SQL MI Monthly Saving", Type: models.ColumnTypeCurrency, Unit: "USD"},
+			{Name: "SQL MI Migration Verdict", Type: models.ColumnTypeEnum},
 		},
 	}
 }
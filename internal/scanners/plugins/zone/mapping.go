@@ -13,6 +13,7 @@ import (
 	"github.com/Azure/azqr/internal/az"
 	"github.com/Azure/azqr/internal/models"
 	"github.com/Azure/azqr/internal/plugins"
+	"github.com/Azure/azqr/internal/progress"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/rs/zerolog/log"
 )
@@ -64,10 +65,12 @@ func (s *ZoneMappingScanner) Scan(ctx context.Context, cred azcore.TokenCredenti
 	const maxWorkers = 5
 	semaphore := make(chan struct{}, maxWorkers)
 
+	tracker := progress.NewTracker(ctx, "subscriptions", len(subscriptions))
 	for subID, subName := range subscriptions {
 		wg.Add(1)
 		go func(subscriptionID, subscriptionName string) {
 			defer wg.Done()
+			defer tracker.Step(subscriptionName)
 
 			// Check for context cancellation before acquiring the semaphore
 			if ctx.Err() != nil {
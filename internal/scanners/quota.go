@@ -0,0 +1,192 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azqr/internal/az"
+	"github.com/Azure/azqr/internal/models"
+	"github.com/Azure/azqr/internal/progress"
+	"github.com/Azure/azqr/internal/quota"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/rs/zerolog/log"
+)
+
+// numQuotaWorkers is the number of subscription+region pairs queried at the same time
+const numQuotaWorkers = 4
+
+// Quota statuses reported in QuotaResult.Status
+const (
+	QuotaStatusNearLimit   = "Near Limit"
+	QuotaStatusAtOverLimit = "At/Over Limit"
+)
+
+// QuotaScanner reports quotas near or over their limit in every subscription
+// and region that holds resources.
+type QuotaScanner struct{}
+
+// quotaLocation is a subscription+region pair to query
+type quotaLocation struct {
+	subscriptionID string
+	location       string
+}
+
+// Scan queries the compute (VM family), network, storage, App Service and SQL
+// quota usages of each subscription+region holding resources and returns the
+// entries with less than thresholdPct percent headroom.
+func (s *QuotaScanner) Scan(ctx context.Context, cred azcore.TokenCredential, clientOpts *arm.ClientOptions, subscriptions map[string]string, resources []*models.Resource, thresholdPct float64) []*models.QuotaResult {
+	models.LogResourceTypeScan("Quota")
+
+	locations := quotaLocations(resources, subscriptions)
+	if len(locations) == 0 {
+		return nil
+	}
+
+	httpClient := az.NewHttpClient(cred, az.DefaultHttpClientOptions(60*time.Second))
+	tracker := progress.NewTracker(ctx, "subscription regions", len(locations))
+
+	jobs := make(chan quotaLocation, len(locations))
+	for _, l := range locations {
+		jobs <- l
+	}
+	close(jobs)
+
+	var (
+		mu      sync.Mutex
+		results []*models.QuotaResult
+		wg      sync.WaitGroup
+	)
+	for i := 0; i < min(numQuotaWorkers, len(locations)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for l := range jobs {
+				usages := fetchQuotaUsages(ctx, cred, clientOpts, httpClient, l)
+				subName := subscriptions[l.subscriptionID]
+				var found []*models.QuotaResult
+				for _, quotaType := range []string{"VM", "Network", "Storage", "App Service", "SQL"} {
+					found = append(found, buildQuotaResults(l.subscriptionID, subName, l.location, quotaType, usages[quotaType], thresholdPct)...)
+				}
+				tracker.Step(subName)
+
+				mu.Lock()
+				results = append(results, found...)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.SubscriptionID != b.SubscriptionID {
+			return a.SubscriptionID < b.SubscriptionID
+		}
+		if a.Location != b.Location {
+			return a.Location < b.Location
+		}
+		if a.QuotaType != b.QuotaType {
+			return a.QuotaType < b.QuotaType
+		}
+		return a.Name < b.Name
+	})
+	return results
+}
+
+// quotaLocations returns the sorted subscription+region pairs holding
+// resources, skipping global and other non-physical locations.
+func quotaLocations(resources []*models.Resource, subscriptions map[string]string) []quotaLocation {
+	seen := make(map[quotaLocation]bool)
+	var locations []quotaLocation
+	for _, r := range resources {
+		if _, ok := subscriptions[r.SubscriptionID]; !ok {
+			continue
+		}
+		location := az.NormalizeRegionName(r.Location)
+		if !az.IsPhysicalRegion(location) {
+			continue
+		}
+		l := quotaLocation{subscriptionID: r.SubscriptionID, location: location}
+		if !seen[l] {
+			seen[l] = true
+			locations = append(locations, l)
+		}
+	}
+	sort.Slice(locations, func(i, j int) bool {
+		if locations[i].subscriptionID != locations[j].subscriptionID {
+			return locations[i].subscriptionID < locations[j].subscriptionID
+		}
+		return locations[i].location < locations[j].location
+	})
+	return locations
+}
+
+// fetchQuotaUsages returns the quota usages of a subscription+region keyed by
+// quota type. Quota types that fail are logged and left out.
+func fetchQuotaUsages(ctx context.Context, cred azcore.TokenCredential, clientOpts *arm.ClientOptions, httpClient *az.HttpClient, l quotaLocation) map[string][]quota.UsageEntry {
+	usages := make(map[string][]quota.UsageEntry)
+
+	vm, err := quota.FetchVMQuota(ctx, cred, clientOpts, l.subscriptionID, l.location)
+	if err != nil {
+		log.Warn().Err(err).Msgf("VM quota unavailable for %s in %s", models.MaskSubscriptionID(l.subscriptionID, true), l.location)
+	} else {
+		usages["VM"] = vm
+	}
+
+	for quotaType, fetch := range map[string]func(context.Context, *az.HttpClient, string, string) ([]quota.UsageEntry, error){
+		"Network":     quota.FetchNetworkQuota,
+		"Storage":     quota.FetchStorageQuota,
+		"App Service": quota.FetchAppServiceQuota,
+		"SQL":         quota.FetchSQLQuota,
+	} {
+		entries, err := fetch(ctx, httpClient, l.subscriptionID, l.location)
+		if err != nil {
+			log.Warn().Err(err).Msgf("%s quota unavailable for %s in %s", quotaType, models.MaskSubscriptionID(l.subscriptionID, true), l.location)
+			continue
+		}
+		usages[quotaType] = entries
+	}
+	return usages
+}
+
+// buildQuotaResults maps the usage entries of one quota type to QuotaResult
+// records, keeping only the entries near or over their limit.
+func buildQuotaResults(subscriptionID, subscriptionName, location, quotaType string, entries []quota.UsageEntry, thresholdPct float64) []*models.QuotaResult {
+	quota.ApplyThreshold(entries, thresholdPct)
+
+	var results []*models.QuotaResult
+	for _, e := range entries {
+		if !e.IsNearLimit && !e.IsAtOrOverLimit {
+			continue
+		}
+		status := QuotaStatusNearLimit
+		if e.IsAtOrOverLimit {
+			status = QuotaStatusAtOverLimit
+		}
+		localizedName := strings.TrimSpace(e.LocalizedName)
+		if localizedName == "" {
+			localizedName = e.ResourceName
+		}
+		results = append(results, &models.QuotaResult{
+			SubscriptionID:   subscriptionID,
+			SubscriptionName: subscriptionName,
+			Location:         location,
+			QuotaType:        quotaType,
+			Name:             e.ResourceName,
+			LocalizedName:    localizedName,
+			Current:          e.CurrentValue,
+			Limit:            e.Limit,
+			Available:        e.Available,
+			HeadroomPct:      e.HeadroomPct,
+			Status:           status,
+		})
+	}
+	return results
+}
@@ -0,0 +1,60 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Azure/azqr/internal/models"
+	"github.com/Azure/azqr/internal/quota"
+)
+
+func TestQuotaLocations(t *testing.T) {
+	subscriptions := map[string]string{"sub1": "Sub 1", "sub2": "Sub 2"}
+	resources := []*models.Resource{
+		{SubscriptionID: "sub2", Location: "westeurope"},
+		{SubscriptionID: "sub1", Location: "West Europe"},
+		{SubscriptionID: "sub1", Location: "westeurope"},
+		{SubscriptionID: "sub1", Location: "eastus"},
+		{SubscriptionID: "sub1", Location: "global"},
+		{SubscriptionID: "sub1", Location: ""},
+		{SubscriptionID: "sub3", Location: "eastus"},
+	}
+
+	got := quotaLocations(resources, subscriptions)
+	want := []quotaLocation{
+		{subscriptionID: "sub1", location: "eastus"},
+		{subscriptionID: "sub1", location: "westeurope"},
+		{subscriptionID: "sub2", location: "westeurope"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("quotaLocations() = %+v, want %+v", got, want)
+	}
+}
+
+func TestBuildQuotaResults(t *testing.T) {
+	entries := []quota.UsageEntry{
+		{ResourceName: "standardDSv3Family", LocalizedName: "Standard DSv3 Family vCPUs", CurrentValue: 90, Limit: 100, Available: 10, HeadroomPct: 10},
+		{ResourceName: "standardEv5Family", CurrentValue: 20, Limit: 20, Available: 0, HeadroomPct: 0, IsAtOrOverLimit: true},
+		{ResourceName: "standardFSv2Family", CurrentValue: 78, Limit: 100, Available: 22, HeadroomPct: 22},
+		{ResourceName: "standardBSFamily", CurrentValue: 10, Limit: 100, Available: 90, HeadroomPct: 90},
+	}
+
+	results := buildQuotaResults("sub1", "Sub 1", "westeurope", "VM", entries, quota.DefaultNearLimitPct)
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	if r := results[0]; r.Status != QuotaStatusNearLimit || r.LocalizedName != "Standard DSv3 Family vCPUs" || r.Available != 10 {
+		t.Errorf("near-limit result = %+v", r)
+	}
+	if r := results[1]; r.Status != QuotaStatusAtOverLimit || r.LocalizedName != "standardEv5Family" {
+		t.Errorf("at-limit result = %+v", r)
+	}
+
+	// A higher threshold also reports the FSv2 family.
+	if results := buildQuotaResults("sub1", "Sub 1", "westeurope", "VM", entries, 25); len(results) != 3 {
+		t.Errorf("threshold 25: got %d results, want 3", len(results))
+	}
+}
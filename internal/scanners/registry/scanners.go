@@ -24,7 +24,7 @@ var registry = map[string][]scannerSpec{
 	"apim":    {{"API Management", []string{"Microsoft.ApiManagement/service"}}},
 	"appcs":   {{"App Configuration", []string{"Microsoft.AppConfiguration/configurationStores"}}},
 	"appi":    {{"Application Insights", []string{"Microsoft.Insights/components", "Microsoft.Insights/activityLogAlerts"}}},
-	"arc":     {{"Azure Arc", []string{"Microsoft.AzureArcData/sqlServerInstances"}}},
+	"arc":     {{"Azure Arc", []string{"Microsoft.AzureArcData/sqlServerInstances", "Microsoft.HybridCompute/machines"}}},
 	"as":      {{"Analysis Services", []string{"Microsoft.AnalysisServices/servers"}}},
 	"asa":     {{"Stream Analytics Job", []string{"Microsoft.StreamAnalytics/streamingJobs"}}},
 	"asp":     {{"App Service Plan", []string{"Microsoft.Web/serverFarms", "Microsoft.Web/sites", "Microsoft.Web/connections", "Microsoft.Web/certificates"}}},
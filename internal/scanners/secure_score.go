@@ -0,0 +1,176 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/Azure/azqr/internal/graph"
+	"github.com/Azure/azqr/internal/models"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/rs/zerolog/log"
+)
+
+// TenantSecureScoreName is the subscription name of the tenant secure score
+const TenantSecureScoreName = "Tenant"
+
+// GetSecureScores returns the Defender for Cloud secure score of each
+// subscription, broken down per security control.
+func (s *DefenderScanner) GetSecureScores(ctx context.Context, cred azcore.TokenCredential, subscriptions map[string]string, filters *models.Filters) []*models.SecureScoreResult {
+	models.LogResourceTypeScan("Defender Secure Score")
+
+	graphClient := graph.NewGraphQuery(cred)
+	query := `
+		securityresources
+		| where (type == 'microsoft.security/securescores' and name =~ 'ascScore')
+			or (type == 'microsoft.security/securescores/securescorecontrols' and id contains '/securescores/ascscore/')
+		| project SubscriptionId = subscriptionId,
+			Type = type,
+			Name = name,
+			DisplayName = tostring(properties.displayName),
+			Current = todouble(properties.score.current),
+			Max = todouble(properties.score.max),
+			Percentage = todouble(properties.score.percentage),
+			Weight = tolong(properties.weight),
+			Healthy = toint(properties.healthyResourceCount),
+			Unhealthy = toint(properties.unhealthyResourceCount),
+			NotApplicable = toint(properties.notApplicableResourceCount)
+	`
+	log.Debug().Msg(query)
+
+	result, err := graphClient.Query(ctx, query, subscriptions)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to query Azure Resource Graph for Defender secure score")
+		return nil
+	}
+	return buildSecureScores(result.Data, subscriptions, filters)
+}
+
+// buildSecureScores maps raw secure score and secure score control rows to
+// one SecureScoreResult per subscription, applying the subscription exclusion
+// filter. Subscriptions and controls are sorted by name.
+func buildSecureScores(data []json.RawMessage, subscriptions map[string]string, filters *models.Filters) []*models.SecureScoreResult {
+	type secureScoreRow struct {
+		SubscriptionID string  `json:"SubscriptionId"`
+		Type           string  `json:"Type"`
+		Name           string  `json:"Name"`
+		DisplayName    string  `json:"DisplayName"`
+		Current        float64 `json:"Current"`
+		Max            float64 `json:"Max"`
+		Percentage     float64 `json:"Percentage"`
+		Weight         int64   `json:"Weight"`
+		Healthy        int     `json:"Healthy"`
+		Unhealthy      int     `json:"Unhealthy"`
+		NotApplicable  int     `json:"NotApplicable"`
+	}
+
+	scores := map[string]*models.SecureScoreResult{}
+	score := func(subscriptionID string) *models.SecureScoreResult {
+		key := strings.ToLower(subscriptionID)
+		if s, ok := scores[key]; ok {
+			return s
+		}
+		s := &models.SecureScoreResult{
+			SubscriptionID:   subscriptionID,
+			SubscriptionName: subscriptions[subscriptionID],
+		}
+		scores[key] = s
+		return s
+	}
+
+	for _, r := range graph.UnmarshalRows[secureScoreRow](data, "Defender secure score") {
+		if filters.Azqr.IsSubscriptionExcluded(r.SubscriptionID) {
+			continue
+		}
+		s := score(r.SubscriptionID)
+		if strings.EqualFold(r.Type, "microsoft.security/securescores") {
+			s.CurrentScore = r.Current
+			s.MaxScore = r.Max
+			s.Percentage = r.Percentage * 100
+			s.Weight = r.Weight
+			continue
+		}
+		s.Controls = append(s.Controls, &models.SecureScoreControl{
+			Name:                   r.Name,
+			DisplayName:            r.DisplayName,
+			CurrentScore:           r.Current,
+			MaxScore:               r.Max,
+			Percentage:             r.Percentage * 100,
+			HealthyResources:       r.Healthy,
+			UnhealthyResources:     r.Unhealthy,
+			NotApplicableResources: r.NotApplicable,
+		})
+	}
+
+	results := make([]*models.SecureScoreResult, 0, len(scores))
+	for _, s := range scores {
+		sortControls(s.Controls)
+		results = append(results, s)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].SubscriptionName != results[j].SubscriptionName {
+			return results[i].SubscriptionName < results[j].SubscriptionName
+		}
+		return results[i].SubscriptionID < results[j].SubscriptionID
+	})
+	return results
+}
+
+// TenantSecureScore rolls the secure scores of the subscriptions up to the
+// tenant. As in Defender for Cloud, the tenant percentage is the average of the
+// subscription percentages weighted by the subscription weights, and each
+// control sums the scores and resource counts of the subscriptions. It returns
+// nil without scores.
+func TenantSecureScore(scores []*models.SecureScoreResult) *models.SecureScoreResult {
+	if len(scores) == 0 {
+		return nil
+	}
+
+	tenant := &models.SecureScoreResult{SubscriptionName: TenantSecureScoreName}
+	controls := map[string]*models.SecureScoreControl{}
+	var weighted float64
+	for _, s := range scores {
+		// Subscriptions without a weight count once
+		weight := s.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+		tenant.CurrentScore += s.CurrentScore
+		tenant.MaxScore += s.MaxScore
+		tenant.Weight += weight
+		weighted += float64(weight) * s.Percentage
+
+		for _, c := range s.Controls {
+			t, ok := controls[c.Name]
+			if !ok {
+				t = &models.SecureScoreControl{Name: c.Name, DisplayName: c.DisplayName}
+				controls[c.Name] = t
+				tenant.Controls = append(tenant.Controls, t)
+			}
+			t.CurrentScore += c.CurrentScore
+			t.MaxScore += c.MaxScore
+			t.HealthyResources += c.HealthyResources
+			t.UnhealthyResources += c.UnhealthyResources
+			t.NotApplicableResources += c.NotApplicableResources
+		}
+	}
+	tenant.Percentage = weighted / float64(tenant.Weight)
+	for _, c := range tenant.Controls {
+		if c.MaxScore > 0 {
+			c.Percentage = c.CurrentScore / c.MaxScore * 100
+		}
+	}
+	sortControls(tenant.Controls)
+	return tenant
+}
+
+// sortControls sorts security controls by display name.
+func sortControls(controls []*models.SecureScoreControl) {
+	sort.Slice(controls, func(i, j int) bool {
+		return controls[i].DisplayName < controls[j].DisplayName
+	})
+}
@@ -2,6 +2,7 @@ package scanners
 
 import (
 	"context"
+	"fmt"
 	"slices"
 
 	"github.com/Azure/azqr/internal/models"
@@ -14,10 +15,10 @@ import (
 
 type SubcriptionDiscovery struct{}
 
-func (sc *SubcriptionDiscovery) ListSubscriptions(ctx context.Context, cred azcore.TokenCredential, subscriptions []string, filters *models.Filters, options *arm.ClientOptions) map[string]string {
+func (sc *SubcriptionDiscovery) ListSubscriptions(ctx context.Context, cred azcore.TokenCredential, subscriptions []string, filters *models.Filters, options *arm.ClientOptions) (map[string]string, error) {
 	client, err := armsubscription.NewSubscriptionsClient(cred, options)
 	if err != nil {
-		log.Fatal().Err(err).Msg("Failed to create subscriptions client")
+		return nil, fmt.Errorf("failed to create subscriptions client: %w", err)
 	}
 
 	resultPager := client.NewListPager(nil)
@@ -26,7 +27,7 @@ func (sc *SubcriptionDiscovery) ListSubscriptions(ctx context.Context, cred azco
 	for resultPager.More() {
 		pageResp, err := resultPager.NextPage(ctx)
 		if err != nil {
-			log.Fatal().Err(err).Msg("Failed to list subscriptions")
+			return nil, fmt.Errorf("failed to list subscriptions: %w", err)
 		}
 
 		for _, s := range pageResp.Value {
@@ -51,5 +52,5 @@ func (sc *SubcriptionDiscovery) ListSubscriptions(ctx context.Context, cred azco
 		}
 	}
 
-	return result
+	return result, nil
 }
@@ -2,30 +2,30 @@ package to
 
 import (
 	"encoding/json"
+	"fmt"
 	"strconv"
-
-	"github.com/rs/zerolog/log"
 )
 
-// String converts an interface{} to string efficiently.
-func String(i interface{}) string {
+// String converts an interface{} to string efficiently. Values other than
+// strings, ints and bools are converted to JSON.
+func String(i interface{}) (string, error) {
 	if i == nil {
-		return ""
+		return "", nil
 	}
 
 	switch v := i.(type) {
 	case string:
-		return v
+		return v, nil
 	case int:
-		return strconv.Itoa(v)
+		return strconv.Itoa(v), nil
 	case bool:
-		return strconv.FormatBool(v)
+		return strconv.FormatBool(v), nil
 	default:
 		jsonStr, err := json.Marshal(i)
 		if err != nil {
-			log.Fatal().Err(err).Msg("Unsupported type found in ARG query result")
+			return "", fmt.Errorf("unsupported type %T: %w", i, err)
 		}
-		return string(jsonStr)
+		return string(jsonStr), nil
 	}
 }
 
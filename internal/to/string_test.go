@@ -77,12 +77,21 @@ func TestString(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := String(tt.input)
+			got, err := String(tt.input)
+			if err != nil {
+				t.Fatalf("String() error = %v", err)
+			}
 			if got != tt.want {
 				t.Errorf("String() = %v, want %v", got, tt.want)
 			}
 		})
 	}
+
+	t.Run("unsupported value", func(t *testing.T) {
+		if _, err := String(make(chan int)); err == nil {
+			t.Error("String() should return an error for a value that cannot be converted to JSON")
+		}
+	})
 }
 
 func TestFloat(t *testing.T) {
@@ -0,0 +1,191 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+// Package azqr runs Azure Quick Review scans from Go programs.
+//
+// Create a Scanner with the scan Options, call Scan to get a typed Report,
+// and optionally render it with one or more Renderers:
+//
+//	scanner, err := azqr.NewScanner(azqr.Options{
+//		Subscriptions: []string{"00000000-0000-0000-0000-000000000000"},
+//		Stages:        []string{"-cost"},
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	report, err := scanner.Scan(ctx)
+//	if err != nil {
+//		return err
+//	}
+//	err = report.Render("governance", azqr.JSONRenderer{})
+//
+// Errors are returned to the caller; the package never exits the process.
+package azqr
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Azure/azqr/internal/models"
+	"github.com/Azure/azqr/internal/pipeline"
+	"github.com/Azure/azqr/internal/plugins"
+	"github.com/Azure/azqr/internal/progress"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+
+	// Register the built-in service scanners and plugins
+	_ "github.com/Azure/azqr/internal/scanners/plugins/aigov"
+	_ "github.com/Azure/azqr/internal/scanners/plugins/carbon"
+	_ "github.com/Azure/azqr/internal/scanners/plugins/costanomaly"
+	_ "github.com/Azure/azqr/internal/scanners/plugins/region"
+	_ "github.com/Azure/azqr/internal/scanners/plugins/servicehealth"
+	_ "github.com/Azure/azqr/internal/scanners/plugins/sqleol"
+	_ "github.com/Azure/azqr/internal/scanners/plugins/zone"
+	_ "github.com/Azure/azqr/internal/scanners/registry"
+)
+
+// Options configures a scan. The zero value scans every subscription the
+// credential can access with the default stages.
+type Options struct {
+	// ManagementGroups scopes the scan to the subscriptions under these management groups
+	ManagementGroups []string
+	// Subscriptions scopes the scan to these subscription IDs
+	Subscriptions []string
+	// ResourceGroups scopes the scan to these resource group names (requires exactly one subscription)
+	ResourceGroups []string
+	// Services limits the scan to these service abbreviations (see ServiceTypes)
+	Services []string
+	// Stages enables ("name") or disables ("-name") scan stages (see Stages)
+	Stages []string
+	// StageParams sets stage options as "stage.key=value"
+	StageParams []string
+	// Plugins enables internal plugins by name (see Plugins)
+	Plugins []string
+	// PluginsOnly runs only the enabled plugins, skipping the resource scan
+	PluginsOnly bool
+	// PluginConcurrency limits how many plugins run at the same time (default 4)
+	PluginConcurrency int
+	// PluginTimeout is the time each plugin may run (default 30 minutes)
+	PluginTimeout time.Duration
+	// FiltersFile is the path of a YAML filters file (optional)
+	FiltersFile string
+	// Mask masks subscription IDs in rendered reports
+	Mask bool
+	// Credential authenticates requests. DefaultAzureCredential is used when nil.
+	Credential azcore.TokenCredential
+	// OutputName is the report name used by Render when none is given (optional)
+	OutputName string
+	// Checkpoint saves progress to <OutputName>.checkpoint.json after each stage
+	Checkpoint bool
+	// ResumeFrom is the path of a checkpoint file to resume from (optional)
+	ResumeFrom string
+	// Progress receives progress events during the scan (optional).
+	// It may be called concurrently from several goroutines.
+	Progress func(ProgressEvent)
+}
+
+// ProgressEvent is a single scan progress update.
+type ProgressEvent = progress.Event
+
+// ProgressEventType identifies the kind of progress event.
+type ProgressEventType = progress.EventType
+
+const (
+	// ProgressStageStarted is sent when a scan stage starts
+	ProgressStageStarted = progress.EventStageStarted
+	// ProgressStageFinished is sent when a scan stage completes or fails
+	ProgressStageFinished = progress.EventStageFinished
+	// ProgressTask is sent when a unit of work inside a stage completes
+	ProgressTask = progress.EventTaskProgress
+)
+
+// Scanner runs azqr scans. It is safe to call Scan more than once.
+type Scanner struct {
+	opts Options
+}
+
+// NewScanner validates the options and returns a Scanner.
+func NewScanner(opts Options) (*Scanner, error) {
+	if _, err := buildParams(opts); err != nil {
+		return nil, err
+	}
+	return &Scanner{opts: opts}, nil
+}
+
+// Scan runs the scan and returns the report. Cancelling ctx stops the scan.
+func (s *Scanner) Scan(ctx context.Context) (*Report, error) {
+	params, err := buildParams(s.opts)
+	if err != nil {
+		return nil, err
+	}
+
+	scanner := pipeline.Scanner{Credential: s.opts.Credential}
+	if s.opts.Progress != nil {
+		scanner.Observer = progress.ObserverFunc(s.opts.Progress)
+	}
+
+	run := scanner.Run
+	if s.opts.PluginsOnly {
+		run = scanner.RunPlugins
+	}
+	data, err := run(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return newReport(data), nil
+}
+
+// buildParams converts the options into scan parameters, returning an error
+// for unknown services, stages, stage options or plugins.
+func buildParams(opts Options) (*models.ScanParams, error) {
+	for _, key := range opts.Services {
+		if _, ok := models.ScannerList[key]; !ok {
+			return nil, fmt.Errorf("unknown service: %s", key)
+		}
+	}
+
+	filters, err := models.ParseFilters(opts.FiltersFile, opts.Services)
+	if err != nil {
+		return nil, err
+	}
+
+	var stages *models.StageConfigs
+	if opts.PluginsOnly {
+		stages = models.NewStageConfigs()
+	} else {
+		stages = models.NewStageConfigsWithDefaults()
+		if err := stages.SelectStages(opts.Stages); err != nil {
+			return nil, err
+		}
+	}
+	if err := stages.ApplyStageParams(opts.StageParams); err != nil {
+		return nil, err
+	}
+
+	enabledPlugins := map[string]bool{}
+	for _, name := range opts.Plugins {
+		if _, ok := plugins.GetRegistry().Get(name); !ok {
+			return nil, fmt.Errorf("unknown plugin: %s", name)
+		}
+		enabledPlugins[name] = true
+	}
+	if opts.PluginsOnly && len(enabledPlugins) == 0 {
+		return nil, fmt.Errorf("PluginsOnly requires at least one plugin")
+	}
+
+	return &models.ScanParams{
+		ManagementGroups:       opts.ManagementGroups,
+		Subscriptions:          opts.Subscriptions,
+		ResourceGroups:         opts.ResourceGroups,
+		OutputName:             opts.OutputName,
+		Stages:                 stages,
+		Mask:                   opts.Mask,
+		ScannerKeys:            opts.Services,
+		Filters:                filters,
+		EnabledInternalPlugins: enabledPlugins,
+		PluginConcurrency:      opts.PluginConcurrency,
+		PluginTimeout:          opts.PluginTimeout,
+		Checkpoint:             opts.Checkpoint,
+		ResumeFrom:             opts.ResumeFrom,
+	}, nil
+}
@@ -0,0 +1,162 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package azqr
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestNewScanner_ValidatesOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    Options
+		wantErr string
+	}{
+		{name: "defaults", opts: Options{}},
+		{name: "services and stages", opts: Options{Services: []string{"aa"}, Stages: []string{"cost", "-advisor"}}},
+		{name: "unknown service", opts: Options{Services: []string{"nope"}}, wantErr: "unknown service"},
+		{name: "unknown stage", opts: Options{Stages: []string{"nope"}}, wantErr: "unknown stage"},
+		{name: "invalid stage param", opts: Options{StageParams: []string{"bad"}}, wantErr: "stage"},
+		{name: "unknown plugin", opts: Options{Plugins: []string{"nope"}}, wantErr: "unknown plugin"},
+		{name: "plugins only without plugins", opts: Options{PluginsOnly: true}, wantErr: "requires at least one plugin"},
+		{name: "missing filters file", opts: Options{FiltersFile: filepath.Join(t.TempDir(), "missing.yaml")}, wantErr: "failed reading"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewScanner(tt.opts)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("NewScanner() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("NewScanner() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestScan_ReturnsValidationError(t *testing.T) {
+	scanner, err := NewScanner(Options{
+		ManagementGroups: []string{"mg"},
+		Subscriptions:    []string{"00000000-0000-0000-0000-000000000000"},
+	})
+	if err != nil {
+		t.Fatalf("NewScanner() error = %v", err)
+	}
+
+	if _, err := scanner.Scan(context.Background()); err == nil {
+		t.Fatal("Scan() expected error for management group combined with subscription")
+	}
+}
+
+func testReport() *Report {
+	return &Report{
+		Name: "sdk",
+		Findings: []*Finding{{
+			RecommendationID: "rec-1",
+			ResourceType:     "Microsoft.Storage/storageAccounts",
+			Recommendation:   "Enable soft delete",
+			ResourceID:       "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/st",
+			SubscriptionID:   "00000000-0000-0000-0000-000000000000",
+			Name:             "st",
+		}},
+		Cost: []*CostResult{{
+			SubscriptionID: "00000000-0000-0000-0000-000000000000",
+			ServiceName:    "Storage",
+			Value:          "12.5",
+			Currency:       "USD",
+		}},
+	}
+}
+
+func TestReport_Tables(t *testing.T) {
+	tables := testReport().Tables()
+
+	impacted, ok := tables["ImpactedResources"]
+	if !ok || len(impacted) != 2 {
+		t.Fatalf("ImpactedResources = %v, want header and one row", impacted)
+	}
+	if _, ok := tables["Costs"]; !ok {
+		t.Error("Costs table missing for a report with cost data")
+	}
+	if _, ok := tables["Azure Policy"]; ok {
+		t.Error("Azure Policy table present for a report without policy data")
+	}
+}
+
+func TestReport_RenderAndWriteJSON(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "report")
+	report := testReport()
+
+	var called bool
+	custom := RendererFunc(func(r *Report, n string) error {
+		called = r == report && n == name
+		return nil
+	})
+	if err := report.Render(name, JSONRenderer{}, CSVRenderer{}, custom); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if !called {
+		t.Error("custom renderer not called with the report and name")
+	}
+	for _, f := range []string{name + ".json", name + ".impacted.csv", name + ".costs.csv"} {
+		if _, err := os.Stat(f); err != nil {
+			t.Errorf("expected file %s: %v", f, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	var out map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("WriteJSON() wrote invalid JSON: %v", err)
+	}
+	if _, ok := out["impacted"]; !ok {
+		t.Errorf("WriteJSON() output missing impacted key: %v", out)
+	}
+}
+
+func TestReport_RenderRequiresName(t *testing.T) {
+	if err := (&Report{}).Render("", JSONRenderer{}); err == nil {
+		t.Fatal("Render() expected error without a name")
+	}
+}
+
+func TestCatalog(t *testing.T) {
+	rules := Rules()
+	if !sort.SliceIsSorted(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID }) {
+		t.Error("Rules() not sorted by ID")
+	}
+
+	if _, ok := ServiceTypes()["aa"]; !ok {
+		t.Error("ServiceTypes() missing aa")
+	}
+
+	stages := Stages()
+	if !stages["graph"] || stages["cost"] {
+		t.Errorf("Stages() defaults = %v", stages)
+	}
+
+	found := false
+	for _, p := range Plugins() {
+		if p.Name == "zone-mapping" {
+			found = p.Type == "internal"
+		}
+	}
+	if !found {
+		t.Error("Plugins() missing internal zone-mapping plugin")
+	}
+}
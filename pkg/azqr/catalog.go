@@ -0,0 +1,100 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package azqr
+
+import (
+	"sort"
+
+	"github.com/Azure/azqr/internal/models"
+	"github.com/Azure/azqr/internal/plugins"
+	"github.com/Azure/azqr/internal/renderers"
+)
+
+// Rule describes a recommendation azqr can evaluate.
+type Rule struct {
+	ID                  string
+	ResourceType        string
+	Category            string
+	Impact              string
+	Recommendation      string
+	LongDescription     string
+	PotentialBenefits   string
+	LearnMoreURL        string
+	AutomationAvailable string
+	Source              string
+}
+
+// Rules returns the catalog of built-in recommendations evaluated by the
+// service scanners, sorted by ID.
+func Rules() []Rule {
+	recommendations, _ := renderers.SupportedRecommendations()
+	rules := make([]Rule, 0, len(recommendations))
+	for _, r := range recommendations {
+		rule := Rule{
+			ID:                  r.RecommendationID,
+			ResourceType:        r.ResourceType,
+			Category:            r.Category,
+			Impact:              r.Impact,
+			Recommendation:      r.Recommendation,
+			LongDescription:     r.LongDescription,
+			PotentialBenefits:   r.PotentialBenefits,
+			AutomationAvailable: r.AutomationAvailable,
+			Source:              r.Source,
+		}
+		if len(r.LearnMoreLink) > 0 {
+			rule.LearnMoreURL = r.LearnMoreLink[0].Url
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// ServiceTypes returns the service abbreviations accepted by Options.Services,
+// each with the Azure resource types it scans.
+func ServiceTypes() map[string][]string {
+	services := map[string][]string{}
+	for key, scanners := range models.ScannerList {
+		for _, s := range scanners {
+			services[key] = append(services[key], s.ResourceTypes()...)
+		}
+	}
+	return services
+}
+
+// Stages returns the stage names accepted by Options.Stages and whether each
+// is enabled by default.
+func Stages() map[string]bool {
+	return models.DefaultStages()
+}
+
+// PluginInfo describes a plugin accepted by Options.Plugins.
+type PluginInfo struct {
+	Name        string
+	Version     string
+	Description string
+	// Type is "internal", "yaml" or "external"
+	Type string
+}
+
+// Plugins returns the registered plugins, sorted by name.
+func Plugins() []PluginInfo {
+	list := plugins.GetRegistry().List()
+	infos := make([]PluginInfo, 0, len(list))
+	for _, p := range list {
+		infos = append(infos, PluginInfo{
+			Name:        p.Metadata.Name,
+			Version:     p.Metadata.Version,
+			Description: p.Metadata.Description,
+			Type:        p.Metadata.Type.String(),
+		})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
+// LoadPlugins loads the verified plugin packages in ~/.azqr/plugins and ./plugins so
+// their rules are evaluated by subsequent scans.
+func LoadPlugins() error {
+	return plugins.LoadAll()
+}
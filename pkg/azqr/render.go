@@ -0,0 +1,121 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package azqr
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/Azure/azqr/internal/models"
+	"github.com/Azure/azqr/internal/renderers/csv"
+	"github.com/Azure/azqr/internal/renderers/excel"
+	"github.com/Azure/azqr/internal/renderers/json"
+)
+
+// Renderer writes a report. name is the base name of the files to create
+// (renderers add their own extension). Implement it to plug in custom formats.
+type Renderer interface {
+	Render(report *Report, name string) error
+}
+
+// RendererFunc adapts a function to the Renderer interface.
+type RendererFunc func(report *Report, name string) error
+
+// Render implements Renderer.
+func (f RendererFunc) Render(report *Report, name string) error {
+	return f(report, name)
+}
+
+// ExcelRenderer writes <name>.xlsx with the same sheets as the azqr CLI.
+type ExcelRenderer struct{}
+
+// Render implements Renderer.
+func (ExcelRenderer) Render(report *Report, name string) error {
+	return excel.CreateExcelReport(report.reportData(name))
+}
+
+// JSONRenderer writes <name>.json with the same structure as the azqr CLI.
+type JSONRenderer struct{}
+
+// Render implements Renderer.
+func (JSONRenderer) Render(report *Report, name string) error {
+	return json.CreateJsonReport(report.reportData(name))
+}
+
+// CSVRenderer writes one <name>.<table>.csv file per table, like the azqr CLI.
+type CSVRenderer struct{}
+
+// Render implements Renderer.
+func (CSVRenderer) Render(report *Report, name string) error {
+	return csv.CreateCsvReport(report.reportData(name))
+}
+
+// Render renders the report with each renderer, stopping at the first error.
+// An empty name falls back to the report name.
+func (r *Report) Render(name string, rs ...Renderer) error {
+	if name == "" {
+		name = r.Name
+	}
+	if name == "" {
+		return fmt.Errorf("report name is required")
+	}
+	for _, renderer := range rs {
+		if err := renderer.Render(r, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteJSON writes the consolidated JSON report (as rendered by JSONRenderer) to w.
+func (r *Report) WriteJSON(w io.Writer) error {
+	out, err := json.CreateJsonOutput(r.reportData(r.Name))
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, out)
+	return err
+}
+
+// Tables returns the report tables keyed by the CLI sheet name, with the
+// header as the first row. Tables of disabled stages are omitted. Custom
+// renderers can use it to reuse the CLI layout.
+func (r *Report) Tables() map[string][][]string {
+	data := r.reportData(r.Name)
+	tables := map[string][][]string{}
+	for _, t := range []struct {
+		stage string
+		sheet string
+		table func() [][]string
+	}{
+		{models.StageNameGraph, "Recommendations", data.RecommendationsTable},
+		{models.StageNameGraph, "ImpactedResources", data.ImpactedTable},
+		{models.StageNameGraph, "ResourceTypes", data.ResourceTypesTable},
+		{models.StageNameGraph, "Inventory", data.ResourcesTable},
+		{models.StageNameGraph, "OutOfScope", data.ExcludedResourcesTable},
+		{models.StageNameAdvisor, "Advisor", data.AdvisorTable},
+		{models.StageNamePolicy, "Azure Policy", data.AzurePolicyTable},
+		{models.StageNamePolicy, "Policy Subscriptions", data.AzurePolicySubscriptionsTable},
+		{models.StageNamePolicy, "Policy Initiatives", data.AzurePolicyInitiativesTable},
+		{models.StageNamePolicy, "Policy Assignments", data.AzurePolicyAssignmentsTable},
+		{models.StageNamePolicy, "Policy Top Non-Compliant", data.AzurePolicyTopTable},
+		{models.StageNamePolicy, "Policy Exemptions", data.AzurePolicyExemptionsTable},
+		{models.StageNameArc, "Arc SQL", data.ArcSQLTable},
+		{models.StageNameArc, "Arc Servers", data.ArcServersTable},
+		{models.StageNameDefenderRecommendations, "DefenderRecommendations", data.DefenderRecommendationsTable},
+		{models.StageNameDefender, "Defender", data.DefenderTable},
+		{models.StageNameDefender, "SecureScore", data.SecureScoreTable},
+		{models.StageNameDefender, "SecureScoreControls", data.SecureScoreControlsTable},
+		{models.StageNameCost, "Costs", data.CostTable},
+		{models.StageNameQuota, "Quota", data.QuotaTable},
+	} {
+		if data.Stages.IsStageEnabled(t.stage) {
+			tables[t.sheet] = t.table()
+		}
+	}
+	for _, p := range data.PluginResults {
+		tables[p.SheetName] = p.Table
+	}
+	return tables
+}
@@ -0,0 +1,171 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package azqr
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Azure/azqr/internal/models"
+	"github.com/Azure/azqr/internal/renderers"
+)
+
+// Typed report models. They are aliases of the models used by the azqr CLI,
+// so a Report carries exactly the data the CLI renders.
+type (
+	// Finding is a resource that does not follow a recommendation
+	Finding = models.GraphResult
+	// Recommendation is a rule evaluated during the scan
+	Recommendation = models.GraphRecommendation
+	// Resource is a resource discovered by the scan
+	Resource = models.Resource
+	// ResourceTypeCount is the number of resources of a type in a subscription
+	ResourceTypeCount = models.ResourceTypeCount
+	// AdvisorResult is an Azure Advisor recommendation
+	AdvisorResult = models.AdvisorResult
+	// DefenderResult is the Microsoft Defender for Cloud plan status of a subscription
+	DefenderResult = models.DefenderResult
+	// DefenderRecommendation is a Microsoft Defender for Cloud recommendation
+	DefenderRecommendation = models.DefenderRecommendation
+	// SecureScoreResult is the Microsoft Defender for Cloud secure score of a subscription
+	SecureScoreResult = models.SecureScoreResult
+	// SecureScoreControl is the secure score of a Microsoft Defender for Cloud security control
+	SecureScoreControl = models.SecureScoreControl
+	// AzurePolicyResult is a non-compliant Azure Policy state
+	AzurePolicyResult = models.AzurePolicyResult
+	// AzurePolicyComplianceResult is the resource compliance of a policy assignment or subscription
+	AzurePolicyComplianceResult = models.AzurePolicyComplianceResult
+	// AzurePolicyExemptionResult is an Azure Policy exemption
+	AzurePolicyExemptionResult = models.AzurePolicyExemptionResult
+	// ArcSQLResult is an Arc-enabled SQL Server instance
+	ArcSQLResult = models.ArcSQLResult
+	// ArcServerResult is an Azure Arc-enabled server
+	ArcServerResult = models.ArcServerResult
+	// CostResult is the cost of a service in a subscription
+	CostResult = models.CostResult
+	// QuotaResult is a quota near or over its limit in a subscription and region
+	QuotaResult = models.QuotaResult
+	// ResourceCost is the cost of a resource in the last calendar month
+	ResourceCost = models.ResourceCost
+	// PluginResult is a table produced by a plugin
+	PluginResult = renderers.PluginResult
+	// PluginRun is the status and duration of a plugin execution
+	PluginRun = renderers.PluginRun
+)
+
+// Report holds the results of a scan. Fields may be modified before rendering.
+type Report struct {
+	// Name is the report name used for rendered files when Render gets an empty name
+	Name string
+	// Mask masks subscription IDs in rendered output
+	Mask bool
+
+	Findings                []*Finding
+	Recommendations         []*Recommendation
+	Resources               []*Resource
+	ExcludedResources       []*Resource
+	ResourceTypeCounts      []*ResourceTypeCount
+	Advisor                 []*AdvisorResult
+	Defender                []*DefenderResult
+	DefenderRecommendations []*DefenderRecommendation
+	SecureScores            []*SecureScoreResult
+	AzurePolicy             []*AzurePolicyResult
+	AzurePolicyCompliance   []*AzurePolicyComplianceResult
+	AzurePolicyExemptions   []*AzurePolicyExemptionResult
+	ArcSQL                  []*ArcSQLResult
+	ArcServers              []*ArcServerResult
+	Cost                    []*CostResult
+	Quota                   []*QuotaResult
+	ResourceCosts           []*ResourceCost
+	Plugins                 []*PluginResult
+	PluginRuns              []*PluginRun
+
+	stages *models.StageConfigs
+}
+
+// newReport converts the pipeline report data into a Report.
+func newReport(data *renderers.ReportData) *Report {
+	r := &Report{
+		Name:                    data.OutputFileName,
+		Mask:                    data.Mask,
+		Findings:                data.Graph,
+		Resources:               data.Resources,
+		ExcludedResources:       data.ExludedResources,
+		ResourceTypeCounts:      data.ResourceTypeCount,
+		Advisor:                 data.Advisor,
+		Defender:                data.Defender,
+		DefenderRecommendations: data.DefenderRecommendations,
+		SecureScores:            data.SecureScores,
+		AzurePolicy:             data.AzurePolicy,
+		AzurePolicyCompliance:   data.AzurePolicyCompliance,
+		AzurePolicyExemptions:   data.AzurePolicyExemptions,
+		ArcSQL:                  data.ArcSQL,
+		ArcServers:              data.ArcServers,
+		Cost:                    data.Cost,
+		Quota:                   data.Quota,
+		ResourceCosts:           data.ResourceCosts,
+		Plugins:                 data.PluginResults,
+		PluginRuns:              data.PluginRuns,
+		stages:                  data.Stages,
+	}
+	for _, byID := range data.Recommendations {
+		for _, rec := range byID {
+			r.Recommendations = append(r.Recommendations, rec)
+		}
+	}
+	sort.Slice(r.Recommendations, func(i, j int) bool {
+		return r.Recommendations[i].RecommendationID < r.Recommendations[j].RecommendationID
+	})
+	return r
+}
+
+// reportData converts the Report back into the report data used by the
+// renderers. Reports built by hand render every stage that has data.
+func (r *Report) reportData(name string) *renderers.ReportData {
+	stages := r.stages
+	if stages == nil {
+		stages = models.NewStageConfigsWithDefaults()
+		for stage, hasData := range map[string]bool{
+			models.StageNameAdvisor:                 len(r.Advisor) > 0,
+			models.StageNameDefender:                len(r.Defender) > 0 || len(r.SecureScores) > 0,
+			models.StageNameDefenderRecommendations: len(r.DefenderRecommendations) > 0,
+			models.StageNamePolicy:                  len(r.AzurePolicy) > 0 || len(r.AzurePolicyCompliance) > 0 || len(r.AzurePolicyExemptions) > 0,
+			models.StageNameArc:                     len(r.ArcSQL) > 0 || len(r.ArcServers) > 0,
+			models.StageNameCost:                    len(r.Cost) > 0 || len(r.ResourceCosts) > 0,
+			models.StageNameQuota:                   len(r.Quota) > 0,
+		} {
+			if hasData {
+				_ = stages.EnableStage(stage)
+			}
+		}
+	}
+
+	data := renderers.NewReportData(name, r.Mask, stages)
+	data.Graph = r.Findings
+	data.Resources = r.Resources
+	data.ExludedResources = r.ExcludedResources
+	data.ResourceTypeCount = r.ResourceTypeCounts
+	data.Advisor = r.Advisor
+	data.Defender = r.Defender
+	data.DefenderRecommendations = r.DefenderRecommendations
+	data.SecureScores = r.SecureScores
+	data.AzurePolicy = r.AzurePolicy
+	data.AzurePolicyCompliance = r.AzurePolicyCompliance
+	data.AzurePolicyExemptions = r.AzurePolicyExemptions
+	data.ArcSQL = r.ArcSQL
+	data.ArcServers = r.ArcServers
+	data.Cost = r.Cost
+	data.Quota = r.Quota
+	data.ResourceCosts = r.ResourceCosts
+	data.PluginResults = r.Plugins
+	data.PluginRuns = r.PluginRuns
+	for _, rec := range r.Recommendations {
+		resourceType := strings.ToLower(rec.ResourceType)
+		if data.Recommendations[resourceType] == nil {
+			data.Recommendations[resourceType] = map[string]*models.GraphRecommendation{}
+		}
+		data.Recommendations[resourceType][rec.RecommendationID] = rec
+	}
+	return &data
+}